@@ -0,0 +1,502 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const versionsJSON = "data/app_versions.json"
+
+// positionals are the words a subcommand takes as its first argument
+var positionals = map[string][]string{
+	"completion": {"bash", "zsh", "fish"},
+	"doctor":     {"data", "env", "status"},
+	"report":     {"monthly", "compare"},
+}
+
+// pathFlags are the flags, across subcommands, whose value is a file
+var pathFlags = map[string]bool{
+	"apps-json":    true,
+	"file":         true,
+	"metrics-file": true,
+	"output":       true,
+	"results":      true,
+	"snippet":      true,
+}
+
+// Kinds of value a flag takes, which decide what the scripts complete it with
+const (
+	valueNone  = ""     // Boolean flag
+	valueDir   = "dir"  // A directory
+	valueSlug  = "slug" // An app slug from data/app_versions.json
+	valueFile  = "file" // A file, one of pathFlags
+	valueOther = "other"
+)
+
+// flagSpec is a flag as the completion scripts offer it
+type flagSpec struct {
+	name  string
+	usage string
+	value string
+}
+
+// commandSpec is a subcommand as the completion scripts offer it: its own
+// flags, without the common ones every subcommand shares
+type commandSpec struct {
+	name    string
+	summary string
+	words   []string
+	flags   []flagSpec
+}
+
+func init() {
+	// Registered here since the scripts list every subcommand, this one included
+	subcommands["completion"] = subcommand{runCompletion, "print a bash, zsh or fish completion script"}
+}
+
+func printCompletionUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: fleet-tracker completion bash|zsh|fish")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "  source <(fleet-tracker completion bash)     # in ~/.bashrc")
+	fmt.Fprintln(os.Stderr, "  source <(fleet-tracker completion zsh)      # in ~/.zshrc")
+	fmt.Fprintln(os.Stderr, "  fleet-tracker completion fish | source      # in ~/.config/fish/config.fish")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "fleet-tracker completion slugs lists the app slugs the scripts complete --slug with.")
+}
+
+// runCompletion prints the completion script for a shell, or the app slugs in
+// data/app_versions.json, which the scripts call back for to complete --slug
+func runCompletion(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("completion", flag.ExitOnError)
+	fs.Usage = printCompletionUsage
+	common.Register(fs)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		printCompletionUsage()
+		os.Exit(2)
+	}
+
+	switch fs.Arg(0) {
+	case "bash":
+		fmt.Print(bashCompletion(commonFlags(), commandSpecs()))
+	case "zsh":
+		fmt.Print(zshCompletion(commonFlags(), commandSpecs()))
+	case "fish":
+		fmt.Print(fishCompletion(commonFlags(), commandSpecs()))
+	case "slugs":
+		if err := common.Apply(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		slugs, err := loadSlugs()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, slug := range slugs {
+			fmt.Println(slug)
+		}
+	default:
+		printCompletionUsage()
+		os.Exit(2)
+	}
+}
+
+// loadSlugs returns the slugs in data/app_versions.json, sorted
+func loadSlugs() ([]string, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s: %w", versionsJSON, err)
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", versionsJSON, err)
+	}
+	slugs := make([]string, 0, len(versions.Apps))
+	for _, app := range versions.Apps {
+		slugs = append(slugs, app.Slug)
+	}
+	sort.Strings(slugs)
+	return slugs, nil
+}
+
+// commonFlags are the flags every subcommand accepts, before or after its name
+func commonFlags() []flagSpec {
+	common := cli.Defaults()
+	fs := flag.NewFlagSet("fleet-tracker", flag.ContinueOnError)
+	common.Register(fs)
+	return flagSpecs(fs, nil)
+}
+
+// commandSpecs describes every subcommand, sorted by name
+func commandSpecs() []commandSpec {
+	shared := make(map[string]bool)
+	for _, f := range commonFlags() {
+		shared[f.name] = true
+	}
+
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	specs := make([]commandSpec, 0, len(names))
+	for _, name := range names {
+		cmd := subcommands[name]
+		specs = append(specs, commandSpec{
+			name:    name,
+			summary: cmd.summary,
+			words:   positionals[name],
+			flags:   flagSpecs(cli.Flags(cmd.run), shared),
+		})
+	}
+	return specs
+}
+
+// flagSpecs lists the flags of fs, sorted by name, leaving out those in skip
+func flagSpecs(fs *flag.FlagSet, skip map[string]bool) []flagSpec {
+	var specs []flagSpec
+	fs.VisitAll(func(f *flag.Flag) {
+		if skip[f.Name] {
+			return
+		}
+		kind, usage := flag.UnquoteUsage(f)
+		spec := flagSpec{name: f.Name, usage: strings.Join(strings.Fields(usage), " ")}
+		switch {
+		case kind == "":
+			spec.value = valueNone
+		case f.Name == "dir":
+			spec.value = valueDir
+		case f.Name == "slug":
+			spec.value = valueSlug
+		case pathFlags[f.Name]:
+			spec.value = valueFile
+		default:
+			spec.value = valueOther
+		}
+		specs = append(specs, spec)
+	})
+	return specs
+}
+
+// valueFlags returns the names of the flags that take a value, space separated
+func valueFlags(flags []flagSpec) string {
+	var names []string
+	for _, f := range flags {
+		if f.value != valueNone {
+			names = append(names, f.name)
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// dashed returns the flags as --name, space separated
+func dashed(flags []flagSpec) string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, "--"+f.name)
+	}
+	return strings.Join(names, " ")
+}
+
+// bashCompletion completes subcommands, their words and flags, directories
+// for --dir and slugs for --slug; other values fall back to file names
+func bashCompletion(common []flagSpec, commands []commandSpec) string {
+	var sb strings.Builder
+	sb.WriteString(`# bash completion for fleet-tracker
+# Load it with: source <(fleet-tracker completion bash)
+
+_fleet_tracker() {
+    local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}
+    local command="" position=0 dir="" i word
+`)
+	fmt.Fprintf(&sb, "    local common=\" %s \"\n", valueFlags(common))
+	sb.WriteString(`
+    # --dir=value is split into --dir, = and value by COMP_WORDBREAKS
+    if [[ $cur == = ]]; then
+        cur=""
+    elif [[ $prev == = ]]; then
+        prev=${COMP_WORDS[COMP_CWORD-2]}
+    fi
+
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word=${COMP_WORDS[i]}
+        if [[ $word == -dir || $word == --dir ]]; then
+            dir=${COMP_WORDS[i+1]}
+            [[ $dir == = ]] && dir=${COMP_WORDS[i+2]}
+        fi
+        if [[ -z $command ]]; then
+            case $word in
+            -*)
+                word=${word#-}
+                if [[ $common == *" ${word#-} "* ]]; then
+                    [[ ${COMP_WORDS[i+1]} == = ]] && ((i++))
+                    ((i++))
+                fi
+                ;;
+            *)
+                command=$word
+                position=$i
+                ;;
+            esac
+        fi
+    done
+    dir=${dir/#\~/$HOME}
+
+    local flags words values
+`)
+	fmt.Fprintf(&sb, "    flags=%q\n", dashed(common))
+	sb.WriteString("    case $command in\n    \"\")\n")
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	fmt.Fprintf(&sb, "        words=%q\n        ;;\n", strings.Join(names, " "))
+	for _, cmd := range commands {
+		fmt.Fprintf(&sb, "    %s)\n", cmd.name)
+		if len(cmd.flags) > 0 {
+			fmt.Fprintf(&sb, "        flags+=%q\n", " "+dashed(cmd.flags))
+		}
+		if v := valueFlags(cmd.flags); v != "" {
+			fmt.Fprintf(&sb, "        values=%q\n", v)
+		}
+		if len(cmd.words) > 0 {
+			fmt.Fprintf(&sb, "        ((COMP_CWORD == position + 1)) && words=%q\n", strings.Join(cmd.words, " "))
+		}
+		sb.WriteString("        ;;\n")
+	}
+	sb.WriteString(`    esac
+
+    local name=${prev#-}
+    name=${name#-}
+    if [[ $prev == -* ]]; then
+        case $name in
+        dir)
+            COMPREPLY=($(compgen -d -- "$cur"))
+            return
+            ;;
+        slug)
+            COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" ${dir:+--dir "$dir"} completion slugs 2>/dev/null)" -- "$cur"))
+            return
+            ;;
+        esac
+        # Leave other flag values to the default completion
+        [[ $common == *" $name "* || " $values " == *" $name "* ]] && return
+    fi
+
+    if [[ $cur == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+    elif [[ -n $words ]]; then
+        COMPREPLY=($(compgen -W "$words" -- "$cur"))
+    fi
+}
+
+complete -o default -F _fleet_tracker fleet-tracker
+`)
+	return sb.String()
+}
+
+// zshEscape quotes s for a single-quoted _arguments spec or _describe item
+func zshEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+	return strings.ReplaceAll(s, "'", `'\''`)
+}
+
+// zshSpec is the _arguments spec of a flag
+func zshSpec(f flagSpec) string {
+	switch f.value {
+	case valueNone:
+		return fmt.Sprintf("'--%s[%s]'", f.name, zshEscape(f.usage))
+	case valueDir:
+		return fmt.Sprintf("'--%s=[%s]:directory:_files -/'", f.name, zshEscape(f.usage))
+	case valueSlug:
+		return fmt.Sprintf("'--%s=[%s]:app slug:_fleet_tracker_slugs'", f.name, zshEscape(f.usage))
+	case valueFile:
+		return fmt.Sprintf("'--%s=[%s]:%s:_files'", f.name, zshEscape(f.usage), f.name)
+	default:
+		return fmt.Sprintf("'--%s=[%s]:%s: '", f.name, zshEscape(f.usage), f.name)
+	}
+}
+
+// zshCompletion completes the same as bashCompletion, with the flags' usage
+// and the subcommands' summaries as descriptions
+func zshCompletion(common []flagSpec, commands []commandSpec) string {
+	var sb strings.Builder
+	sb.WriteString(`#compdef fleet-tracker
+# zsh completion for fleet-tracker
+# Load it with: source <(fleet-tracker completion zsh), or save it as
+# _fleet-tracker in a directory of $fpath
+
+_fleet_tracker_slugs() {
+  local dir=${opt_args[--dir]:-$fleet_tracker_dir}
+  local -a slugs
+  slugs=(${(f)"$($fleet_tracker ${dir:+--dir} ${dir:+${~dir}} completion slugs 2>/dev/null)"})
+  compadd -a slugs
+}
+
+_fleet_tracker() {
+  local curcontext=$curcontext state line fleet_tracker=$words[1] fleet_tracker_dir
+  local -A opt_args
+  local -a common=(
+`)
+	for _, f := range common {
+		fmt.Fprintf(&sb, "    %s\n", zshSpec(f))
+	}
+	sb.WriteString(`  )
+
+  _arguments -C $common '1: :->command' '*:: :->argument'
+  case $state in
+  command)
+    local -a commands=(
+`)
+	for _, cmd := range commands {
+		fmt.Fprintf(&sb, "      '%s:%s'\n", zshEscape(cmd.name), zshEscape(cmd.summary))
+	}
+	sb.WriteString(`    )
+    _describe -t commands command commands
+    ;;
+  argument)
+    fleet_tracker_dir=${opt_args[--dir]}
+    case $words[1] in
+`)
+	for _, cmd := range commands {
+		fmt.Fprintf(&sb, "    %s)\n      _arguments $common", cmd.name)
+		for _, f := range cmd.flags {
+			fmt.Fprintf(&sb, " \\\n        %s", zshSpec(f))
+		}
+		if len(cmd.words) > 0 {
+			fmt.Fprintf(&sb, " \\\n        '1: :(%s)'\n", strings.Join(cmd.words, " "))
+		} else {
+			sb.WriteString(" \\\n        '*: :_files'\n")
+		}
+		sb.WriteString("      ;;\n")
+	}
+	sb.WriteString(`    esac
+    ;;
+  esac
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+  _fleet_tracker "$@"
+else
+  compdef _fleet_tracker fleet-tracker
+fi
+`)
+	return sb.String()
+}
+
+// fishEscape quotes s for a single-quoted fish string
+func fishEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s)
+}
+
+// fishFlag is the complete command offering a flag when condition holds
+func fishFlag(f flagSpec, condition string) string {
+	line := "complete -c fleet-tracker"
+	if condition != "" {
+		line += " -n '" + condition + "'"
+	}
+	line += " -l " + f.name
+	switch f.value {
+	case valueDir:
+		line += " -x -a '(__fish_complete_directories)'"
+	case valueSlug:
+		line += " -x -a '(__fleet_tracker_slugs)'"
+	case valueFile:
+		line += " -r -F"
+	case valueOther:
+		line += " -x"
+	}
+	return line + " -d '" + fishEscape(f.usage) + "'\n"
+}
+
+// fishCompletion completes the same as zshCompletion
+func fishCompletion(common []flagSpec, commands []commandSpec) string {
+	var sb strings.Builder
+	sb.WriteString(`# fish completion for fleet-tracker
+# Load it with: fleet-tracker completion fish | source, or save it as
+# ~/.config/fish/completions/fleet-tracker.fish
+
+function __fleet_tracker_command --description 'Print the subcommand on the command line'
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    while set -q tokens[1]
+        switch $tokens[1]
+            case`)
+	for _, f := range common {
+		if f.value != valueNone {
+			fmt.Fprintf(&sb, " -%s --%s", f.name, f.name)
+		}
+	}
+	sb.WriteString(`
+                set -e tokens[1]
+            case '-*'
+            case '*'
+                echo $tokens[1]
+                return 0
+        end
+        set -e tokens[1]
+    end
+    return 1
+end
+
+function __fleet_tracker_using --description 'Test whether the subcommand is one of the arguments'
+    set -l command (__fleet_tracker_command)
+    and contains -- $command $argv
+end
+
+function __fleet_tracker_slugs --description 'List the app slugs of the checkout given by --dir'
+    set -l tokens (commandline -opc)
+    set -l dir
+    for i in (seq 2 (count $tokens))
+        switch $tokens[$i]
+            case -dir --dir
+                set dir --dir $tokens[(math $i + 1)]
+            case '-dir=*' '--dir=*'
+                set dir --dir (string replace -r '^-+dir=' '' -- $tokens[$i])
+        end
+    end
+    set dir (string replace -r '^~' $HOME -- $dir)
+    $tokens[1] $dir completion slugs 2>/dev/null
+end
+
+complete -c fleet-tracker -f
+`)
+	var plain []string
+	for _, cmd := range commands {
+		fmt.Fprintf(&sb, "complete -c fleet-tracker -n 'not __fleet_tracker_command' -a %s -d '%s'\n", cmd.name, fishEscape(cmd.summary))
+		if len(cmd.words) == 0 {
+			plain = append(plain, cmd.name)
+		}
+	}
+	for _, f := range common {
+		sb.WriteString(fishFlag(f, ""))
+	}
+	for _, cmd := range commands {
+		condition := "__fleet_tracker_using " + cmd.name
+		for _, f := range cmd.flags {
+			sb.WriteString(fishFlag(f, condition))
+		}
+		if len(cmd.words) > 0 {
+			words := strings.Join(cmd.words, " ")
+			fmt.Fprintf(&sb, "complete -c fleet-tracker -n '%s; and not __fish_seen_subcommand_from %s' -a '%s'\n", condition, words, words)
+		}
+	}
+	fmt.Fprintf(&sb, "complete -c fleet-tracker -n '__fleet_tracker_using %s' -F\n", strings.Join(plain, " "))
+	return sb.String()
+}
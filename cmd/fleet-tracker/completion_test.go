@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompletionScriptsCoverEveryCommand(t *testing.T) {
+	common, commands := commonFlags(), commandSpecs()
+	tests := []struct {
+		shell  string
+		script string
+		flag   string // How the script spells a flag
+	}{
+		{"bash", bashCompletion(common, commands), "--"},
+		{"zsh", zshCompletion(common, commands), "--"},
+		{"fish", fishCompletion(common, commands), "-l "},
+	}
+	for _, tt := range tests {
+		for name := range subcommands {
+			if !strings.Contains(tt.script, name) {
+				t.Errorf("%s script doesn't complete %s", tt.shell, name)
+			}
+		}
+		for _, flag := range []string{"dir", "github-token", "refresh", "slug", "all-versions"} {
+			if !strings.Contains(tt.script, tt.flag+flag) {
+				t.Errorf("%s script doesn't complete --%s", tt.shell, flag)
+			}
+		}
+	}
+}
+
+// TestCompletionScriptsParse checks each script with its shell's syntax check,
+// for the shells installed
+func TestCompletionScriptsParse(t *testing.T) {
+	common, commands := commonFlags(), commandSpecs()
+	tests := []struct {
+		shell  string
+		args   []string
+		script string
+	}{
+		{"bash", []string{"-n"}, bashCompletion(common, commands)},
+		{"zsh", []string{"-n"}, zshCompletion(common, commands)},
+		{"fish", []string{"--no-execute"}, fishCompletion(common, commands)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			if _, err := exec.LookPath(tt.shell); err != nil {
+				t.Skipf("%s is not installed", tt.shell)
+			}
+			path := filepath.Join(t.TempDir(), "completion")
+			if err := os.WriteFile(path, []byte(tt.script), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if output, err := exec.Command(tt.shell, append(tt.args, path)...).CombinedOutput(); err != nil {
+				t.Errorf("%s rejects the script: %v\n%s", tt.shell, err, output)
+			}
+		})
+	}
+}
+
+// TestBashCompletion runs the bash completion function on command lines,
+// with a stand-in fleet-tracker that lists slugs for the --dir it's given
+func TestBashCompletion(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "completion.bash")
+	if err := os.WriteFile(script, []byte(bashCompletion(commonFlags(), commandSpecs())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tracker := filepath.Join(dir, "fleet-tracker")
+	stub := "#!/bin/sh\n[ \"$*\" = \"--dir /tracker completion slugs\" ] && printf 'zoom/darwin\\nzoom/windows\\nslack/darwin\\n'\n"
+	if err := os.WriteFile(tracker, []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"ht", []string{"html"}},
+		{"--dir /tracker --github-token t he", nil},
+		{"--dir /tracker --github-token t hi", []string{"history"}},
+		{"collect --t", []string{"--tz"}},
+		{"html --re", []string{"--refresh"}},
+		{"html --pro", []string{"--proxy"}},
+		{"doctor ", []string{"data", "env", "status"}},
+		{"--proxy http://proxy:3128 report c", []string{"compare"}},
+		{"doctor data ", nil},
+		{"completion f", []string{"fish"}},
+		{"--dir /tracker verify --slug zoom", []string{"zoom/darwin", "zoom/windows"}},
+		{"verify --dir /tracker --slug = zoom/w", []string{"zoom/windows"}},
+		{"--dir = /tracker verify --slug s", []string{"slack/darwin"}},
+		{"verify --slug zoom", nil},
+	}
+	for _, tt := range tests {
+		words := append([]string{tracker}, strings.Split(tt.line, " ")...)
+		driver := `source "$1"; shift
+COMP_WORDS=("$@"); COMP_CWORD=$(($# - 1))
+_fleet_tracker
+printf '%s\n' "${COMPREPLY[@]}"`
+		output, err := exec.Command("bash", append([]string{"-c", driver, "bash", script}, words...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%q: %v\n%s", tt.line, err, output)
+		}
+		got := strings.Fields(string(output))
+		if len(got) == 0 {
+			got = nil
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completing %q = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestLoadSlugs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	versions := `{"schemaVersion": 1, "apps": [{"slug": "zoom/windows"}, {"slug": "1password/darwin"}, {"slug": "zoom/darwin"}]}`
+	if err := os.WriteFile(filepath.Join(dir, versionsJSON), []byte(versions), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	slugs, err := loadSlugs()
+	want := []string{"1password/darwin", "zoom/darwin", "zoom/windows"}
+	if err != nil || !reflect.DeepEqual(slugs, want) {
+		t.Errorf("loadSlugs() = %q, %v; want %q", slugs, err, want)
+	}
+}
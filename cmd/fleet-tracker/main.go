@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/advisory"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/changelog"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/checksums"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/collect"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/compress"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/crossref"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/dashboard"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/doctor"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/fleetctl"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/history"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/ics"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/intune"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/jamf"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/lint"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/macsecurity"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/mergesecurity"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/munki"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/ogimage"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/probe"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/publish"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/readme"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/report"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/rss"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/serve"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/snapshot"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/trackfailures"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/upstreamlag"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/verify"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/winsecurity"
+)
+
+// subcommand is a command of the tracker, run in this process
+type subcommand struct {
+	run     cli.Command
+	summary string
+}
+
+// subcommands maps each fleet-tracker subcommand to the command it runs
+var subcommands = map[string]subcommand{
+	"collect":        {collect.Main, "fetch the catalog and update data/"},
+	"html":           {dashboard.Main, "generate index.html"},
+	"rss":            {rss.Main, "generate feed.xml"},
+	"readme":         {readme.Main, "generate README.md"},
+	"history":        {history.Main, "backfill the version history from apps.json commits"},
+	"security":       securityCollector(runtime.GOOS),
+	"merge-security": {mergesecurity.Main, "merge sharded security info results"},
+	"advisory":       {advisory.Main, "generate advisory.xml"},
+	"ics":            {ics.Main, "generate releases.ics"},
+	"changelog":      {changelog.Main, "generate changelog.html and CHANGELOG.md"},
+	"fleetctl":       {fleetctl.Main, "generate Fleet GitOps software files"},
+	"intune":         {intune.Main, "generate Intune detection rules"},
+	"munki":          {munki.Main, "generate Munki pkginfo files"},
+	"jamf":           {jamf.Main, "generate Jamf extension attributes"},
+	"checksums":      {checksums.Main, "generate SHA256SUMS"},
+	"og-image":       {ogimage.Main, "render og-image.png"},
+	"compress":       {compress.Main, "write gzip copies of the pages, feeds and data files"},
+	"probe":          {probe.Main, "check that every installer URL responds"},
+	"crossref":       {crossref.Main, "compare Windows apps with winget and Chocolatey"},
+	"upstream-lag":   {upstreamlag.Main, "compare apps with their vendors' latest releases"},
+	"track-failures": {trackfailures.Main, "file and close collection failure issues"},
+	"doctor":         {doctor.Main, "diagnose the data or a collector's environment"},
+	"report":         {report.Main, "write monthly and comparison reports"},
+	"snapshot":       {snapshot.Main, "build the monthly data release tarball"},
+	"publish":        {publish.Main, "sync the site to a bucket"},
+	"serve":          {serve.Main, "serve the site locally"},
+	"lint":           {lint.Main, "check apps.json and the data files for consistency problems"},
+	"verify":         {verify.Main, "check an installer or app against the recorded hashes"},
+}
+
+// securityCollector is the collector for the platform fleet-tracker runs on
+func securityCollector(goos string) subcommand {
+	if goos == "windows" {
+		return subcommand{winsecurity.Main, "collect security info on this platform's apps"}
+	}
+	return subcommand{macsecurity.Main, "collect security info on this platform's apps"}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: fleet-tracker [--dir path] [--proxy url] [--github-token token] <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-15s %s\n", name, subcommands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "The flags before the command apply to it as if given after it.")
+	fmt.Fprintln(os.Stderr, "Run fleet-tracker <command> --help for a command's flags.")
+}
+
+// fleet-tracker - One binary for every command of the tracker, so workflows
+// and contributors don't need the Go toolchain or have to remember which
+// script does what:
+//
+//	go build -o fleet-tracker ./cmd/fleet-tracker
+//	./fleet-tracker --dir ~/tracker collect && ./fleet-tracker --dir ~/tracker html
+//
+// Subcommands run in this process, in the checkout given by --dir, and exit
+// with the command's status. The root scripts wrap the same commands for go
+// run. --dir, --proxy and --github-token apply to every subcommand.
+func main() {
+	common := cli.Defaults()
+	common.Register(flag.CommandLine)
+	flag.Usage = printUsage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd, ok := subcommands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "❌ Error: unknown command %q\n\n", flag.Arg(0))
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd.run(common, flag.Args()[1:])
+}
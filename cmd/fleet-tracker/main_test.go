@@ -0,0 +1,34 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/macsecurity"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/winsecurity"
+)
+
+func TestSubcommands(t *testing.T) {
+	for name, cmd := range subcommands {
+		if cmd.run == nil || cmd.summary == "" {
+			t.Errorf("%s: missing command or summary", name)
+		}
+	}
+}
+
+func TestSecurityCollector(t *testing.T) {
+	tests := []struct {
+		goos string
+		want interface{}
+	}{
+		{"darwin", macsecurity.Main},
+		{"linux", macsecurity.Main},
+		{"windows", winsecurity.Main},
+	}
+	for _, tt := range tests {
+		got := securityCollector(tt.goos).run
+		if reflect.ValueOf(got).Pointer() != reflect.ValueOf(tt.want).Pointer() {
+			t.Errorf("securityCollector(%q) runs the wrong collector", tt.goos)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/compress"
+)
+
+// compress_outputs.go - Writes a gzip-compressed copy next to each generated
+// page, feed and data file (index.html.gz, data/app_versions.json.gz, ...),
+// for servers that send precompressed files instead of compressing on every
+// request, such as serve.go or nginx's gzip_static:
+//
+//	go run compress_outputs.go [--min-size 1024]
+func main() {
+	compress.Main(cli.Defaults(), os.Args[1:])
+}
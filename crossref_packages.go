@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/crossref"
+)
+
+// crossref_packages.go - Looks up every Windows app in winget and Chocolatey
+// and records the package IDs, their latest versions and how Fleet compares:
+//
+//	go run crossref_packages.go
+//
+// winget IDs come from Fleet's own winget inputs, Chocolatey IDs from a search
+// by app name; data/package_ids.json overrides either per slug. Results go to
+// data/package_parity.json, which generate_html.go shows in app details.
+func main() {
+	crossref.Main(cli.Defaults(), os.Args[1:])
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/doctor"
+)
+
+// doctor.go - Diagnoses problems in the generated data, or in the environment
+// a security info collector is about to run in:
+//
+//	go run doctor.go data [--fix]
+//	go run doctor.go env
+//	go run doctor.go status
+func main() {
+	doctor.Main(cli.Defaults(), os.Args[1:])
+}
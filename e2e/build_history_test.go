@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildHistory(t *testing.T) {
+	bin := buildScript(t, "build_history.go")
+	dir := newWorkDir(t)
+
+	runScript(t, bin, dir, "build_history.json", "-concurrency", "1")
+
+	var history versionHistory
+	readJSON(t, filepath.Join(dir, "data", "version_history.json"), &history)
+	got := make(map[string]versionChange)
+	for _, c := range history.Changes {
+		got[c.Slug] = c
+	}
+	want := map[string]versionChange{
+		"zoom/darwin":   {Slug: "zoom/darwin", OldVersion: "6.3.0", NewVersion: "6.3.5"},
+		"slack/darwin":  {Slug: "slack/darwin", OldVersion: "", NewVersion: "4.41.105"},
+		"7-zip/windows": {Slug: "7-zip/windows", OldVersion: "", NewVersion: "24.09"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("version_history.json changes = %+v, want %+v", got, want)
+	}
+
+	var firstSeen struct {
+		Apps []struct {
+			Slug      string `json:"slug"`
+			CommitSha string `json:"commitSha"`
+		} `json:"apps"`
+	}
+	readJSON(t, filepath.Join(dir, "data", "app_first_seen.json"), &firstSeen)
+	gotSeen := make(map[string]string)
+	for _, app := range firstSeen.Apps {
+		gotSeen[app.Slug] = app.CommitSha[:1]
+	}
+	wantSeen := map[string]string{"zoom/darwin": "a", "slack/darwin": "b", "7-zip/windows": "b"}
+	if !reflect.DeepEqual(gotSeen, wantSeen) {
+		t.Errorf("first-seen commits = %v, want %v", gotSeen, wantSeen)
+	}
+}
+
+func TestBuildHistoryResumesFromCheckpoint(t *testing.T) {
+	bin := buildScript(t, "build_history.go")
+	dir := newWorkDir(t)
+
+	runScript(t, bin, dir, "build_history.json", "-max-commits", "1")
+
+	var history versionHistory
+	readJSON(t, filepath.Join(dir, "data", "version_history.json"), &history)
+	if len(history.Changes) != 0 {
+		t.Errorf("after the first commit, changes = %+v, want none", history.Changes)
+	}
+
+	runScript(t, bin, dir, "build_history.json", "-max-commits", "1")
+
+	readJSON(t, filepath.Join(dir, "data", "version_history.json"), &history)
+	if len(history.Changes) != 3 {
+		t.Errorf("after resuming, got %d changes, want 3", len(history.Changes))
+	}
+}
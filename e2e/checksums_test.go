@@ -0,0 +1,18 @@
+package e2e
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestChecksumsCoverPublishedFiles fails when the committed SHA256SUMS lists
+// other files than generate_checksums.go publishes
+func TestChecksumsCoverPublishedFiles(t *testing.T) {
+	bin := buildScript(t, "generate_checksums.go")
+
+	cmd := exec.Command(bin, "--check")
+	cmd.Dir = ".."
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("SHA256SUMS doesn't match publishedFiles: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,95 @@
+// Package e2e runs the data scripts end to end against recorded GitHub
+// responses (see internal/vcr), so no network access is needed. Cassettes live
+// in testdata/; re-record one by running a script with VCR_MODE=record.
+package e2e
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildScript compiles one of the root scripts (e.g. "main.go") into a temp dir
+func buildScript(t *testing.T, script string) string {
+	t.Helper()
+
+	bin := filepath.Join(t.TempDir(), strings.TrimSuffix(script, ".go"))
+	cmd := exec.Command("go", "build", "-o", bin, script)
+	cmd.Dir = ".."
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build %s: %v\n%s", script, err, output)
+	}
+	return bin
+}
+
+// runScript runs bin in workDir, replaying the given cassette from testdata/
+func runScript(t *testing.T, bin, workDir, cassette string, args ...string) string {
+	t.Helper()
+
+	cassettePath, err := filepath.Abs(filepath.Join("testdata", cassette))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(),
+		"VCR_MODE=replay",
+		"VCR_CASSETTE="+cassettePath,
+		"GITHUB_TOKEN=",
+		"GH_TOKEN=",
+		"GITHUB_API_URL=",
+		"GITHUB_RAW_URL=",
+		"GITHUB_STEP_SUMMARY=",
+		"METRICS_TEXTFILE=",
+		"PUSHGATEWAY_URL=",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s failed: %v\n%s", filepath.Base(bin), err, output)
+	}
+	return string(output)
+}
+
+// newWorkDir returns an empty directory with a data/ subdirectory
+func newWorkDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func readJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+	return rows
+}
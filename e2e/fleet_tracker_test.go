@@ -0,0 +1,40 @@
+package e2e
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestFleetTrackerRunsInProcess runs a generator through fleet-tracker from
+// outside the checkout with no Go toolchain on PATH, which only works if the
+// subcommand runs inside the binary. -buildvcs=false keeps the generator
+// version at "dev", like the scripts the golden files were rendered with
+func TestFleetTrackerRunsInProcess(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "fleet-tracker")
+	build := exec.Command("go", "build", "-buildvcs=false", "-o", bin, "./cmd/fleet-tracker")
+	build.Dir = ".."
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build fleet-tracker: %v\n%s", err, output)
+	}
+
+	dir := newWorkDir(t)
+	copyDir(t, filepath.Join("testdata", "golden", "data"), filepath.Join(dir, "data"))
+	t.Setenv("SOURCE_DATE_EPOCH", goldenEpoch)
+	t.Setenv("PATH", "")
+	runScript(t, bin, t.TempDir(), filepath.Join("golden", "cassette.json"), "--dir", dir, "rss")
+
+	got, err := os.ReadFile(filepath.Join(dir, "feed.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "golden", "want", "feed.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("feed.xml differs from the golden file:\n%s", firstDifference(string(want), string(got)))
+	}
+}
@@ -0,0 +1,110 @@
+package e2e
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden/want with the current output")
+
+// goldenEpoch pins "now" (SOURCE_DATE_EPOCH) to 2025-01-07 12:00 UTC, the end of the fixture data
+const goldenEpoch = "1736251200"
+
+// TestGenerators renders every generator against the fixture data in
+// testdata/golden/data and compares the result with testdata/golden/want.
+// After an intended change to an output, run
+//
+//	go test ./e2e/ -run TestGenerators -update
+//
+// and review the diff of the golden files.
+func TestGenerators(t *testing.T) {
+	generators := []struct {
+		script string
+		output string
+	}{
+		{"generate_html.go", "index.html"},
+		{"generate_readme.go", "README.md"},
+		{"generate_rss.go", "feed.xml"},
+		{"generate_advisory.go", "advisory.xml"},
+		{"generate_ics.go", "releases.ics"},
+		{"generate_changelog.go", "changelog.html"},
+		{"generate_changelog.go", "CHANGELOG.md"},
+	}
+
+	for _, g := range generators {
+		t.Run(g.output, func(t *testing.T) {
+			bin := buildScript(t, g.script)
+			dir := newWorkDir(t)
+			copyDir(t, filepath.Join("testdata", "golden", "data"), filepath.Join(dir, "data"))
+
+			t.Setenv("SOURCE_DATE_EPOCH", goldenEpoch)
+			runScript(t, bin, dir, filepath.Join("golden", "cassette.json"))
+
+			got, err := os.ReadFile(filepath.Join(dir, g.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantPath := filepath.Join("testdata", "golden", "want", g.output)
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(wantPath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(wantPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(wantPath)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s differs from %s:\n%s", g.output, wantPath, firstDifference(string(want), string(got)))
+			}
+		})
+	}
+}
+
+func copyDir(t *testing.T, src, dst string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// firstDifference describes the first line where want and got disagree
+func firstDifference(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return "outputs differ only in line endings"
+}
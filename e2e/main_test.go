@@ -0,0 +1,215 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type versionChange struct {
+	Slug       string `json:"slug"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+}
+
+type versionHistory struct {
+	Changes []versionChange `json:"changes"`
+}
+
+func TestMainGeneratesGrowthAndVersions(t *testing.T) {
+	bin := buildScript(t, "main.go")
+	dir := newWorkDir(t)
+
+	runScript(t, bin, dir, "main_initial.json")
+
+	// The CSV runs up to today, so only check the days covered by the cassette
+	rows := readCSV(t, filepath.Join(dir, "data", "apps_growth.csv"))
+	if len(rows) < 3 {
+		t.Fatalf("apps_growth.csv has %d rows, want at least 3", len(rows))
+	}
+	want := [][]string{
+		{"date", "app_count", "apps_added_since_previous", "mac_count", "windows_count", "apps_removed_since_previous", "net_change",
+			"category_communication", "category_productivity", "category_utilities"},
+		// Categories were first listed in the second commit
+		{"2025-01-01", "1", "1", "1", "0", "0", "1", "0", "0", "0"},
+		{"2025-01-02", "3", "2", "2", "1", "0", "2", "2", "1", "1"},
+	}
+	if !reflect.DeepEqual(rows[:3], want) {
+		t.Errorf("apps_growth.csv starts with\n%v\nwant\n%v", rows[:3], want)
+	}
+
+	var versions struct {
+		Apps []struct {
+			Slug    string `json:"slug"`
+			Version string `json:"version"`
+		} `json:"apps"`
+	}
+	readJSON(t, filepath.Join(dir, "data", "app_versions.json"), &versions)
+	got := make(map[string]string)
+	for _, app := range versions.Apps {
+		got[app.Slug] = app.Version
+	}
+	wantVersions := map[string]string{"zoom/darwin": "6.3.0", "slack/darwin": "4.41.105", "7-zip/windows": "24.09"}
+	if !reflect.DeepEqual(got, wantVersions) {
+		t.Errorf("app_versions.json = %v, want %v", got, wantVersions)
+	}
+
+	var stats struct {
+		Runs []struct {
+			Success        bool  `json:"success"`
+			GitHubRequests int64 `json:"githubRequests"`
+		} `json:"runs"`
+	}
+	readJSON(t, filepath.Join(dir, "data", "run_stats.json"), &stats)
+	if len(stats.Runs) != 1 || !stats.Runs[0].Success || stats.Runs[0].GitHubRequests != 7 {
+		t.Errorf("run_stats.json runs = %+v, want one successful run with 7 requests", stats.Runs)
+	}
+}
+
+func TestMainRecordsVersionChanges(t *testing.T) {
+	bin := buildScript(t, "main.go")
+	dir := newWorkDir(t)
+
+	runScript(t, bin, dir, "main_initial.json")
+	// Zoom is updated and 7-Zip's version file can't be fetched
+	runScript(t, bin, dir, "main_update.json")
+
+	var history versionHistory
+	readJSON(t, filepath.Join(dir, "data", "version_history.json"), &history)
+
+	want := []versionChange{{Slug: "zoom/darwin", OldVersion: "6.3.0", NewVersion: "6.3.5"}}
+	if !reflect.DeepEqual(history.Changes, want) {
+		t.Errorf("version_history.json changes = %+v, want %+v", history.Changes, want)
+	}
+}
+
+func TestMainRecordsScriptChanges(t *testing.T) {
+	bin := buildScript(t, "main.go")
+	dir := newWorkDir(t)
+
+	runScript(t, bin, dir, "main_initial.json")
+	// Zoom's install script gains a `set -e` with the update
+	runScript(t, bin, dir, "main_update.json")
+
+	data, err := os.ReadFile(filepath.Join(dir, "data", "script_changes.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var change struct {
+		Slug       string `json:"slug"`
+		Script     string `json:"script"`
+		OldVersion string `json:"oldVersion"`
+		NewVersion string `json:"newVersion"`
+		Diff       string `json:"diff"`
+	}
+	if err := json.Unmarshal(data, &change); err != nil {
+		t.Fatal(err)
+	}
+	if change.Slug != "zoom/darwin" || change.Script != "install" || change.OldVersion != "6.3.0" || change.NewVersion != "6.3.5" {
+		t.Errorf("script change = %+v, want zoom/darwin install 6.3.0 -> 6.3.5", change)
+	}
+	if !strings.Contains(change.Diff, "\n+set -e\n") {
+		t.Errorf("diff doesn't add `set -e`:\n%s", change.Diff)
+	}
+
+	script, err := os.ReadFile(filepath.Join(dir, "data", "scripts", "zoom", "darwin", "install.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(script), "#!/bin/sh\nset -e\n") {
+		t.Errorf("data/scripts/zoom/darwin/install.sh = %q, want the updated script", script)
+	}
+
+	// The archive keeps what each version shipped with when first seen
+	archived, err := os.ReadFile(filepath.Join(dir, "archive", "zoom", "darwin", "6.3.0", "install.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(archived), "set -e") {
+		t.Errorf("archive/zoom/darwin/6.3.0/install.sh was rewritten with the 6.3.5 script:\n%s", archived)
+	}
+	for _, name := range []string{"install.sh", "queries.json"} {
+		if _, err := os.Stat(filepath.Join(dir, "archive", "zoom", "darwin", "6.3.5", name)); err != nil {
+			t.Errorf("archive/zoom/darwin/6.3.5/%s: %v", name, err)
+		}
+	}
+}
+
+func TestMainRotatesOldChangesIntoYearlyArchives(t *testing.T) {
+	bin := buildScript(t, "main.go")
+	dir := newWorkDir(t)
+
+	runScript(t, bin, dir, "main_initial.json")
+
+	// A change from two years before the next one is recorded
+	old := `{"schemaVersion": 1, "changes": [{"date": "2023-05-01T10:00:00Z", "appName": "Slack", "slug": "slack/darwin", "platform": "darwin", "oldVersion": "4.30.0", "newVersion": "4.31.0"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "data", "version_history.json"), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runScript(t, bin, dir, "main_update.json")
+
+	var history versionHistory
+	readJSON(t, filepath.Join(dir, "data", "version_history.json"), &history)
+	want := []versionChange{{Slug: "zoom/darwin", OldVersion: "6.3.0", NewVersion: "6.3.5"}}
+	if !reflect.DeepEqual(history.Changes, want) {
+		t.Errorf("version_history.json changes = %+v, want %+v", history.Changes, want)
+	}
+
+	var archive versionHistory
+	readJSON(t, filepath.Join(dir, "data", "version_history-2023.json"), &archive)
+	want = []versionChange{{Slug: "slack/darwin", OldVersion: "4.30.0", NewVersion: "4.31.0"}}
+	if !reflect.DeepEqual(archive.Changes, want) {
+		t.Errorf("version_history-2023.json changes = %+v, want %+v", archive.Changes, want)
+	}
+}
+
+func TestMainRecordsCollectionLatency(t *testing.T) {
+	bin := buildScript(t, "main.go")
+	dir := newWorkDir(t)
+
+	runScript(t, bin, dir, "main_initial.json")
+	runScript(t, bin, dir, "main_update.json")
+
+	var history struct {
+		Changes []struct {
+			Date string `json:"date"`
+		} `json:"changes"`
+	}
+	readJSON(t, filepath.Join(dir, "data", "version_history.json"), &history)
+	if len(history.Changes) != 1 {
+		t.Fatalf("version_history.json has %d changes, want 1", len(history.Changes))
+	}
+	detected, err := time.Parse(time.RFC3339, history.Changes[0].Date)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A collector picks up Zoom 6.3.5 two hours after the bump
+	security := fmt.Sprintf(`{"schemaVersion": 1, "apps": [{"slug": "zoom/darwin", "version": "6.3.5", "lastUpdated": %q}]}`,
+		detected.Add(2*time.Hour).Format(time.RFC3339))
+	if err := os.WriteFile(filepath.Join(dir, "data", "app_security_info.json"), []byte(security), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runScript(t, bin, dir, "main_update.json")
+	runScript(t, bin, dir, "main_update.json")
+
+	var stats struct {
+		CollectionLatency []struct {
+			Slug    string  `json:"slug"`
+			Version string  `json:"version"`
+			Seconds float64 `json:"seconds"`
+		} `json:"collectionLatency"`
+	}
+	readJSON(t, filepath.Join(dir, "data", "run_stats.json"), &stats)
+	if len(stats.CollectionLatency) != 1 {
+		t.Fatalf("run_stats.json collectionLatency = %+v, want one entry", stats.CollectionLatency)
+	}
+	if got := stats.CollectionLatency[0]; got.Slug != "zoom/darwin" || got.Version != "6.3.5" || got.Seconds != 7200 {
+		t.Errorf("collection latency = %+v, want zoom/darwin 6.3.5 after 7200s", got)
+	}
+}
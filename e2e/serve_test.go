@@ -0,0 +1,305 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startServe runs serve.go over dir and returns its base URL
+func startServe(t *testing.T, dir string) string {
+	t.Helper()
+
+	bin := buildScript(t, "serve.go")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	cmd := exec.Command(bin, "--addr", addr, "--dir", dir, "--watch-interval", "0")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	base := "http://" + addr
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, err := http.Get(base + "/graphql/schema")
+		if err == nil {
+			resp.Body.Close()
+			return base
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("serve.go didn't start: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// getJSON decodes the JSON response to a GET of base+path
+func getJSON(t *testing.T, base, path string) interface{} {
+	t.Helper()
+
+	resp, err := http.Get(base + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("GET %s = %d: %s", path, resp.StatusCode, body)
+	}
+
+	var v interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	return v
+}
+
+// postGraphQL sends a query to /graphql and returns the raw response
+func postGraphQL(t *testing.T, base, query string, variables map[string]interface{}) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(base+"/graphql", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.TrimSpace(out)
+}
+
+// TestServeOnlyServesTheSite checks serve.go answers for the generated site
+// and hides the rest of the checkout
+func TestServeOnlyServesTheSite(t *testing.T) {
+	base := startServe(t, "..")
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/", http.StatusOK},
+		{"/index.html", http.StatusMovedPermanently},
+		{"/feed.xml", http.StatusOK},
+		{"/data/app_versions.json", http.StatusOK},
+		{"/go.mod", http.StatusNotFound},
+		{"/serve.go", http.StatusNotFound},
+		{"/SETUP.md", http.StatusNotFound},
+		{"/internal/schema/schema.go", http.StatusNotFound},
+		{"/.git/config", http.StatusNotFound},
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	for _, tt := range tests {
+		resp, err := client.Get(base + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.path, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+// TestServeGraphQL runs queries against serve.go's schema over the fixture data
+func TestServeGraphQL(t *testing.T) {
+	base := startServe(t, "testdata/serve")
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{
+			name:  "apps filtered by platform",
+			query: `{ lastUpdated apps(platform: "windows") { slug version } }`,
+			want:  `{"data":{"lastUpdated":"2025-01-07T06:00:00Z","apps":[{"slug":"7-zip/windows","version":"24.09"}]}}`,
+		},
+		{
+			name:  "apps by slug",
+			query: `{ apps(slugs: ["zoom/darwin"]) { slug } }`,
+			want:  `{"data":{"apps":[{"slug":"zoom/darwin"}]}}`,
+		},
+		{
+			name:  "null slugs are rejected",
+			query: `{ apps(slugs: [null]) { slug } }`,
+			want:  `{"errors":[{"message":"argument \"slugs\": expected String!, got null","path":["apps"]}]}`,
+		},
+		{
+			name:  "app with nested history, security info and manifest fields",
+			query: `{ app(slug: "zoom/darwin") { name arch flags { selfService defaultCategories } history(limit: 1) { oldVersion newVersion } security { teamId persistence { kind label } downloadTls { host } } } }`,
+			want: `{"data":{"app":{"name":"Zoom","arch":"arm64","flags":{"selfService":true,"defaultCategories":["Communication","Productivity"]},
+				"history":[{"oldVersion":"6.3.0","newVersion":"6.3.5"}],
+				"security":{"teamId":"BJ4HAAB9B3","persistence":[{"kind":"launch-daemon","label":"us.zoom.ZoomDaemon"}],"downloadTls":{"host":"cdn.zoom.us"}}}}}`,
+		},
+		{
+			name:      "variables, aliases and fragments",
+			query:     `query ($since: String!, $slug: String!) { recent: apps(changedSince: $since) { ...Names } one: app(slug: $slug) { ...Names } } fragment Names on App { slug name }`,
+			variables: map[string]interface{}{"since": "2025-01-04", "slug": "7-zip/windows"},
+			want:      `{"data":{"recent":[{"slug":"zoom/darwin","name":"Zoom"}],"one":{"slug":"7-zip/windows","name":"7-Zip"}}}`,
+		},
+		{
+			name:  "version changes in a date range, newest first",
+			query: `{ versionChanges(from: "2025-01-02", to: "2025-01-05") { date slug } }`,
+			want:  `{"data":{"versionChanges":[{"date":"2025-01-05T10:00:00Z","slug":"zoom/darwin"},{"date":"2025-01-02T09:00:00Z","slug":"7-zip/windows"}]}}`,
+		},
+		{
+			name:  "growth points",
+			query: `{ growth(from: "2025-01-02") { date appCount windowsCount } }`,
+			want:  `{"data":{"growth":[{"date":"2025-01-02","appCount":2,"windowsCount":1}]}}`,
+		},
+		{
+			name:  "security info of suites and Windows apps",
+			query: `{ suite: securityInfo(slug: "teleport-suite/darwin") { apps { name teamId } } win: securityInfo(platform: "windows") { installerSize certChain { subject } payload { path size } } }`,
+			want:  `{"data":{"suite":[{"apps":[{"name":"tsh","teamId":"QH8AA5B8UP"}]}],"win":[{"installerSize":null,"certChain":[{"subject":"CN=Igor Pavlov"}],"payload":[{"path":"Files/7-Zip/7z.exe","size":562688}]}]}}`,
+		},
+		{
+			name:  "field errors keep a path",
+			query: `{ versionChanges(from: "yesterday") { slug } }`,
+			want:  `{"errors":[{"message":"invalid from: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\"","path":["versionChanges"]}]}`,
+		},
+		{
+			name:  "unknown fields",
+			query: `{ app(slug: "zoom/darwin") { name downloads } }`,
+			want:  `{"data":{"app":{"name":"Zoom","downloads":null}},"errors":[{"message":"cannot query field \"downloads\" on type \"App\"","path":["app","downloads"]}]}`,
+		},
+		{
+			name:  "syntax errors",
+			query: "{ apps { slug }",
+			want:  `{"errors":[{"message":"syntax error at offset 15: unexpected end of document"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want bytes.Buffer
+			if err := json.Compact(&want, []byte(tt.want)); err != nil {
+				t.Fatal(err)
+			}
+			if got := postGraphQL(t, base, tt.query, tt.variables); string(got) != want.String() {
+				t.Errorf("got  %s\nwant %s", got, want.String())
+			}
+		})
+	}
+}
+
+// TestServeGraphQLCoversAPI queries every field the REST API returns through
+// GraphQL, so a field added to the data files but not to the schema fails
+func TestServeGraphQLCoversAPI(t *testing.T) {
+	base := startServe(t, "testdata/serve")
+
+	apps := getJSON(t, base, "/api/apps").(map[string]interface{})["apps"].([]interface{})
+	query := "{ apps { " + selectionFor(apps) + " } }"
+	got := graphQLData(t, base, query, nil)["apps"]
+	if !reflect.DeepEqual(got, apps) {
+		t.Errorf("GraphQL apps differ from /api/apps\nquery %s\n got %v\nwant %v", query, got, apps)
+	}
+
+	for _, slug := range []string{"zoom/darwin", "7-zip/windows", "teleport-suite/darwin"} {
+		info := getJSON(t, base, "/api/security/"+url.PathEscape(slug))
+		query := "query ($slug: String!) { securityInfo(slug: $slug) { " + selectionFor([]interface{}{info}) + " } }"
+		got := graphQLData(t, base, query, map[string]interface{}{"slug": slug})["securityInfo"]
+		if !reflect.DeepEqual(got, []interface{}{info}) {
+			t.Errorf("GraphQL security info of %s differs from /api/security\nquery %s\n got %v\nwant %v", slug, query, got, info)
+		}
+	}
+}
+
+// graphQLData runs a query that must succeed and returns its data with null
+// fields dropped, as the REST API omits them
+func graphQLData(t *testing.T, base, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	var resp struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []interface{}          `json:"errors"`
+	}
+	if err := json.Unmarshal(postGraphQL(t, base, query, variables), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("query %s failed: %v", query, resp.Errors)
+	}
+	return dropNulls(resp.Data).(map[string]interface{})
+}
+
+// selectionFor builds a selection set naming every key of the objects in
+// values, recursing into nested objects and lists of objects
+func selectionFor(values []interface{}) string {
+	children := make(map[string][]interface{})
+	for _, value := range values {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, child := range obj {
+			if list, ok := child.([]interface{}); ok {
+				children[key] = append(children[key], list...)
+			} else {
+				children[key] = append(children[key], child)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(children))
+	for key := range children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields []string
+	for _, key := range keys {
+		if sub := selectionFor(children[key]); sub != "" {
+			fields = append(fields, key+" { "+sub+" }")
+		} else {
+			fields = append(fields, key)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+func dropNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if child == nil {
+				delete(v, key)
+			} else {
+				v[key] = dropNulls(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = dropNulls(child)
+		}
+	}
+	return v
+}
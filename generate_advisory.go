@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/advisory"
+)
+
+// generate_advisory.go - Writes advisory.xml, an RSS feed of only the
+// security-relevant events for subscribers who don't want every version bump:
+//
+//	go run generate_advisory.go
+func main() {
+	advisory.Main(cli.Defaults(), os.Args[1:])
+}
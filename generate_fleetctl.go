@@ -0,0 +1,12 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/fleetctl"
+)
+
+func main() {
+	fleetctl.Main(cli.Defaults(), os.Args[1:])
+}
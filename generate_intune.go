@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/intune"
+)
+
+// generate_intune.go - Writes Intune Win32 app detection rules for every
+// Windows app whose MSI product code or install path the Windows collector
+// has recorded: intune/<app>-windows.json, a Microsoft Graph win32LobApp
+// PATCH body with the rules, and intune/<app>-windows.ps1, an equivalent
+// custom detection script that also checks the publisher's signature.
+func main() {
+	intune.Main(cli.Defaults(), os.Args[1:])
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/jamf"
+)
+
+// generate_jamf.go - Writes jamf/<app>-darwin.sh, a Jamf Pro extension
+// attribute for every Mac app with a recorded Team ID, reporting whether the
+// installed app is signed by that team and is the version tracked in
+// data/app_security_info.json. Paste a script into a new extension attribute
+// (Input Type: Script) and build smart groups on its result.
+func main() {
+	jamf.Main(cli.Defaults(), os.Args[1:])
+}
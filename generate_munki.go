@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/munki"
+)
+
+// generate_munki.go - Writes munki/<app>-darwin.plist, a Munki pkginfo for
+// the current version of every Mac app whose bundle the macOS collector has
+// recorded: the vendor's installer URL, its verified SHA-256 and an installs
+// array with the app's path, CFBundleIdentifier and version. Import them into
+// a Munki repo's pkgsinfo/ and run makecatalogs.
+func main() {
+	munki.Main(cli.Defaults(), os.Args[1:])
+}
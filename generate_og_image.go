@@ -0,0 +1,15 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/ogimage"
+)
+
+// generate_og_image.go - Renders og-image.png, the social preview linked
+// from index.html: cloud-city.png with the current app count, a sparkline of
+// the last 90 days and the date of the latest data drawn over it.
+func main() {
+	ogimage.Main(cli.Defaults(), os.Args[1:])
+}
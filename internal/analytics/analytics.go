@@ -0,0 +1,88 @@
+// Package analytics builds the optional, cookieless analytics snippet the
+// HTML generators add to each page's <head>. It is configured from the
+// environment, so hosted copies can measure traffic without editing the
+// generated files after every run:
+//
+//	ANALYTICS_PROVIDER=plausible ANALYTICS_SITE_ID=fmalibrary.com go run generate_html.go
+//	ANALYTICS_PROVIDER=umami ANALYTICS_SITE_ID=<website id> ANALYTICS_SCRIPT_URL=https://umami.example.com/script.js go run generate_html.go
+//
+// Nothing is added unless ANALYTICS_PROVIDER is set.
+package analytics
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Environment variables read by FromEnv
+const (
+	ProviderEnv  = "ANALYTICS_PROVIDER"
+	SiteIDEnv    = "ANALYTICS_SITE_ID"
+	ScriptURLEnv = "ANALYTICS_SCRIPT_URL"
+)
+
+// Supported providers
+const (
+	Plausible = "plausible"
+	Umami     = "umami"
+)
+
+// defaultPlausibleScript is Plausible's hosted script; self-hosted
+// instances set ANALYTICS_SCRIPT_URL. Umami has no hosted default.
+const defaultPlausibleScript = "https://plausible.io/js/script.js"
+
+// Snippet returns the <script> tag for provider, indented for the generated
+// <head>. siteID is the domain registered with Plausible or the Umami website
+// ID; scriptURL defaults to Plausible's hosted script. An empty provider
+// returns an empty snippet.
+func Snippet(provider, siteID, scriptURL string) (string, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		return "", nil
+	}
+	if siteID == "" {
+		return "", fmt.Errorf("%s is required for %s analytics", SiteIDEnv, provider)
+	}
+
+	switch provider {
+	case Plausible:
+		if scriptURL == "" {
+			scriptURL = defaultPlausibleScript
+		}
+		if err := checkScriptURL(scriptURL); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("    <script defer data-domain=\"%s\" src=\"%s\"></script>\n",
+			html.EscapeString(siteID), html.EscapeString(scriptURL)), nil
+	case Umami:
+		if scriptURL == "" {
+			return "", fmt.Errorf("%s is required for umami analytics", ScriptURLEnv)
+		}
+		if err := checkScriptURL(scriptURL); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("    <script defer src=\"%s\" data-website-id=\"%s\"></script>\n",
+			html.EscapeString(scriptURL), html.EscapeString(siteID)), nil
+	default:
+		return "", fmt.Errorf("unknown %s %q (expected %q or %q)", ProviderEnv, provider, Plausible, Umami)
+	}
+}
+
+// FromEnv returns the snippet configured by ANALYTICS_PROVIDER,
+// ANALYTICS_SITE_ID and ANALYTICS_SCRIPT_URL
+func FromEnv() (string, error) {
+	return Snippet(os.Getenv(ProviderEnv), os.Getenv(SiteIDEnv), os.Getenv(ScriptURLEnv))
+}
+
+// checkScriptURL rejects anything but an absolute https URL, so a typo can't
+// load the script over plain HTTP or from a relative path on the site
+func checkScriptURL(scriptURL string) error {
+	u, err := url.Parse(scriptURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid %s %q (expected an https URL)", ScriptURLEnv, scriptURL)
+	}
+	return nil
+}
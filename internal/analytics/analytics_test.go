@@ -0,0 +1,25 @@
+package analytics
+
+import "testing"
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		provider, siteID, scriptURL string
+		want                        string
+		wantErr                     bool
+	}{
+		{"", "", "", "", false},
+		{"plausible", "fmalibrary.com", "", "    <script defer data-domain=\"fmalibrary.com\" src=\"https://plausible.io/js/script.js\"></script>\n", false},
+		{"Umami", "1234-abcd", "https://umami.example.com/script.js", "    <script defer src=\"https://umami.example.com/script.js\" data-website-id=\"1234-abcd\"></script>\n", false},
+		{"plausible", "", "", "", true},
+		{"umami", "1234-abcd", "", "", true},
+		{"plausible", "fmalibrary.com", "http://plausible.example.com/js/script.js", "", true},
+		{"matomo", "1", "https://matomo.example.com/matomo.js", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Snippet(tt.provider, tt.siteID, tt.scriptURL)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Snippet(%q, %q, %q) = %q, %v; want %q, error %v", tt.provider, tt.siteID, tt.scriptURL, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
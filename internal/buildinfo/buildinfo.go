@@ -0,0 +1,90 @@
+// Package buildinfo identifies the revision of the tracker that produced an
+// output, so consumers can tell which code generated a given dataset. The
+// values are stamped at build time:
+//
+//	go build -ldflags "-X github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo.Commit=$(git rev-parse HEAD)" ./cmd/collect-security-info
+//
+// Unstamped binaries fall back to the VCS information `go build` records for
+// packages, and `go run` of a single file reports plain "dev".
+package buildinfo
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strconv"
+)
+
+// Set with -ldflags "-X github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo.<Name>=<value>"
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = "" // Build or commit date, RFC 3339
+)
+
+func init() {
+	if Commit != "" {
+		return
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			Commit = setting.Value
+		case "vcs.time":
+			if Date == "" {
+				Date = setting.Value
+			}
+		}
+	}
+}
+
+// GeneratorVersion is the value embedded in outputs: the version, plus the
+// short commit when it's known (e.g. "dev+1a2b3c4d5e6f")
+func GeneratorVersion() string {
+	if Commit == "" {
+		return Version
+	}
+	short := Commit
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	return Version + "+" + short
+}
+
+// String describes the build for --version
+func String() string {
+	s := "fleet-apps-growth-tracker " + Version
+	if Commit != "" {
+		s += " (commit " + Commit
+		if Date != "" {
+			s += ", " + Date
+		}
+		s += ")"
+	}
+	return s
+}
+
+// RegisterFlag adds --version to fs; it prints the build and exits as soon as
+// it's parsed
+func RegisterFlag(fs *flag.FlagSet) {
+	fs.Var(versionFlag{}, "version", "print the version and exit")
+}
+
+type versionFlag struct{}
+
+func (versionFlag) IsBoolFlag() bool { return true }
+func (versionFlag) String() string   { return "" }
+
+func (versionFlag) Set(value string) error {
+	if show, err := strconv.ParseBool(value); err != nil || !show {
+		return err
+	}
+	fmt.Println(String())
+	os.Exit(0)
+	return nil
+}
@@ -0,0 +1,72 @@
+// Package cli holds the flags every command of the tracker shares. fleet-tracker
+// takes them before the subcommand name and passes them on, and each command
+// accepts them again after it, so these are equivalent:
+//
+//	fleet-tracker --dir ~/tracker --proxy http://proxy:3128 collect
+//	fleet-tracker collect --dir ~/tracker --proxy http://proxy:3128
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/proxy"
+)
+
+// Common are the flags shared by every command
+type Common struct {
+	Dir         string // Tracker checkout the command reads and writes data/ in
+	Proxy       string // Proxy URL for HTTP(S) requests, empty to use the environment
+	GitHubToken string // Token for GitHub API requests, empty to use the environment
+
+	flags chan<- *flag.FlagSet // Set by Flags to stop the command once its flags are defined
+}
+
+// Defaults are the common flags' values when none is given
+func Defaults() Common {
+	return Common{Dir: "."}
+}
+
+// Command runs a command with the common flags and the arguments following its
+// name. Like a main function, it exits the process on failure.
+type Command func(common Common, args []string)
+
+// Register adds the common flags and --version to fs. Their defaults are the
+// values already in c, so flags given before a subcommand carry over to it.
+func (c *Common) Register(fs *flag.FlagSet) {
+	fs.StringVar(&c.Dir, "dir", c.Dir, "tracker checkout to run in, containing data/")
+	fs.StringVar(&c.Proxy, "proxy", c.Proxy, proxy.FlagUsage)
+	fs.StringVar(&c.GitHubToken, "github-token", c.GitHubToken, github.TokenFlagUsage)
+	buildinfo.RegisterFlag(fs)
+	if c.flags != nil {
+		c.flags <- fs
+		runtime.Goexit()
+	}
+}
+
+// Flags returns the flags cmd accepts without running it. Every command
+// defines its own flags, then calls Register before parsing any, which hands
+// the set back here and ends the command's goroutine.
+func Flags(cmd Command) *flag.FlagSet {
+	flags := make(chan *flag.FlagSet)
+	go func() {
+		defer close(flags)
+		common := Defaults()
+		common.flags = flags
+		cmd(common, nil)
+	}()
+	return <-flags
+}
+
+// Apply changes to the checkout and routes HTTP traffic through the proxy. It
+// must be called once the flags are parsed and before the first request.
+func (c Common) Apply() error {
+	if err := os.Chdir(c.Dir); err != nil {
+		return fmt.Errorf("failed to change to --dir: %w", err)
+	}
+	return proxy.Configure(c.Proxy)
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+// TestRegisterCarriesOver checks flags given before a subcommand become the
+// defaults of its own flag set, where they can still be overridden
+func TestRegisterCarriesOver(t *testing.T) {
+	common := Defaults()
+	global := flag.NewFlagSet("fleet-tracker", flag.ContinueOnError)
+	common.Register(global)
+	if err := global.Parse([]string{"--dir", "/tracker", "--github-token", "global", "html", "--refresh"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := flag.NewFlagSet("html", flag.ContinueOnError)
+	sub.SetOutput(io.Discard)
+	common.Register(sub)
+	refresh := sub.Bool("refresh", false, "")
+	if err := sub.Parse(append(global.Args()[1:], "--proxy", "http://proxy:3128", "--github-token", "sub")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Common{Dir: "/tracker", Proxy: "http://proxy:3128", GitHubToken: "sub"}
+	if common != want || !*refresh {
+		t.Errorf("common = %+v, refresh = %v; want %+v, true", common, *refresh, want)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	ran := false
+	fs := Flags(func(common Common, args []string) {
+		fs := flag.NewFlagSet("html", flag.ExitOnError)
+		fs.Bool("refresh", false, "")
+		common.Register(fs)
+		ran = true
+	})
+	if ran {
+		t.Error("Flags() ran the command past Register")
+	}
+	for _, name := range []string{"refresh", "dir", "proxy", "github-token", "version"} {
+		if fs.Lookup(name) == nil {
+			t.Errorf("Flags() is missing --%s", name)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+// Package advisory implements "fleet-tracker advisory", which generates
+// advisory.xml; generate_advisory.go runs it on its own.
+package advisory
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	securityInfoJSON = "data/app_security_info.json"
+	hashDriftJSONL   = "data/hash_drift.jsonl"
+	scriptChanges    = "data/script_changes.jsonl"
+	outputAdvisory   = "advisory.xml"
+	siteURL          = "https://fmalibrary.com"
+	maxAdvisories    = 200
+)
+
+var scriptLabels = map[string]string{"install": "Install", "uninstall": "Uninstall"}
+
+// Anomaly types reported by the collectors that belong in the advisory feed;
+// the rest (e.g. unchanged-binary) point at metadata mistakes, not risk
+var advisoryAnomalies = map[string]string{
+	"component-signer-changed": "Bundled library signer changed",
+}
+
+// advisory is one item of the advisory feed
+type advisory struct {
+	Date        string
+	Title       string
+	Description string
+	GUID        string
+}
+
+func generateAdvisoryFeed() error {
+	fmt.Println("🛡️  Generating security advisory feed...")
+
+	security, err := loadSecurityInfo()
+	if err != nil {
+		return fmt.Errorf("failed to load security info: %w", err)
+	}
+	drifts, err := loadHashDrift()
+	if err != nil {
+		return fmt.Errorf("failed to load hash drift: %w", err)
+	}
+	scripts, err := loadScriptChanges()
+	if err != nil {
+		return fmt.Errorf("failed to load script changes: %w", err)
+	}
+
+	advisories := signerChangeAdvisories(security)
+	advisories = append(advisories, signatureAdvisories(security)...)
+	advisories = append(advisories, anomalyAdvisories(security)...)
+	advisories = append(advisories, hashDriftAdvisories(drifts)...)
+	advisories = append(advisories, scriptChangeAdvisories(scripts)...)
+
+	// Newest first; the GUID breaks ties so the output is stable
+	sort.Slice(advisories, func(i, j int) bool {
+		if advisories[i].Date != advisories[j].Date {
+			return advisories[i].Date > advisories[j].Date
+		}
+		return advisories[i].GUID < advisories[j].GUID
+	})
+	if len(advisories) > maxAdvisories {
+		advisories = advisories[:maxAdvisories]
+	}
+
+	if err := os.WriteFile(outputAdvisory, []byte(generateAdvisoryContent(advisories)), 0644); err != nil {
+		return fmt.Errorf("failed to write advisory feed: %w", err)
+	}
+
+	fmt.Printf("✅ Generated: %s\n", outputAdvisory)
+	fmt.Printf("   📝 %d advisories in feed\n", len(advisories))
+
+	return nil
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+func loadHashDrift() ([]datafile.HashDrift, error) {
+	file, err := os.Open(hashDriftJSONL)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var drifts []datafile.HashDrift
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var drift datafile.HashDrift
+		if err := json.Unmarshal(scanner.Bytes(), &drift); err != nil {
+			continue
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, scanner.Err()
+}
+
+func loadScriptChanges() ([]datafile.ScriptChange, error) {
+	file, err := os.Open(scriptChanges)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var changes []datafile.ScriptChange
+	scanner := bufio.NewScanner(file)
+	// A line carries a whole diff, which can outgrow the default 64 KB
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var change datafile.ScriptChange
+		if err := json.Unmarshal(scanner.Bytes(), &change); err != nil {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, scanner.Err()
+}
+
+// signerChangeAdvisories compares each collected version of an app with the
+// one collected before it and reports a changed Team ID, Signing ID,
+// publisher or issuer
+func signerChangeAdvisories(security *datafile.SecurityInfo) []advisory {
+	bySlug := make(map[string][]datafile.AppSecurityInfo)
+	for _, entry := range append(append([]datafile.AppSecurityInfo{}, security.Versions...), security.Apps...) {
+		bySlug[entry.Slug] = append(bySlug[entry.Slug], entry)
+	}
+
+	var advisories []advisory
+	for slug, entries := range bySlug {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].LastUpdated < entries[j].LastUpdated
+		})
+		for i := 1; i < len(entries); i++ {
+			previous, current := entries[i-1], entries[i]
+			for _, field := range []struct{ name, old, new string }{
+				{"Team ID", previous.TeamID, current.TeamID},
+				{"Signing ID", previous.SigningID, current.SigningID},
+				{"Publisher", previous.Publisher, current.Publisher},
+				{"Issuer", previous.Issuer, current.Issuer},
+			} {
+				if field.old == "" || field.new == "" || field.old == field.new {
+					continue
+				}
+				advisories = append(advisories, advisory{
+					Date:  current.LastUpdated,
+					Title: fmt.Sprintf("%s changed: %s %s (%s)", field.name, current.Name, current.Version, platformLabel(slug)),
+					Description: fmt.Sprintf("The %s of %s changed from %s (version %s) to %s (version %s). Confirm the new signer before allowing this version.",
+						field.name, current.Name, field.old, previous.Version, field.new, current.Version),
+					GUID: fmt.Sprintf("%s-%s-%s", slug, current.Version, strings.ToLower(strings.ReplaceAll(field.name, " ", "-"))),
+				})
+			}
+		}
+	}
+	return advisories
+}
+
+// signatureAdvisories reports every collected version whose Authenticode
+// signature WinVerifyTrust didn't accept
+func signatureAdvisories(security *datafile.SecurityInfo) []advisory {
+	var advisories []advisory
+	for _, entry := range append(append([]datafile.AppSecurityInfo{}, security.Apps...), security.Versions...) {
+		if entry.SignatureStatus == "" || entry.SignatureStatus == "valid" {
+			continue
+		}
+		description := fmt.Sprintf("The signature of %s %s is %s.", entry.Name, entry.Version, entry.SignatureStatus)
+		if entry.SignatureDetail != "" {
+			description += " " + entry.SignatureDetail
+		}
+		advisories = append(advisories, advisory{
+			Date:        entry.LastUpdated,
+			Title:       fmt.Sprintf("Signature %s: %s %s (%s)", entry.SignatureStatus, entry.Name, entry.Version, platformLabel(entry.Slug)),
+			Description: description,
+			GUID:        fmt.Sprintf("%s-%s-signature-%s", entry.Slug, entry.Version, entry.SignatureStatus),
+		})
+	}
+	return advisories
+}
+
+func anomalyAdvisories(security *datafile.SecurityInfo) []advisory {
+	var advisories []advisory
+	for _, entry := range append(append([]datafile.AppSecurityInfo{}, security.Apps...), security.Versions...) {
+		for i, anomaly := range entry.Anomalies {
+			label, ok := advisoryAnomalies[anomaly.Type]
+			if !ok {
+				continue
+			}
+			advisories = append(advisories, advisory{
+				Date:        anomaly.DetectedAt,
+				Title:       fmt.Sprintf("%s: %s %s (%s)", label, entry.Name, entry.Version, platformLabel(entry.Slug)),
+				Description: fmt.Sprintf("%s %s: %s.", entry.Name, entry.Version, anomaly.Detail),
+				GUID:        fmt.Sprintf("%s-%s-%s-%d", entry.Slug, entry.Version, anomaly.Type, i),
+			})
+		}
+	}
+	return advisories
+}
+
+func hashDriftAdvisories(drifts []datafile.HashDrift) []advisory {
+	var advisories []advisory
+	for _, drift := range drifts {
+		description := fmt.Sprintf("The installer of %s %s no longer matches the hash recorded when this version was collected: SHA-256 %s, now %s. The vendor republished different bytes under the same version.",
+			drift.Name, drift.Version, drift.RecordedSha256, drift.CurrentSha256)
+		if drift.InstallerURL != "" {
+			description += fmt.Sprintf(" <a href=\"%s\">Installer</a>", escapeXML(drift.InstallerURL))
+		}
+		advisories = append(advisories, advisory{
+			Date:        drift.DetectedAt,
+			Title:       fmt.Sprintf("Installer changed without a version bump: %s %s (%s)", drift.Name, drift.Version, platformLabel(drift.Slug)),
+			Description: description,
+			GUID:        fmt.Sprintf("%s-%s-hash-drift-%s", drift.Slug, drift.Version, drift.CurrentSha256),
+		})
+	}
+	return advisories
+}
+
+func scriptChangeAdvisories(changes []datafile.ScriptChange) []advisory {
+	var advisories []advisory
+	for _, change := range changes {
+		version := change.NewVersion
+		description := fmt.Sprintf("The %s script Fleet runs for %s changed", change.Script, change.Name)
+		if change.OldVersion != change.NewVersion {
+			description += fmt.Sprintf(" from version %s to %s", change.OldVersion, change.NewVersion)
+		} else {
+			description += fmt.Sprintf(" without a version bump (%s)", version)
+		}
+		description += fmt.Sprintf(": SHA-256 %s, now %s.", change.OldSha256, change.NewSha256)
+		if change.Diff != "" {
+			description += "\n\n" + change.Diff
+		}
+		advisories = append(advisories, advisory{
+			Date:        change.DetectedAt,
+			Title:       fmt.Sprintf("%s script changed: %s %s (%s)", scriptLabels[change.Script], change.Name, version, platformLabel(change.Slug)),
+			Description: description,
+			GUID:        fmt.Sprintf("%s-%s-%s-script-%s", change.Slug, version, change.Script, change.NewSha256),
+		})
+	}
+	return advisories
+}
+
+func generateAdvisoryContent(advisories []advisory) string {
+	lastBuildDate := time.Now().UTC().Format(time.RFC1123Z)
+	if len(advisories) > 0 {
+		if t, err := time.Parse(time.RFC3339, advisories[0].Date); err == nil {
+			lastBuildDate = t.UTC().Format(time.RFC1123Z)
+		}
+	}
+
+	rss := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Fleet-maintained apps security advisories</title>
+    <link>` + siteURL + `</link>
+    <description>High-signal security events for Fleet-maintained apps: signer changes, installers republished under the same version, changed install and uninstall scripts, invalid signatures and bundled libraries with a new signer. Routine version updates are in feed.xml.</description>
+    <language>en-us</language>
+    <generator>fleet-apps-growth-tracker ` + escapeXML(buildinfo.GeneratorVersion()) + `</generator>
+    <lastBuildDate>` + lastBuildDate + `</lastBuildDate>
+    <atom:link href="` + siteURL + `/advisory.xml" rel="self" type="application/rss+xml"/>
+`
+
+	for _, a := range advisories {
+		pubDate := lastBuildDate
+		if t, err := time.Parse(time.RFC3339, a.Date); err == nil {
+			pubDate = t.UTC().Format(time.RFC1123Z)
+		}
+
+		rss += `    <item>
+      <title>` + escapeXML(a.Title) + `</title>
+      <link>` + siteURL + `</link>
+      <description>` + escapeXML(a.Description) + `</description>
+      <pubDate>` + pubDate + `</pubDate>
+      <guid isPermaLink="false">` + escapeXML(a.GUID) + `</guid>
+    </item>
+`
+	}
+
+	rss += `  </channel>
+</rss>`
+
+	return rss
+}
+
+// platformLabel returns Mac or Windows from the platform suffix of a slug
+func platformLabel(slug string) string {
+	if strings.HasSuffix(slug, "/darwin") {
+		return "Mac"
+	}
+	return "Windows"
+}
+
+func escapeXML(s string) string {
+	result := ""
+	for _, r := range s {
+		switch r {
+		case '<':
+			result += "&lt;"
+		case '>':
+			result += "&gt;"
+		case '&':
+			result += "&amp;"
+		case '"':
+			result += "&quot;"
+		case '\'':
+			result += "&apos;"
+		default:
+			result += string(r)
+		}
+	}
+	return result
+}
+
+// Main runs fleet-tracker advisory with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("advisory", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateAdvisoryFeed(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
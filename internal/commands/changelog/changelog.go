@@ -0,0 +1,392 @@
+// Package changelog implements "fleet-tracker changelog", which generates
+// changelog.html and CHANGELOG.md; generate_changelog.go runs it on its own.
+package changelog
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/analytics"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionHistoryJSON = "data/version_history.json"
+	versionHistoryGlob = "data/version_history-*.json"
+	outputChangelog    = "changelog.html"
+	outputChangelogMD  = "CHANGELOG.md"
+	siteURL            = "https://fmalibrary.com"
+	manifestURLFormat  = "https://github.com/fleetdm/fleet/blob/main/ee/maintained-apps/outputs/%s.json"
+)
+
+// changelogPeriod holds the changes recorded in one Monday-to-Sunday week
+// (UTC) for changelog.html, or in one day for CHANGELOG.md
+type changelogPeriod struct {
+	Start   time.Time
+	NewApps []datafile.VersionChange
+	Updates []datafile.VersionChange
+	Removed []datafile.VersionChange
+}
+
+func generateChangelog() error {
+	fmt.Println("🗒️  Generating changelog...")
+
+	history, err := loadVersionHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load version history: %w", err)
+	}
+
+	analyticsSnippet, err := analytics.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	changes := dedupeChanges(history.Changes)
+	weeks := groupByWeek(changes)
+	days := groupByDay(changes)
+
+	if err := os.WriteFile(outputChangelog, []byte(generateChangelogHTML(weeks, analyticsSnippet)), 0644); err != nil {
+		return fmt.Errorf("failed to write changelog page: %w", err)
+	}
+	if err := os.WriteFile(outputChangelogMD, []byte(generateChangelogMarkdown(days)), 0644); err != nil {
+		return fmt.Errorf("failed to write changelog markdown: %w", err)
+	}
+
+	fmt.Printf("✅ Generated: %s and %s\n", outputChangelog, outputChangelogMD)
+	fmt.Printf("   📝 %d weeks (%d days) of changes\n", len(weeks), len(days))
+
+	return nil
+}
+
+// loadVersionHistory reads the current version history and the yearly
+// archives main.go rotates older changes into, so the changelog keeps them
+func loadVersionHistory() (*datafile.VersionHistory, error) {
+	archives, err := filepath.Glob(versionHistoryGlob)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &datafile.VersionHistory{Changes: []datafile.VersionChange{}}
+	for _, path := range append([]string{versionHistoryJSON}, archives...) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		data, err = schema.Upgrade(schema.VersionHistory, data)
+		if err != nil {
+			return nil, err
+		}
+
+		var file datafile.VersionHistory
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		history.Changes = append(history.Changes, file.Changes...)
+	}
+
+	return history, nil
+}
+
+// dedupeChanges returns the changes in chronological order with identical
+// consecutive changes for the same app removed (e.g. the same bump recorded
+// by both the daily tracker and a history rebuild)
+func dedupeChanges(changes []datafile.VersionChange) []datafile.VersionChange {
+	sorted := make([]datafile.VersionChange, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+
+	lastBySlug := make(map[string]datafile.VersionChange)
+	result := make([]datafile.VersionChange, 0, len(sorted))
+	for _, change := range sorted {
+		if last, exists := lastBySlug[change.Slug]; exists &&
+			last.OldVersion == change.OldVersion && last.NewVersion == change.NewVersion {
+			continue
+		}
+		lastBySlug[change.Slug] = change
+		result = append(result, change)
+	}
+
+	return result
+}
+
+// groupByWeek buckets changes by the week they were recorded in, newest week
+// first; within a week the newest changes come first
+func groupByWeek(changes []datafile.VersionChange) []changelogPeriod {
+	return groupChanges(changes, weekStart)
+}
+
+// groupByDay buckets changes by the UTC day they were recorded on, newest day
+// first
+func groupByDay(changes []datafile.VersionChange) []changelogPeriod {
+	return groupChanges(changes, func(t time.Time) time.Time {
+		t = t.UTC()
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	})
+}
+
+// groupChanges buckets changes by the period start returns for their date
+func groupChanges(changes []datafile.VersionChange, start func(time.Time) time.Time) []changelogPeriod {
+	byStart := make(map[time.Time]*changelogPeriod)
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		t, err := time.Parse(time.RFC3339, change.Date)
+		if err != nil {
+			continue
+		}
+		periodStart := start(t)
+		period, ok := byStart[periodStart]
+		if !ok {
+			period = &changelogPeriod{Start: periodStart}
+			byStart[periodStart] = period
+		}
+		if change.OldVersion == "" {
+			period.NewApps = append(period.NewApps, change)
+		} else if change.NewVersion == "" {
+			period.Removed = append(period.Removed, change)
+		} else {
+			period.Updates = append(period.Updates, change)
+		}
+	}
+
+	periods := make([]changelogPeriod, 0, len(byStart))
+	for _, period := range byStart {
+		periods = append(periods, *period)
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Start.After(periods[j].Start)
+	})
+	return periods
+}
+
+// weekStart returns midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func weekTitle(week changelogPeriod) string {
+	return "Week of " + week.Start.Format("January 2, 2006")
+}
+
+func periodSummary(period changelogPeriod) string {
+	summary := fmt.Sprintf("%s, %s", pluralize(len(period.NewApps), "new app", "new apps"), pluralize(len(period.Updates), "version update", "version updates"))
+	if len(period.Removed) > 0 {
+		summary += ", " + pluralize(len(period.Removed), "removed app", "removed apps")
+	}
+	return summary
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// generateChangelogMarkdown renders one section per day, headed by its
+// YYYY-MM-DD date, so `grep` and `git log -p` find a day or an app directly
+func generateChangelogMarkdown(days []changelogPeriod) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Changelog\n\n")
+	sb.WriteString("New, updated and removed Fleet-maintained apps, grouped by day (UTC). Generated from `data/version_history.json`; the weekly summary is at [" + siteURL + "/changelog.html](" + siteURL + "/changelog.html).\n")
+
+	for _, day := range days {
+		sb.WriteString("\n## " + day.Start.Format("2006-01-02") + "\n\n")
+		sb.WriteString("_" + periodSummary(day) + "_\n")
+
+		if len(day.NewApps) > 0 {
+			sb.WriteString("\n### New apps\n\n")
+			for _, change := range day.NewApps {
+				sb.WriteString(fmt.Sprintf("- **%s** %s (%s)%s\n", change.AppName, change.NewVersion, getPlatformLabel(change.Platform), markdownLinks(change)))
+			}
+		}
+		if len(day.Updates) > 0 {
+			sb.WriteString("\n### Version updates\n\n")
+			for _, change := range day.Updates {
+				sb.WriteString(fmt.Sprintf("- **%s** %s → %s (%s)%s\n", change.AppName, change.OldVersion, change.NewVersion, getPlatformLabel(change.Platform), markdownLinks(change)))
+			}
+		}
+		if len(day.Removed) > 0 {
+			sb.WriteString("\n### Removed apps\n\n")
+			for _, change := range day.Removed {
+				sb.WriteString(fmt.Sprintf("- **%s** %s (%s), last maintained version%s\n", change.AppName, change.OldVersion, getPlatformLabel(change.Platform), markdownLinks(change)))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func markdownLinks(change datafile.VersionChange) string {
+	links := " — " + formatDate(change.Date) + " · [manifest](" + fmt.Sprintf(manifestURLFormat, change.Slug) + ")"
+	if change.InstallerURL != "" {
+		links += " · [installer](" + change.InstallerURL + ")"
+	}
+	return links
+}
+
+func generateChangelogHTML(weeks []changelogPeriod, analyticsSnippet string) string {
+	var sb strings.Builder
+
+	sb.WriteString(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <meta name="generator" content="fleet-apps-growth-tracker ` + html.EscapeString(buildinfo.GeneratorVersion()) + `">
+    <meta name="description" content="Weekly changelog of new Fleet-maintained apps and version updates.">
+    <link rel="alternate" type="application/rss+xml" title="Fleet Maintained Apps - Version Updates" href="` + siteURL + `/feed.xml">
+    <link rel="icon" href="data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' viewBox='0 0 100 100'%3E%3Ctext y='0.9em' font-size='90'%3E🦢%3C/text%3E%3C/svg%3E">
+    <title>Changelog - Fleet Maintained Apps</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            margin: 0;
+            padding: 20px;
+            background: #f5f5f5;
+            color: #1e293b;
+        }
+        .container {
+            max-width: 900px;
+            margin: 0 auto;
+            background: white;
+            padding: 30px;
+            border-radius: 8px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        h1 {
+            margin-top: 0;
+            margin-bottom: 10px;
+        }
+        .subtitle, .week-summary, .date {
+            color: #64748b;
+        }
+        .week {
+            border-top: 2px solid #e2e8f0;
+            margin-top: 30px;
+            padding-top: 10px;
+        }
+        h3 {
+            font-size: 15px;
+            color: #475569;
+            margin-bottom: 8px;
+        }
+        ul {
+            padding-left: 20px;
+            margin-top: 0;
+        }
+        li {
+            margin-bottom: 6px;
+        }
+        a {
+            color: #2563eb;
+        }
+    </style>
+` + analyticsSnippet + `</head>
+<body>
+    <div class="container">
+        <h1>Changelog</h1>
+        <p class="subtitle">New Fleet-maintained apps and version updates, grouped by week (UTC). <a href="index.html">Back to the dashboard</a> · <a href="feed.xml">RSS</a></p>
+`)
+
+	if len(weeks) == 0 {
+		sb.WriteString("        <p>No changes recorded yet.</p>\n")
+	}
+
+	for _, week := range weeks {
+		sb.WriteString(fmt.Sprintf("        <section class=\"week\" id=\"week-%s\">\n", week.Start.Format("2006-01-02")))
+		sb.WriteString("            <h2>" + html.EscapeString(weekTitle(week)) + "</h2>\n")
+		sb.WriteString("            <p class=\"week-summary\">" + html.EscapeString(periodSummary(week)) + "</p>\n")
+
+		if len(week.NewApps) > 0 {
+			sb.WriteString("            <h3>New apps</h3>\n            <ul>\n")
+			for _, change := range week.NewApps {
+				title := fmt.Sprintf("<strong>%s</strong> %s (%s)", html.EscapeString(change.AppName), html.EscapeString(change.NewVersion), getPlatformLabel(change.Platform))
+				sb.WriteString("                <li>" + title + htmlLinks(change) + "</li>\n")
+			}
+			sb.WriteString("            </ul>\n")
+		}
+		if len(week.Updates) > 0 {
+			sb.WriteString("            <h3>Version updates</h3>\n            <ul>\n")
+			for _, change := range week.Updates {
+				title := fmt.Sprintf("<strong>%s</strong> %s → %s (%s)", html.EscapeString(change.AppName), html.EscapeString(change.OldVersion), html.EscapeString(change.NewVersion), getPlatformLabel(change.Platform))
+				sb.WriteString("                <li>" + title + htmlLinks(change) + "</li>\n")
+			}
+			sb.WriteString("            </ul>\n")
+		}
+		if len(week.Removed) > 0 {
+			sb.WriteString("            <h3>Removed apps</h3>\n            <ul>\n")
+			for _, change := range week.Removed {
+				title := fmt.Sprintf("<strong>%s</strong> %s (%s), last maintained version", html.EscapeString(change.AppName), html.EscapeString(change.OldVersion), getPlatformLabel(change.Platform))
+				sb.WriteString("                <li>" + title + htmlLinks(change) + "</li>\n")
+			}
+			sb.WriteString("            </ul>\n")
+		}
+
+		sb.WriteString("        </section>\n")
+	}
+
+	sb.WriteString(`    </div>
+</body>
+</html>
+`)
+
+	return sb.String()
+}
+
+func htmlLinks(change datafile.VersionChange) string {
+	links := ` <span class="date">` + html.EscapeString(formatDate(change.Date)) + `</span> · <a href="` + html.EscapeString(fmt.Sprintf(manifestURLFormat, change.Slug)) + `">manifest</a>`
+	if change.InstallerURL != "" {
+		links += ` · <a href="` + html.EscapeString(change.InstallerURL) + `">installer</a>`
+	}
+	return links
+}
+
+func getPlatformLabel(platform string) string {
+	if platform == "darwin" {
+		return "Mac"
+	}
+	return "Windows"
+}
+
+func formatDate(dateStr string) string {
+	if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
+		return t.UTC().Format("January 2, 2006")
+	}
+	return dateStr
+}
+
+// Main runs fleet-tracker changelog with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("changelog", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateChangelog(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,156 @@
+// Package checksums implements "fleet-tracker checksums", which generates
+// SHA256SUMS; generate_checksums.go runs it on its own.
+package checksums
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+)
+
+const outputSums = "SHA256SUMS"
+
+// publishedFiles lists the outputs covered by the manifest; globs are expanded
+var publishedFiles = []string{
+	"data/*.csv",
+	"data/*.json",
+	"data/*.jsonl",
+	"index.html",
+	"feed.xml",
+	"advisory.xml",
+	"releases.ics",
+	"changelog.html",
+}
+
+// publishedPaths expands publishedFiles into the sorted files it covers
+func publishedPaths() ([]string, error) {
+	var files []string
+	for _, pattern := range publishedFiles {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand %s: %w", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func generateChecksums() error {
+	fmt.Println("🔐 Generating SHA256SUMS manifest...")
+
+	files, err := publishedPaths()
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, path := range files {
+		sum, err := sha256File(path)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		// Same format as sha256sum, so `sha256sum -c SHA256SUMS` verifies it
+		sb.WriteString(fmt.Sprintf("%s  %s\n", sum, filepath.ToSlash(path)))
+	}
+
+	if err := os.WriteFile(outputSums, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputSums, err)
+	}
+
+	fmt.Printf("✅ Generated: %s\n", outputSums)
+	fmt.Printf("   📝 %d files covered\n", len(files))
+
+	return nil
+}
+
+// checkChecksums reports files publishedFiles covers that SHA256SUMS lacks,
+// and entries of SHA256SUMS for files it no longer covers. Hashes aren't
+// compared: the security collectors commit data without regenerating it.
+func checkChecksums() error {
+	fmt.Println("🔍 Checking SHA256SUMS against the published files...")
+
+	files, err := publishedPaths()
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(outputSums)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", outputSums, err)
+	}
+
+	listed := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		if _, path, ok := strings.Cut(line, "  "); ok {
+			listed[path] = true
+		}
+	}
+
+	var problems []string
+	for _, path := range files {
+		path = filepath.ToSlash(path)
+		if !listed[path] {
+			problems = append(problems, fmt.Sprintf("%s is published but missing from %s", path, outputSums))
+		}
+		delete(listed, path)
+	}
+	for path := range listed {
+		problems = append(problems, fmt.Sprintf("%s is in %s but not published", path, outputSums))
+	}
+	sort.Strings(problems)
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Printf("   ❌ %s\n", problem)
+		}
+		return fmt.Errorf("%s is out of date; run go run generate_checksums.go", outputSums)
+	}
+
+	fmt.Printf("✅ %s covers all %d published files\n", outputSums, len(files))
+	return nil
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Main runs fleet-tracker checksums with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("checksums", flag.ExitOnError)
+	check := fs.Bool("check", false, "check that SHA256SUMS lists exactly the published files instead of writing it")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	run := generateChecksums
+	if *check {
+		run = checkChecksums
+	}
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
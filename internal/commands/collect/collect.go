@@ -0,0 +1,1716 @@
+// Package collect implements "fleet-tracker collect", which fetches the
+// catalog and updates data/; main.go runs it on its own.
+package collect
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/fleetapi"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/httpcache"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/metrics"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/summary"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/vcr"
+)
+
+const (
+	repoOwner             = "fleetdm"
+	repoName              = "fleet"
+	appsJSONPath          = "ee/maintained-apps/outputs/apps.json"
+	outputDir             = "data"
+	outputCSV             = "data/apps_growth.csv"
+	categoryColumnPrefix  = "category_" // Per-category count columns of apps_growth.csv
+	versionsJSON          = "data/app_versions.json"
+	versionHistoryJSON    = "data/version_history.json"
+	versionHistoryArchive = "data/version_history-%d.json" // Changes from before last year, one file per year
+	scriptChangesJSONL    = "data/script_changes.jsonl"
+	scriptsDir            = "data/scripts" // Latest install and uninstall script of each app
+	archiveDir            = "archive"      // Scripts and queries of every version observed
+	runStatsJSON          = "data/run_stats.json"
+	maxRunStats           = 720 // About a month of hourly runs
+	perPage               = 100 // GitHub API max per page
+	securityInfoJSON      = "data/app_security_info.json"
+
+	// How long collection latencies are kept in run_stats.json
+	latencyWindow = 180 * 24 * time.Hour
+)
+
+// bucketLocation is the timezone used to bucket commits into days. It defaults
+// to UTC so rows don't shift with the author's or runner's local timezone.
+var bucketLocation = time.UTC
+
+// ghClient is shared by all GitHub requests so they are authenticated and retried consistently
+var ghClient = github.NewClient()
+
+// GitHub endpoints, overridable through GITHUB_API_URL and GITHUB_RAW_URL to
+// read a GitHub Enterprise Server mirror of fleetdm/fleet
+var (
+	githubAPIBase = github.APIBase()
+	githubRawBase = github.RawBase()
+	appBaseURL    = fmt.Sprintf("%s/%s/%s/main/ee/maintained-apps/outputs", githubRawBase, repoOwner, repoName)
+)
+
+// fleetClient is set when --source=fleet; the catalog then comes from that
+// Fleet server's API instead of the fleetdm/fleet repository
+var fleetClient *fleetapi.Client
+
+// Run metrics, written on exit in Prometheus format when a textfile path or
+// Pushgateway URL is configured
+var (
+	runMetrics     = metrics.New()
+	runStart       = time.Now()
+	metricsFile    string
+	pushgatewayURL string
+)
+
+type commitData struct {
+	date         string
+	count        int
+	macCount     int
+	windowsCount int
+	// categoryCounts maps category columns (see categoryColumn) to the number
+	// of apps in that category; nil when the source has no categories
+	categoryCounts map[string]int
+}
+
+// appManifest is what main.go reads from Fleet's per-app JSON
+type appManifest struct {
+	Published    []datafile.PublishedVersion
+	Flags        *datafile.ManifestFlags
+	ScriptBodies map[string]string // SHA-256 -> contents of every script a version references
+}
+
+// flagChange is a manifest flag of an app that changed since the last run
+type flagChange struct {
+	AppName  string
+	Platform string
+	Flag     string
+	Old      string
+	New      string
+}
+
+// Per-stage wall times, failure reasons and outcomes of this run
+var (
+	stageDurations  = make(map[string]float64)
+	runFailures     []string
+	appsProcessed   int
+	detectedChanges []datafile.VersionChange
+	flagChanges     []flagChange
+	scriptChanges   []datafile.ScriptChange
+	scriptBodies    = make(map[string]map[string]string) // Slug -> SHA-256 -> script contents
+)
+
+// Main runs fleet-tracker collect with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("collect", flag.ExitOnError)
+	fs.StringVar(&metricsFile, "metrics-file", os.Getenv("METRICS_TEXTFILE"), "write run metrics in Prometheus textfile format to this path")
+	fs.StringVar(&pushgatewayURL, "pushgateway", os.Getenv("PUSHGATEWAY_URL"), "push run metrics to this Prometheus Pushgateway URL")
+	source := fs.String("source", "github", "where to read the catalog from: github (fleetdm/fleet main) or fleet (a Fleet server's API)")
+	fleetURL := fs.String("fleet-url", os.Getenv("FLEET_URL"), "Fleet server URL for --source=fleet; the API token is read from FLEET_API_TOKEN")
+	tz := fs.String("tz", "UTC", "IANA timezone used to bucket commits into days (e.g. America/New_York)")
+	common.Register(fs)
+	fs.Parse(args)
+
+	ghClient.SetToken(common.GitHubToken)
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	httpcache.InstallFromEnv()
+	if err := vcr.InstallFromEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: invalid --tz %q: %v\n", *tz, err)
+		os.Exit(1)
+	}
+	bucketLocation = loc
+
+	switch *source {
+	case "github":
+	case "fleet":
+		fleetClient, err = fleetapi.NewClient(*fleetURL, os.Getenv("FLEET_API_TOKEN"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "❌ Error: invalid --source %q (want github or fleet)\n", *source)
+		os.Exit(1)
+	}
+
+	fmt.Println("🚀 Fleet Apps Growth Tracker - Data Generator")
+	fmt.Println("=============================================")
+	fmt.Printf("🕒 Bucketing dates in %s\n", bucketLocation)
+	if *source == "github" && !ghClient.Authenticated() {
+		fmt.Println("⚠️  No GitHub token (GITHUB_TOKEN or --github-token): limited to 60 API requests an hour, pausing whenever they run out")
+	}
+	fmt.Println()
+
+	var commits []commitData
+	stageStart := time.Now()
+	if fleetClient != nil {
+		// A Fleet server has no commit history: keep the existing rows and
+		// record today's catalog size
+		fmt.Printf("📡 Fetching catalog from Fleet server %s...\n", *fleetURL)
+		commits, err = getFleetServerCounts()
+	} else {
+		fmt.Println("📡 Fetching commit history from GitHub API...")
+		commits, err = getGitHubCommits()
+	}
+	recordStage("fetch_commits", stageStart)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error getting commits: %v\n", err)
+		recordFailure(fmt.Sprintf("failed to get commits: %v", err))
+		exit(1)
+	}
+
+	if len(commits) == 0 {
+		fmt.Println("❌ No commits found!")
+		recordFailure("no commits found")
+		exit(1)
+	}
+
+	fmt.Printf("✅ Found %d commits\n\n", len(commits))
+
+	// Generate continuous data
+	stageStart = time.Now()
+	err = generateContinuousData(commits)
+	recordStage("generate_csv", stageStart)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error generating data: %v\n", err)
+		recordFailure(fmt.Sprintf("failed to generate data: %v", err))
+		exit(1)
+	}
+
+	// Track app versions
+	fmt.Println("\n📦 Tracking app versions...")
+	stageStart = time.Now()
+	err = trackAppVersions()
+	recordStage("track_versions", stageStart)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to track app versions: %v\n", err)
+		recordFailure(fmt.Sprintf("failed to track app versions: %v", err))
+		// Don't exit - version tracking is optional
+	}
+
+	fmt.Println("\n✅ Data generation completed successfully!")
+	exit(0)
+}
+
+// exit records the run outcome in the run stats and metrics output and
+// terminates with code
+func exit(code int) {
+	if err := saveRunStats(code == 0); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to save run stats: %v\n", err)
+	}
+	if err := writeStepSummary(code == 0); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to write step summary: %v\n", err)
+	}
+	if err := writeRunMetrics(code == 0); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to write metrics: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// writeStepSummary reports the run outcome in the GitHub Actions job summary
+func writeStepSummary(success bool) error {
+	if !summary.Enabled() {
+		return nil
+	}
+
+	var b summary.Builder
+	b.Heading(2, "📊 Fleet Apps Growth Tracker")
+
+	status := "✅ Succeeded"
+	if !success {
+		status = "❌ Failed"
+	}
+	b.Line("**Status:** %s in %s  ", status, time.Since(runStart).Round(time.Second))
+	b.Line("**Apps processed:** %d  ", appsProcessed)
+	b.Line("**GitHub requests:** %d", ghClient.Requests())
+	b.Line("")
+
+	b.Heading(3, fmt.Sprintf("Changes detected (%d)", len(detectedChanges)))
+	if len(detectedChanges) == 0 {
+		b.Line("No version changes.\n")
+	} else {
+		var rows [][]string
+		for _, c := range detectedChanges {
+			oldVersion, newVersion := c.OldVersion, c.NewVersion
+			if oldVersion == "" {
+				oldVersion = "🆕 new"
+			}
+			if newVersion == "" {
+				newVersion = "🗑️ removed"
+			}
+			diffURL := fmt.Sprintf("https://github.com/%s/%s/commits/main/ee/maintained-apps/outputs/%s.json", repoOwner, repoName, c.Slug)
+			rows = append(rows, []string{c.AppName, c.Platform, oldVersion, newVersion, fmt.Sprintf("[history](%s)", diffURL)})
+		}
+		b.Table([]string{"App", "Platform", "Old", "New", "Upstream diff"}, rows)
+	}
+
+	if len(flagChanges) > 0 {
+		b.Heading(3, fmt.Sprintf("⚠️ Manifest flag changes (%d)", len(flagChanges)))
+		var rows [][]string
+		for _, c := range flagChanges {
+			rows = append(rows, []string{c.AppName, c.Platform, c.Flag, c.Old, c.New})
+		}
+		b.Table([]string{"App", "Platform", "Flag", "Old", "New"}, rows)
+	}
+
+	if len(scriptChanges) > 0 {
+		b.Heading(3, fmt.Sprintf("📜 Script changes (%d)", len(scriptChanges)))
+		var rows [][]string
+		for _, c := range scriptChanges {
+			rows = append(rows, []string{c.Name, c.Slug, c.Script, c.OldVersion, c.NewVersion})
+		}
+		b.Table([]string{"App", "Slug", "Script", "Old version", "New version"}, rows)
+	}
+
+	b.Heading(3, fmt.Sprintf("Failures (%d)", len(runFailures)))
+	if len(runFailures) == 0 {
+		b.Line("None.\n")
+	} else {
+		for _, reason := range runFailures {
+			b.Line("- %s", reason)
+		}
+		b.Line("")
+	}
+
+	return b.Write()
+}
+
+// recordStage records the wall time of a pipeline stage started at start
+func recordStage(name string, start time.Time) {
+	stageDurations[name] = time.Since(start).Seconds()
+}
+
+// saveRunStats appends this run to data/run_stats.json, keeping the most recent runs
+func saveRunStats(success bool) error {
+	stats := &datafile.RunStats{}
+	data, err := os.ReadFile(runStatsJSON)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		data, err = schema.Upgrade(schema.RunStats, data)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, stats); err != nil {
+			return err
+		}
+	}
+
+	entry := datafile.RunStatsEntry{
+		StartedAt:        runStart.UTC().Format(time.RFC3339),
+		GeneratorVersion: buildinfo.GeneratorVersion(),
+		DurationSeconds:  time.Since(runStart).Seconds(),
+		Success:          success,
+		GitHubRequests:   ghClient.Requests(),
+		BytesDownloaded:  ghClient.BytesDownloaded(),
+		Failures:         len(runFailures),
+		Stages:           stageDurations,
+	}
+	if remaining, ok := ghClient.RateLimitRemaining(); ok {
+		entry.RateLimitRemaining = &remaining
+	}
+
+	if err := recordCollectionLatency(stats); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to record collection latency: %v\n", err)
+	}
+
+	stats.Runs = append(stats.Runs, entry)
+	if len(stats.Runs) > maxRunStats {
+		stats.Runs = stats.Runs[len(stats.Runs)-maxRunStats:]
+	}
+	stats.SchemaVersion = schema.Current(schema.RunStats)
+
+	jsonData, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run stats: %w", err)
+	}
+
+	if err := os.WriteFile(runStatsJSON, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write run stats: %w", err)
+	}
+
+	return nil
+}
+
+// recordCollectionLatency adds the latency of every version bump of the last
+// latencyWindow whose security info has been collected since, once per
+// version, and drops the entries that have left the window. A bump recorded
+// twice (e.g. by both main.go and build_history.go) counts from the earliest.
+func recordCollectionLatency(stats *datafile.RunStats) error {
+	history, err := loadVersionHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load version history: %w", err)
+	}
+	collected, err := loadCollectionTimes()
+	if err != nil {
+		return fmt.Errorf("failed to load security info: %w", err)
+	}
+
+	cutoff := time.Now().Add(-latencyWindow)
+	recorded := make(map[string]bool)
+	kept := stats.CollectionLatency[:0]
+	for _, latency := range stats.CollectionLatency {
+		detected, err := time.Parse(time.RFC3339, latency.DetectedAt)
+		if err != nil || detected.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, latency)
+		recorded[latency.Slug+"|"+latency.Version] = true
+	}
+
+	detectedAt := make(map[string]time.Time)
+	for _, change := range history.Changes {
+		if change.OldVersion == "" || change.NewVersion == "" {
+			continue // New or removed app, not a version bump
+		}
+		t, err := time.Parse(time.RFC3339, change.Date)
+		if err != nil {
+			continue
+		}
+		key := change.Slug + "|" + change.NewVersion
+		if first, ok := detectedAt[key]; !ok || t.Before(first) {
+			detectedAt[key] = t
+		}
+	}
+
+	for key, detected := range detectedAt {
+		collectedAt, ok := collected[key]
+		if recorded[key] || !ok || detected.Before(cutoff) || collectedAt.Before(detected) {
+			continue
+		}
+		slug, version, _ := strings.Cut(key, "|")
+		kept = append(kept, datafile.CollectionLatency{
+			Slug:        slug,
+			Version:     version,
+			DetectedAt:  detected.UTC().Format(time.RFC3339),
+			CollectedAt: collectedAt.UTC().Format(time.RFC3339),
+			Seconds:     collectedAt.Sub(detected).Seconds(),
+		})
+	}
+
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].DetectedAt != kept[j].DetectedAt {
+			return kept[i].DetectedAt < kept[j].DetectedAt
+		}
+		return kept[i].Slug < kept[j].Slug
+	})
+	stats.CollectionLatency = kept
+
+	return nil
+}
+
+// loadCollectionTimes returns when the security info of each slug and version
+// in app_security_info.json (current and older versions) was collected
+func loadCollectionTimes() (map[string]time.Time, error) {
+	times := make(map[string]time.Time)
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return times, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	for _, e := range append(security.Apps, security.Versions...) {
+		if t, err := time.Parse(time.RFC3339, e.LastUpdated); err == nil {
+			times[e.Slug+"|"+e.Version] = t
+		}
+	}
+	return times, nil
+}
+
+func writeRunMetrics(success bool) error {
+	if metricsFile == "" && pushgatewayURL == "" {
+		return nil
+	}
+
+	now := time.Now()
+	runMetrics.Set("fleet_tracker_run_duration_seconds", "Duration of the last run.", now.Sub(runStart).Seconds())
+	runMetrics.SetTime("fleet_tracker_last_run_timestamp_seconds", "Time the last run finished.", now)
+	runMetrics.Set("fleet_tracker_github_requests_total", "GitHub HTTP requests made during the last run, including retries.", float64(ghClient.Requests()))
+	// Adding zero ensures the failure count is exported even when nothing failed
+	runMetrics.Add("fleet_tracker_failures_total", "Failures (errors and skipped items) during the last run.", 0)
+
+	successValue := 0.0
+	if success {
+		successValue = 1
+	}
+	runMetrics.Set("fleet_tracker_last_run_success", "Whether the last run completed successfully (1) or failed (0).", successValue)
+
+	// Data freshness survives failed runs because it comes from the committed data
+	if versions, err := loadExistingVersions(); err == nil && versions != nil {
+		if t, err := time.Parse(time.RFC3339, versions.LastUpdated); err == nil {
+			runMetrics.SetTime("fleet_tracker_data_last_updated_timestamp_seconds", "Time app_versions.json was last updated.", t)
+		}
+	}
+
+	if metricsFile != "" {
+		if err := runMetrics.WriteFile(metricsFile); err != nil {
+			return err
+		}
+		fmt.Printf("📈 Metrics written to %s\n", metricsFile)
+	}
+
+	if pushgatewayURL != "" {
+		if err := runMetrics.Push(pushgatewayURL, "fleet_apps_growth_tracker"); err != nil {
+			return err
+		}
+		fmt.Println("📈 Metrics pushed to Pushgateway")
+	}
+
+	return nil
+}
+
+// recordFailure records a failed or skipped item in the run stats, metrics and step summary
+func recordFailure(reason string) {
+	runFailures = append(runFailures, reason)
+	runMetrics.Add("fleet_tracker_failures_total", "Failures (errors and skipped items) during the last run.", 1)
+}
+
+func getGitHubCommits() ([]commitData, error) {
+	commits := make(map[string]commitData) // date -> commitData
+
+	url := fmt.Sprintf("%s/repos/%s/%s/commits?path=%s&per_page=%d",
+		githubAPIBase, repoOwner, repoName, appsJSONPath, perPage)
+
+	err := ghClient.GetPages(url, func(page int, body []byte) error {
+		fmt.Printf("📥 Fetching page %d...\n", page)
+
+		var githubCommits []github.Commit
+		if err := json.Unmarshal(body, &githubCommits); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		// Commits are returned newest first, so the first one is the latest upstream change
+		if page == 1 && len(githubCommits) > 0 {
+			if latest, err := time.Parse(time.RFC3339, githubCommits[0].Commit.Author.Date); err == nil {
+				runMetrics.SetTime("fleet_tracker_upstream_last_commit_timestamp_seconds", "Time of the latest upstream apps.json commit.", latest)
+			}
+		}
+
+		// Process each commit
+		for _, gc := range githubCommits {
+			// Parse commit date
+			commitTime, err := time.Parse(time.RFC3339, gc.Commit.Author.Date)
+			if err != nil {
+				continue
+			}
+			dateStr := commitTime.In(bucketLocation).Format("2006-01-02")
+
+			// Skip if we already have data for this date (deduplicate)
+			if _, exists := commits[dateStr]; exists {
+				continue
+			}
+
+			// Fetch file content at this commit
+			data, err := getAppCountAtCommit(gc.Sha)
+			if err != nil {
+				fmt.Printf("⚠️  Warning: failed to get app count for commit %s: %v\n", gc.Sha[:7], err)
+				recordFailure(fmt.Sprintf("failed to get app count for commit %s: %v", gc.Sha[:7], err))
+				continue
+			}
+
+			data.date = dateStr
+			commits[dateStr] = data
+			fmt.Printf("  ✓ %s: %d apps (%d Mac, %d Windows)\n", dateStr, data.count, data.macCount, data.windowsCount)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commits: %w", err)
+	}
+
+	// Convert to slice and sort
+	result := make([]commitData, 0, len(commits))
+	for _, data := range commits {
+		result = append(result, data)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].date < result[j].date
+	})
+
+	return result, nil
+}
+
+// getAppCountAtCommit counts the apps in apps.json at a commit, in total, per
+// platform and per category
+func getAppCountAtCommit(sha string) (commitData, error) {
+	// Use raw GitHub URL to get file content at specific commit
+	url := fmt.Sprintf("%s/%s/%s/%s/%s",
+		githubRawBase, repoOwner, repoName, sha, appsJSONPath)
+
+	body, err := ghClient.Get(url)
+	if err != nil {
+		return commitData{}, fmt.Errorf("failed to fetch file: %w", err)
+	}
+
+	var data struct {
+		Apps []struct {
+			Platform   string   `json:"platform"`
+			Categories []string `json:"categories"`
+		} `json:"apps"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return commitData{}, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	counts := commitData{count: len(data.Apps), categoryCounts: make(map[string]int)}
+	for _, app := range data.Apps {
+		if app.Platform == "darwin" {
+			counts.macCount++
+		} else if app.Platform == "windows" {
+			counts.windowsCount++
+		}
+		// An app listed twice under one category still counts once
+		seen := make(map[string]bool)
+		for _, category := range app.Categories {
+			column := categoryColumn(category)
+			if column != "" && !seen[column] {
+				seen[column] = true
+				counts.categoryCounts[column]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+// categoryColumn returns the apps_growth.csv column for a category:
+// "Developer tools" becomes "category_developer_tools"
+func categoryColumn(category string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(category)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return categoryColumnPrefix + b.String()
+}
+
+// getFleetServerCounts returns the counts already in apps_growth.csv plus
+// today's counts from the Fleet server's catalog, in date order
+func getFleetServerCounts() ([]commitData, error) {
+	apps, err := fleetClient.ListApps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Fleet-maintained apps: %w", err)
+	}
+
+	today := commitData{date: time.Now().In(bucketLocation).Format("2006-01-02"), count: len(apps)}
+	for _, app := range apps {
+		switch app.Platform {
+		case "darwin":
+			today.macCount++
+		case "windows":
+			today.windowsCount++
+		}
+	}
+	fmt.Printf("  📊 %s: %d apps (%d Mac, %d Windows)\n", today.date, today.count, today.macCount, today.windowsCount)
+
+	commits, err := loadExistingCounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", outputCSV, err)
+	}
+	for len(commits) > 0 && commits[len(commits)-1].date >= today.date {
+		commits = commits[:len(commits)-1]
+	}
+	return append(commits, today), nil
+}
+
+// loadExistingCounts reads the rows of apps_growth.csv back as data points
+func loadExistingCounts() ([]commitData, error) {
+	file, err := os.Open(outputCSV)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []commitData
+	for i, record := range records {
+		if i == 0 || len(record) < 2 {
+			continue
+		}
+		point := commitData{date: record[0]}
+		point.count, _ = strconv.Atoi(record[1])
+		if len(record) >= 5 {
+			point.macCount, _ = strconv.Atoi(record[3])
+			point.windowsCount, _ = strconv.Atoi(record[4])
+		}
+		// Category cells are empty for days whose source had no categories
+		for col, name := range records[0] {
+			if !strings.HasPrefix(name, categoryColumnPrefix) || col >= len(record) || record[col] == "" {
+				continue
+			}
+			if point.categoryCounts == nil {
+				point.categoryCounts = make(map[string]int)
+			}
+			point.categoryCounts[name], _ = strconv.Atoi(record[col])
+		}
+		counts = append(counts, point)
+	}
+	return counts, nil
+}
+
+func generateContinuousData(commits []commitData) error {
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits provided")
+	}
+
+	firstDateStr := commits[0].date
+	lastDateStr := commits[len(commits)-1].date
+	todayStr := time.Now().In(bucketLocation).Format("2006-01-02")
+
+	// Use today as end date if it's later than last commit
+	endDateStr := lastDateStr
+	if todayStr > lastDateStr {
+		endDateStr = todayStr
+	}
+
+	fmt.Printf("📅 Date range: %s to %s\n", firstDateStr, endDateStr)
+
+	// Parse dates
+	firstDate, err := time.Parse("2006-01-02", firstDateStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse first date: %w", err)
+	}
+
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse end date: %w", err)
+	}
+
+	// Create maps of commit dates to counts
+	commitCounts := make(map[string]int)
+	commitMacCounts := make(map[string]int)
+	commitWindowsCounts := make(map[string]int)
+	commitCategoryCounts := make(map[string]map[string]int)
+	categorySet := make(map[string]bool)
+	for _, commit := range commits {
+		commitCounts[commit.date] = commit.count
+		commitMacCounts[commit.date] = commit.macCount
+		commitWindowsCounts[commit.date] = commit.windowsCount
+		commitCategoryCounts[commit.date] = commit.categoryCounts
+		for column := range commit.categoryCounts {
+			categorySet[column] = true
+		}
+	}
+
+	// One column per category seen in any commit, after the fixed columns
+	categoryColumns := make([]string, 0, len(categorySet))
+	for column := range categorySet {
+		categoryColumns = append(categoryColumns, column)
+	}
+	sort.Strings(categoryColumns)
+
+	// Ensure output directory exists
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Generate CSV
+	file, err := os.Create(outputCSV)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"date", "app_count", "apps_added_since_previous", "mac_count", "windows_count", "apps_removed_since_previous", "net_change"}
+	if err := writer.Write(append(header, categoryColumns...)); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	currentDate := firstDate
+	currentCount := 0
+	lastKnownCount := 0
+	lastWrittenCount := 0
+	currentMacCount := 0
+	lastKnownMacCount := 0
+	currentWindowsCount := 0
+	lastKnownWindowsCount := 0
+	var lastKnownCategoryCounts map[string]int
+	entryCount := 0
+
+	for !currentDate.After(endDate) {
+		dateStr := currentDate.Format("2006-01-02")
+
+		// Check if this date has a commit
+		if count, exists := commitCounts[dateStr]; exists {
+			currentCount = count
+			lastKnownCount = count
+		}
+		if macCount, exists := commitMacCounts[dateStr]; exists {
+			currentMacCount = macCount
+			lastKnownMacCount = macCount
+		}
+		if windowsCount, exists := commitWindowsCounts[dateStr]; exists {
+			currentWindowsCount = windowsCount
+			lastKnownWindowsCount = windowsCount
+		}
+		if categoryCounts, exists := commitCategoryCounts[dateStr]; exists {
+			lastKnownCategoryCounts = categoryCounts
+		}
+
+		// Use last known count (carry forward if no commit on this date)
+		if currentCount == 0 && lastKnownCount == 0 {
+			currentDate = currentDate.AddDate(0, 0, 1)
+			continue
+		}
+
+		// Use last known count for days without commits
+		displayCount := lastKnownCount
+		if currentCount > 0 {
+			displayCount = currentCount
+		}
+		displayMacCount := lastKnownMacCount
+		if currentMacCount > 0 {
+			displayMacCount = currentMacCount
+		}
+		displayWindowsCount := lastKnownWindowsCount
+		if currentWindowsCount > 0 {
+			displayWindowsCount = currentWindowsCount
+		}
+
+		// Split the net change into additions and removals so shrinkage stays
+		// visible. Both are net: a day that adds 3 apps and removes 1 records
+		// 2 added and 0 removed. The rows only keep counts, and --source=fleet
+		// rebuilds from them, so the apps behind a change aren't known here;
+		// version_history.json lists them
+		var added, removed, netChange int
+		if entryCount == 0 {
+			netChange = displayCount // First entry
+		} else {
+			netChange = displayCount - lastWrittenCount
+		}
+		if netChange > 0 {
+			added = netChange
+		} else {
+			removed = -netChange
+		}
+
+		// Write entry for every day
+		row := []string{
+			dateStr,
+			fmt.Sprintf("%d", displayCount),
+			fmt.Sprintf("%d", added),
+			fmt.Sprintf("%d", displayMacCount),
+			fmt.Sprintf("%d", displayWindowsCount),
+			fmt.Sprintf("%d", removed),
+			fmt.Sprintf("%d", netChange),
+		}
+		for _, column := range categoryColumns {
+			// Left empty, rather than 0, when the data point had no categories
+			if lastKnownCategoryCounts == nil {
+				row = append(row, "")
+			} else {
+				row = append(row, fmt.Sprintf("%d", lastKnownCategoryCounts[column]))
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+
+		lastWrittenCount = displayCount
+
+		// Reset currentCount for next iteration
+		if _, exists := commitCounts[dateStr]; !exists {
+			currentCount = 0
+		}
+		if _, exists := commitMacCounts[dateStr]; !exists {
+			currentMacCount = 0
+		}
+		if _, exists := commitWindowsCounts[dateStr]; !exists {
+			currentWindowsCount = 0
+		}
+
+		currentDate = currentDate.AddDate(0, 0, 1)
+		entryCount++
+	}
+
+	fmt.Printf("✅ Generated: %s\n", outputCSV)
+	fmt.Printf("📊 Total entries: %d\n", entryCount)
+	fmt.Printf("📈 Final app count: %d\n", lastWrittenCount)
+
+	runMetrics.Set("fleet_tracker_days_total", "Days covered by apps_growth.csv.", float64(entryCount))
+	runMetrics.Set("fleet_tracker_apps", "Fleet-maintained apps in the latest apps.json.", float64(lastWrittenCount))
+
+	return nil
+}
+
+func trackAppVersions() error {
+	var versions []datafile.AppVersion
+	var err error
+	if fleetClient != nil {
+		versions, err = fetchFleetServerVersions()
+	} else {
+		versions, err = fetchGitHubVersions()
+	}
+	if err != nil {
+		return err
+	}
+
+	appsProcessed = len(versions)
+	runMetrics.Set("fleet_tracker_apps_processed", "Apps whose version was checked during the last run.", float64(appsProcessed))
+
+	// Load existing versions to compare
+	existingVersions, _ := loadExistingVersions()
+
+	// Check if versions changed
+	var existingApps []datafile.AppVersion
+	if existingVersions != nil {
+		existingApps = existingVersions.Apps
+	}
+	versionsChanged := !versionsEqual(existingApps, versions)
+	trackFlagChanges(existingApps, versions)
+	if err := trackScriptChanges(existingApps, versions); err != nil {
+		fmt.Printf("⚠️  Warning: failed to track script changes: %v\n", err)
+		recordFailure(fmt.Sprintf("failed to track script changes: %v", err))
+	}
+	if err := archiveVersions(versions); err != nil {
+		fmt.Printf("⚠️  Warning: failed to archive scripts: %v\n", err)
+		recordFailure(fmt.Sprintf("failed to archive scripts: %v", err))
+	}
+
+	// Save new versions
+	versionsData := datafile.AppVersions{
+		SchemaVersion:    schema.Current(schema.AppVersions),
+		GeneratorVersion: buildinfo.GeneratorVersion(),
+		LastUpdated:      time.Now().UTC().Format(time.RFC3339),
+		Apps:             versions,
+	}
+
+	jsonData, err := json.MarshalIndent(versionsData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal versions: %w", err)
+	}
+
+	if err := os.WriteFile(versionsJSON, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write versions file: %w", err)
+	}
+
+	if versionsChanged {
+		fmt.Printf("✅ Versions updated: %s\n", versionsJSON)
+		if existingVersions != nil {
+			fmt.Println("   📝 Version changes detected!")
+			// Track version changes for RSS feed
+			if err := trackVersionChanges(existingApps, versions); err != nil {
+				fmt.Printf("⚠️  Warning: failed to track version changes: %v\n", err)
+				recordFailure(fmt.Sprintf("failed to track version changes: %v", err))
+			}
+		}
+	} else {
+		fmt.Printf("✅ Versions checked: %s (no changes)\n", versionsJSON)
+	}
+
+	return nil
+}
+
+// fetchGitHubVersions reads apps.json and each app's manifest from fleetdm/fleet main
+func fetchGitHubVersions() ([]datafile.AppVersion, error) {
+	appsJSONURL := fmt.Sprintf("%s/%s/%s/main/%s", githubRawBase, repoOwner, repoName, appsJSONPath)
+	body, err := ghClient.Get(appsJSONURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch apps.json: %w", err)
+	}
+
+	var appsData struct {
+		Apps []struct {
+			Name     string `json:"name"`
+			Slug     string `json:"slug"`
+			Platform string `json:"platform"`
+		} `json:"apps"`
+	}
+	if err := json.Unmarshal(body, &appsData); err != nil {
+		return nil, fmt.Errorf("failed to parse apps.json: %w", err)
+	}
+
+	// Fetch versions for each app
+	versions := make([]datafile.AppVersion, 0, len(appsData.Apps))
+	for _, app := range appsData.Apps {
+		manifest, err := fetchManifest(app.Slug)
+		if err != nil {
+			// If version fetch fails, still include the app with empty version
+			fmt.Printf("  ⚠️  Warning: failed to get version for %s/%s: %v\n", app.Slug, app.Platform, err)
+			recordFailure(fmt.Sprintf("failed to get version for %s: %v", app.Slug, err))
+			versions = append(versions, datafile.AppVersion{
+				Slug:         app.Slug,
+				Name:         app.Name,
+				Platform:     app.Platform,
+				Version:      "",
+				InstallerURL: "",
+			})
+			continue
+		}
+		latest := manifest.Published[0]
+		versions = append(versions, datafile.AppVersion{
+			Slug:              app.Slug,
+			Name:              app.Name,
+			Platform:          app.Platform,
+			Version:           latest.Version,
+			InstallerURL:      latest.InstallerURL,
+			Arch:              latest.Arch,
+			Variants:          archVariants(manifest.Published),
+			PublishedVersions: manifest.Published,
+			Flags:             manifest.Flags,
+			Scripts:           latest.Scripts,
+		})
+		scriptBodies[app.Slug] = manifest.ScriptBodies
+		fmt.Printf("  ✓ %s (%s): %s\n", app.Name, app.Platform, latest.Version)
+	}
+
+	return versions, nil
+}
+
+// fetchFleetServerVersions reads the catalog and each app's installer from the
+// Fleet server's API. The API only exposes the version the server offers, so
+// publishedVersions is left empty.
+func fetchFleetServerVersions() ([]datafile.AppVersion, error) {
+	apps, err := fleetClient.ListApps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Fleet-maintained apps: %w", err)
+	}
+
+	versions := make([]datafile.AppVersion, 0, len(apps))
+	for _, app := range apps {
+		info := datafile.AppVersion{
+			Slug:     app.Slug,
+			Name:     app.Name,
+			Platform: app.Platform,
+			Version:  app.Version,
+		}
+		detail, err := fleetClient.GetApp(app.ID)
+		if err != nil {
+			// Keep the list's version, just without an installer URL
+			fmt.Printf("  ⚠️  Warning: failed to get installer for %s/%s: %v\n", app.Slug, app.Platform, err)
+			recordFailure(fmt.Sprintf("failed to get installer for %s: %v", app.Slug, err))
+		} else {
+			if detail.Version != "" {
+				info.Version = detail.Version
+			}
+			info.InstallerURL = detail.InstallerURL
+			if app.Platform == "darwin" {
+				info.Arch = installerArch(detail.InstallerURL)
+			}
+		}
+		versions = append(versions, info)
+		fmt.Printf("  ✓ %s (%s): %s\n", app.Name, app.Platform, info.Version)
+	}
+
+	return versions, nil
+}
+
+// trackFlagChanges warns about every manifest flag that changed for an app
+// since the last run. A new default category or install option changes what
+// Fleet does on hosts without a version bump, so it's worth a look.
+func trackFlagChanges(oldVersions, newVersions []datafile.AppVersion) {
+	oldMap := make(map[string]datafile.AppVersion)
+	for _, v := range oldVersions {
+		oldMap[v.Slug] = v
+	}
+
+	for _, newVersion := range newVersions {
+		oldVersion, exists := oldMap[newVersion.Slug]
+		// Apps recorded before flags were tracked, or whose manifest
+		// couldn't be read, have nothing to compare against
+		if !exists || oldVersion.Flags == nil || newVersion.Flags == nil {
+			continue
+		}
+		old, new := oldVersion.Flags, newVersion.Flags
+		for _, field := range []struct{ name, old, new string }{
+			{"default categories", strings.Join(old.DefaultCategories, ", "), strings.Join(new.DefaultCategories, ", ")},
+			{"self-service", flagValue(old.SelfService), flagValue(new.SelfService)},
+			{"automatic install", flagValue(old.AutomaticInstall), flagValue(new.AutomaticInstall)},
+		} {
+			if field.old == field.new {
+				continue
+			}
+			flagChanges = append(flagChanges, flagChange{
+				AppName:  newVersion.Name,
+				Platform: newVersion.Platform,
+				Flag:     field.name,
+				Old:      field.old,
+				New:      field.new,
+			})
+			fmt.Printf("   🚩 %s (%s): %s changed from %q to %q\n", newVersion.Name, newVersion.Platform, field.name, field.old, field.new)
+		}
+	}
+
+	runMetrics.Set("fleet_tracker_flag_changes", "Manifest flag changes detected during the last run.", float64(len(flagChanges)))
+}
+
+// flagValue formats an optional install option for comparison and display
+func flagValue(v *bool) string {
+	switch {
+	case v == nil:
+		return "unset"
+	case *v:
+		return "on"
+	}
+	return "off"
+}
+
+// trackScriptChanges records every install or uninstall script that changed
+// since the last run in script_changes.jsonl, diffed against the copy kept in
+// data/scripts, then refreshes those copies. Scripts run on every host that
+// installs the app, so reviewers audit each change.
+func trackScriptChanges(oldVersions, newVersions []datafile.AppVersion) error {
+	oldMap := make(map[string]datafile.AppVersion)
+	for _, v := range oldVersions {
+		oldMap[v.Slug] = v
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	var changes []datafile.ScriptChange
+	for _, app := range newVersions {
+		if app.Scripts == nil {
+			continue
+		}
+		var oldScripts datafile.ScriptHashes
+		oldApp, exists := oldMap[app.Slug]
+		if exists && oldApp.Scripts != nil {
+			oldScripts = *oldApp.Scripts
+		}
+
+		for _, script := range []struct{ kind, old, new string }{
+			{"install", oldScripts.Install, app.Scripts.Install},
+			{"uninstall", oldScripts.Uninstall, app.Scripts.Uninstall},
+		} {
+			body, ok := scriptBodies[app.Slug][script.new]
+			if !ok {
+				continue
+			}
+			path := scriptPath(app, script.kind)
+
+			if script.old != "" && script.old != script.new {
+				change := datafile.ScriptChange{
+					DetectedAt: now,
+					Slug:       app.Slug,
+					Name:       app.Name,
+					Script:     script.kind,
+					OldVersion: oldApp.Version,
+					NewVersion: app.Version,
+					OldSha256:  script.old,
+					NewSha256:  script.new,
+				}
+				// The kept copy may be missing or stale if an earlier run failed to write it
+				if previous, err := os.ReadFile(path); err == nil && sha256Hex(string(previous)) == script.old {
+					change.Diff = unifiedDiff(string(previous), body, filepath.ToSlash(path))
+				}
+				changes = append(changes, change)
+				fmt.Printf("   📜 %s (%s): %s script changed\n", app.Name, app.Platform, script.kind)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+			}
+			if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+		}
+	}
+
+	scriptChanges = append(scriptChanges, changes...)
+	runMetrics.Set("fleet_tracker_script_changes", "Install and uninstall script changes detected during the last run.", float64(len(changes)))
+	if len(changes) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(scriptChangesJSONL, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", scriptChangesJSONL, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, change := range changes {
+		if err := encoder.Encode(change); err != nil {
+			return fmt.Errorf("failed to write %s: %w", scriptChangesJSONL, err)
+		}
+	}
+	return nil
+}
+
+// scriptPath returns where the latest script of a kind is kept, e.g.
+// data/scripts/zoom/darwin/install.sh; Windows scripts are PowerShell
+func scriptPath(app datafile.AppVersion, kind string) string {
+	ext := ".sh"
+	if app.Platform == "windows" {
+		ext = ".ps1"
+	}
+	return filepath.Join(scriptsDir, filepath.FromSlash(app.Slug), kind+ext)
+}
+
+// archiveVersions writes the scripts and queries of every published version
+// not archived yet to archive/<app>/<platform>/<version>/. An archived version
+// is never rewritten, so the directory shows what Fleet would have run on
+// hosts when the version was first seen; later changes to the same version's
+// scripts are in script_changes.jsonl.
+func archiveVersions(versions []datafile.AppVersion) error {
+	archived := 0
+	for _, app := range versions {
+		for _, v := range app.PublishedVersions {
+			// The version becomes a path component
+			if v.Version == "" || v.Version == "." || v.Version == ".." || strings.ContainsAny(v.Version, `/\`) {
+				continue
+			}
+			dir := filepath.Join(archiveDir, filepath.FromSlash(app.Slug), v.Version)
+			if _, err := os.Stat(dir); err == nil {
+				continue
+			}
+
+			files := make(map[string][]byte)
+			if v.Scripts != nil {
+				for kind, hash := range map[string]string{"install": v.Scripts.Install, "uninstall": v.Scripts.Uninstall} {
+					if body, ok := scriptBodies[app.Slug][hash]; ok {
+						files[filepath.Base(scriptPath(app, kind))] = []byte(body)
+					}
+				}
+			}
+			if len(v.Queries) > 0 {
+				data, err := json.MarshalIndent(v.Queries, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal queries of %s %s: %w", app.Slug, v.Version, err)
+				}
+				files["queries.json"] = data
+			}
+			if len(files) == 0 {
+				continue
+			}
+
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+			for name, data := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", filepath.Join(dir, name), err)
+				}
+			}
+			archived++
+		}
+	}
+
+	if archived > 0 {
+		fmt.Printf("🗄️  Archived scripts and queries of %d new versions in %s/\n", archived, archiveDir)
+	}
+	return nil
+}
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// unifiedDiff returns a unified diff of two versions of a script. Scripts are
+// a few hundred lines at most, so a plain LCS table is fast enough.
+func unifiedDiff(oldText, newText, name string) string {
+	a := strings.Split(strings.TrimSuffix(oldText, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(newText, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type edit struct {
+		op     byte // ' ', '-' or '+'
+		line   string
+		oldPos int
+		newPos int
+	}
+	var edits []edit
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", name, name)
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+
+		// A hunk runs until more than twice the context of unchanged lines
+		start, end := max(k-diffContext, 0), k
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			run := 0
+			for end+run < len(edits) && edits[end+run].op == ' ' {
+				run++
+			}
+			if end+run == len(edits) || run > 2*diffContext {
+				end += min(run, diffContext)
+				break
+			}
+			end += run
+		}
+
+		hunk := edits[start:end]
+		oldCount, newCount := 0, 0
+		for _, e := range hunk {
+			if e.op != '+' {
+				oldCount++
+			}
+			if e.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", hunk[0].oldPos+1, oldCount, hunk[0].newPos+1, newCount)
+		for _, e := range hunk {
+			sb.WriteByte(e.op)
+			sb.WriteString(e.line)
+			sb.WriteByte('\n')
+		}
+		k = end
+	}
+	return sb.String()
+}
+
+func trackVersionChanges(oldVersions, newVersions []datafile.AppVersion) error {
+	// Load existing history
+	history, err := loadVersionHistory()
+	if err != nil {
+		history = &datafile.VersionHistory{Changes: []datafile.VersionChange{}}
+	}
+
+	// Create maps for comparison
+	oldMap := make(map[string]datafile.AppVersion)
+	for _, v := range oldVersions {
+		oldMap[v.Slug] = v
+	}
+
+	newMap := make(map[string]datafile.AppVersion)
+	for _, v := range newVersions {
+		newMap[v.Slug] = v
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	// Detect version changes
+	var changes []datafile.VersionChange
+	for slug, newVersion := range newMap {
+		oldVersion, exists := oldMap[slug]
+		if exists && oldVersion.Version != "" && newVersion.Version != "" && oldVersion.Version != newVersion.Version {
+			// Version changed
+			change := datafile.VersionChange{
+				Date:         now,
+				AppName:      newVersion.Name,
+				Slug:         slug,
+				Platform:     newVersion.Platform,
+				OldVersion:   oldVersion.Version,
+				NewVersion:   newVersion.Version,
+				InstallerURL: newVersion.InstallerURL,
+			}
+			changes = append(changes, change)
+			fmt.Printf("   📌 %s: %s → %s\n", newVersion.Name, oldVersion.Version, newVersion.Version)
+		} else if !exists && newVersion.Version != "" {
+			// New app added
+			change := datafile.VersionChange{
+				Date:         now,
+				AppName:      newVersion.Name,
+				Slug:         slug,
+				Platform:     newVersion.Platform,
+				OldVersion:   "",
+				NewVersion:   newVersion.Version,
+				InstallerURL: newVersion.InstallerURL,
+			}
+			changes = append(changes, change)
+			fmt.Printf("   🆕 New app: %s (%s)\n", newVersion.Name, newVersion.Version)
+		}
+	}
+
+	// Detect removed apps, recorded with the last known version and no new one.
+	// Apps whose version couldn't be fetched are still listed, so a missing
+	// slug means Fleet stopped maintaining the app.
+	if len(newMap) > 0 {
+		for slug, oldVersion := range oldMap {
+			if _, exists := newMap[slug]; exists || oldVersion.Version == "" {
+				continue
+			}
+			change := datafile.VersionChange{
+				Date:       now,
+				AppName:    oldVersion.Name,
+				Slug:       slug,
+				Platform:   oldVersion.Platform,
+				OldVersion: oldVersion.Version,
+				NewVersion: "",
+			}
+			changes = append(changes, change)
+			fmt.Printf("   🗑️  Removed app: %s (%s)\n", oldVersion.Name, oldVersion.Version)
+		}
+	}
+
+	// Merge without duplicating changes already recorded (e.g. by build_history.go)
+	added := mergeChanges(history, changes)
+	detectedChanges = append(detectedChanges, changes...)
+	runMetrics.Set("fleet_tracker_version_changes", "Version changes recorded during the last run.", float64(added))
+
+	// Sort by date (newest first), matching build_history.go
+	sort.SliceStable(history.Changes, func(i, j int) bool {
+		return history.Changes[i].Date > history.Changes[j].Date
+	})
+
+	// Move changes from before last year into the yearly archives
+	if err := rotateHistory(history); err != nil {
+		return fmt.Errorf("failed to rotate version history: %w", err)
+	}
+
+	return writeVersionHistory(versionHistoryJSON, history)
+}
+
+// rotateHistory moves the changes from before last year (relative to the
+// newest change) out of history into data/version_history-YYYY.json, one
+// archive per year, so the current file stays bounded without losing anything
+func rotateHistory(history *datafile.VersionHistory) error {
+	if len(history.Changes) == 0 {
+		return nil
+	}
+	newest, err := time.Parse(time.RFC3339, history.Changes[0].Date)
+	if err != nil {
+		return nil
+	}
+	keepFrom := newest.UTC().Year() - 1
+
+	kept := []datafile.VersionChange{}
+	archived := make(map[int][]datafile.VersionChange)
+	for _, c := range history.Changes {
+		t, err := time.Parse(time.RFC3339, c.Date)
+		if err != nil || t.UTC().Year() >= keepFrom {
+			kept = append(kept, c)
+			continue
+		}
+		archived[t.UTC().Year()] = append(archived[t.UTC().Year()], c)
+	}
+
+	for year, changes := range archived {
+		path := fmt.Sprintf(versionHistoryArchive, year)
+		archive, err := loadVersionHistoryFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		added := mergeChanges(archive, changes)
+		sort.SliceStable(archive.Changes, func(i, j int) bool {
+			return archive.Changes[i].Date > archive.Changes[j].Date
+		})
+		if err := writeVersionHistory(path, archive); err != nil {
+			return err
+		}
+		fmt.Printf("🗄️  Archived %d change(s) from %d to %s\n", added, year, path)
+	}
+
+	history.Changes = kept
+	return nil
+}
+
+func writeVersionHistory(path string, history *datafile.VersionHistory) error {
+	history.SchemaVersion = schema.Current(schema.VersionHistory)
+	history.GeneratorVersion = buildinfo.GeneratorVersion()
+	jsonData, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal version history: %w", err)
+	}
+
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write version history: %w", err)
+	}
+
+	return nil
+}
+
+func loadVersionHistory() (*datafile.VersionHistory, error) {
+	return loadVersionHistoryFile(versionHistoryJSON)
+}
+
+// loadVersionHistoryFile reads the current version history or a yearly archive
+func loadVersionHistoryFile(path string) (*datafile.VersionHistory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.VersionHistory{Changes: []datafile.VersionChange{}}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.VersionHistory, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var history datafile.VersionHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// changeKey identifies a version change for deduplication. The date is
+// reduced to its UTC day so a change recorded by both the hourly tracker and
+// a history rebuild (which uses the commit time) is only stored once.
+func changeKey(c datafile.VersionChange) string {
+	day := c.Date
+	if t, err := time.Parse(time.RFC3339, c.Date); err == nil {
+		day = t.UTC().Format("2006-01-02")
+	}
+	return c.Slug + "|" + c.OldVersion + "|" + c.NewVersion + "|" + day
+}
+
+// mergeChanges adds incoming changes to history, skipping any already present,
+// and returns the number of changes added
+func mergeChanges(history *datafile.VersionHistory, incoming []datafile.VersionChange) int {
+	seen := make(map[string]bool, len(history.Changes))
+	for _, c := range history.Changes {
+		seen[changeKey(c)] = true
+	}
+
+	added := 0
+	for _, c := range incoming {
+		key := changeKey(c)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		history.Changes = append(history.Changes, c)
+		added++
+	}
+
+	return added
+}
+
+// fetchManifest reads the app's JSON: every version it lists, latest first,
+// the flags of the latest version and the scripts the versions reference
+func fetchManifest(slug string) (*appManifest, error) {
+	// Construct URL: slug format is "app-name/platform", we need "app-name/platform.json"
+	url := fmt.Sprintf("%s/%s.json", appBaseURL, slug)
+
+	body, err := ghClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch version file: %w", err)
+	}
+
+	var versionData struct {
+		Versions []struct {
+			Version            string            `json:"version"`
+			InstallerURL       string            `json:"installer_url"`
+			Queries            map[string]string `json:"queries"`
+			InstallScriptRef   string            `json:"install_script_ref"`
+			UninstallScriptRef string            `json:"uninstall_script_ref"`
+			DefaultCategories  []string          `json:"default_categories"`
+			SelfService        *bool             `json:"self_service"`
+			AutomaticInstall   *bool             `json:"automatic_install"`
+		} `json:"versions"`
+		Refs map[string]string `json:"refs"` // Script ref -> script contents
+	}
+	if err := json.Unmarshal(body, &versionData); err != nil {
+		return nil, fmt.Errorf("failed to parse version JSON: %w", err)
+	}
+
+	if len(versionData.Versions) == 0 {
+		return nil, fmt.Errorf("no versions found")
+	}
+
+	// The first entry is the latest version
+	manifest := &appManifest{ScriptBodies: make(map[string]string)}
+	for _, v := range versionData.Versions {
+		entry := datafile.PublishedVersion{Version: v.Version, InstallerURL: v.InstallerURL, Queries: v.Queries}
+		if strings.HasSuffix(slug, "/darwin") {
+			entry.Arch = installerArch(v.InstallerURL)
+		}
+		install, installOK := versionData.Refs[v.InstallScriptRef]
+		uninstall, uninstallOK := versionData.Refs[v.UninstallScriptRef]
+		if installOK || uninstallOK {
+			entry.Scripts = &datafile.ScriptHashes{}
+			if installOK {
+				entry.Scripts.Install = sha256Hex(install)
+				manifest.ScriptBodies[entry.Scripts.Install] = install
+			}
+			if uninstallOK {
+				entry.Scripts.Uninstall = sha256Hex(uninstall)
+				manifest.ScriptBodies[entry.Scripts.Uninstall] = uninstall
+			}
+		}
+		manifest.Published = append(manifest.Published, entry)
+	}
+
+	if latest := versionData.Versions[0]; len(latest.DefaultCategories) > 0 || latest.SelfService != nil || latest.AutomaticInstall != nil {
+		manifest.Flags = &datafile.ManifestFlags{
+			DefaultCategories: latest.DefaultCategories,
+			SelfService:       latest.SelfService,
+			AutomaticInstall:  latest.AutomaticInstall,
+		}
+	}
+	return manifest, nil
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// archVariants returns the other installers Fleet lists for the latest version,
+// which is how apps with separate Apple Silicon and Intel downloads show up
+func archVariants(published []datafile.PublishedVersion) []datafile.PublishedVersion {
+	var variants []datafile.PublishedVersion
+	for _, v := range published[1:] {
+		if v.Version == published[0].Version && v.InstallerURL != published[0].InstallerURL {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// installerArch guesses the architecture of a macOS installer from its URL;
+// an empty result means the URL doesn't say
+func installerArch(installerURL string) string {
+	u := strings.ToLower(installerURL)
+	switch {
+	case strings.Contains(u, "universal"):
+		return "universal"
+	case strings.Contains(u, "arm64"), strings.Contains(u, "aarch64"), strings.Contains(u, "apple-silicon"), strings.Contains(u, "applesilicon"):
+		return "arm64"
+	case strings.Contains(u, "x86_64"), strings.Contains(u, "x86-64"), strings.Contains(u, "x64"), strings.Contains(u, "amd64"), strings.Contains(u, "intel"):
+		return "x86_64"
+	}
+	return ""
+}
+
+func loadExistingVersions() (*datafile.AppVersions, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // File doesn't exist yet, that's okay
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+func versionsEqual(old, new []datafile.AppVersion) bool {
+	if old == nil {
+		return false // First time, consider it changed
+	}
+
+	if len(old) != len(new) {
+		return false
+	}
+
+	// Create maps for easier comparison
+	oldMap := make(map[string]datafile.AppVersion)
+	for _, v := range old {
+		oldMap[v.Slug] = v
+	}
+
+	newMap := make(map[string]datafile.AppVersion)
+	for _, v := range new {
+		newMap[v.Slug] = v
+	}
+
+	// Check if all slugs match
+	for slug, newVersion := range newMap {
+		oldVersion, exists := oldMap[slug]
+		if !exists {
+			return false // New app added
+		}
+		if oldVersion.Version != newVersion.Version {
+			return false // Version changed
+		}
+	}
+
+	// Check if any apps were removed
+	for slug := range oldMap {
+		if _, exists := newMap[slug]; !exists {
+			return false // App removed
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,138 @@
+// Package compress implements "fleet-tracker compress", which writes gzip
+// copies of the pages, feeds and data files; compress_outputs.go runs it on
+// its own.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+)
+
+// compressedFiles lists the outputs that get a precompressed .gz variant;
+// globs are expanded, and the api/ export is walked for JSON files
+var compressedFiles = []string{
+	"index.html",
+	"changelog.html",
+	"feed.xml",
+	"advisory.xml",
+	"releases.ics",
+	"data/*.csv",
+	"data/*.json",
+}
+
+const apiExportDir = "api"
+
+func compressOutputs(minSize int64) error {
+	fmt.Println("🗜️  Precompressing outputs...")
+
+	var files []string
+	for _, pattern := range compressedFiles {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("failed to expand %s: %w", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	err := filepath.WalkDir(apiExportDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk %s: %w", apiExportDir, err)
+	}
+
+	written, unchanged, saved := 0, 0, int64(0)
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if int64(len(data)) < minSize {
+			os.Remove(path + ".gz")
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		compressed, err := gzipBytes(data)
+		if err != nil {
+			return fmt.Errorf("failed to compress %s: %w", path, err)
+		}
+		saved += int64(len(data) - len(compressed))
+
+		// Unchanged outputs keep their .gz, but the generators rewrite the
+		// source on every run, so the .gz takes the source's mtime either
+		// way: servers such as fleet-tracker serve skip a .gz older than
+		// its source
+		if existing, err := os.ReadFile(path + ".gz"); err == nil && bytes.Equal(existing, compressed) {
+			unchanged++
+		} else {
+			if err := os.WriteFile(path+".gz", compressed, 0644); err != nil {
+				return fmt.Errorf("failed to write %s.gz: %w", path, err)
+			}
+			written++
+		}
+		if err := os.Chtimes(path+".gz", info.ModTime(), info.ModTime()); err != nil {
+			return fmt.Errorf("failed to set the mtime of %s.gz: %w", path, err)
+		}
+	}
+
+	fmt.Printf("✅ Precompressed %d files (%d unchanged)\n", written+unchanged, unchanged)
+	fmt.Printf("   📉 %.1f KB saved per full download\n", float64(saved)/1024)
+
+	return nil
+}
+
+// gzipBytes compresses data at the best compression level. The header
+// carries no name or timestamp, so the same input always produces the same
+// bytes.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Main runs fleet-tracker compress with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("compress", flag.ExitOnError)
+	minSize := fs.Int64("min-size", 1024, "skip (and remove the .gz of) files smaller than this many bytes")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := compressOutputs(*minSize); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,47 @@
+package compress
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestUnchangedOutputFollowsSource regenerates index.html with the same
+// content and checks the .gz isn't left older than it
+func TestUnchangedOutputFollowsSource(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	page := []byte("<html>the same page every run</html>")
+	if err := os.WriteFile("index.html", page, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressOutputs(0); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := os.WriteFile("index.html", page, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes("index.html", later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressOutputs(0); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat("index.html.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(later) {
+		t.Errorf("index.html.gz mtime = %v, want the source's %v", info.ModTime(), later)
+	}
+}
@@ -0,0 +1,345 @@
+// Package crossref implements "fleet-tracker crossref", which compares Windows
+// apps with winget and Chocolatey; crossref_packages.go runs it on its own.
+package crossref
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/httpcache"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionsJSON      = "data/app_versions.json"
+	packageIDsJSON    = "data/package_ids.json"
+	packageParityJSON = "data/package_parity.json"
+	repoOwner         = "fleetdm"
+	repoName          = "fleet"
+	wingetInputsPath  = "ee/maintained-apps/inputs/winget"
+	wingetRepo        = "microsoft/winget-pkgs"
+	chocolateyFeed    = "https://community.chocolatey.org/api/v2"
+)
+
+// Package comparison results, from Fleet's point of view
+const (
+	paritySame   = "same"
+	parityBehind = "fleet-behind"
+	parityAhead  = "fleet-ahead"
+)
+
+// packageIDs overrides the package IDs looked up for an app
+type packageIDs struct {
+	Winget     string `json:"winget,omitempty"`
+	Chocolatey string `json:"chocolatey,omitempty"`
+}
+
+// chocolateyFeedXML is the part of a Chocolatey OData (Atom) response used here
+type chocolateyFeedXML struct {
+	Entries []struct {
+		Properties struct {
+			ID      string `xml:"Id"`
+			Title   string `xml:"Title"`
+			Version string `xml:"Version"`
+		} `xml:"properties"`
+	} `xml:"entry"`
+}
+
+var (
+	ghClient   = github.NewClient()
+	httpClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+// Main runs fleet-tracker crossref with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("crossref", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	ghClient.SetToken(common.GitHubToken)
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	httpcache.InstallFromEnv()
+
+	if err := crossrefPackages(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func crossrefPackages() error {
+	fmt.Println("🔗 Cross-referencing Windows apps with winget and Chocolatey...")
+
+	apps, err := loadWindowsApps()
+	if err != nil {
+		return err
+	}
+	overrides, err := loadPackageIDs()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   📦 %d Windows apps\n", len(apps))
+
+	result := datafile.PackageParity{
+		SchemaVersion:    schema.Current(schema.PackageParity),
+		GeneratorVersion: buildinfo.GeneratorVersion(),
+		LastUpdated:      time.Now().UTC().Format(time.RFC3339),
+		Apps:             []datafile.AppParity{},
+	}
+	wingetFound, chocoFound := 0, 0
+	for _, app := range apps {
+		parity := datafile.AppParity{Slug: app.Slug, Name: app.Name, FleetVersion: app.Version}
+		ids := overrides[app.Slug]
+
+		winget, err := wingetPackage(app, ids.Winget)
+		if err != nil {
+			fmt.Printf("   ⚠️  %s: winget lookup failed: %v\n", app.Slug, err)
+		} else if winget != nil {
+			parity.Winget = winget
+			wingetFound++
+		}
+
+		choco, err := chocolateyPackage(app, ids.Chocolatey)
+		if err != nil {
+			fmt.Printf("   ⚠️  %s: Chocolatey lookup failed: %v\n", app.Slug, err)
+		} else if choco != nil {
+			parity.Chocolatey = choco
+			chocoFound++
+		}
+
+		result.Apps = append(result.Apps, parity)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal package parity: %w", err)
+	}
+	if err := os.WriteFile(packageParityJSON, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", packageParityJSON, err)
+	}
+
+	fmt.Printf("✅ Matched %d apps in winget and %d in Chocolatey\n", wingetFound, chocoFound)
+	fmt.Printf("   📝 Wrote %s\n", packageParityJSON)
+	return nil
+}
+
+func loadWindowsApps() ([]datafile.AppVersion, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", versionsJSON, err)
+	}
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s: %w", versionsJSON, err)
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", versionsJSON, err)
+	}
+
+	var apps []datafile.AppVersion
+	for _, app := range versions.Apps {
+		if app.Platform == "windows" {
+			apps = append(apps, app)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Slug < apps[j].Slug })
+	return apps, nil
+}
+
+// loadPackageIDs reads the optional per-slug overrides in packageIDsJSON
+func loadPackageIDs() (map[string]packageIDs, error) {
+	data, err := os.ReadFile(packageIDsJSON)
+	if os.IsNotExist(err) {
+		return map[string]packageIDs{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids map[string]packageIDs
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", packageIDsJSON, err)
+	}
+	return ids, nil
+}
+
+// wingetPackage finds the app's winget package and its latest version in
+// microsoft/winget-pkgs, or nil when it has none
+func wingetPackage(app datafile.AppVersion, id string) (*datafile.PackageMatch, error) {
+	if id == "" {
+		var err error
+		if id, err = fleetWingetID(app.Slug); err != nil || id == "" {
+			return nil, err
+		}
+	}
+
+	// Manifests live under manifests/<first letter>/<ID with dots as directories>/<version>
+	manifestPath := "manifests/" + strings.ToLower(id[:1]) + "/" + strings.ReplaceAll(id, ".", "/")
+	body, err := ghClient.Get(fmt.Sprintf("%s/repos/%s/contents/%s", github.APIBase(), wingetRepo, manifestPath))
+	var statusErr *github.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s listing: %w", manifestPath, err)
+	}
+	latest := ""
+	for _, entry := range entries {
+		// Nested packages (e.g. Zoom.Zoom.Rooms) sit next to the version directories
+		if entry.Type != "dir" || entry.Name == "" || !unicode.IsDigit(rune(entry.Name[0])) {
+			continue
+		}
+		if latest == "" || compareVersions(entry.Name, latest) > 0 {
+			latest = entry.Name
+		}
+	}
+	if latest == "" {
+		return nil, nil
+	}
+	return &datafile.PackageMatch{ID: id, Version: latest, Status: parityStatus(app.Version, latest)}, nil
+}
+
+// fleetWingetID reads the winget package identifier from the input Fleet
+// builds the app's manifest from, or "" when there is no winget input
+func fleetWingetID(slug string) (string, error) {
+	name := strings.TrimSuffix(slug, "/windows")
+	inputURL := fmt.Sprintf("%s/%s/%s/main/%s/%s.json", github.RawBase(), repoOwner, repoName, wingetInputsPath, name)
+	body, err := ghClient.Get(inputURL)
+	var statusErr *github.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var input struct {
+		PackageIdentifier string `json:"package_identifier"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", inputURL, err)
+	}
+	return input.PackageIdentifier, nil
+}
+
+// chocolateyPackage finds the app's latest Chocolatey package, by ID when one
+// is given and otherwise by searching for a package titled like the app
+func chocolateyPackage(app datafile.AppVersion, id string) (*datafile.PackageMatch, error) {
+	query := url.Values{}
+	var endpoint string
+	if id != "" {
+		endpoint = "Packages()"
+		query.Set("$filter", fmt.Sprintf("Id eq '%s' and IsLatestVersion", strings.ReplaceAll(id, "'", "''")))
+	} else {
+		endpoint = "Search()"
+		query.Set("searchTerm", "'"+strings.ReplaceAll(app.Name, "'", "''")+"'")
+		query.Set("$filter", "IsLatestVersion")
+		query.Set("includePrerelease", "false")
+		query.Set("targetFramework", "''")
+		query.Set("$top", "20")
+	}
+
+	resp, err := httpClient.Get(chocolateyFeed + "/" + endpoint + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var feed chocolateyFeedXML
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	for _, entry := range feed.Entries {
+		p := entry.Properties
+		if id != "" || strings.EqualFold(p.Title, app.Name) || strings.EqualFold(p.ID, chocolateyID(app.Name)) {
+			return &datafile.PackageMatch{ID: p.ID, Version: p.Version, Status: parityStatus(app.Version, p.Version)}, nil
+		}
+	}
+	return nil, nil
+}
+
+// chocolateyID is the conventional package ID for an app name: lowercase
+// with spaces as dashes, e.g. "Google Chrome" -> "google-chrome"
+func chocolateyID(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "-")
+}
+
+// parityStatus compares Fleet's version with a package repository's
+func parityStatus(fleet, other string) string {
+	switch c := compareVersions(fleet, other); {
+	case c < 0:
+		return parityBehind
+	case c > 0:
+		return parityAhead
+	default:
+		return paritySame
+	}
+}
+
+// compareVersions compares the numeric components of two version strings,
+// e.g. 6.10.2 > 6.9.14, ignoring anything that isn't a digit
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) []int {
+	var parts []int
+	for _, field := range strings.FieldsFunc(version, func(r rune) bool { return !unicode.IsDigit(r) }) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			n = 0
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
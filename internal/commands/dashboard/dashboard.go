@@ -0,0 +1,3467 @@
+// Package dashboard implements "fleet-tracker html", which generates
+// index.html; generate_html.go runs it on its own.
+package dashboard
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/analytics"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/httpcache"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/milestone"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/tlsinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/vcr"
+)
+
+const (
+	csvFile          = "data/apps_growth.csv"
+	outputHTML       = "index.html"
+	iconsBaseURL     = "https://raw.githubusercontent.com/fleetdm/fleet/main/website/assets/images"
+	securityInfoJSON = "data/app_security_info.json"
+	appsMetadataJSON = "data/apps_metadata.json"
+	versionsJSON     = "data/app_versions.json"
+	versionHistory   = "data/version_history.json"
+	firstSeenJSON    = "data/app_first_seen.json"
+	installerUptime  = "data/installer_uptime.jsonl"
+	packageParity    = "data/package_parity.json"
+	upstreamLag      = "data/upstream_lag.json"
+	runStatsJSON     = "data/run_stats.json"
+	uptimeWindowDays = 30 // availability is computed over this many days of probes
+
+	// Health panel thresholds: the tracker runs hourly, the collectors after
+	// each data update that changes a version
+	trackerAmberAfter   = 3 * time.Hour
+	trackerRedAfter     = 24 * time.Hour
+	collectorAmberAfter = 48 * time.Hour
+	collectorRedAfter   = 7 * 24 * time.Hour
+)
+
+// Upstream manifests; GITHUB_API_URL or GITHUB_RAW_URL point these at a
+// GitHub Enterprise Server mirror of fleetdm/fleet
+var (
+	appBaseURL  = github.RawBase() + "/fleetdm/fleet/main/ee/maintained-apps/outputs"
+	appsJSONURL = appBaseURL + "/apps.json"
+)
+
+// ghClient fetches app manifests with --refresh; it retries, authenticates
+// with GITHUB_TOKEN and paces the parallel requests
+var ghClient = github.NewClient()
+
+type csvData struct {
+	Dates           []string `json:"dates"`
+	Counts          []int    `json:"counts"`
+	Additions       []int    `json:"additions"`
+	MacCounts       []int    `json:"macCounts"`
+	WindowsCounts   []int    `json:"windowsCounts"`
+	GrowthDates     []string `json:"growthDates"`
+	GrowthCounts    []int    `json:"growthCounts"`
+	GrowthAdditions []int    `json:"growthAdditions"`
+}
+
+// weeklyUpdates counts version-change events (not new apps) per week; Weeks
+// holds the Monday (UTC) each week starts on
+type weeklyUpdates struct {
+	Weeks   []string `json:"weeks"`
+	Updates []int    `json:"updates"`
+}
+
+// weeklyLatency is the median and 95th percentile collection latency, in
+// hours, of the version bumps detected each week; weeks without a collected
+// bump are left out
+type weeklyLatency struct {
+	Weeks   []string  `json:"weeks"`
+	Median  []float64 `json:"median"`
+	P95     []float64 `json:"p95"`
+	Samples []int     `json:"samples"`
+}
+
+// Health panel indicators, in order of severity
+const (
+	healthGreen = "green"
+	healthAmber = "amber"
+	healthRed   = "red"
+)
+
+// pipelineHealth is the dashboard's health panel: the state of the tracker
+// and of each collector, and the apps whose security info is behind. Status
+// is the worst of the pipelines'.
+type pipelineHealth struct {
+	Status    string           `json:"status"`
+	Pipelines []pipelineStatus `json:"pipelines"`
+	Missing   int              `json:"missing"`  // Apps with an installer but no security info
+	Outdated  int              `json:"outdated"` // Apps whose security info is for an older version
+	Failures  int              `json:"failures"` // Failures in the tracker's latest run
+}
+
+type pipelineStatus struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	LastSuccess string `json:"lastSuccess,omitempty"` // RFC 3339
+	Detail      string `json:"detail"`
+}
+
+type appData struct {
+	Name         string                  `json:"name"`
+	Slug         string                  `json:"slug"`
+	Platform     string                  `json:"platform"`
+	Description  string                  `json:"description"`
+	Categories   []string                `json:"categories,omitempty"`
+	Version      string                  `json:"version"`
+	InstallerURL string                  `json:"installerUrl"`
+	SecurityInfo *appSecurityInfoData    `json:"securityInfo,omitempty"`
+	Availability *installerAvailability  `json:"availability,omitempty"`
+	Packages     *appPackages            `json:"packages,omitempty"`
+	Upstream     *appUpstream            `json:"upstream,omitempty"`
+	Flags        *datafile.ManifestFlags `json:"flags,omitempty"`
+}
+
+// appPackages is an app's winget and Chocolatey packages from crossref_packages.go
+type appPackages struct {
+	Winget     *datafile.PackageMatch `json:"winget,omitempty"`
+	Chocolatey *datafile.PackageMatch `json:"chocolatey,omitempty"`
+}
+
+// appUpstream is the vendor's latest version and Fleet's lag behind it, from
+// upstream_lag.go
+type appUpstream struct {
+	Version     string `json:"version"`
+	Source      string `json:"source"`
+	ReleasedAt  string `json:"releasedAt,omitempty"`
+	Status      string `json:"status"`
+	BehindSince string `json:"behindSince,omitempty"`
+	LagDays     int    `json:"lagDays"`
+}
+
+// installerAvailability summarizes the installer probes from probe_installers.go
+type installerAvailability struct {
+	Percent     float64 `json:"percent"`
+	Checks      int     `json:"checks"`
+	LastOK      bool    `json:"lastOk"`
+	LastChecked string  `json:"lastChecked"`
+}
+
+type appSecurityInfoData struct {
+	Name         string                `json:"name,omitempty"`
+	Sha256       string                `json:"sha256,omitempty"`
+	Cdhash       string                `json:"cdhash,omitempty"`
+	SigningID    string                `json:"signingId,omitempty"`
+	TeamID       string                `json:"teamId,omitempty"`
+	Publisher    string                `json:"publisher,omitempty"`       // Windows: Certificate subject
+	Issuer       string                `json:"issuer,omitempty"`          // Windows: Certificate authority
+	SerialNumber string                `json:"serialNumber,omitempty"`    // Windows: Certificate serial
+	Thumbprint   string                `json:"thumbprint,omitempty"`      // Windows: Certificate thumbprint
+	Timestamp    string                `json:"timestamp,omitempty"`       // Windows: Signing timestamp
+	SigStatus    string                `json:"signatureStatus,omitempty"` // Windows: WinVerifyTrust verdict
+	SigDetail    string                `json:"signatureDetail,omitempty"` // Windows: Why the signature isn't valid
+	DownloadTLS  *tlsinfo.Info         `json:"downloadTls,omitempty"`
+	Arch         string                `json:"arch,omitempty"`
+	Slices       []datafile.ArchSlice  `json:"architectures,omitempty"`
+	Variants     []appSecurityInfoData `json:"variants,omitempty"` // Installers for other architectures
+	LastUpdated  string                `json:"lastUpdated,omitempty"`
+	Apps         []appSecurityInfoData `json:"apps,omitempty"` // For suites with multiple apps
+
+	Sandboxed       *bool `json:"sandboxed,omitempty"`       // macOS: App Sandbox entitlement
+	HardenedRuntime *bool `json:"hardenedRuntime,omitempty"` // macOS: Hardened runtime flag
+
+	SignatureFormat string `json:"signatureFormat,omitempty"` // macOS: codesign Format
+	SigningTime     string `json:"signingTime,omitempty"`     // macOS: When the app was signed
+	LeafCertificate string `json:"leafCertificate,omitempty"` // macOS: Signing certificate common name
+	Stapled         *bool  `json:"stapled,omitempty"`         // macOS: Notarization ticket is stapled
+	NotarizedAt     string `json:"notarizedAt,omitempty"`     // macOS: When Apple issued the notarization ticket
+	BundleID        string `json:"bundleId,omitempty"`        // macOS: CFBundleIdentifier
+	RequiresRosetta *bool  `json:"requiresRosetta,omitempty"` // macOS: x86_64-only main executable
+
+	Persistence  []datafile.PersistenceItem `json:"persistence,omitempty"`  // macOS: Launch daemons, launch agents and login items the installer added
+	PrivacyUsage []datafile.PrivacyUsage    `json:"privacyUsage,omitempty"` // macOS: Privacy permissions declared in Info.plist
+
+	CertChain []datafile.ChainCert `json:"certChain,omitempty"` // Windows: Authenticode chain, leaf first
+}
+
+type appsJSON struct {
+	Apps []appData `json:"apps"`
+}
+
+func generateHTML(refresh bool, concurrency int) error {
+	fmt.Println("🎨 Generating HTML visualization...")
+
+	data, err := loadCSVData()
+	if err != nil {
+		return fmt.Errorf("failed to load CSV data: %w", err)
+	}
+
+	apps, err := fetchAppsData(refresh, concurrency)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to fetch apps data: %v\n", err)
+		apps, err = loadCachedAppsData()
+		if err != nil {
+			fmt.Printf("⚠️  Warning: failed to load cached apps metadata: %v\n", err)
+			apps = &appsJSON{Apps: []appData{}}
+		} else {
+			fmt.Printf("📦 Using %d apps from %s\n", len(apps.Apps), appsMetadataJSON)
+		}
+	} else {
+		fmt.Printf("✅ Fetched %d apps\n", len(apps.Apps))
+		if err := saveAppsMetadata(apps); err != nil {
+			fmt.Printf("⚠️  Warning: failed to cache apps metadata: %v\n", err)
+		}
+	}
+
+	// Load security info and merge with apps
+	securityInfo, _ := loadSecurityInfo()
+	mergeSecurityInfo(apps, securityInfo)
+
+	if availability, err := loadInstallerAvailability(); err != nil {
+		fmt.Printf("⚠️  Warning: failed to load installer availability: %v\n", err)
+	} else {
+		for i := range apps.Apps {
+			apps.Apps[i].Availability = availability[apps.Apps[i].Slug]
+		}
+	}
+
+	if packages, err := loadPackageParity(); err != nil {
+		fmt.Printf("⚠️  Warning: failed to load package parity: %v\n", err)
+	} else {
+		for i := range apps.Apps {
+			apps.Apps[i].Packages = packages[apps.Apps[i].Slug]
+		}
+	}
+
+	if lag, err := loadUpstreamLag(); err != nil {
+		fmt.Printf("⚠️  Warning: failed to load upstream lag: %v\n", err)
+	} else {
+		for i := range apps.Apps {
+			apps.Apps[i].Upstream = lag[apps.Apps[i].Slug]
+		}
+	}
+
+	if flags, err := loadManifestFlags(); err != nil {
+		fmt.Printf("⚠️  Warning: failed to load manifest flags: %v\n", err)
+	} else {
+		for i := range apps.Apps {
+			apps.Apps[i].Flags = flags[apps.Apps[i].Slug]
+		}
+	}
+
+	updates, err := loadWeeklyUpdates()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to load version history: %v\n", err)
+		updates = &weeklyUpdates{Weeks: []string{}, Updates: []int{}}
+	}
+
+	health, err := loadPipelineHealth(now())
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to load run reports: %v\n", err)
+		health = &pipelineHealth{Status: healthAmber, Pipelines: []pipelineStatus{}}
+	}
+
+	latency, err := loadWeeklyLatency()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to load collection latency: %v\n", err)
+		latency = &weeklyLatency{Weeks: []string{}, Median: []float64{}, P95: []float64{}, Samples: []int{}}
+	}
+
+	history, err := loadCatalogHistory()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to load catalog history: %v\n", err)
+		history = &catalogHistory{Changes: []catalogChange{}, FirstSeen: map[string]string{}}
+	}
+
+	analyticsSnippet, err := analytics.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	htmlContent := generateHTMLContent(data, apps, updates, latency, health, milestone.Project(data.Dates, data.Counts), history, analyticsSnippet)
+
+	if err := os.WriteFile(outputHTML, []byte(htmlContent), 0644); err != nil {
+		return fmt.Errorf("failed to write HTML file: %w", err)
+	}
+
+	fmt.Printf("✅ Generated %s\n", outputHTML)
+	fmt.Printf("   Total days: %d\n", len(data.Dates))
+	fmt.Printf("   Growth events: %d\n", len(data.GrowthDates))
+
+	return nil
+}
+
+func loadCSVData() (*csvData, error) {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV file is empty or has no data rows")
+	}
+
+	data := &csvData{
+		Dates:           make([]string, 0),
+		Counts:          make([]int, 0),
+		Additions:       make([]int, 0),
+		MacCounts:       make([]int, 0),
+		WindowsCounts:   make([]int, 0),
+		GrowthDates:     make([]string, 0),
+		GrowthCounts:    make([]int, 0),
+		GrowthAdditions: make([]int, 0),
+	}
+
+	for i := 1; i < len(records); i++ {
+		row := records[i]
+		if len(row) < 3 {
+			continue
+		}
+
+		dateStr := row[0]
+		var count, added, macCount, windowsCount int
+		fmt.Sscanf(row[1], "%d", &count)
+		fmt.Sscanf(row[2], "%d", &added)
+		if len(row) >= 4 {
+			fmt.Sscanf(row[3], "%d", &macCount)
+		}
+		if len(row) >= 5 {
+			fmt.Sscanf(row[4], "%d", &windowsCount)
+		}
+
+		data.Dates = append(data.Dates, dateStr)
+		data.Counts = append(data.Counts, count)
+		data.Additions = append(data.Additions, added)
+		data.MacCounts = append(data.MacCounts, macCount)
+		data.WindowsCounts = append(data.WindowsCounts, windowsCount)
+
+		if added > 0 {
+			data.GrowthDates = append(data.GrowthDates, dateStr)
+			data.GrowthCounts = append(data.GrowthCounts, count)
+			data.GrowthAdditions = append(data.GrowthAdditions, added)
+		}
+	}
+
+	return data, nil
+}
+
+// fetchAppsData fetches apps.json for the app metadata. Versions and installer
+// URLs come from app_versions.json, which main.go has just written; with
+// refresh, or when that file doesn't exist yet, every app's manifest is
+// fetched instead, up to concurrency at a time.
+func fetchAppsData(refresh bool, concurrency int) (*appsJSON, error) {
+	resp, err := http.Get(appsJSONURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch apps.json: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch apps.json (status %d)", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apps appsJSON
+	if err := json.Unmarshal(body, &apps); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	if !refresh {
+		versions, err := loadLocalVersions()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", versionsJSON, err)
+		}
+		if versions != nil {
+			for i := range apps.Apps {
+				if v, ok := versions[apps.Apps[i].Slug]; ok {
+					apps.Apps[i].Version = v.Version
+					apps.Apps[i].InstallerURL = v.InstallerURL
+				}
+			}
+			return &apps, nil
+		}
+		fmt.Printf("⚠️  Warning: %s not found, fetching every app's manifest\n", versionsJSON)
+	}
+
+	fetchAppVersions(apps.Apps, concurrency)
+
+	return &apps, nil
+}
+
+// saveAppsMetadata caches the fetched app metadata so the page can still be
+// generated when apps.json can't be fetched. The file is only rewritten when
+// the metadata changed, to keep hourly runs from committing timestamp churn.
+func saveAppsMetadata(apps *appsJSON) error {
+	metadata := datafile.AppsMetadata{
+		SchemaVersion:    schema.Current(schema.AppsMetadata),
+		GeneratorVersion: buildinfo.GeneratorVersion(),
+		LastUpdated:      now().UTC().Format(time.RFC3339),
+		Apps:             make([]datafile.AppMetadata, 0, len(apps.Apps)),
+	}
+	for _, app := range apps.Apps {
+		metadata.Apps = append(metadata.Apps, datafile.AppMetadata{
+			Slug:        app.Slug,
+			Name:        app.Name,
+			Platform:    app.Platform,
+			Description: app.Description,
+			Categories:  app.Categories,
+		})
+	}
+
+	if existing, err := loadAppsMetadata(); err == nil && reflect.DeepEqual(existing.Apps, metadata.Apps) {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal apps metadata: %w", err)
+	}
+
+	if err := os.WriteFile(appsMetadataJSON, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", appsMetadataJSON, err)
+	}
+
+	return nil
+}
+
+func loadAppsMetadata() (*datafile.AppsMetadata, error) {
+	data, err := os.ReadFile(appsMetadataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppsMetadata, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata datafile.AppsMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// loadCachedAppsData rebuilds the app list from the cached metadata, taking
+// versions and installer URLs from app_versions.json when it's available
+func loadCachedAppsData() (*appsJSON, error) {
+	metadata, err := loadAppsMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, _ := loadLocalVersions()
+
+	apps := &appsJSON{Apps: make([]appData, 0, len(metadata.Apps))}
+	for _, meta := range metadata.Apps {
+		app := appData{
+			Name:        meta.Name,
+			Slug:        meta.Slug,
+			Platform:    meta.Platform,
+			Description: meta.Description,
+			Categories:  meta.Categories,
+		}
+		if v, ok := versions[meta.Slug]; ok {
+			app.Version = v.Version
+			app.InstallerURL = v.InstallerURL
+		}
+		apps.Apps = append(apps.Apps, app)
+	}
+
+	return apps, nil
+}
+
+// localVersion is an app's latest version as main.go recorded it
+type localVersion struct {
+	Version      string `json:"version"`
+	InstallerURL string `json:"installerUrl"`
+}
+
+// loadLocalVersions reads the latest version and installer URL of each app
+// from app_versions.json by slug; it returns nil when the file doesn't exist
+func loadLocalVersions() (map[string]localVersion, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var file datafile.AppVersions
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]localVersion, len(file.Apps))
+	for _, app := range file.Apps {
+		versions[app.Slug] = localVersion{Version: app.Version, InstallerURL: app.InstallerURL}
+	}
+
+	return versions, nil
+}
+
+// loadInstallerAvailability computes per-app availability over the last
+// uptimeWindowDays of installer_uptime.jsonl
+func loadInstallerAvailability() (map[string]*installerAvailability, error) {
+	availability := make(map[string]*installerAvailability)
+
+	file, err := os.Open(installerUptime)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return availability, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	cutoff := now().AddDate(0, 0, -uptimeWindowDays)
+	ok := make(map[string]int)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var probe datafile.ProbeResult
+		if err := json.Unmarshal(scanner.Bytes(), &probe); err != nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, probe.Time)
+		if err != nil || t.Before(cutoff) {
+			continue
+		}
+
+		a := availability[probe.Slug]
+		if a == nil {
+			a = &installerAvailability{}
+			availability[probe.Slug] = a
+		}
+		a.Checks++
+		if probe.OK {
+			ok[probe.Slug]++
+		}
+		// Probes are appended in time order
+		a.LastOK = probe.OK
+		a.LastChecked = probe.Time
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for slug, a := range availability {
+		a.Percent = math.Round(float64(ok[slug])/float64(a.Checks)*1000) / 10
+	}
+
+	return availability, nil
+}
+
+// loadPackageParity reads the winget and Chocolatey matches by slug
+func loadPackageParity() (map[string]*appPackages, error) {
+	packages := make(map[string]*appPackages)
+
+	data, err := os.ReadFile(packageParity)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return packages, nil
+		}
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.PackageParity, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var parity datafile.PackageParity
+	if err := json.Unmarshal(data, &parity); err != nil {
+		return nil, err
+	}
+	for _, app := range parity.Apps {
+		if app.Winget != nil || app.Chocolatey != nil {
+			packages[app.Slug] = &appPackages{Winget: app.Winget, Chocolatey: app.Chocolatey}
+		}
+	}
+
+	return packages, nil
+}
+
+// loadUpstreamLag reads the vendor versions and lag by slug
+func loadUpstreamLag() (map[string]*appUpstream, error) {
+	lag := make(map[string]*appUpstream)
+
+	data, err := os.ReadFile(upstreamLag)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lag, nil
+		}
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.UpstreamLag, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var file datafile.UpstreamLag
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for _, app := range file.Apps {
+		lag[app.Slug] = &appUpstream{
+			Version:     app.UpstreamVersion,
+			Source:      app.Source,
+			ReleasedAt:  app.ReleasedAt,
+			Status:      app.Status,
+			BehindSince: app.BehindSince,
+			LagDays:     app.LagDays,
+		}
+	}
+
+	return lag, nil
+}
+
+// loadManifestFlags reads the manifest flags in app_versions.json by slug
+func loadManifestFlags() (map[string]*datafile.ManifestFlags, error) {
+	flags := make(map[string]*datafile.ManifestFlags)
+
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return flags, nil
+		}
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+	for _, app := range versions.Apps {
+		if app.Flags != nil {
+			flags[app.Slug] = app.Flags
+		}
+	}
+
+	return flags, nil
+}
+
+// loadWeeklyUpdates counts the version bumps in version_history.json per
+// week, from the first recorded bump through the current week. A bump
+// recorded twice (e.g. by both main.go and build_history.go) counts once.
+func loadWeeklyUpdates() (*weeklyUpdates, error) {
+	updates := &weeklyUpdates{Weeks: []string{}, Updates: []int{}}
+
+	data, err := os.ReadFile(versionHistory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return updates, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.VersionHistory, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var history datafile.VersionHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[time.Time]int)
+	seen := make(map[string]bool)
+	var first time.Time
+	for _, change := range history.Changes {
+		if change.OldVersion == "" || change.NewVersion == "" {
+			continue // New or removed app, already shown by the growth chart
+		}
+		key := change.Slug + "|" + change.OldVersion + "|" + change.NewVersion
+		if seen[key] {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, change.Date)
+		if err != nil {
+			continue
+		}
+		seen[key] = true
+		week := weekStart(t)
+		counts[week]++
+		if first.IsZero() || week.Before(first) {
+			first = week
+		}
+	}
+	if first.IsZero() {
+		return updates, nil
+	}
+
+	// Include empty weeks so quiet periods show as gaps
+	for week := first; !week.After(weekStart(now())); week = week.AddDate(0, 0, 7) {
+		updates.Weeks = append(updates.Weeks, week.Format("2006-01-02"))
+		updates.Updates = append(updates.Updates, counts[week])
+	}
+
+	return updates, nil
+}
+
+// loadWeeklyLatency groups the collection latencies main.go records in
+// run_stats.json by the week the version bump was detected
+func loadWeeklyLatency() (*weeklyLatency, error) {
+	latency := &weeklyLatency{Weeks: []string{}, Median: []float64{}, P95: []float64{}, Samples: []int{}}
+
+	data, err := os.ReadFile(runStatsJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return latency, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.RunStats, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats datafile.RunStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+
+	hours := make(map[time.Time][]float64)
+	for _, entry := range stats.CollectionLatency {
+		t, err := time.Parse(time.RFC3339, entry.DetectedAt)
+		if err != nil {
+			continue
+		}
+		week := weekStart(t)
+		hours[week] = append(hours[week], entry.Seconds/3600)
+	}
+
+	weeks := make([]time.Time, 0, len(hours))
+	for week := range hours {
+		weeks = append(weeks, week)
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Before(weeks[j]) })
+
+	for _, week := range weeks {
+		samples := hours[week]
+		sort.Float64s(samples)
+		latency.Weeks = append(latency.Weeks, week.Format("2006-01-02"))
+		latency.Median = append(latency.Median, roundTenth(percentile(samples, 0.5)))
+		latency.P95 = append(latency.P95, roundTenth(percentile(samples, 0.95)))
+		latency.Samples = append(latency.Samples, len(samples))
+	}
+
+	return latency, nil
+}
+
+// percentile returns the nearest-rank percentile p (0-1) of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// loadPipelineHealth reads the run reports: the tracker's runs in
+// run_stats.json and, since the collectors stamp every entry they write, the
+// newest security info entry of each platform. A collector with no apps
+// waiting for security info is healthy however long ago it last ran.
+func loadPipelineHealth(at time.Time) (*pipelineHealth, error) {
+	health := &pipelineHealth{Status: healthGreen, Pipelines: []pipelineStatus{}}
+
+	var stats datafile.RunStats
+	if err := readDataFile(runStatsJSON, schema.RunStats, &stats); err != nil {
+		return nil, err
+	}
+
+	// Without run_stats.json (e.g. a fresh checkout) the tracker isn't shown
+	tracker := pipelineStatus{Name: "Growth tracking", Status: healthRed, Detail: "No successful run recorded"}
+	for i := len(stats.Runs) - 1; i >= 0; i-- {
+		if !stats.Runs[i].Success {
+			continue
+		}
+		started, err := time.Parse(time.RFC3339, stats.Runs[i].StartedAt)
+		if err != nil {
+			continue
+		}
+		tracker.LastSuccess = started.UTC().Format(time.RFC3339)
+		tracker.Status = ageStatus(at.Sub(started), trackerAmberAfter, trackerRedAfter)
+		tracker.Detail = "Runs hourly"
+		break
+	}
+	if len(stats.Runs) > 0 {
+		latest := stats.Runs[len(stats.Runs)-1]
+		health.Failures = latest.Failures
+		if !latest.Success {
+			tracker.Status = worstStatus(tracker.Status, healthAmber)
+			tracker.Detail = "Latest run failed"
+		}
+		health.Pipelines = append(health.Pipelines, tracker)
+	}
+
+	var versions datafile.AppVersions
+	if err := readDataFile(versionsJSON, schema.AppVersions, &versions); err != nil {
+		return nil, err
+	}
+	var security datafile.SecurityInfo
+	if err := readDataFile(securityInfoJSON, schema.SecurityInfo, &security); err != nil {
+		return nil, err
+	}
+
+	collected := make(map[string]string)
+	newest := make(map[string]time.Time)
+	for _, app := range security.Apps {
+		collected[app.Slug] = app.Version
+		platform := app.Slug[strings.LastIndex(app.Slug, "/")+1:]
+		if t, err := time.Parse(time.RFC3339, app.LastUpdated); err == nil && t.After(newest[platform]) {
+			newest[platform] = t
+		}
+	}
+	pending := make(map[string]int)
+	for _, app := range versions.Apps {
+		if app.InstallerURL == "" {
+			continue // The collectors skip apps without an installer
+		}
+		version, exists := collected[app.Slug]
+		switch {
+		case !exists:
+			health.Missing++
+			pending[app.Platform]++
+		case version != app.Version:
+			health.Outdated++
+			pending[app.Platform]++
+		}
+	}
+
+	for _, collector := range []struct{ platform, name string }{{"darwin", "macOS collection"}, {"windows", "Windows collection"}} {
+		status := pipelineStatus{Name: collector.name, Status: healthGreen, Detail: "Up to date"}
+		last, ok := newest[collector.platform]
+		if ok {
+			status.LastSuccess = last.UTC().Format(time.RFC3339)
+		}
+		if waiting := pending[collector.platform]; waiting > 0 {
+			status.Detail = fmt.Sprintf("%d %s waiting for security info", waiting, pluralize(waiting, "app", "apps"))
+			status.Status = healthRed
+			if ok {
+				status.Status = ageStatus(at.Sub(last), collectorAmberAfter, collectorRedAfter)
+			}
+		}
+		health.Pipelines = append(health.Pipelines, status)
+	}
+
+	for _, pipeline := range health.Pipelines {
+		health.Status = worstStatus(health.Status, pipeline.Status)
+	}
+	return health, nil
+}
+
+// ageStatus rates how long ago a pipeline last succeeded
+func ageStatus(age, amberAfter, redAfter time.Duration) string {
+	switch {
+	case age >= redAfter:
+		return healthRed
+	case age >= amberAfter:
+		return healthAmber
+	}
+	return healthGreen
+}
+
+func worstStatus(a, b string) string {
+	rank := map[string]int{healthGreen: 0, healthAmber: 1, healthRed: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// readDataFile decodes a versioned data file into v, leaving v empty when the
+// file doesn't exist
+func readDataFile(path, kind string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	data, err = schema.Upgrade(kind, data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// catalogChange is a version_history.json entry in the compact form the
+// dashboard's time slider replays; the name and platform are only kept for
+// removals, since removed apps are missing from the current app list
+type catalogChange struct {
+	Date       string `json:"d"` // YYYY-MM-DD
+	Slug       string `json:"s"`
+	Name       string `json:"n,omitempty"`
+	Platform   string `json:"p,omitempty"`
+	OldVersion string `json:"o"`
+	NewVersion string `json:"v"`
+}
+
+// catalogHistory lets the page reconstruct the catalog as of a past date:
+// Changes are undone newest first, and FirstSeen (slug to YYYY-MM-DD, from
+// build_history.go) drops apps that didn't exist yet before the history starts
+type catalogHistory struct {
+	Changes   []catalogChange   `json:"changes"`
+	FirstSeen map[string]string `json:"firstSeen"`
+}
+
+// loadCatalogHistory reads version_history.json, oldest change first, and
+// app_first_seen.json when it exists. A change recorded twice (e.g. by both
+// main.go and build_history.go) is kept once, at its earliest date.
+func loadCatalogHistory() (*catalogHistory, error) {
+	history := &catalogHistory{Changes: []catalogChange{}, FirstSeen: map[string]string{}}
+
+	data, err := os.ReadFile(versionHistory)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		data, err = schema.Upgrade(schema.VersionHistory, data)
+		if err != nil {
+			return nil, err
+		}
+		var file datafile.VersionHistory
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+
+		seen := make(map[string]int)
+		for _, change := range file.Changes {
+			if len(change.Date) < len("2006-01-02") {
+				continue
+			}
+			c := catalogChange{
+				Date:       change.Date[:len("2006-01-02")],
+				Slug:       change.Slug,
+				OldVersion: change.OldVersion,
+				NewVersion: change.NewVersion,
+			}
+			if c.NewVersion == "" {
+				c.Name, c.Platform = change.AppName, change.Platform
+			}
+			key := c.Slug + "|" + c.OldVersion + "|" + c.NewVersion
+			if i, ok := seen[key]; ok {
+				if c.Date < history.Changes[i].Date {
+					history.Changes[i].Date = c.Date
+				}
+				continue
+			}
+			seen[key] = len(history.Changes)
+			history.Changes = append(history.Changes, c)
+		}
+		sort.SliceStable(history.Changes, func(i, j int) bool {
+			return history.Changes[i].Date < history.Changes[j].Date
+		})
+	}
+
+	data, err = os.ReadFile(firstSeenJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.FirstSeen, data)
+	if err != nil {
+		return nil, err
+	}
+	var firstSeen datafile.FirstSeen
+	if err := json.Unmarshal(data, &firstSeen); err != nil {
+		return nil, err
+	}
+	for _, app := range firstSeen.Apps {
+		if len(app.FirstSeen) >= len("2006-01-02") {
+			history.FirstSeen[app.Slug] = app.FirstSeen[:len("2006-01-02")]
+		}
+	}
+
+	return history, nil
+}
+
+// weekStart returns midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{Apps: []datafile.AppSecurityInfo{}}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+func mergeSecurityInfo(apps *appsJSON, security *datafile.SecurityInfo) {
+	// Create a map of security info by slug
+	securityMap := make(map[string]datafile.AppSecurityInfo)
+	for _, sec := range security.Apps {
+		securityMap[sec.Slug] = sec
+	}
+
+	// Merge security info into apps (both macOS and Windows)
+	for i := range apps.Apps {
+		if sec, exists := securityMap[apps.Apps[i].Slug]; exists {
+			securityData := &appSecurityInfoData{
+				Sha256:       sec.Sha256,
+				Cdhash:       sec.Cdhash,
+				SigningID:    sec.SigningID,
+				TeamID:       sec.TeamID,
+				Publisher:    sec.Publisher,
+				Issuer:       sec.Issuer,
+				SerialNumber: sec.SerialNumber,
+				Thumbprint:   sec.Thumbprint,
+				Timestamp:    sec.Timestamp,
+				SigStatus:    sec.SignatureStatus,
+				SigDetail:    sec.SignatureDetail,
+				DownloadTLS:  sec.DownloadTLS,
+				Arch:         sec.Arch,
+				Slices:       sec.Architectures,
+				LastUpdated:  sec.LastUpdated,
+
+				Sandboxed:       sec.Sandboxed,
+				HardenedRuntime: sec.HardenedRuntime,
+				SignatureFormat: sec.SignatureFormat,
+				SigningTime:     sec.SigningTime,
+				LeafCertificate: sec.LeafCertificate,
+				Stapled:         sec.Stapled,
+				NotarizedAt:     sec.NotarizedAt,
+				BundleID:        sec.BundleID,
+				RequiresRosetta: sec.RequiresRosetta,
+
+				Persistence:  sec.Persistence,
+				PrivacyUsage: sec.PrivacyUsage,
+
+				CertChain: sec.CertChain,
+			}
+
+			for _, variant := range sec.Variants {
+				securityData.Variants = append(securityData.Variants, appSecurityInfoData{
+					Sha256: variant.Sha256,
+					Cdhash: variant.Cdhash,
+					Arch:   variant.Arch,
+					Slices: variant.Architectures,
+				})
+			}
+
+			// If this is a suite with multiple apps, include them
+			if len(sec.Apps) > 0 {
+				securityData.Apps = make([]appSecurityInfoData, len(sec.Apps))
+				for j, app := range sec.Apps {
+					securityData.Apps[j] = appSecurityInfoData{
+						Name:         app.Name,
+						Sha256:       app.Sha256,
+						Cdhash:       app.Cdhash,
+						SigningID:    app.SigningID,
+						TeamID:       app.TeamID,
+						Publisher:    app.Publisher,
+						Issuer:       app.Issuer,
+						SerialNumber: app.SerialNumber,
+						Thumbprint:   app.Thumbprint,
+						Timestamp:    app.Timestamp,
+						SigStatus:    app.SignatureStatus,
+						SigDetail:    app.SignatureDetail,
+						LastUpdated:  app.LastUpdated,
+
+						BundleID:  app.BundleID,
+						CertChain: app.CertChain,
+					}
+				}
+			}
+
+			apps.Apps[i].SecurityInfo = securityData
+		}
+	}
+}
+
+// fetchAppVersions fills in the latest version and installer URL of each app
+// from its manifest, using up to concurrency workers. Each slug is fetched
+// once however often it's listed; an app whose manifest can't be fetched is
+// left without a version.
+func fetchAppVersions(apps []appData, concurrency int) {
+	type manifest struct {
+		version, installerURL string
+	}
+
+	var (
+		mu        sync.Mutex
+		manifests = make(map[string]manifest)
+	)
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for slug := range jobs {
+				version, installerURL, err := fetchAppVersionAndURL(slug)
+				if err != nil {
+					continue
+				}
+				mu.Lock()
+				manifests[slug] = manifest{version, installerURL}
+				mu.Unlock()
+			}
+		}()
+	}
+	queued := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		if !queued[app.Slug] {
+			queued[app.Slug] = true
+			jobs <- app.Slug
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := range apps {
+		m := manifests[apps[i].Slug]
+		apps[i].Version = m.version
+		apps[i].InstallerURL = m.installerURL
+	}
+}
+
+func fetchAppVersionAndURL(slug string) (version string, installerURL string, err error) {
+	// Construct URL: slug format is "app-name/platform", we need "app-name/platform.json"
+	url := fmt.Sprintf("%s/%s.json", appBaseURL, slug)
+
+	body, err := ghClient.Get(url)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch version file: %w", err)
+	}
+
+	var versionData struct {
+		Versions []struct {
+			Version      string `json:"version"`
+			InstallerURL string `json:"installer_url"`
+		} `json:"versions"`
+	}
+	if err := json.Unmarshal(body, &versionData); err != nil {
+		return "", "", fmt.Errorf("failed to parse version JSON: %w", err)
+	}
+
+	if len(versionData.Versions) == 0 {
+		return "", "", fmt.Errorf("no versions found")
+	}
+
+	// Return the first (latest) version and installer URL
+	return versionData.Versions[0].Version, versionData.Versions[0].InstallerURL, nil
+}
+
+// now returns the current time, or SOURCE_DATE_EPOCH when it is set so the
+// page can be reproduced byte for byte (used by the golden-file tests)
+func now() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0)
+	}
+	return time.Now()
+}
+
+// Main runs fleet-tracker html with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("html", flag.ExitOnError)
+	refresh := fs.Bool("refresh", false, "fetch every app's manifest from GitHub instead of reading versions from "+versionsJSON)
+	concurrency := fs.Int("concurrency", 8, "number of app manifests fetched in parallel with --refresh")
+	common.Register(fs)
+	fs.Parse(args)
+
+	ghClient.SetToken(common.GitHubToken)
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	httpcache.InstallFromEnv()
+	if err := vcr.InstallFromEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateHTML(*refresh, *concurrency); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generateHTMLContent(data *csvData, apps *appsJSON, updates *weeklyUpdates, latency *weeklyLatency, health *pipelineHealth, projected []milestone.Milestone, history *catalogHistory, analyticsSnippet string) string {
+	dataJSON, _ := json.MarshalIndent(data, "        ", "  ")
+	dataJSONStr := string(dataJSON)
+
+	updatesJSON, _ := json.MarshalIndent(updates, "        ", "  ")
+	updatesJSONStr := string(updatesJSON)
+
+	latencyJSON, _ := json.MarshalIndent(latency, "        ", "  ")
+	latencyJSONStr := string(latencyJSON)
+
+	healthJSON, _ := json.MarshalIndent(health, "        ", "  ")
+	healthJSONStr := string(healthJSON)
+
+	if projected == nil {
+		projected = []milestone.Milestone{}
+	}
+	milestonesJSON, _ := json.MarshalIndent(projected, "        ", "  ")
+	milestonesJSONStr := string(milestonesJSON)
+
+	historyJSON, _ := json.Marshal(history)
+	historyJSONStr := string(historyJSON)
+
+	// og-image.png is rendered from the growth data by generate_og_image.go
+	// when the site is deployed; the date makes link previews refetch it
+	ogImageURL := "https://fmalibrary.com/og-image.png"
+	if len(data.Dates) > 0 {
+		ogImageURL += "?v=" + data.Dates[len(data.Dates)-1]
+	}
+
+	appsJSONBytes, _ := json.MarshalIndent(apps.Apps, "            ", "  ")
+	appsJSONStr := string(appsJSONBytes)
+
+	// Generate timestamp for when this HTML was created (in CST)
+	cstLocation, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		// Fallback to UTC if CST location can't be loaded
+		cstLocation = time.UTC
+	}
+	lastUpdated := now().In(cstLocation).Format("January 2, 2006 at 3:04 PM MST")
+
+	return `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <meta name="generator" content="fleet-apps-growth-tracker ` + buildinfo.GeneratorVersion() + `">
+    <meta name="description" content="Track the growth of Fleet-maintained apps over time. View app versions, download installers, and explore the expanding library of macOS and Windows applications.">
+    
+    <!-- Open Graph / Facebook / LinkedIn -->
+    <meta property="og:type" content="website">
+    <meta property="og:url" content="https://fmalibrary.com/">
+    <meta property="og:title" content="Fleet Maintained Apps Library">
+    <meta property="og:description" content="Track the growth of Fleet-maintained apps over time. View app versions, download installers, and explore the expanding library of macOS and Windows applications.">
+    <meta property="og:image" content="` + ogImageURL + `">
+    <meta property="og:image:secure_url" content="` + ogImageURL + `">
+    <meta property="og:image:type" content="image/png">
+    <meta property="og:image:width" content="1920">
+    <meta property="og:image:height" content="1080">
+    <meta property="og:image:alt" content="Fleet Maintained Apps Library - Growth tracking dashboard">
+    <meta property="og:site_name" content="Fleet Maintained Apps Library">
+    <meta property="og:locale" content="en_US">
+    
+    <!-- Twitter -->
+    <meta name="twitter:card" content="summary_large_image">
+    <meta name="twitter:url" content="https://fmalibrary.com/">
+    <meta name="twitter:title" content="Fleet Maintained Apps Library">
+    <meta name="twitter:description" content="Track the growth of Fleet-maintained apps over time. View app versions, download installers, and explore the expanding library of macOS and Windows applications.">
+    <meta name="twitter:image" content="` + ogImageURL + `">
+    <meta name="twitter:image:alt" content="Fleet Maintained Apps Library - Growth tracking dashboard">
+    
+    <!-- RSS Feed -->
+    <link rel="alternate" type="application/rss+xml" title="Fleet Maintained Apps - Version Updates" href="https://fmalibrary.com/feed.xml">
+    <link rel="alternate" type="application/rss+xml" title="Fleet Maintained Apps - Security Advisories" href="https://fmalibrary.com/advisory.xml">
+    
+    <!-- Favicon (Swan Emoji) -->
+    <link rel="icon" href="data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' viewBox='0 0 100 100'%3E%3Ctext y='0.9em' font-size='90'%3E🦢%3C/text%3E%3C/svg%3E">
+    <link rel="apple-touch-icon" href="data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' viewBox='0 0 100 100'%3E%3Ctext y='0.9em' font-size='90'%3E🦢%3C/text%3E%3C/svg%3E">
+    
+    <title>Fleet Maintained Apps Growth</title>
+    <script src="https://cdn.jsdelivr.net/npm/chart.js@4.4.0/dist/chart.umd.min.js"></script>
+    <script src="https://cdn.jsdelivr.net/npm/chartjs-adapter-date-fns@3.0.0/dist/chartjs-adapter-date-fns.bundle.min.js"></script>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            margin: 0;
+            padding: 20px;
+            background: #f5f5f5;
+        }
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+            background: white;
+            padding: 30px;
+            border-radius: 8px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            position: relative;
+        }
+        .header-section {
+            display: flex;
+            justify-content: space-between;
+            align-items: flex-start;
+            margin-bottom: 30px;
+        }
+        .header-content {
+            flex: 1;
+        }
+        h1 {
+            color: #1e293b;
+            margin-bottom: 10px;
+            margin-top: 0;
+        }
+        .subtitle {
+            color: #64748b;
+            margin-bottom: 0;
+        }
+        .chart-container {
+            position: relative;
+            height: 450px;
+            margin-bottom: 40px;
+        }
+        .chart-container.updates-chart {
+            height: 300px;
+        }
+        .chart-mode-toggle {
+            display: flex;
+            justify-content: flex-end;
+            gap: 8px;
+            margin-bottom: 10px;
+        }
+        .chart-mode-button {
+            padding: 6px 12px;
+            border: 1px solid #e2e8f0;
+            border-radius: 6px;
+            background: #f8fafc;
+            color: #475569;
+            font-size: 13px;
+            cursor: pointer;
+        }
+        .chart-mode-button.active {
+            background: #2563eb;
+            border-color: #2563eb;
+            color: white;
+        }
+        .stats {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(200px, 1fr));
+            gap: 20px;
+            margin-top: 30px;
+            padding-top: 30px;
+            border-top: 2px solid #e2e8f0;
+        }
+        .stat-card {
+            background: #f8fafc;
+            padding: 20px;
+            border-radius: 6px;
+            border-left: 4px solid #2563eb;
+            cursor: pointer;
+            transition: all 0.2s ease;
+        }
+        .stat-card:hover {
+            background: #f1f5f9;
+            transform: translateY(-2px);
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+        }
+        .stat-card.active {
+            background: #eff6ff;
+            border-left-color: #1d4ed8;
+            box-shadow: 0 2px 4px rgba(37, 99, 235, 0.2);
+        }
+        .stat-card.clickable {
+            cursor: pointer;
+        }
+        .stat-card:not(.clickable) {
+            cursor: default;
+        }
+        .stat-value {
+            font-size: 32px;
+            font-weight: bold;
+            color: #1e293b;
+            margin-bottom: 5px;
+        }
+        .stat-label {
+            color: #64748b;
+            font-size: 14px;
+        }
+        .footer {
+            margin-top: 40px;
+            padding-top: 20px;
+            border-top: 2px solid #e2e8f0;
+            text-align: center;
+            color: #64748b;
+            font-size: 14px;
+        }
+        .freshness-section {
+            margin-top: 50px;
+            padding-top: 40px;
+            border-top: 2px solid #e2e8f0;
+        }
+        .freshness-section h2 {
+            color: #1e293b;
+            margin-bottom: 10px;
+            font-size: 24px;
+        }
+        .freshness-note {
+            color: #64748b;
+            font-size: 14px;
+            margin-bottom: 16px;
+        }
+        .freshness-list {
+            list-style: none;
+            padding: 0;
+            margin: 0;
+        }
+        .freshness-item {
+            display: flex;
+            justify-content: space-between;
+            gap: 12px;
+            padding: 10px 12px;
+            border-bottom: 1px solid #e2e8f0;
+            cursor: pointer;
+        }
+        .freshness-item:hover {
+            background: #f8fafc;
+        }
+        .freshness-versions {
+            color: #64748b;
+            font-size: 14px;
+        }
+        .freshness-lag {
+            color: #b45309;
+            font-weight: 600;
+            white-space: nowrap;
+        }
+        .health-section {
+            margin-top: 50px;
+            padding-top: 40px;
+            border-top: 2px solid #e2e8f0;
+        }
+        .health-section h2 {
+            color: #1e293b;
+            margin-bottom: 10px;
+            font-size: 24px;
+        }
+        .health-list {
+            list-style: none;
+            padding: 0;
+            margin: 0 0 12px;
+        }
+        .health-item {
+            display: flex;
+            justify-content: space-between;
+            gap: 12px;
+            padding: 10px 12px;
+            border-bottom: 1px solid #e2e8f0;
+        }
+        .health-detail,
+        .health-time,
+        .health-note {
+            color: #64748b;
+            font-size: 14px;
+        }
+        .health-time {
+            white-space: nowrap;
+        }
+        .health-dot {
+            display: inline-block;
+            width: 10px;
+            height: 10px;
+            border-radius: 50%;
+            margin-right: 8px;
+            background: #94a3b8;
+        }
+        .health-dot.green {
+            background: #16a34a;
+        }
+        .health-dot.amber {
+            background: #f59e0b;
+        }
+        .health-dot.red {
+            background: #dc2626;
+        }
+        .apps-section {
+            margin-top: 50px;
+            padding-top: 40px;
+            border-top: 2px solid #e2e8f0;
+        }
+        .apps-header {
+            margin-bottom: 30px;
+        }
+        .apps-header h2 {
+            color: #1e293b;
+            margin-bottom: 10px;
+            font-size: 24px;
+        }
+        .apps-count {
+            color: #64748b;
+            font-size: 16px;
+        }
+        .apps-flag-filter {
+            margin-top: 12px;
+            padding: 6px 10px;
+            border: 1px solid #e2e8f0;
+            border-radius: 6px;
+            background: white;
+            color: #1e293b;
+            font-size: 14px;
+        }
+        .apps-as-of {
+            display: flex;
+            flex-wrap: wrap;
+            align-items: center;
+            justify-content: center;
+            gap: 10px;
+            margin-top: 12px;
+            color: #475569;
+            font-size: 14px;
+        }
+        .apps-as-of input[type="range"] {
+            width: min(420px, 100%);
+        }
+        .apps-as-of button {
+            padding: 4px 10px;
+            border: 1px solid #e2e8f0;
+            border-radius: 6px;
+            background: white;
+            color: #1e293b;
+            cursor: pointer;
+        }
+        .apps-as-of-note {
+            flex-basis: 100%;
+            text-align: center;
+            color: #94a3b8;
+            font-size: 12px;
+        }
+        .apps-grid {
+            display: grid;
+            grid-template-columns: repeat(auto-fill, minmax(200px, 1fr));
+            gap: 20px;
+            margin-top: 20px;
+        }
+        .app-card {
+            background: #f8fafc;
+            border: 1px solid #e2e8f0;
+            border-radius: 8px;
+            padding: 20px;
+            transition: all 0.2s ease;
+            cursor: pointer;
+            display: flex;
+            flex-direction: column;
+            align-items: center;
+            text-align: center;
+            color: inherit;
+        }
+        .app-card:hover {
+            transform: translateY(-4px);
+            box-shadow: 0 8px 16px rgba(0,0,0,0.1);
+            border-color: #2563eb;
+        }
+        .app-icon {
+            width: 64px;
+            height: 64px;
+            border-radius: 12px;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+            margin-bottom: 12px;
+            box-shadow: 0 2px 8px rgba(0,0,0,0.15);
+            overflow: hidden;
+            background: #f8fafc;
+        }
+        .app-icon img {
+            width: 100%;
+            height: 100%;
+            object-fit: contain;
+        }
+        .app-name {
+            font-weight: 600;
+            color: #1e293b;
+            font-size: 16px;
+            margin-bottom: 8px;
+            line-height: 1.3;
+        }
+        .app-platform {
+            display: inline-block;
+            padding: 4px 8px;
+            border-radius: 4px;
+            font-size: 12px;
+            font-weight: 500;
+            margin-top: 8px;
+        }
+        .app-platform.darwin {
+            background: #dbeafe;
+            color: #1e40af;
+        }
+        .app-platform.windows {
+            background: #dbeafe;
+            color: #0284c7;
+        }
+        .signature-indicator {
+            display: inline-block;
+            width: 10px;
+            height: 10px;
+            border-radius: 50%;
+            margin-left: 6px;
+            vertical-align: middle;
+        }
+        .signature-indicator.green {
+            background: #16a34a;
+        }
+        .signature-indicator.yellow {
+            background: #eab308;
+        }
+        .signature-indicator.red {
+            background: #dc2626;
+        }
+        .app-version {
+            font-size: 13px;
+            color: #64748b;
+            line-height: 1.4;
+            margin-top: 8px;
+            font-weight: 500;
+        }
+        .apps-grid.hidden {
+            display: none;
+        }
+        /* Modal Styles */
+        .modal {
+            display: none !important;
+            position: fixed;
+            z-index: 1000;
+            left: 0;
+            top: 0;
+            width: 100%;
+            height: 100%;
+            overflow: auto;
+            background-color: rgba(0, 0, 0, 0.5);
+            animation: fadeIn 0.2s ease;
+            visibility: hidden;
+            opacity: 0;
+        }
+        .modal.show {
+            display: flex !important;
+            align-items: center;
+            justify-content: center;
+            visibility: visible;
+            opacity: 1;
+        }
+        @keyframes fadeIn {
+            from { opacity: 0; }
+            to { opacity: 1; }
+        }
+        .modal-content {
+            background-color: white;
+            margin: auto;
+            padding: 0;
+            border-radius: 12px;
+            width: 90%;
+            max-width: 600px;
+            max-height: 90vh;
+            overflow-y: auto;
+            box-shadow: 0 20px 60px rgba(0, 0, 0, 0.3);
+            animation: slideUp 0.3s ease;
+        }
+        @keyframes slideUp {
+            from {
+                transform: translateY(50px);
+                opacity: 0;
+            }
+            to {
+                transform: translateY(0);
+                opacity: 1;
+            }
+        }
+        .modal-header {
+            padding: 24px;
+            border-bottom: 1px solid #e2e8f0;
+            display: flex;
+            align-items: center;
+            gap: 16px;
+        }
+        .modal-icon {
+            width: 64px;
+            height: 64px;
+            border-radius: 12px;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+            box-shadow: 0 2px 8px rgba(0,0,0,0.15);
+            overflow: hidden;
+            background: #f8fafc;
+            flex-shrink: 0;
+        }
+        .modal-icon img {
+            width: 100%;
+            height: 100%;
+            object-fit: contain;
+        }
+        .modal-title-section {
+            flex: 1;
+        }
+        .modal-title {
+            font-size: 24px;
+            font-weight: 600;
+            color: #1e293b;
+            margin: 0 0 4px 0;
+        }
+        .modal-platform {
+            display: inline-block;
+            padding: 4px 12px;
+            border-radius: 6px;
+            font-size: 13px;
+            font-weight: 500;
+            margin-top: 4px;
+        }
+        .modal-platform.darwin {
+            background: #dbeafe;
+            color: #1e40af;
+        }
+        .modal-platform.windows {
+            background: #dbeafe;
+            color: #0284c7;
+        }
+        .modal-close {
+            color: #64748b;
+            font-size: 28px;
+            font-weight: 300;
+            cursor: pointer;
+            line-height: 1;
+            padding: 0;
+            background: none;
+            border: none;
+            width: 32px;
+            height: 32px;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+            border-radius: 6px;
+            transition: all 0.2s ease;
+        }
+        .modal-close:hover {
+            background: #f1f5f9;
+            color: #1e293b;
+        }
+        .modal-body {
+            padding: 24px;
+        }
+        .modal-footer {
+            padding: 16px 24px;
+            border-top: 1px solid #e2e8f0;
+            text-align: center;
+        }
+        .modal-footer p {
+            margin: 0;
+            color: #64748b;
+            font-size: 12px;
+        }
+        .modal-info-row {
+            margin-bottom: 20px;
+        }
+        .modal-info-label {
+            font-size: 12px;
+            font-weight: 600;
+            color: #64748b;
+            text-transform: uppercase;
+            letter-spacing: 0.5px;
+            margin-bottom: 6px;
+        }
+        .modal-info-value {
+            font-size: 16px;
+            color: #1e293b;
+            line-height: 1.6;
+        }
+        .modal-installer-link {
+            display: block;
+            padding: 12px 24px;
+            background: #2563eb;
+            color: white;
+            text-decoration: none;
+            border-radius: 6px;
+            font-weight: 500;
+            text-align: center;
+            transition: all 0.2s ease;
+            width: 100%;
+            box-sizing: border-box;
+        }
+        .modal-installer-link:hover {
+            background: #1d4ed8;
+            transform: translateY(-2px);
+            box-shadow: 0 4px 6px rgba(37, 99, 235, 0.3);
+        }
+        .modal-security-info {
+            background: #f8fafc;
+            border: 1px solid #e2e8f0;
+            border-radius: 8px;
+            padding: 16px;
+            margin-top: 8px;
+        }
+        .modal-security-item {
+            margin-bottom: 12px;
+            display: flex;
+            align-items: center;
+            gap: 8px;
+        }
+        .modal-security-item:last-child {
+            margin-bottom: 0;
+        }
+        .modal-security-label {
+            font-weight: 600;
+            color: #475569;
+            flex-shrink: 0;
+            min-width: 100px;
+            font-size: 14px;
+        }
+        .modal-security-value {
+            font-family: 'Monaco', 'Menlo', 'Courier New', monospace;
+            font-size: 13px;
+            background: white;
+            padding: 4px 8px;
+            border-radius: 4px;
+            border: 1px solid #e2e8f0;
+            color: #1e293b;
+            white-space: nowrap;
+            overflow-x: auto;
+            flex: 1;
+            min-width: 0;
+            cursor: pointer;
+            transition: all 0.2s ease;
+            position: relative;
+        }
+        .modal-security-value:hover {
+            background: #f1f5f9;
+            border-color: #2563eb;
+        }
+        .modal-security-value:active {
+            background: #e0e7ff;
+        }
+        .modal-security-value.copied {
+            background: #dcfce7;
+            border-color: #22c55e;
+        }
+        .modal-security-value::after {
+            content: 'Click to copy';
+            position: absolute;
+            bottom: 100%;
+            left: 50%;
+            transform: translateX(-50%);
+            background: #1e293b;
+            color: white;
+            padding: 4px 8px;
+            border-radius: 4px;
+            font-size: 11px;
+            white-space: nowrap;
+            opacity: 0;
+            pointer-events: none;
+            transition: opacity 0.2s ease;
+            margin-bottom: 4px;
+        }
+        .modal-security-value:hover::after {
+            opacity: 1;
+        }
+        .rss-button {
+            display: inline-flex;
+            align-items: center;
+            gap: 8px;
+            padding: 10px 20px;
+            background: #2563eb;
+            color: white;
+            text-decoration: none;
+            border-radius: 6px;
+            font-weight: 500;
+            font-size: 14px;
+            transition: all 0.2s ease;
+            flex-shrink: 0;
+        }
+        .rss-button:hover {
+            background: #1d4ed8;
+            transform: translateY(-2px);
+            box-shadow: 0 4px 6px rgba(37, 99, 235, 0.3);
+        }
+        .rss-button svg {
+            width: 18px;
+            height: 18px;
+            fill: currentColor;
+            flex-shrink: 0;
+        }
+        @media (max-width: 768px) {
+            .header-section {
+                flex-direction: column;
+                align-items: stretch;
+            }
+            .rss-button {
+                margin-top: 15px;
+                width: 100%;
+                justify-content: center;
+            }
+            .apps-grid {
+                grid-template-columns: repeat(auto-fill, minmax(150px, 1fr));
+                gap: 15px;
+            }
+            .app-card {
+                padding: 15px;
+            }
+            .app-icon {
+                width: 48px;
+                height: 48px;
+                font-size: 24px;
+            }
+        }
+    </style>
+` + analyticsSnippet + `</head>
+<body>
+    <div class="container">
+        <div class="header-section">
+            <div class="header-content">
+                <h1>Fleet-maintained app library</h1>
+                <p class="subtitle">Continuous daily tracking of the Fleet-maintained app library · <a href="changelog.html">Weekly changelog</a></p>
+            </div>
+            <a href="feed.xml" class="rss-button" title="Subscribe to version updates">
+                <svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+                    <path d="M6.503 20.752c0 1.794-1.456 3.248-3.251 3.248-1.796 0-3.252-1.454-3.252-3.248 0-1.794 1.456-3.248 3.252-3.248 1.795.001 3.251 1.454 3.251 3.248zm-6.503-12.572v4.811c6.05.062 10.96 4.966 11.022 11.009h4.817c-.062-8.71-7.118-15.758-15.839-15.82zm0-3.368c10.58.046 19.152 8.594 19.183 19.188h4.817c-.03-13.231-10.755-23.954-24-24v4.812z"/>
+                </svg>
+                Subscribe to updates
+            </a>
+        </div>
+        
+        <div class="chart-mode-toggle">
+            <button class="chart-mode-button active" data-mode="growth" onclick="setChartMode('growth')">Cumulative growth</button>
+            <button class="chart-mode-button" data-mode="yoy" onclick="setChartMode('yoy')">Year over year</button>
+        </div>
+        
+        <div class="chart-container">
+            <canvas id="cumulativeChart"></canvas>
+            <canvas id="yoyChart" style="display: none;"></canvas>
+        </div>
+        
+        <div class="chart-container updates-chart" id="updatesChartContainer">
+            <canvas id="updatesChart"></canvas>
+        </div>
+        
+        <div class="chart-container updates-chart" id="latencyChartContainer">
+            <canvas id="latencyChart"></canvas>
+        </div>
+        
+        <div class="stats" id="stats">
+            <!-- Stats will be populated by JavaScript -->
+        </div>
+        
+        <div class="freshness-section" id="freshnessSection" style="display: none;">
+            <h2>Freshness</h2>
+            <p class="freshness-note">Maintained apps furthest behind their vendor's latest release (from Sparkle appcasts, GitHub releases, winget and Chocolatey)</p>
+            <ol class="freshness-list" id="freshnessList"></ol>
+        </div>
+        
+        <div class="health-section" id="healthSection" style="display: none;">
+            <h2><span class="health-dot" id="healthDot"></span>Pipeline health</h2>
+            <ul class="health-list" id="healthList"></ul>
+            <p class="health-note" id="healthNote"></p>
+        </div>
+        
+        <div class="apps-section">
+            <div class="apps-header">
+                <h2>Fleet-maintained apps</h2>
+                <p class="apps-count"><span id="appsCount">0</span> and counting...</p>
+                <select class="apps-flag-filter" id="flagFilter" onchange="setFlagFilter(this.value)" style="display: none;">
+                    <option value="">All apps</option>
+                </select>
+                <div class="apps-as-of" id="asOfControl" style="display: none;">
+                    <label for="asOfSlider">Catalog as of <strong id="asOfLabel">today</strong></label>
+                    <input type="range" id="asOfSlider" min="0" max="0" value="0" oninput="setAsOf(Number(this.value))">
+                    <button type="button" id="asOfReset" onclick="resetAsOf()" style="display: none;">Back to today</button>
+                    <span class="apps-as-of-note" id="asOfNote"></span>
+                </div>
+            </div>
+            <div class="apps-grid" id="appsGrid">
+                <!-- Apps will be populated by JavaScript -->
+            </div>
+        </div>
+        
+        <div class="footer">
+            <p>Data source: <a href="https://github.com/fleetdm/fleet" target="_blank">fleetdm/fleet</a> | 
+            Last updated: ` + lastUpdated + `</p>
+        </div>
+    </div>
+
+    <!-- App Details Modal -->
+    <div id="appModal" class="modal">
+        <div class="modal-content">
+            <div class="modal-header">
+                <div class="modal-icon" id="modalIcon">
+                    <img id="modalIconImg" src="" alt="" onerror="handleModalIconError(this);">
+                </div>
+                <div class="modal-title-section">
+                    <h2 class="modal-title" id="modalTitle"></h2>
+                    <span class="modal-platform" id="modalPlatform"></span>
+                </div>
+                <button class="modal-close" onclick="closeModal()">&times;</button>
+            </div>
+            <div class="modal-body">
+                <div class="modal-info-row">
+                    <div class="modal-info-label">Version</div>
+                    <div class="modal-info-value" id="modalVersion"></div>
+                </div>
+                <div class="modal-info-row">
+                    <div class="modal-info-label">Description</div>
+                    <div class="modal-info-value" id="modalDescription"></div>
+                </div>
+                <div class="modal-info-row" id="modalAvailabilityRow" style="display: none;">
+                    <div class="modal-info-label">Installer Availability (30 days)</div>
+                    <div class="modal-info-value" id="modalAvailability"></div>
+                </div>
+                <div class="modal-info-row" id="modalFlagsRow" style="display: none;">
+                    <div class="modal-info-label">Fleet Defaults</div>
+                    <div class="modal-info-value" id="modalFlags"></div>
+                </div>
+                <div class="modal-info-row" id="modalPackagesRow" style="display: none;">
+                    <div class="modal-info-label">Package Managers</div>
+                    <div class="modal-info-value" id="modalPackages"></div>
+                </div>
+                <div class="modal-info-row" id="modalUpstreamRow" style="display: none;">
+                    <div class="modal-info-label">Vendor Release</div>
+                    <div class="modal-info-value" id="modalUpstream"></div>
+                </div>
+                <div class="modal-info-row" id="modalSecurityRow" style="display: none;">
+                    <div class="modal-info-label">Security Information</div>
+                    <div id="modalSecurityContainer">
+                        <!-- Single app security info (legacy) -->
+                        <div class="modal-security-info" id="modalSecuritySingle">
+                            <div class="modal-security-item">
+                                <span class="modal-security-label">SHA-256:</span>
+                                <code class="modal-security-value" id="modalSha256"></code>
+                            </div>
+                            <div class="modal-security-item">
+                                <span class="modal-security-label">CDHash:</span>
+                                <code class="modal-security-value" id="modalCdhash"></code>
+                            </div>
+                            <div class="modal-security-item">
+                                <span class="modal-security-label">Signing ID:</span>
+                                <code class="modal-security-value" id="modalSigningID"></code>
+                            </div>
+                            <div class="modal-security-item">
+                                <span class="modal-security-label">Team ID:</span>
+                                <code class="modal-security-value" id="modalTeamID"></code>
+                            </div>
+                        </div>
+                        <!-- Multiple apps security info (suites) -->
+                        <div id="modalSecurityMultiple"></div>
+                    </div>
+                </div>
+                <div class="modal-info-row" id="modalInstallerRow" style="display: none; margin-top: 24px;">
+                    <a href="#" id="modalInstallerLink" class="modal-installer-link" target="_blank" rel="noopener noreferrer">Download Installer</a>
+                </div>
+            </div>
+            <div class="modal-footer">
+                <p id="modalLastUpdated">Last updated: ` + lastUpdated + `</p>
+            </div>
+        </div>
+    </div>
+
+    <script>
+        // Embedded CSV data
+        const csvData = ` + dataJSONStr + `;
+        
+        // Embedded apps data
+        const appsData = ` + appsJSONStr + `;
+        
+        // Embedded version updates per week (from version_history.json)
+        const weeklyUpdates = ` + updatesJSONStr + `;
+        
+        // Embedded weekly collection latency (from run_stats.json)
+        const collectionLatency = ` + latencyJSONStr + `;
+        
+        // Embedded pipeline health (from run_stats.json and app_security_info.json)
+        const pipelineHealth = ` + healthJSONStr + `;
+        
+        // Projected dates for the next app-count milestones
+        const milestones = ` + milestonesJSONStr + `;
+        
+        // Version changes and first-seen dates for the catalog time slider
+        const catalogHistory = ` + historyJSONStr + `;
+        
+        // Process data into format needed for charts
+        function processData() {
+            const data = {
+                dates: csvData.dates.map(d => new Date(d + 'T00:00:00')),
+                counts: csvData.counts,
+                additions: csvData.additions,
+                macCounts: csvData.macCounts || [],
+                windowsCounts: csvData.windowsCounts || [],
+                growthDates: csvData.growthDates.map(d => new Date(d + 'T00:00:00')),
+                growthCounts: csvData.growthCounts,
+                growthAdditions: csvData.growthAdditions
+            };
+            return data;
+        }
+        
+        // Describe a day-over-day change, including removals
+        function formatChange(change) {
+            if (change > 0) return ' (+' + change + ' added)';
+            if (change < 0) return ' (' + (-change) + ' removed)';
+            return '';
+        }
+        
+        // Describe how an installer was served, flagging plain HTTP and expiring certificates
+        function formatDownloadTLS(info) {
+            if (!info) return '';
+            if (info.plainHttp) return info.host + ' (⚠️ plain HTTP)';
+            let text = info.host + ' - ' + info.issuer;
+            if (info.notAfter) {
+                text += ', expires ' + info.notAfter.substring(0, 10);
+            }
+            if (info.expiresSoon) {
+                text += ' ⚠️';
+            }
+            return text;
+        }
+        
+        // Windows signature verdicts recorded by the collector (WinVerifyTrust)
+        const signatureVerdicts = {
+            'valid': { level: 'green', icon: '🟢', label: 'Valid' },
+            'expired': { level: 'yellow', icon: '🟡', label: 'Certificate expired' },
+            'untrusted-root': { level: 'yellow', icon: '🟡', label: 'Untrusted root' },
+            'revoked': { level: 'red', icon: '🔴', label: 'Certificate revoked' },
+            'hash-mismatch': { level: 'red', icon: '🔴', label: 'Hash mismatch' },
+            'invalid': { level: 'red', icon: '🔴', label: 'Invalid' }
+        };
+        
+        // Describe a Windows signature verdict, e.g. "🟡 Certificate expired - <status message>"
+        function formatSignatureStatus(info) {
+            const verdict = signatureVerdicts[info.signatureStatus];
+            if (!verdict) return '';
+            let text = verdict.icon + ' ' + verdict.label;
+            if (info.signatureDetail) {
+                text += ' - ' + info.signatureDetail;
+            }
+            return text;
+        }
+        
+        // Icons for the macOS App Sandbox and hardened runtime, empty when neither was recorded
+        function formatProtections(info) {
+            const parts = [];
+            if (info.hardenedRuntime !== undefined) {
+                parts.push(info.hardenedRuntime ? '🛡️ Hardened runtime' : '⚠️ No hardened runtime');
+            }
+            if (info.sandboxed !== undefined) {
+                parts.push(info.sandboxed ? '📦 Sandboxed' : '🔓 Not sandboxed');
+            }
+            return parts.join(' · ');
+        }
+        
+        // Notarization date and how long before the version was collected it was
+        // notarized, empty when the app isn't notarized
+        function formatNotarization(info) {
+            if (!info.notarizedAt) return '';
+            let text = info.notarizedAt.slice(0, 10);
+            if (info.lastUpdated) {
+                const days = Math.floor((new Date(info.lastUpdated) - new Date(info.notarizedAt)) / 86400000);
+                if (days >= 0) {
+                    text += ' (' + days + (days === 1 ? ' day' : ' days') + ' before Fleet)';
+                }
+            }
+            if (info.stapled !== undefined) {
+                text += info.stapled ? ' · 📎 Stapled' : ' · Not stapled';
+            }
+            return text;
+        }
+        
+        // Green/yellow/red dot for an app card, empty when no verdict was recorded
+        function signatureIndicator(app) {
+            const verdict = app.securityInfo && signatureVerdicts[app.securityInfo.signatureStatus];
+            if (!verdict) return '';
+            return '<span class="signature-indicator ' + verdict.level + '" title="Signature: ' + escapeHtml(verdict.label) + '"></span>';
+        }
+        
+        // Describe the architectures of a macOS app, e.g. "Universal (x86_64, arm64)"
+        function formatArchitectures(info) {
+            const slices = info.architectures || [];
+            if (slices.length > 1) {
+                return 'Universal (' + slices.map(s => s.arch).join(', ') + ')';
+            }
+            if (slices.length === 1) {
+                return slices[0].arch + (info.requiresRosetta ? ' (requires Rosetta on Apple Silicon)' : '');
+            }
+            return info.arch || '';
+        }
+        
+        // One SHA-256 row per slice of a universal binary and per architecture-specific installer
+        function architectureHashFields(info) {
+            const fields = [];
+            const slices = info.architectures || [];
+            if (slices.length > 1) {
+                slices.forEach(s => fields.push({ label: 'SHA-256 (' + s.arch + ' slice)', value: s.sha256, id: 'slice-' + s.arch }));
+            }
+            (info.variants || []).forEach(v => {
+                const arch = formatArchitectures(v) || 'variant';
+                fields.push({ label: 'SHA-256 (' + arch + ' installer)', value: v.sha256, id: 'variant-' + arch });
+            });
+            return fields;
+        }
+        
+        // One field per certificate of a Windows signing chain, for WDAC and
+        // AppLocker rules that anchor on an intermediate or root
+        function certificateChainFields(info) {
+            const chain = info.certChain || [];
+            if (chain.length < 2) return [];
+            return chain.map((c, i) => {
+                const role = i === 0 ? 'leaf' : i === chain.length - 1 ? 'root' : 'intermediate';
+                const validity = c.notBefore && c.notAfter ? ' (valid ' + c.notBefore.substring(0, 10) + ' to ' + c.notAfter.substring(0, 10) + ')' : '';
+                return { label: 'Chain ' + (i + 1) + ' (' + role + ')', value: c.subject + ' · ' + c.thumbprint + validity, id: 'chain-' + i };
+            });
+        }
+        
+        // One field per privacy permission a macOS app asks for, labeled with
+        // the key and the PPPC service that preapproves it, e.g.
+        // "NSCameraUsageDescription (PPPC: Camera)"
+        function privacyUsageFields(info) {
+            return (info.privacyUsage || []).map(p => {
+                const label = p.key + (p.service ? ' (PPPC: ' + p.service + ')' : '');
+                return { label: label, value: p.description || '(no description)', id: 'privacy-' + p.key };
+            });
+        }
+        
+        // Persistence kinds recorded by the macOS collector
+        const persistenceKinds = {
+            'launch-daemon': 'Launch daemon',
+            'launch-agent': 'Launch agent',
+            'login-item': 'Login item'
+        };
+        
+        // One field per launch daemon, launch agent and login item a macOS
+        // installer added, e.g. "com.example.helper · /Library/PrivilegedHelperTools/com.example.helper"
+        function persistenceFields(info) {
+            return (info.persistence || []).map((p, i) => {
+                const value = p.label ? p.label + ' · ' + (p.program || p.path) : p.path;
+                return { label: persistenceKinds[p.kind] || p.kind, value: value, id: 'persistence-' + i };
+            });
+        }
+        
+        let chartInstance = null;
+        let chartData = null;
+        let currentFilter = 'total';
+        let currentFlagFilter = '';
+        let currentAsOf = null; // YYYY-MM-DD, or null for today
+        let displayedApps = appsData;
+        let chartMode = 'growth';
+        let yoyChartInstance = null;
+        
+        function getAppIconUrl(slug) {
+            // Convert slug format "app-name/platform" to icon filename "app-icon-app-name-60x60@2x.png"
+            const appName = slug.split('/')[0];
+            const iconFilename = 'app-icon-' + appName + '-60x60@2x.png';
+            return 'https://raw.githubusercontent.com/fleetdm/fleet/main/website/assets/images/' + iconFilename;
+        }
+        
+        function getAppIconFallback(name) {
+            // Get first letter or first two letters for fallback icon
+            const words = name.split(' ');
+            if (words.length > 1) {
+                return (words[0][0] + words[1][0]).toUpperCase();
+            }
+            return name.substring(0, 2).toUpperCase();
+        }
+        
+        function getPlatformLabel(platform) {
+            return platform === 'darwin' ? 'Mac' : 'Windows';
+        }
+        
+        function handleIconError(img) {
+            const iconDiv = img.parentElement;
+            const fallbackText = iconDiv.getAttribute('data-fallback') || '?';
+            img.style.display = 'none';
+            iconDiv.innerHTML = '<div style="width:100%;height:100%;display:flex;align-items:center;justify-content:center;background:linear-gradient(135deg, #667eea 0%, #764ba2 100%);color:white;font-weight:bold;font-size:24px;">' + escapeHtml(fallbackText) + '</div>';
+        }
+        
+        function escapeHtml(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+        
+        function filterApps(viewType) {
+            currentFilter = viewType;
+            const grid = document.getElementById('appsGrid');
+            const countEl = document.getElementById('appsCount');
+            
+            const catalog = currentAsOf ? catalogAsOf(currentAsOf) : appsData;
+            let filteredApps = catalog;
+            
+            if (viewType === 'mac') {
+                filteredApps = catalog.filter(app => app.platform === 'darwin');
+            } else if (viewType === 'windows') {
+                filteredApps = catalog.filter(app => app.platform === 'windows');
+            }
+            if (currentFlagFilter) {
+                filteredApps = filteredApps.filter(app => matchesFlagFilter(app, currentFlagFilter));
+            }
+            
+            // Sort apps by name (case-insensitive), then by platform to group same-name apps together
+            filteredApps.sort((a, b) => {
+                const nameA = a.name.toLowerCase();
+                const nameB = b.name.toLowerCase();
+                if (nameA !== nameB) {
+                    return nameA.localeCompare(nameB);
+                }
+                // If names are the same, sort by platform (darwin before windows)
+                return a.platform.localeCompare(b.platform);
+            });
+            
+            countEl.textContent = filteredApps.length;
+            displayedApps = catalog;
+            
+            grid.innerHTML = filteredApps.map(app => {
+                const iconUrl = getAppIconUrl(app.slug);
+                const fallbackText = getAppIconFallback(app.name);
+                const platformLabel = getPlatformLabel(app.platform);
+                const version = app.version || 'N/A';
+                const versionHtml = '<div class="app-version">' + escapeHtml(version) + '</div>';
+                
+                // Make cards clickable divs that open modal
+                // Store app slug to find app data when clicked
+                return '<div class="app-card" data-platform="' + escapeHtml(app.platform) + '" data-app-slug="' + escapeHtml(app.slug) + '" onclick="openModalFromCard(this)" style="cursor: pointer;">' +
+                    '<div class="app-icon" data-fallback="' + escapeHtml(fallbackText) + '">' +
+                    '<img src="' + escapeHtml(iconUrl) + '" alt="' + escapeHtml(app.name) + '" onerror="handleIconError(this);">' +
+                    '</div>' +
+                    '<div class="app-name">' + escapeHtml(app.name) + '</div>' +
+                    versionHtml +
+                    '<span class="app-platform ' + escapeHtml(app.platform) + '">' + escapeHtml(platformLabel) + '</span>' +
+                    signatureIndicator(app) +
+                    '</div>';
+            }).join('');
+        }
+        
+        // Rebuilds the app list as of date by undoing every later change, newest
+        // first. Apps whose version is rolled back lose their current installer,
+        // security and availability details, which describe the latest version.
+        function catalogAsOf(date) {
+            const apps = new Map(appsData.map(app => [app.slug, Object.assign({}, app)]));
+            for (let i = catalogHistory.changes.length - 1; i >= 0; i--) {
+                const change = catalogHistory.changes[i];
+                if (change.d <= date) break;
+                if (change.v === '') {
+                    // Removed later, so it was still in the catalog
+                    apps.set(change.s, { slug: change.s, name: change.n, platform: change.p, version: change.o });
+                } else if (change.o === '') {
+                    apps.delete(change.s);
+                } else if (apps.has(change.s)) {
+                    const app = apps.get(change.s);
+                    app.version = change.o;
+                    delete app.installerUrl;
+                    delete app.securityInfo;
+                    delete app.availability;
+                    delete app.packages;
+                }
+            }
+            Object.entries(catalogHistory.firstSeen).forEach(([slug, firstSeen]) => {
+                if (firstSeen > date) apps.delete(slug);
+            });
+            return Array.from(apps.values());
+        }
+        
+        function setAsOf(index) {
+            const dates = csvData.dates;
+            const latest = index >= dates.length - 1;
+            currentAsOf = latest ? null : dates[index];
+            document.getElementById('asOfLabel').textContent = latest ? 'today' :
+                new Date(currentAsOf + 'T00:00:00').toLocaleDateString('en-US', { year: 'numeric', month: 'short', day: 'numeric' });
+            document.getElementById('asOfReset').style.display = latest ? 'none' : 'inline-block';
+            
+            // Before the first recorded change, versions are the oldest ones known
+            const historyStart = catalogHistory.changes.length ? catalogHistory.changes[0].d : null;
+            const note = document.getElementById('asOfNote');
+            note.textContent = currentAsOf && historyStart && currentAsOf < historyStart ?
+                'Version history starts ' + historyStart + '; earlier dates show the oldest recorded versions.' : '';
+            filterApps(currentFilter);
+        }
+        
+        function resetAsOf() {
+            const slider = document.getElementById('asOfSlider');
+            slider.value = slider.max;
+            setAsOf(Number(slider.max));
+        }
+        
+        // The slider steps through the days of the growth CSV
+        // Freshness leaderboard: the apps Fleet has been behind the vendor on longest
+        function renderFreshness() {
+            const lagging = appsData.filter(app => app.upstream && app.upstream.status === 'fleet-behind')
+                .sort((a, b) => b.upstream.lagDays - a.upstream.lagDays || a.name.localeCompare(b.name))
+                .slice(0, 10);
+            if (lagging.length === 0) return;
+            
+            const list = document.getElementById('freshnessList');
+            lagging.forEach(app => {
+                const item = document.createElement('li');
+                item.className = 'freshness-item';
+                const days = app.upstream.lagDays;
+                item.innerHTML = '<span><strong>' + escapeHtml(app.name) + '</strong> ' +
+                    '<span class="freshness-versions">' + escapeHtml(getPlatformLabel(app.platform)) + ' · ' +
+                    escapeHtml(app.version) + ' → ' + escapeHtml(app.upstream.version) + '</span></span>' +
+                    '<span class="freshness-lag">' + (days > 0 ? days + (days === 1 ? ' day' : ' days') + ' behind' : 'behind since today') + '</span>';
+                item.addEventListener('click', () => openModal(app));
+                list.appendChild(item);
+            });
+            document.getElementById('freshnessSection').style.display = 'block';
+        }
+        
+        // Pipeline health: when the tracker and each collector last succeeded
+        function renderHealth() {
+            if (!pipelineHealth.pipelines || pipelineHealth.pipelines.length === 0) return;
+            
+            document.getElementById('healthDot').className = 'health-dot ' + pipelineHealth.status;
+            const list = document.getElementById('healthList');
+            pipelineHealth.pipelines.forEach(pipeline => {
+                const item = document.createElement('li');
+                item.className = 'health-item';
+                const last = pipeline.lastSuccess ? 'last success ' + formatAgo(new Date(pipeline.lastSuccess)) : 'no successful run';
+                item.innerHTML = '<span><span class="health-dot ' + pipeline.status + '"></span><strong>' + escapeHtml(pipeline.name) + '</strong> ' +
+                    '<span class="health-detail">' + escapeHtml(pipeline.detail) + '</span></span>' +
+                    '<span class="health-time">' + escapeHtml(last) + '</span>';
+                list.appendChild(item);
+            });
+            document.getElementById('healthNote').textContent =
+                pipelineHealth.missing + ' apps without security info · ' +
+                pipelineHealth.outdated + ' with security info for an older version · ' +
+                pipelineHealth.failures + (pipelineHealth.failures === 1 ? ' failure' : ' failures') + ' in the latest tracker run';
+            document.getElementById('healthSection').style.display = 'block';
+        }
+        
+        function formatAgo(date) {
+            const minutes = Math.max(0, Math.round((Date.now() - date.getTime()) / 60000));
+            if (minutes < 60) return minutes + 'm ago';
+            const hours = Math.floor(minutes / 60);
+            if (hours < 24) return hours + 'h ' + (minutes % 60) + 'm ago';
+            return Math.floor(hours / 24) + 'd ' + (hours % 24) + 'h ago';
+        }
+        
+        function setupAsOfSlider() {
+            if (csvData.dates.length < 2 || (!catalogHistory.changes.length && !Object.keys(catalogHistory.firstSeen).length)) {
+                return;
+            }
+            const slider = document.getElementById('asOfSlider');
+            slider.max = csvData.dates.length - 1;
+            slider.value = slider.max;
+            document.getElementById('asOfControl').style.display = 'flex';
+        }
+        
+        // Flag filters are "category:<name>", "selfService" or "automaticInstall"
+        function matchesFlagFilter(app, filter) {
+            const flags = app.flags || {};
+            if (filter.startsWith('category:')) {
+                return (flags.defaultCategories || []).includes(filter.substring('category:'.length));
+            }
+            return flags[filter] === true;
+        }
+        
+        function setFlagFilter(filter) {
+            currentFlagFilter = filter;
+            filterApps(currentFilter);
+        }
+        
+        // Offers a filter option for every flag at least one app has
+        function populateFlagFilter() {
+            const select = document.getElementById('flagFilter');
+            const categories = new Set();
+            let selfService = false;
+            let automaticInstall = false;
+            appsData.forEach(app => {
+                if (!app.flags) return;
+                (app.flags.defaultCategories || []).forEach(c => categories.add(c));
+                selfService = selfService || app.flags.selfService === true;
+                automaticInstall = automaticInstall || app.flags.automaticInstall === true;
+            });
+            const options = Array.from(categories).sort().map(c => ['category:' + c, 'Category: ' + c]);
+            if (selfService) options.push(['selfService', 'Self-service']);
+            if (automaticInstall) options.push(['automaticInstall', 'Automatic install']);
+            options.forEach(([value, label]) => {
+                const option = document.createElement('option');
+                option.value = value;
+                option.textContent = label;
+                select.appendChild(option);
+            });
+            select.style.display = options.length ? 'inline-block' : 'none';
+        }
+        
+        function updateChart(viewType) {
+            if (!chartInstance || !chartData) return;
+            if (chartMode !== 'growth') {
+                setChartMode('growth');
+            }
+            
+            let dataArray, label, color, borderColor, backgroundColor;
+            
+            switch(viewType) {
+                case 'total':
+                    dataArray = chartData.counts;
+                    label = 'Total Apps';
+                    color = '#2563eb';
+                    borderColor = '#2563eb';
+                    backgroundColor = 'rgba(37, 99, 235, 0.1)';
+                    break;
+                case 'mac':
+                    dataArray = chartData.macCounts;
+                    label = 'Mac Apps';
+                    color = '#059669';
+                    borderColor = '#059669';
+                    backgroundColor = 'rgba(5, 150, 105, 0.1)';
+                    break;
+                case 'windows':
+                    dataArray = chartData.windowsCounts;
+                    label = 'Windows Apps';
+                    color = '#0284c7';
+                    borderColor = '#0284c7';
+                    backgroundColor = 'rgba(2, 132, 199, 0.1)';
+                    break;
+                default:
+                    return;
+            }
+            
+            // Update chart data
+            chartInstance.data.datasets[0].label = label;
+            chartInstance.data.datasets[0].data = chartData.dates.map((date, i) => ({x: date, y: dataArray[i]}));
+            chartInstance.data.datasets[0].borderColor = borderColor;
+            chartInstance.data.datasets[0].backgroundColor = backgroundColor;
+            
+            // Update tooltip callback
+            chartInstance.options.plugins.tooltip.callbacks.label = function(context) {
+                const idx = chartData.dates.findIndex(d => 
+                    d.getTime() === context.raw.x.getTime());
+                const current = dataArray[idx];
+                const prev = idx > 0 ? dataArray[idx - 1] : 0;
+                const change = current - prev;
+                return label + ': ' + context.parsed.y + ' apps' + formatChange(change);
+            };
+            
+            // Update active state
+            document.querySelectorAll('.stat-card').forEach(card => {
+                card.classList.remove('active');
+            });
+            document.querySelector('.stat-card[data-view="' + viewType + '"]').classList.add('active');
+            
+            // Update apps filter
+            filterApps(viewType);
+            
+            chartInstance.update();
+        }
+        
+        function createCharts() {
+            const data = processData();
+            chartData = data;
+            
+            // Calculate stats
+            const daysSpan = Math.ceil((data.dates[data.dates.length - 1] - data.dates[0]) / (1000 * 60 * 60 * 24));
+            const totalApps = data.counts[data.counts.length - 1];
+            const macApps = data.macCounts.length > 0 ? data.macCounts[data.macCounts.length - 1] : 0;
+            const windowsApps = data.windowsCounts.length > 0 ? data.windowsCounts[data.windowsCounts.length - 1] : 0;
+            
+            // Update stats cards
+            document.getElementById('stats').innerHTML = 
+                '<div class="stat-card clickable active" data-view="total">' +
+                    '<div class="stat-value">' + totalApps + '</div>' +
+                    '<div class="stat-label">Total Apps</div>' +
+                '</div>' +
+                '<div class="stat-card clickable" data-view="mac">' +
+                    '<div class="stat-value">' + macApps + '</div>' +
+                    '<div class="stat-label">Mac Apps</div>' +
+                '</div>' +
+                '<div class="stat-card clickable" data-view="windows">' +
+                    '<div class="stat-value">' + windowsApps + '</div>' +
+                    '<div class="stat-label">Windows Apps</div>' +
+                '</div>' +
+                '<div class="stat-card">' +
+                    '<div class="stat-value">' + daysSpan + '</div>' +
+                    '<div class="stat-label">Days Tracked</div>' +
+                '</div>' +
+                milestoneCard();
+            
+            // Add click event listeners to stat cards
+            document.querySelectorAll('.stat-card.clickable').forEach(card => {
+                card.addEventListener('click', function() {
+                    const viewType = this.getAttribute('data-view');
+                    updateChart(viewType);
+                });
+            });
+            
+            // Initialize apps display
+            populateFlagFilter();
+            setupAsOfSlider();
+            renderFreshness();
+            renderHealth();
+            filterApps('total');
+            
+            // Cumulative Growth Chart
+            const ctx1 = document.getElementById('cumulativeChart').getContext('2d');
+            chartInstance = new Chart(ctx1, {
+                type: 'line',
+                data: {
+                    datasets: [{
+                        label: 'Total Apps',
+                        data: data.dates.map((date, i) => ({x: date, y: data.counts[i]})),
+                        borderColor: '#2563eb',
+                        backgroundColor: 'rgba(37, 99, 235, 0.1)',
+                        borderWidth: 2.5,
+                        pointRadius: 0,
+                        fill: true,
+                        tension: 0,
+                        stepped: 'after'
+                    }]
+                },
+                options: {
+                    responsive: true,
+                    maintainAspectRatio: false,
+                    plugins: {
+                        title: {
+                            display: true,
+                            text: 'Cumulative Growth (Daily)',
+                            font: { size: 16, weight: 'bold' }
+                        },
+                        legend: {
+                            display: true,
+                            position: 'top'
+                        },
+                        tooltip: {
+                            callbacks: {
+                                label: function(context) {
+                                    const idx = data.dates.findIndex(d => 
+                                        d.getTime() === context.raw.x.getTime());
+                                    const change = idx > 0 ? data.counts[idx] - data.counts[idx - 1] : data.counts[idx];
+                                    return 'Total Apps: ' + context.parsed.y + ' apps' + formatChange(change);
+                                }
+                            }
+                        }
+                    },
+                    scales: {
+                        x: {
+                            type: 'time',
+                            time: {
+                                unit: 'month',
+                                displayFormats: {
+                                    month: 'MMM'
+                                }
+                            },
+                            title: {
+                                display: true,
+                                text: 'Date',
+                                font: { weight: 'bold' }
+                            }
+                        },
+                        y: {
+                            beginAtZero: true,
+                            title: {
+                                display: true,
+                                text: 'Number of Apps',
+                                font: { weight: 'bold' }
+                            },
+                            ticks: {
+                                stepSize: 5
+                            }
+                        }
+                    }
+                }
+            });
+            
+            createUpdatesChart();
+            createLatencyChart();
+        }
+        
+        // Switch the main chart between cumulative growth and the year-over-year overlay
+        function setChartMode(mode) {
+            chartMode = mode;
+            document.querySelectorAll('.chart-mode-button').forEach(button => {
+                button.classList.toggle('active', button.getAttribute('data-mode') === mode);
+            });
+            document.getElementById('cumulativeChart').style.display = mode === 'growth' ? 'block' : 'none';
+            document.getElementById('yoyChart').style.display = mode === 'yoy' ? 'block' : 'none';
+            if (mode === 'yoy' && !yoyChartInstance) {
+                createYearOverYearChart();
+            }
+        }
+        
+        // Apps added since the start of each year, plotted against day of year.
+        // Every date is moved into leap year 2000 so the years share one time axis.
+        function yearOverYearDatasets(data) {
+            const years = {};
+            let previousCount = null;
+            data.dates.forEach((date, i) => {
+                const year = date.getFullYear();
+                if (!years[year]) {
+                    // Baseline is the count at the end of the previous year, or the first count we have
+                    years[year] = { baseline: previousCount !== null ? previousCount : data.counts[i], points: [] };
+                }
+                const alignedDate = new Date(2000, date.getMonth(), date.getDate());
+                years[year].points.push({ x: alignedDate, y: data.counts[i] - years[year].baseline, date: date });
+                previousCount = data.counts[i];
+            });
+            
+            const yearList = Object.keys(years).map(Number).sort((a, b) => b - a);
+            const priorColors = ['#94a3b8', '#cbd5e1', '#e2e8f0'];
+            return yearList.map((year, index) => ({
+                label: String(year),
+                data: years[year].points,
+                borderColor: index === 0 ? '#2563eb' : priorColors[Math.min(index - 1, priorColors.length - 1)],
+                backgroundColor: 'transparent',
+                borderWidth: index === 0 ? 3 : 2,
+                pointRadius: 0,
+                fill: false,
+                tension: 0,
+                stepped: 'after'
+            }));
+        }
+        
+        function createYearOverYearChart() {
+            const ctx = document.getElementById('yoyChart').getContext('2d');
+            yoyChartInstance = new Chart(ctx, {
+                type: 'line',
+                data: {
+                    datasets: yearOverYearDatasets(chartData)
+                },
+                options: {
+                    responsive: true,
+                    maintainAspectRatio: false,
+                    interaction: {
+                        mode: 'nearest',
+                        axis: 'x',
+                        intersect: false
+                    },
+                    plugins: {
+                        title: {
+                            display: true,
+                            text: 'Apps Added Year to Date',
+                            font: { size: 16, weight: 'bold' }
+                        },
+                        legend: {
+                            display: true,
+                            position: 'top'
+                        },
+                        tooltip: {
+                            callbacks: {
+                                title: function(items) {
+                                    return items[0].raw.x.toLocaleDateString('en-US', { month: 'long', day: 'numeric' });
+                                },
+                                label: function(context) {
+                                    return context.dataset.label + ': +' + context.parsed.y + ' apps';
+                                }
+                            }
+                        }
+                    },
+                    scales: {
+                        x: {
+                            type: 'time',
+                            min: new Date(2000, 0, 1),
+                            max: new Date(2000, 11, 31),
+                            time: {
+                                unit: 'month',
+                                displayFormats: {
+                                    month: 'MMM'
+                                }
+                            },
+                            title: {
+                                display: true,
+                                text: 'Day of Year',
+                                font: { weight: 'bold' }
+                            }
+                        },
+                        y: {
+                            beginAtZero: true,
+                            title: {
+                                display: true,
+                                text: 'Apps Added Since January 1',
+                                font: { weight: 'bold' }
+                            }
+                        }
+                    }
+                }
+            });
+        }
+        
+        // Stat card for the next projected milestone, with the one after it in the tooltip
+        function milestoneCard() {
+            if (!milestones || milestones.length === 0) return '';
+            const formatMilestoneDate = m => new Date(m.date + 'T00:00:00').toLocaleDateString('en-US', { month: 'short', day: 'numeric', year: 'numeric' });
+            const title = milestones.map(m => m.count + ' apps: ~' + formatMilestoneDate(m)).join('\n');
+            return '<div class="stat-card" title="' + escapeHtml(title) + '">' +
+                    '<div class="stat-value">' + milestones[0].count + '</div>' +
+                    '<div class="stat-label">Projected by ' + escapeHtml(formatMilestoneDate(milestones[0])) + '</div>' +
+                '</div>';
+        }
+        
+        // Version Updates per Week Chart
+        function createUpdatesChart() {
+            const container = document.getElementById('updatesChartContainer');
+            if (!weeklyUpdates.weeks || weeklyUpdates.weeks.length === 0) {
+                container.style.display = 'none';
+                return;
+            }
+            const ctx2 = document.getElementById('updatesChart').getContext('2d');
+            new Chart(ctx2, {
+                type: 'bar',
+                data: {
+                    datasets: [{
+                        label: 'Version Updates',
+                        data: weeklyUpdates.weeks.map((week, i) => ({x: new Date(week + 'T00:00:00'), y: weeklyUpdates.updates[i]})),
+                        backgroundColor: 'rgba(37, 99, 235, 0.6)',
+                        borderColor: '#2563eb',
+                        borderWidth: 1
+                    }]
+                },
+                options: {
+                    responsive: true,
+                    maintainAspectRatio: false,
+                    plugins: {
+                        title: {
+                            display: true,
+                            text: 'Version Updates (Weekly)',
+                            font: { size: 16, weight: 'bold' }
+                        },
+                        legend: {
+                            display: false
+                        },
+                        tooltip: {
+                            callbacks: {
+                                title: function(items) {
+                                    return 'Week of ' + items[0].raw.x.toLocaleDateString('en-US', { month: 'long', day: 'numeric', year: 'numeric' });
+                                },
+                                label: function(context) {
+                                    const count = context.parsed.y;
+                                    return count + (count === 1 ? ' version update' : ' version updates');
+                                }
+                            }
+                        }
+                    },
+                    scales: {
+                        x: {
+                            type: 'time',
+                            time: {
+                                unit: 'month',
+                                displayFormats: {
+                                    month: 'MMM'
+                                }
+                            },
+                            title: {
+                                display: true,
+                                text: 'Week',
+                                font: { weight: 'bold' }
+                            }
+                        },
+                        y: {
+                            beginAtZero: true,
+                            title: {
+                                display: true,
+                                text: 'Version Updates',
+                                font: { weight: 'bold' }
+                            },
+                            ticks: {
+                                precision: 0
+                            }
+                        }
+                    }
+                }
+            });
+        }
+        
+        // Hours from a version bump to its security info, per week
+        function createLatencyChart() {
+            const container = document.getElementById('latencyChartContainer');
+            if (!collectionLatency.weeks || collectionLatency.weeks.length === 0) {
+                container.style.display = 'none';
+                return;
+            }
+            const points = (values) => collectionLatency.weeks.map((week, i) => ({x: new Date(week + 'T00:00:00'), y: values[i]}));
+            const ctx = document.getElementById('latencyChart').getContext('2d');
+            new Chart(ctx, {
+                type: 'line',
+                data: {
+                    datasets: [{
+                        label: 'Median',
+                        data: points(collectionLatency.median),
+                        borderColor: '#2563eb',
+                        backgroundColor: 'rgba(37, 99, 235, 0.1)',
+                        tension: 0.2
+                    }, {
+                        label: '95th percentile',
+                        data: points(collectionLatency.p95),
+                        borderColor: '#f59e0b',
+                        backgroundColor: 'rgba(245, 158, 11, 0.1)',
+                        borderDash: [6, 4],
+                        tension: 0.2
+                    }]
+                },
+                options: {
+                    responsive: true,
+                    maintainAspectRatio: false,
+                    plugins: {
+                        title: {
+                            display: true,
+                            text: 'Security Info Latency (Weekly)',
+                            font: { size: 16, weight: 'bold' }
+                        },
+                        tooltip: {
+                            callbacks: {
+                                title: function(items) {
+                                    const samples = collectionLatency.samples[items[0].dataIndex];
+                                    return 'Week of ' + items[0].raw.x.toLocaleDateString('en-US', { month: 'long', day: 'numeric', year: 'numeric' }) +
+                                        ' (' + samples + (samples === 1 ? ' update)' : ' updates)');
+                                },
+                                label: function(context) {
+                                    return context.dataset.label + ': ' + context.parsed.y + ' h';
+                                }
+                            }
+                        }
+                    },
+                    scales: {
+                        x: {
+                            type: 'time',
+                            time: {
+                                unit: 'month',
+                                displayFormats: {
+                                    month: 'MMM'
+                                }
+                            },
+                            title: {
+                                display: true,
+                                text: 'Week the version was published',
+                                font: { weight: 'bold' }
+                            }
+                        },
+                        y: {
+                            beginAtZero: true,
+                            title: {
+                                display: true,
+                                text: 'Hours to security info',
+                                font: { weight: 'bold' }
+                            }
+                        }
+                    }
+                }
+            });
+        }
+        
+        createCharts();
+        
+        // Modal functions
+        function openModalFromCard(cardElement) {
+            // Handle clicks on child elements - find the card element
+            let card = cardElement;
+            while (card && !card.classList.contains('app-card')) {
+                card = card.parentElement;
+            }
+            if (!card) {
+                console.error('Could not find app-card element');
+                return;
+            }
+            
+            const appSlug = card.getAttribute('data-app-slug');
+            if (!appSlug) {
+                console.error('No app-slug attribute found');
+                return;
+            }
+            
+            // Find the app in the catalog currently shown
+            const app = displayedApps.find(a => a.slug === appSlug);
+            if (app) {
+                openModal(app);
+            } else {
+                console.error('App not found for slug:', appSlug);
+            }
+        }
+        
+        function openModal(app) {
+            const modal = document.getElementById('appModal');
+            if (!modal) {
+                console.error('Modal element not found');
+                return;
+            }
+            
+            const iconUrl = getAppIconUrl(app.slug);
+            const fallbackText = getAppIconFallback(app.name);
+            const platformLabel = getPlatformLabel(app.platform);
+            
+            // Set modal icon - reset and reload to ensure it displays
+            const modalIcon = document.getElementById('modalIcon');
+            if (modalIcon) {
+                modalIcon.setAttribute('data-fallback', fallbackText);
+                // Reset the icon container and create new image element with the URL directly
+                modalIcon.innerHTML = '<img id="modalIconImg" src="' + escapeHtml(iconUrl) + '" alt="' + escapeHtml(app.name) + '" onerror="handleModalIconError(this);" style="display:block;width:100%;height:100%;object-fit:contain;">';
+            }
+            
+            // Set modal title and platform
+            const modalTitle = document.getElementById('modalTitle');
+            if (modalTitle) {
+                modalTitle.textContent = app.name;
+            }
+            
+            const modalPlatform = document.getElementById('modalPlatform');
+            if (modalPlatform) {
+                modalPlatform.textContent = platformLabel;
+                modalPlatform.className = 'modal-platform ' + app.platform;
+            }
+            
+            // Set version
+            const modalVersion = document.getElementById('modalVersion');
+            if (modalVersion) {
+                modalVersion.textContent = app.version || 'N/A';
+            }
+            
+            // Set description
+            const modalDescription = document.getElementById('modalDescription');
+            if (modalDescription) {
+                const description = app.description || 'No description available.';
+                modalDescription.textContent = description;
+            }
+            
+            // Set installer availability
+            const availabilityRow = document.getElementById('modalAvailabilityRow');
+            const modalAvailability = document.getElementById('modalAvailability');
+            if (availabilityRow && modalAvailability) {
+                if (app.availability) {
+                    const a = app.availability;
+                    modalAvailability.textContent = a.percent + '% of ' + a.checks + ' checks' +
+                        (a.lastOk ? '' : ' (⚠️ unavailable at last check, ' + a.lastChecked.substring(0, 10) + ')');
+                    availabilityRow.style.display = 'block';
+                } else {
+                    availabilityRow.style.display = 'none';
+                }
+            }
+            
+            // Set default categories and install options from Fleet's manifest
+            const flagsRow = document.getElementById('modalFlagsRow');
+            const modalFlags = document.getElementById('modalFlags');
+            if (flagsRow && modalFlags) {
+                const lines = [];
+                if (app.flags) {
+                    if (app.flags.defaultCategories && app.flags.defaultCategories.length) {
+                        lines.push('Categories: ' + app.flags.defaultCategories.join(', '));
+                    }
+                    [['Self-service', app.flags.selfService], ['Automatic install', app.flags.automaticInstall]].forEach(([label, v]) => {
+                        if (v === undefined || v === null) return;
+                        lines.push(label + ': ' + (v ? 'on' : 'off'));
+                    });
+                }
+                modalFlags.textContent = lines.join('\n');
+                modalFlags.style.whiteSpace = 'pre-line';
+                flagsRow.style.display = lines.length ? 'block' : 'none';
+            }
+            
+            // Set the vendor's latest release
+            const upstreamRow = document.getElementById('modalUpstreamRow');
+            const modalUpstream = document.getElementById('modalUpstream');
+            if (upstreamRow && modalUpstream) {
+                const u = app.upstream;
+                let text = '';
+                if (u) {
+                    text = u.version + ' (' + u.source + (u.releasedAt ? ', released ' + u.releasedAt.slice(0, 10) : '') + ')';
+                    if (u.status === 'fleet-behind') {
+                        text += ' · ⚠️ Fleet is ' + (u.lagDays > 0 ? u.lagDays + ' day(s) behind' : 'behind');
+                    } else if (u.status === 'fleet-ahead') {
+                        text += ' · Fleet is ahead';
+                    } else {
+                        text += ' · Fleet is up to date';
+                    }
+                }
+                modalUpstream.textContent = text;
+                upstreamRow.style.display = text ? 'block' : 'none';
+            }
+            
+            // Set winget and Chocolatey packages
+            const packagesRow = document.getElementById('modalPackagesRow');
+            const modalPackages = document.getElementById('modalPackages');
+            if (packagesRow && modalPackages) {
+                const lines = [];
+                if (app.packages) {
+                    [['winget', app.packages.winget], ['Chocolatey', app.packages.chocolatey]].forEach(([label, p]) => {
+                        if (!p) return;
+                        const parity = p.status === 'fleet-behind' ? ' (⚠️ Fleet is behind)' :
+                            p.status === 'fleet-ahead' ? ' (Fleet is ahead)' : ' (same as Fleet)';
+                        lines.push(label + ': ' + p.id + ' ' + p.version + parity);
+                    });
+                }
+                modalPackages.textContent = lines.join('\n');
+                modalPackages.style.whiteSpace = 'pre-line';
+                packagesRow.style.display = lines.length ? 'block' : 'none';
+            }
+            
+            // Set installer link
+            const installerRow = document.getElementById('modalInstallerRow');
+            const installerLink = document.getElementById('modalInstallerLink');
+            if (installerRow && installerLink) {
+                if (app.installerUrl) {
+                    installerLink.href = app.installerUrl;
+                    installerRow.style.display = 'block';
+                } else {
+                    installerRow.style.display = 'none';
+                }
+            }
+            
+            // Set security info (macOS and Windows)
+            const securityRow = document.getElementById('modalSecurityRow');
+            const securitySingle = document.getElementById('modalSecuritySingle');
+            const securityMultiple = document.getElementById('modalSecurityMultiple');
+            
+            // Debug logging
+            console.log('Security Info Debug:', {
+                hasSecurityInfo: !!app.securityInfo,
+                securityInfo: app.securityInfo,
+                platform: app.platform,
+                slug: app.slug
+            });
+            // Log the actual security info keys and values for debugging
+            if (app.securityInfo) {
+                console.log('Security Info Keys:', Object.keys(app.securityInfo));
+                console.log('Security Info Full Object:', JSON.stringify(app.securityInfo, null, 2));
+                // Log individual field accesses
+                if (app.platform === 'windows') {
+                    console.log('Windows fields:', {
+                        publisher: app.securityInfo.publisher,
+                        issuer: app.securityInfo.issuer,
+                        serialNumber: app.securityInfo.serialNumber,
+                        thumbprint: app.securityInfo.thumbprint,
+                        timestamp: app.securityInfo.timestamp
+                    });
+                } else {
+                    console.log('macOS fields:', {
+                        cdhash: app.securityInfo.cdhash,
+                        signingId: app.securityInfo.signingId,
+                        teamId: app.securityInfo.teamId
+                    });
+                }
+            }
+            
+            if (securityRow) {
+                if (app.securityInfo) {
+                    // Check if this is a suite with multiple apps
+                    if (app.securityInfo.apps && app.securityInfo.apps.length > 0) {
+                        console.log('Suite detected with', app.securityInfo.apps.length, 'apps');
+                        // Hide single app view, show multiple apps view
+                        if (securitySingle) securitySingle.style.display = 'none';
+                        if (securityMultiple) {
+                            securityMultiple.innerHTML = '';
+                            
+                            // Create a section for each app in the suite
+                            app.securityInfo.apps.forEach((suiteApp, index) => {
+                                console.log('Processing suite app', index, ':', suiteApp.name, suiteApp);
+                                const appSection = document.createElement('div');
+                                appSection.className = 'modal-security-app-section';
+                                appSection.style.marginBottom = index < app.securityInfo.apps.length - 1 ? '24px' : '0';
+                                
+                                const appTitle = document.createElement('div');
+                                appTitle.className = 'modal-security-app-title';
+                                appTitle.textContent = suiteApp.name || 'App ' + (index + 1);
+                                appTitle.style.fontWeight = '600';
+                                appTitle.style.color = '#1e293b';
+                                appTitle.style.marginBottom = '12px';
+                                appTitle.style.fontSize = '15px';
+                                
+                                const appInfo = document.createElement('div');
+                                appInfo.className = 'modal-security-info';
+                                
+                                // Determine fields based on platform
+                                const isWindows = app.platform === 'windows';
+                                const fields = isWindows ? [
+                                    { label: 'SHA-256', value: suiteApp.sha256, id: 'sha256' },
+                                    { label: 'Signature', value: formatSignatureStatus(suiteApp), id: 'signatureStatus' },
+                                    { label: 'Publisher', value: suiteApp.publisher, id: 'publisher' },
+                                    { label: 'Issuer', value: suiteApp.issuer, id: 'issuer' },
+                                    { label: 'Serial Number', value: suiteApp.serialNumber, id: 'serialNumber' },
+                                    { label: 'Thumbprint', value: suiteApp.thumbprint, id: 'thumbprint' },
+                                    { label: 'Timestamp', value: suiteApp.timestamp, id: 'timestamp' },
+                                    ...certificateChainFields(suiteApp)
+                                ] : [
+                                    { label: 'SHA-256', value: suiteApp.sha256, id: 'sha256' },
+                                    { label: 'CDHash', value: suiteApp.cdhash, id: 'cdhash' },
+                                    { label: 'Signing ID', value: suiteApp.signingId, id: 'signingId' },
+                                    { label: 'Team ID', value: suiteApp.teamId, id: 'teamId' },
+                                    { label: 'Bundle ID', value: suiteApp.bundleId, id: 'bundleId' }
+                                ];
+                                
+                                fields.forEach(field => {
+                                    // Check if value exists and is not empty string
+                                    const value = field.value;
+                                    if (value !== undefined && value !== null && value !== '') {
+                                        const item = document.createElement('div');
+                                        item.className = 'modal-security-item';
+                                        
+                                        const label = document.createElement('span');
+                                        label.className = 'modal-security-label';
+                                        label.textContent = field.label + ':';
+                                        
+                                        const valueElement = document.createElement('code');
+                                        valueElement.className = 'modal-security-value';
+                                        valueElement.textContent = value;
+                                        setupCopyToClipboard(valueElement, value);
+                                        
+                                        item.appendChild(label);
+                                        item.appendChild(valueElement);
+                                        appInfo.appendChild(item);
+                                    }
+                                });
+                                
+                                appSection.appendChild(appTitle);
+                                appSection.appendChild(appInfo);
+                                securityMultiple.appendChild(appSection);
+                            });
+                            
+                            securityMultiple.style.display = 'block';
+                            securityRow.style.display = 'block';
+                        }
+                    } else {
+                        // Single app view - dynamically build security info based on platform
+                        if (securitySingle) {
+                            securitySingle.style.display = 'block';
+                            // Ensure the container has the correct class
+                            if (!securitySingle.classList.contains('modal-security-info')) {
+                                securitySingle.classList.add('modal-security-info');
+                            }
+                        }
+                        if (securityMultiple) securityMultiple.style.display = 'none';
+                        
+                        // Clear existing content and rebuild based on platform
+                        const securityContainer = securitySingle;
+                        if (securityContainer) {
+                            securityContainer.innerHTML = '';
+                            
+                            const isWindows = app.platform === 'windows';
+                            const fields = isWindows ? [
+                                { label: 'SHA-256', value: app.securityInfo.sha256, id: 'sha256' },
+                                { label: 'Signature', value: formatSignatureStatus(app.securityInfo), id: 'signatureStatus' },
+                                { label: 'Publisher', value: app.securityInfo.publisher, id: 'publisher' },
+                                { label: 'Issuer', value: app.securityInfo.issuer, id: 'issuer' },
+                                { label: 'Serial Number', value: app.securityInfo.serialNumber, id: 'serialNumber' },
+                                { label: 'Thumbprint', value: app.securityInfo.thumbprint, id: 'thumbprint' },
+                                { label: 'Timestamp', value: app.securityInfo.timestamp, id: 'timestamp' },
+                                ...certificateChainFields(app.securityInfo),
+                                { label: 'Download', value: formatDownloadTLS(app.securityInfo.downloadTls), id: 'downloadTls' }
+                            ] : [
+                                { label: 'SHA-256', value: app.securityInfo.sha256, id: 'sha256' },
+                                { label: 'CDHash', value: app.securityInfo.cdhash, id: 'cdhash' },
+                                { label: 'Signing ID', value: app.securityInfo.signingId, id: 'signingId' },
+                                { label: 'Team ID', value: app.securityInfo.teamId, id: 'teamId' },
+                                { label: 'Bundle ID', value: app.securityInfo.bundleId, id: 'bundleId' },
+                                { label: 'Architecture', value: formatArchitectures(app.securityInfo), id: 'architectures' },
+                                { label: 'Protections', value: formatProtections(app.securityInfo), id: 'protections' },
+                                { label: 'Certificate', value: app.securityInfo.leafCertificate, id: 'leafCertificate' },
+                                { label: 'Signed', value: app.securityInfo.signingTime, id: 'signingTime' },
+                                { label: 'Format', value: app.securityInfo.signatureFormat, id: 'signatureFormat' },
+                                { label: 'Notarized', value: formatNotarization(app.securityInfo), id: 'notarizedAt' },
+                                ...privacyUsageFields(app.securityInfo),
+                                ...persistenceFields(app.securityInfo),
+                                ...architectureHashFields(app.securityInfo),
+                                { label: 'Download', value: formatDownloadTLS(app.securityInfo.downloadTls), id: 'downloadTls' }
+                            ];
+                            
+                            let hasFields = false;
+                            console.log('Single app security fields:', fields);
+                            console.log('Security info object:', app.securityInfo);
+                            fields.forEach(field => {
+                                // Check if value exists and is not empty string
+                                const value = field.value;
+                                if (value !== undefined && value !== null && value !== '') {
+                                    hasFields = true;
+                                    console.log('Adding field:', field.label, '=', value);
+                                    const item = document.createElement('div');
+                                    item.className = 'modal-security-item';
+                                    
+                                    const label = document.createElement('span');
+                                    label.className = 'modal-security-label';
+                                    label.textContent = field.label + ':';
+                                    
+                                    const valueElement = document.createElement('code');
+                                    valueElement.className = 'modal-security-value';
+                                    valueElement.textContent = value;
+                                    setupCopyToClipboard(valueElement, value);
+                                    
+                                    item.appendChild(label);
+                                    item.appendChild(valueElement);
+                                    securityContainer.appendChild(item);
+                                } else {
+                                    console.log('Skipping field:', field.label, 'value:', value, 'type:', typeof value);
+                                }
+                            });
+                            
+                            // Only show security row if we have at least one field
+                            console.log('Single app hasFields:', hasFields);
+                            if (hasFields) {
+                                securityRow.style.display = 'block';
+                                console.log('Security row set to block');
+                            } else {
+                                securityRow.style.display = 'none';
+                                console.log('Security row set to none (no fields)');
+                            }
+                        } else {
+                            securityRow.style.display = 'block';
+                        }
+                    }
+                } else {
+                    securityRow.style.display = 'none';
+                }
+            }
+            
+            // Set last updated timestamp
+            const modalLastUpdated = document.getElementById('modalLastUpdated');
+            if (modalLastUpdated) {
+                let timestampText = 'Last updated: ' + ` + "`" + lastUpdated + "`" + `;
+                
+                // If app has security info with lastUpdated, use that instead
+                if (app.securityInfo && app.securityInfo.lastUpdated) {
+                    // Parse RFC3339 timestamp (UTC) and convert to CST
+                    const securityDate = new Date(app.securityInfo.lastUpdated);
+                    
+                    // Format in CST timezone: "January 2, 2006 at 3:04 PM CST"
+                    const cstFormatter = new Intl.DateTimeFormat('en-US', {
+                        timeZone: 'America/Chicago',
+                        year: 'numeric',
+                        month: 'long',
+                        day: 'numeric',
+                        hour: 'numeric',
+                        minute: '2-digit',
+                        hour12: true
+                    });
+                    
+                    const parts = cstFormatter.formatToParts(securityDate);
+                    const month = parts.find(p => p.type === 'month').value;
+                    const day = parts.find(p => p.type === 'day').value;
+                    const year = parts.find(p => p.type === 'year').value;
+                    const hour = parts.find(p => p.type === 'hour').value;
+                    const minute = parts.find(p => p.type === 'minute').value;
+                    const dayPeriod = parts.find(p => p.type === 'dayPeriod').value.toUpperCase();
+                    
+                    timestampText = 'Last updated: ' + month + ' ' + day + ', ' + year + ' at ' + hour + ':' + minute + ' ' + dayPeriod + ' CST';
+                }
+                
+                modalLastUpdated.textContent = timestampText;
+            }
+            
+            // Show modal
+            modal.classList.add('show');
+            document.body.style.overflow = 'hidden';
+        }
+        
+        function closeModal() {
+            const modal = document.getElementById('appModal');
+            modal.classList.remove('show');
+            document.body.style.overflow = '';
+        }
+        
+        function handleModalIconError(img) {
+            const iconDiv = img.parentElement;
+            const fallbackText = iconDiv.getAttribute('data-fallback') || '?';
+            img.style.display = 'none';
+            iconDiv.innerHTML = '<div style="width:100%;height:100%;display:flex;align-items:center;justify-content:center;background:linear-gradient(135deg, #667eea 0%, #764ba2 100%);color:white;font-weight:bold;font-size:24px;">' + escapeHtml(fallbackText) + '</div>';
+        }
+        
+        // Close modal when clicking outside (on the backdrop)
+        document.getElementById('appModal').addEventListener('click', function(event) {
+            // Only close if clicking directly on the modal backdrop, not on modal-content
+            if (event.target.id === 'appModal') {
+                closeModal();
+            }
+        });
+        
+        // Close modal with Escape key
+        document.addEventListener('keydown', function(event) {
+            if (event.key === 'Escape') {
+                closeModal();
+            }
+        });
+        
+        // Copy to clipboard functionality
+        function setupCopyToClipboard(element, text) {
+            if (!element || text === 'N/A') return;
+            
+            element.addEventListener('click', async function() {
+                try {
+                    await navigator.clipboard.writeText(text);
+                    // Visual feedback
+                    element.classList.add('copied');
+                    const originalText = element.textContent;
+                    element.textContent = 'Copied!';
+                    
+                    setTimeout(() => {
+                        element.classList.remove('copied');
+                        element.textContent = originalText;
+                    }, 2000);
+                } catch (err) {
+                    // Fallback for older browsers
+                    const textArea = document.createElement('textarea');
+                    textArea.value = text;
+                    textArea.style.position = 'fixed';
+                    textArea.style.opacity = '0';
+                    document.body.appendChild(textArea);
+                    textArea.select();
+                    try {
+                        document.execCommand('copy');
+                        element.classList.add('copied');
+                        const originalText = element.textContent;
+                        element.textContent = 'Copied!';
+                        setTimeout(() => {
+                            element.classList.remove('copied');
+                            element.textContent = originalText;
+                        }, 2000);
+                    } catch (fallbackErr) {
+                        console.error('Failed to copy:', fallbackErr);
+                    }
+                    document.body.removeChild(textArea);
+                }
+            });
+        }
+        
+        // Live updates when served by serve.go; on static hosting the stream
+        // 404s and EventSource gives up without retrying
+        if (window.EventSource && location.protocol.startsWith('http')) {
+            const liveEvents = new EventSource('api/events');
+            let reloadTimer = null;
+            liveEvents.addEventListener('change', () => {
+                // Data files and index.html are often written moments apart
+                clearTimeout(reloadTimer);
+                reloadTimer = setTimeout(() => location.reload(), 1500);
+            });
+        }
+    </script>
+</body>
+</html>`
+}
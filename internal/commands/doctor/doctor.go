@@ -0,0 +1,807 @@
+// Package doctor implements "fleet-tracker doctor", which diagnoses the data
+// or a collector's environment; doctor.go runs it on its own.
+package doctor
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	growthCSV              = "data/apps_growth.csv"
+	versionsJSON           = "data/app_versions.json"
+	versionHistoryJSON     = "data/version_history.json"
+	versionHistoryArchives = "data/version_history-*.json"
+	securityInfoJSON       = "data/app_security_info.json"
+	firstSeenJSON          = "data/app_first_seen.json"
+	checkpointJSON         = "data/history_checkpoint.json"
+	runStatsJSON           = "data/run_stats.json"
+	appsMetadataJSON       = "data/apps_metadata.json"
+	installerUptime        = "data/installer_uptime.jsonl"
+	packageParityJSON      = "data/package_parity.json"
+	upstreamLagJSON        = "data/upstream_lag.json"
+)
+
+// statusStaleAfter is the age past which status flags a data file; the data
+// is regenerated hourly and the collectors and probes run daily
+const statusStaleAfter = 48 * time.Hour
+
+// statusListLimit caps the slugs listed per status line
+const statusListLimit = 10
+
+// minFreeDiskGB is the free space a collection run needs in the temp
+// directory: the largest installers (Xcode-sized suites) plus their extraction
+const minFreeDiskGB = 10
+
+// CSV column indexes, matching the header written by main.go
+const (
+	colDate = iota
+	colCount
+	colAdded
+	colMac
+	colWindows
+	colRemoved
+	colNet
+)
+
+// Main runs fleet-tracker doctor with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		printDoctorUsage()
+		os.Exit(2)
+	}
+
+	args = fs.Args()
+	switch args[0] {
+	case "data":
+		fs := flag.NewFlagSet("data", flag.ExitOnError)
+		fix := fs.Bool("fix", false, "repair gaps in apps_growth.csv by carrying values forward")
+		fs.Parse(args[1:])
+
+		problems, err := doctorData(*fix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if problems > 0 {
+			fmt.Printf("\n❌ Found %d problem(s)\n", problems)
+			os.Exit(1)
+		}
+		fmt.Println("\n✅ Data looks healthy")
+	case "env":
+		flag.NewFlagSet("env", flag.ExitOnError).Parse(args[1:])
+
+		if problems := doctorEnv(); problems > 0 {
+			fmt.Printf("\n❌ Found %d problem(s); fix them before starting a collection run\n", problems)
+			os.Exit(1)
+		}
+		fmt.Println("\n✅ Ready to collect security info")
+	case "status":
+		flag.NewFlagSet("status", flag.ExitOnError).Parse(args[1:])
+
+		doctorStatus(time.Now().UTC())
+	default:
+		printDoctorUsage()
+		os.Exit(2)
+	}
+}
+
+func printDoctorUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: fleet-tracker doctor data [--fix]")
+	fmt.Fprintln(os.Stderr, "       fleet-tracker doctor env")
+	fmt.Fprintln(os.Stderr, "       fleet-tracker doctor status")
+}
+
+// doctorStatus reports how fresh the data is: the age of each data file, the
+// apps whose security info is missing or for an older version, and the last
+// successful run of the tracker and of each collector. It only informs, so
+// it never fails.
+func doctorStatus(now time.Time) {
+	fmt.Println("📋 Data status")
+	fmt.Println("==============")
+
+	fmt.Println("\n🕒 Data files")
+	files := []struct {
+		path    string
+		updated func(string) (time.Time, error)
+	}{
+		{growthCSV, lastCSVDate},
+		{versionsJSON, lastUpdatedField},
+		{versionHistoryJSON, newestChange},
+		{securityInfoJSON, lastUpdatedField},
+		{runStatsJSON, lastSuccessfulRun},
+		{installerUptime, lastProbe},
+		{packageParityJSON, lastUpdatedField},
+		{upstreamLagJSON, lastUpdatedField},
+	}
+	for _, file := range files {
+		updated, err := file.updated(file.path)
+		switch {
+		case os.IsNotExist(err):
+			fmt.Printf("   ⏭️  %s (not present)\n", file.path)
+		case err != nil:
+			fmt.Printf("   ❌ %s: %v\n", file.path, err)
+		default:
+			icon := "✅"
+			if now.Sub(updated) > statusStaleAfter {
+				icon = "⚠️ "
+			}
+			fmt.Printf("   %s %s: updated %s ago (%s)\n", icon, file.path, formatAge(now.Sub(updated)), updated.Format(time.RFC3339))
+		}
+	}
+
+	var versions datafile.AppVersions
+	var security datafile.SecurityInfo
+	haveVersions := readJSON(versionsJSON, &versions)
+	haveSecurity := readJSON(securityInfoJSON, &security)
+
+	fmt.Println("\n🔐 Security info")
+	if !haveVersions {
+		fmt.Println("   ⏭️  Skipped (app_versions.json unavailable)")
+	} else {
+		collected := make(map[string]string)
+		for _, app := range security.Apps {
+			collected[app.Slug] = app.Version
+		}
+		var missing, stale []string
+		for _, app := range versions.Apps {
+			version, exists := collected[app.Slug]
+			switch {
+			case !exists:
+				missing = append(missing, app.Slug)
+			case app.Version != "" && version != app.Version:
+				stale = append(stale, fmt.Sprintf("%s (%s, current %s)", app.Slug, version, app.Version))
+			}
+		}
+		fmt.Printf("   📦 %d apps tracked, %d with security info\n", len(versions.Apps), len(versions.Apps)-len(missing))
+		printStatusList("apps lack security info", missing)
+		printStatusList("security entries are for an older version", stale)
+	}
+
+	fmt.Println("\n🏃 Last successful runs")
+	if updated, err := lastSuccessfulRun(runStatsJSON); err == nil {
+		fmt.Printf("   📡 Tracker (main.go): %s ago\n", formatAge(now.Sub(updated)))
+	} else {
+		fmt.Println("   📡 Tracker (main.go): no successful run recorded")
+	}
+	// The collectors stamp every entry they write, so the newest entry per
+	// platform is their last run that collected anything
+	newest := make(map[string]time.Time)
+	if haveSecurity {
+		for _, app := range security.Apps {
+			platform := app.Slug[strings.LastIndex(app.Slug, "/")+1:]
+			if t, err := time.Parse(time.RFC3339, app.LastUpdated); err == nil && t.After(newest[platform]) {
+				newest[platform] = t
+			}
+		}
+	}
+	for _, collector := range []struct{ platform, label string }{{"darwin", "macOS collector"}, {"windows", "Windows collector"}} {
+		if t, ok := newest[collector.platform]; ok {
+			fmt.Printf("   🔐 %s: %s ago\n", collector.label, formatAge(now.Sub(t)))
+		} else {
+			fmt.Printf("   🔐 %s: no entries collected\n", collector.label)
+		}
+	}
+}
+
+func printStatusList(label string, slugs []string) {
+	if len(slugs) == 0 {
+		fmt.Printf("   ✅ 0 %s\n", label)
+		return
+	}
+	fmt.Printf("   ⚠️  %d %s:\n", len(slugs), label)
+	sort.Strings(slugs)
+	for i, slug := range slugs {
+		if i == statusListLimit {
+			fmt.Printf("      … and %d more\n", len(slugs)-statusListLimit)
+			break
+		}
+		fmt.Printf("      - %s\n", slug)
+	}
+}
+
+// formatAge renders a duration in days and hours, or hours and minutes
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+func lastCSVDate(path string) (time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(records) < 2 {
+		return time.Time{}, fmt.Errorf("no data rows")
+	}
+	return time.Parse("2006-01-02", records[len(records)-1][colDate])
+}
+
+func lastUpdatedField(path string) (time.Time, error) {
+	var data struct {
+		LastUpdated string `json:"lastUpdated"`
+	}
+	if err := readJSONFile(path, &data); err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, data.LastUpdated)
+}
+
+func newestChange(path string) (time.Time, error) {
+	var history datafile.VersionHistory
+	if err := readJSONFile(path, &history); err != nil {
+		return time.Time{}, err
+	}
+	var newest time.Time
+	for _, change := range history.Changes {
+		if t, err := time.Parse(time.RFC3339, change.Date); err == nil && t.After(newest) {
+			newest = t
+		}
+	}
+	if newest.IsZero() {
+		return newest, fmt.Errorf("no changes recorded")
+	}
+	return newest, nil
+}
+
+func lastSuccessfulRun(path string) (time.Time, error) {
+	var stats datafile.RunStats
+	if err := readJSONFile(path, &stats); err != nil {
+		return time.Time{}, err
+	}
+	// Runs are appended in order
+	for i := len(stats.Runs) - 1; i >= 0; i-- {
+		if stats.Runs[i].Success {
+			return time.Parse(time.RFC3339, stats.Runs[i].StartedAt)
+		}
+	}
+	return time.Time{}, fmt.Errorf("no successful run recorded")
+}
+
+func lastProbe(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var probe datafile.ProbeResult
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &probe); err != nil {
+		return time.Time{}, fmt.Errorf("invalid last line: %w", err)
+	}
+	return time.Parse(time.RFC3339, probe.Time)
+}
+
+// readJSONFile is readJSON with the error, so a missing file can be told apart
+func readJSONFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// envCheck is one prerequisite of the collector for this platform
+type envCheck struct {
+	name    string
+	check   func() (string, error) // Returns a detail to show when the check passes
+	fix     string
+	warning bool // Reported without counting as a problem
+}
+
+// doctorEnv checks the tools and settings the collector for this platform
+// relies on and returns the number of problems, so a run that would fail an
+// hour in fails up front instead
+func doctorEnv() int {
+	fmt.Printf("🩺 Checking the collection environment (%s)\n", runtime.GOOS)
+	fmt.Println("==============================================")
+	fmt.Println()
+
+	var checks []envCheck
+	switch runtime.GOOS {
+	case "darwin":
+		checks = macEnvChecks()
+	case "windows":
+		checks = windowsEnvChecks()
+	default:
+		fmt.Printf("   ⚠️  The collectors only run on macOS and Windows; checking the common prerequisites\n")
+	}
+	checks = append(checks, commonEnvChecks()...)
+
+	problems := 0
+	for _, c := range checks {
+		detail, err := c.check()
+		switch {
+		case err == nil && detail != "":
+			fmt.Printf("   ✅ %s (%s)\n", c.name, detail)
+		case err == nil:
+			fmt.Printf("   ✅ %s\n", c.name)
+		case c.warning:
+			fmt.Printf("   ⚠️  %s: %v\n      → %s\n", c.name, err, c.fix)
+		default:
+			fmt.Printf("   ❌ %s: %v\n      → %s\n", c.name, err, c.fix)
+			problems++
+		}
+	}
+
+	return problems
+}
+
+func macEnvChecks() []envCheck {
+	checks := []envCheck{
+		{
+			name:  "santactl installed",
+			check: lookPath("santactl"),
+			fix:   "install Santa with `brew install santa` or the PKG from https://github.com/northpolesec/santa/releases",
+		},
+		{
+			name:    "Santa daemon reachable",
+			check:   runCommand("santactl", "status"),
+			fix:     "approve Santa's system extension in System Settings > Privacy & Security; `santactl fileinfo` still works without it, but rules and events won't",
+			warning: true,
+		},
+	}
+	for _, tool := range []string{"hdiutil", "ditto", "codesign"} {
+		checks = append(checks, envCheck{
+			name:  tool + " available",
+			check: lookPath(tool),
+			fix:   "it ships with macOS; make sure /usr/bin is on PATH",
+		})
+	}
+	checks = append(checks, envCheck{
+		name:  "sudo without a password",
+		check: runCommand("sudo", "-n", "true"),
+		fix:   "the collector installs and removes apps with sudo; add a NOPASSWD rule for this user in /etc/sudoers.d",
+	})
+	return checks
+}
+
+func windowsEnvChecks() []envCheck {
+	return []envCheck{
+		{
+			name:  "PowerShell available",
+			check: lookPath("powershell"),
+			fix:   "install Windows PowerShell or put powershell.exe on PATH",
+		},
+		{
+			name:  "PowerShell execution policy",
+			check: executionPolicy,
+			fix:   "the collector runs its scripts with -ExecutionPolicy Bypass, but a Group Policy setting takes precedence; have the MachinePolicy or UserPolicy scope allow RemoteSigned or Bypass",
+		},
+		{
+			name:  "msiexec available",
+			check: lookPath("msiexec"),
+			fix:   "it ships with Windows; make sure C:\\Windows\\System32 is on PATH",
+		},
+	}
+}
+
+func commonEnvChecks() []envCheck {
+	return []envCheck{
+		{
+			name:  fmt.Sprintf("Free disk space in %s", os.TempDir()),
+			check: freeDiskSpace,
+			fix:   fmt.Sprintf("free up at least %d GB, or point TMPDIR (TEMP on Windows) at a larger volume", minFreeDiskGB),
+		},
+		{
+			name:  "git available",
+			check: lookPath("git"),
+			fix:   "install git; the collectors commit data/app_security_info.json with it",
+		},
+		{
+			name:    "git identity",
+			check:   gitIdentity,
+			fix:     "run `git config user.name` and `git config user.email` to commit results by hand; the collectors set a local identity when they commit",
+			warning: true,
+		},
+	}
+}
+
+// lookPath checks that a tool is on PATH
+func lookPath(name string) func() (string, error) {
+	return func() (string, error) {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			return "", fmt.Errorf("not found on PATH")
+		}
+		return path, nil
+	}
+}
+
+// runCommand checks that a command exits successfully within 30 seconds
+func runCommand(name string, args ...string) func() (string, error) {
+	return func() (string, error) {
+		output, err := commandOutput(name, args...)
+		if err != nil {
+			return "", err
+		}
+		return firstLine(output), nil
+	}
+}
+
+func commandOutput(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		if line := firstLine(string(output)); line != "" {
+			return "", fmt.Errorf("%w: %s", err, line)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+// executionPolicy fails when a Group Policy enforces a policy that
+// -ExecutionPolicy Bypass can't override
+func executionPolicy() (string, error) {
+	for _, scope := range []string{"MachinePolicy", "UserPolicy"} {
+		policy, err := commandOutput("powershell", "-NoProfile", "-Command", "Get-ExecutionPolicy -Scope "+scope)
+		if err != nil {
+			return "", err
+		}
+		if policy == "Restricted" || policy == "AllSigned" {
+			return "", fmt.Errorf("%s enforced by Group Policy (%s scope)", policy, scope)
+		}
+	}
+	policy, err := commandOutput("powershell", "-NoProfile", "-Command", "Get-ExecutionPolicy")
+	if err != nil {
+		return "", err
+	}
+	return policy + ", overridden with Bypass", nil
+}
+
+// freeDiskSpace checks the volume holding the temp directory, where
+// installers are downloaded and extracted
+func freeDiskSpace() (string, error) {
+	dir := os.TempDir()
+	var free int64
+	if runtime.GOOS == "windows" {
+		drive := strings.TrimSuffix(filepath.VolumeName(dir), ":")
+		output, err := commandOutput("powershell", "-NoProfile", "-Command", "(Get-PSDrive -Name "+drive+").Free")
+		if err != nil {
+			return "", err
+		}
+		if free, err = strconv.ParseInt(output, 10, 64); err != nil {
+			return "", fmt.Errorf("unexpected Get-PSDrive output %q", output)
+		}
+	} else {
+		// POSIX df: the fourth column of the second line is the available 1K blocks
+		output, err := commandOutput("df", "-Pk", dir)
+		if err != nil {
+			return "", err
+		}
+		lines := strings.Split(output, "\n")
+		fields := strings.Fields(lines[len(lines)-1])
+		if len(fields) < 4 {
+			return "", fmt.Errorf("unexpected df output %q", output)
+		}
+		kb, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("unexpected df output %q", output)
+		}
+		free = kb * 1024
+	}
+
+	freeGB := float64(free) / (1 << 30)
+	if freeGB < minFreeDiskGB {
+		return "", fmt.Errorf("%.1f GB free, need %d GB", freeGB, minFreeDiskGB)
+	}
+	return fmt.Sprintf("%.1f GB free", freeGB), nil
+}
+
+func gitIdentity() (string, error) {
+	name, _ := commandOutput("git", "config", "user.name")
+	email, _ := commandOutput("git", "config", "user.email")
+	if name == "" || email == "" {
+		return "", fmt.Errorf("user.name or user.email is not set")
+	}
+	return fmt.Sprintf("%s <%s>", name, email), nil
+}
+
+// doctorData checks the CSV and JSON data files and returns the number of
+// problems that remain (after fixing, when fix is set)
+func doctorData(fix bool) (int, error) {
+	fmt.Println("🩺 Checking data files")
+	fmt.Println("======================")
+	fmt.Println()
+
+	problems := 0
+
+	csvProblems, err := checkGrowthCSV(fix)
+	if err != nil {
+		return 0, err
+	}
+	problems += csvProblems
+
+	problems += checkJSONFiles()
+
+	return problems, nil
+}
+
+func checkGrowthCSV(fix bool) (int, error) {
+	fmt.Printf("📊 %s\n", growthCSV)
+
+	file, err := os.Open(growthCSV)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open CSV: %w", err)
+	}
+	records, err := csv.NewReader(file).ReadAll()
+	file.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	if len(records) < 2 {
+		fmt.Println("   ❌ CSV has no data rows")
+		return 1, nil
+	}
+
+	header, rows := records[0], records[1:]
+	problems := 0
+	report := func(format string, args ...interface{}) {
+		fmt.Printf("   ❌ "+format+"\n", args...)
+		problems++
+	}
+
+	var gaps []int // row indexes preceded by a gap
+	var prevDate time.Time
+	prevCount := -1
+	for i, row := range rows {
+		line := i + 2 // 1-based, after the header
+
+		if len(row) < len(header) {
+			report("line %d: expected %d columns, got %d", line, len(header), len(row))
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", row[colDate])
+		if err != nil {
+			report("line %d: invalid date %q", line, row[colDate])
+			continue
+		}
+
+		if !prevDate.IsZero() {
+			switch days := int(date.Sub(prevDate).Hours() / 24); {
+			case days <= 0:
+				report("line %d: date %s is not after %s", line, row[colDate], prevDate.Format("2006-01-02"))
+			case days > 1:
+				report("line %d: %d missing day(s) before %s", line, days-1, row[colDate])
+				gaps = append(gaps, i)
+			}
+		}
+		prevDate = date
+
+		count, countErr := strconv.Atoi(row[colCount])
+		mac, macErr := strconv.Atoi(row[colMac])
+		windows, windowsErr := strconv.Atoi(row[colWindows])
+		if countErr != nil || macErr != nil || windowsErr != nil {
+			report("line %d: non-numeric count", line)
+			continue
+		}
+		if count != mac+windows {
+			report("line %d: app_count %d != mac_count %d + windows_count %d", line, count, mac, windows)
+		}
+
+		if prevCount >= 0 && len(row) > colNet {
+			if net, err := strconv.Atoi(row[colNet]); err != nil || net != count-prevCount {
+				report("line %d: net_change %s doesn't match count change %d", line, row[colNet], count-prevCount)
+			}
+		}
+		prevCount = count
+	}
+
+	rowCount := len(rows)
+	if len(gaps) > 0 && fix {
+		fixed := fillGaps(header, rows)
+		rowCount = len(fixed)
+		if err := writeGrowthCSV(append([][]string{header}, fixed...)); err != nil {
+			return 0, err
+		}
+		fmt.Printf("   🔧 Filled %d gap(s) by carrying values forward (%d rows added)\n", len(gaps), len(fixed)-len(rows))
+		problems -= len(gaps)
+	}
+
+	if problems == 0 {
+		fmt.Printf("   ✅ %d rows, dates contiguous, counts consistent\n", rowCount)
+	}
+
+	return problems, nil
+}
+
+// fillGaps inserts a row for each missing day, carrying forward the previous
+// row's counts with no additions or removals
+func fillGaps(header []string, rows [][]string) [][]string {
+	var result [][]string
+	var prev []string
+	var prevDate time.Time
+
+	for _, row := range rows {
+		date, err := time.Parse("2006-01-02", row[colDate])
+		if err == nil && prev != nil {
+			for d := prevDate.AddDate(0, 0, 1); d.Before(date); d = d.AddDate(0, 0, 1) {
+				filler := make([]string, len(header))
+				copy(filler, prev)
+				filler[colDate] = d.Format("2006-01-02")
+				filler[colAdded] = "0"
+				if len(filler) > colRemoved {
+					filler[colRemoved] = "0"
+				}
+				if len(filler) > colNet {
+					filler[colNet] = "0"
+				}
+				result = append(result, filler)
+			}
+		}
+
+		result = append(result, row)
+		if err == nil {
+			prev = row
+			prevDate = date
+		}
+	}
+
+	return result
+}
+
+func writeGrowthCSV(records [][]string) error {
+	file, err := os.Create(growthCSV)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return nil
+}
+
+func checkJSONFiles() int {
+	problems := 0
+	report := func(format string, args ...interface{}) {
+		fmt.Printf("   ❌ "+format+"\n", args...)
+		problems++
+	}
+
+	// Every JSON data file must parse
+	fmt.Println("\n🧾 JSON files")
+	files := []struct{ path, kind string }{
+		{versionsJSON, schema.AppVersions},
+		{versionHistoryJSON, schema.VersionHistory},
+		{securityInfoJSON, schema.SecurityInfo},
+		{firstSeenJSON, schema.FirstSeen},
+		{checkpointJSON, schema.HistoryCheckpoint},
+		{runStatsJSON, schema.RunStats},
+		{appsMetadataJSON, schema.AppsMetadata},
+	}
+	// Yearly archives of the version history rotated out by main.go
+	archives, _ := filepath.Glob(versionHistoryArchives)
+	for _, path := range archives {
+		files = append(files, struct{ path, kind string }{path, schema.VersionHistory})
+	}
+	for _, file := range files {
+		path := file.path
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			fmt.Printf("   ⏭️  %s (not present)\n", path)
+			continue
+		}
+		if err != nil {
+			report("%s: %v", path, err)
+			continue
+		}
+		if !json.Valid(data) {
+			report("%s: invalid JSON", path)
+			continue
+		}
+		if _, err := schema.Upgrade(file.kind, data); err != nil {
+			report("%s: %v", path, err)
+			continue
+		}
+		if version, _ := schema.Version(data); version < schema.Current(file.kind) {
+			fmt.Printf("   ✅ %s (schema version %d, upgraded to %d on next write)\n", path, version, schema.Current(file.kind))
+			continue
+		}
+		fmt.Printf("   ✅ %s\n", path)
+	}
+
+	// Cross-reference the files against app_versions.json
+	fmt.Println("\n🔗 Cross-references")
+	var versions datafile.AppVersions
+	if !readJSON(versionsJSON, &versions) {
+		fmt.Println("   ⏭️  Skipped (app_versions.json unavailable)")
+		return problems
+	}
+	current := make(map[string]string)
+	for _, app := range versions.Apps {
+		current[app.Slug] = app.Version
+	}
+
+	before := problems
+
+	var security datafile.AppVersions
+	if readJSON(securityInfoJSON, &security) {
+		for _, app := range security.Apps {
+			if _, exists := current[app.Slug]; !exists {
+				report("%s: %s is not in %s", securityInfoJSON, app.Slug, versionsJSON)
+			}
+		}
+	}
+
+	var history datafile.VersionHistory
+	if readJSON(versionHistoryJSON, &history) {
+		latest := make(map[string]datafile.VersionChange)
+		for _, change := range history.Changes {
+			if _, err := time.Parse(time.RFC3339, change.Date); err != nil {
+				report("%s: %s has invalid date %q", versionHistoryJSON, change.Slug, change.Date)
+				continue
+			}
+			if prev, exists := latest[change.Slug]; !exists || change.Date > prev.Date {
+				latest[change.Slug] = change
+			}
+		}
+		for slug, change := range latest {
+			if version, exists := current[slug]; exists && version != "" && version != change.NewVersion {
+				report("%s: latest change for %s is %s but current version is %s", versionHistoryJSON, slug, change.NewVersion, version)
+			}
+		}
+	}
+
+	if problems == before {
+		fmt.Println("   ✅ Files reference each other consistently")
+	}
+
+	return problems
+}
+
+// readJSON decodes path into v, reporting whether it succeeded
+func readJSON(path string, v interface{}) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
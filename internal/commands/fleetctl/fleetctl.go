@@ -0,0 +1,357 @@
+// Package fleetctl implements "fleet-tracker fleetctl", which generates Fleet
+// GitOps software files; generate_fleetctl.go runs it on its own.
+package fleetctl
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionsJSON      = "data/app_versions.json"
+	securityInfoJSON  = "data/app_security_info.json"
+	appsMetadataJSON  = "data/apps_metadata.json"
+	outputFleetctlDir = "fleetctl"
+	outputSnippet     = "fleetctl/software.yml"
+	manifestURLFormat = "https://github.com/fleetdm/fleet/blob/main/ee/maintained-apps/outputs/%s.json"
+)
+
+// snippetFilter selects the apps included in the consolidated snippet
+type snippetFilter struct {
+	Platform     string
+	Category     string
+	VerifiedOnly bool
+}
+
+// Installer types Fleet writes default install and uninstall scripts for
+var defaultScriptTypes = map[string]bool{"pkg": true, "msi": true, "deb": true, "rpm": true}
+
+func generateFleetctl(snippetPath string, filter snippetFilter) error {
+	fmt.Println("📄 Generating fleetctl software package YAML...")
+
+	versions, err := loadVersions()
+	if err != nil {
+		return fmt.Errorf("failed to load app versions: %w", err)
+	}
+	security, err := loadSecurityInfo()
+	if err != nil {
+		return fmt.Errorf("failed to load security info: %w", err)
+	}
+
+	// Installer hashes by slug and version; current entries win over older ones
+	hashes := make(map[string]string)
+	for _, entry := range append(security.Versions, security.Apps...) {
+		if entry.InstallerSha256 != "" {
+			hashes[entry.Slug+"@"+entry.Version] = entry.InstallerSha256
+		}
+	}
+
+	if err := os.MkdirAll(outputFleetctlDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Remove the previous per-app files so apps that left the catalog don't linger
+	var stale []string
+	for _, platform := range []string{"darwin", "windows"} {
+		files, err := filepath.Glob(filepath.Join(outputFleetctlDir, "*-"+platform+".yml"))
+		if err != nil {
+			return fmt.Errorf("failed to list existing files: %w", err)
+		}
+		stale = append(stale, files...)
+	}
+	for _, file := range stale {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+
+	written, verified := 0, 0
+	for _, app := range versions.Apps {
+		if app.InstallerURL == "" {
+			fmt.Printf("  ⚠️  Skipping %s: no installer URL\n", app.Slug)
+			continue
+		}
+
+		hash := hashes[app.Slug+"@"+app.Version]
+		file := filepath.Join(outputFleetctlDir, packageFileName(app.Slug))
+		if err := os.WriteFile(file, []byte(packageYAML(app, hash)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+
+		written++
+		if hash != "" {
+			verified++
+		}
+	}
+
+	fmt.Printf("✅ Generated: %d files in %s/\n", written, outputFleetctlDir)
+	fmt.Printf("   🔒 %d with a verified installer hash\n", verified)
+
+	var categories map[string][]string
+	if filter.Category != "" {
+		if categories, err = loadCategories(); err != nil {
+			return fmt.Errorf("failed to load app categories: %w", err)
+		}
+	}
+
+	var selected []datafile.AppVersion
+	for _, app := range versions.Apps {
+		if app.InstallerURL == "" || (filter.Platform != "" && app.Platform != filter.Platform) {
+			continue
+		}
+		if filter.VerifiedOnly && hashes[app.Slug+"@"+app.Version] == "" {
+			continue
+		}
+		if filter.Category != "" && !hasCategory(categories[app.Slug], filter.Category) {
+			continue
+		}
+		selected = append(selected, app)
+	}
+
+	if dir := filepath.Dir(snippetPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(snippetPath, []byte(snippetYAML(selected, hashes, filter)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", snippetPath, err)
+	}
+	fmt.Printf("✅ Generated: %s (%d apps)\n", snippetPath, len(selected))
+
+	return nil
+}
+
+// snippetYAML renders the selected apps as the software section of a Fleet
+// GitOps team file
+func snippetYAML(apps []datafile.AppVersion, hashes map[string]string, filter snippetFilter) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Fleet-maintained apps as Fleet GitOps software packages, with installer hashes\n")
+	sb.WriteString("# collected independently by fmalibrary.com (" + describeFilter(filter) + ").\n")
+	sb.WriteString("# Generated by generate_fleetctl.go; paste into a team file. Installers other than\n")
+	sb.WriteString("# .pkg, .msi, .deb and .rpm also need install_script and uninstall_script, see the\n")
+	sb.WriteString("# app's file in fleetctl/.\n")
+	sb.WriteString("software:\n")
+	if len(apps) == 0 {
+		sb.WriteString("  packages: []\n")
+		return sb.String()
+	}
+
+	sb.WriteString("  packages:\n")
+	for _, app := range apps {
+		sb.WriteString(fmt.Sprintf("    # %s %s (%s)\n", app.Name, app.Version, getPlatformLabel(app.Platform)))
+		sb.WriteString("    - url: " + strconv.Quote(app.InstallerURL) + "\n")
+		if hash := hashes[app.Slug+"@"+app.Version]; hash != "" {
+			sb.WriteString("      hash_sha256: " + strconv.Quote(hash) + "\n")
+		} else {
+			sb.WriteString("      # hash_sha256: not collected for " + app.Version + " yet\n")
+		}
+	}
+
+	return sb.String()
+}
+
+func describeFilter(filter snippetFilter) string {
+	var parts []string
+	if filter.Platform != "" {
+		parts = append(parts, "platform: "+filter.Platform)
+	}
+	if filter.Category != "" {
+		parts = append(parts, "category: "+filter.Category)
+	}
+	if filter.VerifiedOnly {
+		parts = append(parts, "verified hashes only")
+	}
+	if len(parts) == 0 {
+		return "all apps"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func hasCategory(categories []string, category string) bool {
+	for _, c := range categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// packageFileName turns a slug like "zoom/darwin" into "zoom-darwin.yml"
+func packageFileName(slug string) string {
+	return strings.ReplaceAll(slug, "/", "-") + ".yml"
+}
+
+// packageYAML renders a Fleet GitOps software package file for app. hash is
+// the collected installer SHA-256 for app's current version, if any.
+func packageYAML(app datafile.AppVersion, hash string) string {
+	var sb strings.Builder
+	base := strings.TrimSuffix(packageFileName(app.Slug), ".yml")
+	manifestURL := fmt.Sprintf(manifestURLFormat, app.Slug)
+
+	sb.WriteString(fmt.Sprintf("# %s %s (%s)\n", app.Name, app.Version, getPlatformLabel(app.Platform)))
+	sb.WriteString("# Generated by generate_fleetctl.go from data/app_versions.json and data/app_security_info.json.\n")
+	sb.WriteString("# Copy it into your GitOps repo and list it under a team's software packages, e.g.:\n")
+	sb.WriteString("#   software:\n")
+	sb.WriteString("#     packages:\n")
+	sb.WriteString("#       - path: ../lib/software/" + packageFileName(app.Slug) + "\n")
+	sb.WriteString("url: " + strconv.Quote(app.InstallerURL) + "\n")
+	if hash != "" {
+		sb.WriteString("hash_sha256: " + strconv.Quote(hash) + "\n")
+	} else {
+		sb.WriteString("# hash_sha256: not collected for " + app.Version + " yet\n")
+	}
+
+	ext := installerType(app.InstallerURL)
+	sb.WriteString("\n")
+	if defaultScriptTypes[ext] {
+		sb.WriteString("# Fleet generates install and uninstall scripts for ." + ext + " packages. To use the\n")
+		sb.WriteString("# maintained app's own scripts instead, copy them from its manifest:\n")
+	} else {
+		label := "this installer"
+		if ext != "" {
+			label = "." + ext + " installers"
+		}
+		sb.WriteString("# Fleet needs install and uninstall scripts for " + label + ". Copy the maintained\n")
+		sb.WriteString("# app's scripts from its manifest:\n")
+	}
+	sb.WriteString("# " + manifestURL + "\n")
+	sb.WriteString("# install_script:\n")
+	sb.WriteString("#   path: ../lib/software/scripts/" + base + "-install" + scriptExtension(app.Platform) + "\n")
+	sb.WriteString("# uninstall_script:\n")
+	sb.WriteString("#   path: ../lib/software/scripts/" + base + "-uninstall" + scriptExtension(app.Platform) + "\n")
+
+	return sb.String()
+}
+
+// installerType returns the lowercase file extension of the installer URL's
+// path, or "" when it doesn't end in one (e.g. a redirecting download link)
+func installerType(installerURL string) string {
+	parsed, err := url.Parse(installerURL)
+	if err != nil {
+		return ""
+	}
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(parsed.Path), "."))
+	switch ext {
+	case "pkg", "dmg", "zip", "msi", "exe", "deb", "rpm":
+		return ext
+	}
+	return ""
+}
+
+func scriptExtension(platform string) string {
+	if platform == "windows" {
+		return ".ps1"
+	}
+	return ".sh"
+}
+
+func getPlatformLabel(platform string) string {
+	if platform == "darwin" {
+		return "Mac"
+	}
+	return "Windows"
+}
+
+func loadVersions() (*datafile.AppVersions, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+// loadCategories returns each app's categories from data/apps_metadata.json,
+// which generate_html.go writes
+func loadCategories() (map[string][]string, error) {
+	data, err := os.ReadFile(appsMetadataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppsMetadata, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata datafile.AppsMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string][]string, len(metadata.Apps))
+	for _, app := range metadata.Apps {
+		categories[app.Slug] = app.Categories
+	}
+	return categories, nil
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// Main runs fleet-tracker fleetctl with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("fleetctl", flag.ExitOnError)
+	var filter snippetFilter
+	snippetPath := fs.String("snippet", outputSnippet, "write the consolidated software snippet to this path")
+	fs.StringVar(&filter.Platform, "platform", "", "only include apps for this platform in the snippet (darwin or windows)")
+	fs.StringVar(&filter.Category, "category", "", "only include apps in this category in the snippet (e.g. Browsers)")
+	fs.BoolVar(&filter.VerifiedOnly, "verified-only", false, "only include apps whose current installer hash has been collected")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if filter.Platform != "" && filter.Platform != "darwin" && filter.Platform != "windows" {
+		fmt.Fprintf(os.Stderr, "❌ Error: invalid --platform %q (want darwin or windows)\n", filter.Platform)
+		os.Exit(1)
+	}
+
+	if err := generateFleetctl(*snippetPath, filter); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,621 @@
+// Package history implements "fleet-tracker history", which backfills the
+// version history from apps.json commits; build_history.go runs it on its own.
+package history
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/httpcache"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/vcr"
+)
+
+const (
+	repoOwner             = "fleetdm"
+	repoName              = "fleet"
+	appsJSONPath          = "ee/maintained-apps/outputs/apps.json"
+	versionHistoryJSON    = "data/version_history.json"
+	versionHistoryArchive = "data/version_history-%d.json" // Changes from before last year, one file per year
+	checkpointJSON        = "data/history_checkpoint.json"
+	firstSeenJSON         = "data/app_first_seen.json"
+	perPage               = 100 // GitHub API max per page
+)
+
+// ghClient is shared by all GitHub requests so they are authenticated and retried consistently
+var ghClient = github.NewClient()
+
+// GitHub endpoints, overridable through GITHUB_API_URL and GITHUB_RAW_URL to
+// read a GitHub Enterprise Server mirror of fleetdm/fleet
+var (
+	githubAPIBase = github.APIBase()
+	githubRawBase = github.RawBase()
+)
+
+// historyCheckpoint records how far the backfill has progressed so the next
+// invocation can resume where the previous one stopped
+type historyCheckpoint struct {
+	SchemaVersion     int                            `json:"schemaVersion"`
+	GeneratorVersion  string                         `json:"generatorVersion,omitempty"`
+	LastProcessedSha  string                         `json:"lastProcessedSha"`
+	LastProcessedDate string                         `json:"lastProcessedDate"`
+	ProcessedCommits  int                            `json:"processedCommits"`
+	Versions          map[string]datafile.AppVersion `json:"versions"`
+	LastUpdated       string                         `json:"lastUpdated"`
+}
+
+// Main runs fleet-tracker history with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	maxCommits := fs.Int("max-commits", 50, "maximum number of commits to process in this invocation (0 = no limit)")
+	concurrency := fs.Int("concurrency", 8, "number of app version files fetched in parallel per commit")
+	common.Register(fs)
+	fs.Parse(args)
+
+	ghClient.SetToken(common.GitHubToken)
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	httpcache.InstallFromEnv()
+	if err := vcr.InstallFromEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+
+	fmt.Println("📚 Building Historical Version Changes")
+	fmt.Println("=====================================")
+	fmt.Println("This will process commits to build version history.")
+	fmt.Println("This may take several minutes...")
+	if !ghClient.Authenticated() {
+		fmt.Println("⚠️  No GitHub token (GITHUB_TOKEN or --github-token): limited to 60 API requests an hour, pausing whenever they run out")
+	}
+	fmt.Println()
+
+	// Get all commits that changed apps.json
+	fmt.Println("📥 Fetching commit SHAs for apps.json...")
+	commitSHAs, err := getAllCommitSHAs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: failed to get commit SHAs: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(commitSHAs) == 0 {
+		fmt.Fprintf(os.Stderr, "❌ Error: no commits found\n")
+		os.Exit(1)
+	}
+
+	// Resume from the checkpoint, if any
+	checkpoint, err := loadCheckpoint()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: failed to load checkpoint: %v\n", err)
+		os.Exit(1)
+	}
+
+	startIndex := 0
+	previousVersions := make(map[string]datafile.AppVersion)
+	if checkpoint.LastProcessedSha != "" {
+		found := false
+		for i, commit := range commitSHAs {
+			if commit.Sha == checkpoint.LastProcessedSha {
+				startIndex = i + 1
+				found = true
+				break
+			}
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "❌ Error: checkpoint commit %s not found in history; delete %s to start over\n", checkpoint.LastProcessedSha, checkpointJSON)
+			os.Exit(1)
+		}
+		if checkpoint.Versions != nil {
+			previousVersions = checkpoint.Versions
+		}
+		fmt.Printf("🔖 Resuming after commit %s (%s)\n", checkpoint.LastProcessedSha[:7], checkpoint.LastProcessedDate)
+	}
+
+	remaining := commitSHAs[startIndex:]
+	if len(remaining) == 0 {
+		fmt.Printf("✅ History is complete: all %d commits have been processed\n", len(commitSHAs))
+		return
+	}
+
+	if *maxCommits > 0 && len(remaining) > *maxCommits {
+		remaining = remaining[:*maxCommits]
+	}
+
+	fmt.Printf("✅ Processing %d of %d remaining commits (%d total)...\n\n", len(remaining), len(commitSHAs)-startIndex, len(commitSHAs))
+
+	// Process commits in chronological order (oldest first)
+	history, err := loadVersionHistory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: failed to load version history: %v\n", err)
+		os.Exit(1)
+	}
+
+	firstSeen, err := loadFirstSeen()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: failed to load first-seen dates: %v\n", err)
+		os.Exit(1)
+	}
+
+	for i, commit := range remaining {
+		// Show progress every 5 commits
+		if i%5 == 0 || i == len(remaining)-1 {
+			fmt.Printf("📦 Processing commit %d/%d (%s)...\n", startIndex+i+1, len(commitSHAs), commit.Sha[:7])
+		}
+
+		// Fetch app versions at this commit
+		currentVersions, err := getAppVersionsAtCommit(commit.Sha, commit.Date, *concurrency)
+		if err != nil {
+			// Skip commits where we can't fetch versions, but still advance the checkpoint
+			checkpoint.LastProcessedSha = commit.Sha
+			checkpoint.LastProcessedDate = commit.Date
+			continue
+		}
+
+		// Compare with previous versions
+		var changes []datafile.VersionChange
+		if len(previousVersions) > 0 {
+			for slug, currentVersion := range currentVersions {
+				previousVersion, exists := previousVersions[slug]
+
+				if !exists && currentVersion.Version != "" {
+					// New app added
+					change := datafile.VersionChange{
+						Date:         commit.Date,
+						AppName:      currentVersion.Name,
+						Slug:         slug,
+						Platform:     currentVersion.Platform,
+						OldVersion:   "",
+						NewVersion:   currentVersion.Version,
+						InstallerURL: currentVersion.InstallerURL,
+					}
+					changes = append(changes, change)
+					fmt.Printf("  🆕 New app: %s (%s)\n", currentVersion.Name, currentVersion.Version)
+				} else if exists && previousVersion.Version != "" && currentVersion.Version != "" && previousVersion.Version != currentVersion.Version {
+					// Version changed
+					change := datafile.VersionChange{
+						Date:         commit.Date,
+						AppName:      currentVersion.Name,
+						Slug:         slug,
+						Platform:     currentVersion.Platform,
+						OldVersion:   previousVersion.Version,
+						NewVersion:   currentVersion.Version,
+						InstallerURL: currentVersion.InstallerURL,
+					}
+					changes = append(changes, change)
+					fmt.Printf("  📌 %s: %s → %s\n", currentVersion.Name, previousVersion.Version, currentVersion.Version)
+				}
+			}
+		}
+
+		// Merge so re-running over already processed commits adds nothing
+		mergeChanges(history, changes)
+
+		// Record the first commit each slug appears in, including the oldest commit
+		for slug, info := range currentVersions {
+			if _, exists := firstSeen[slug]; !exists {
+				firstSeen[slug] = datafile.AppFirstSeen{
+					Slug:      slug,
+					Name:      info.Name,
+					Platform:  info.Platform,
+					FirstSeen: commit.Date,
+					CommitSha: commit.Sha,
+				}
+			}
+		}
+
+		// Update previous versions for next iteration
+		previousVersions = currentVersions
+
+		checkpoint.LastProcessedSha = commit.Sha
+		checkpoint.LastProcessedDate = commit.Date
+		checkpoint.ProcessedCommits = startIndex + i + 1
+		checkpoint.Versions = previousVersions
+
+		// Save progress every 10 commits so an interrupted run loses little work
+		if (i+1)%10 == 0 {
+			if err := saveProgress(history, firstSeen, checkpoint); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to save progress: %v\n", err)
+			}
+		}
+	}
+
+	checkpoint.ProcessedCommits = startIndex + len(remaining)
+	if err := saveProgress(history, firstSeen, checkpoint); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n✅ Built historical version changes: %d entries\n", len(history.Changes))
+	fmt.Println("✅ Historical data saved to:", versionHistoryJSON)
+	fmt.Printf("✅ First-seen dates for %d apps saved to: %s\n", len(firstSeen), firstSeenJSON)
+
+	if left := len(commitSHAs) - checkpoint.ProcessedCommits; left > 0 {
+		fmt.Printf("🔖 Checkpoint saved to %s; %d commits remaining. Run again to continue.\n", checkpointJSON, left)
+	} else {
+		fmt.Println("✅ History is complete: all commits have been processed")
+	}
+	fmt.Println("\nNow run: fleet-tracker rss")
+}
+
+// saveProgress writes the version history and first-seen dates followed by the
+// checkpoint, so the checkpoint never points past changes that weren't saved
+func saveProgress(history *datafile.VersionHistory, firstSeen map[string]datafile.AppFirstSeen, checkpoint *historyCheckpoint) error {
+	// Sort by date (newest first)
+	sort.SliceStable(history.Changes, func(i, j int) bool {
+		return history.Changes[i].Date > history.Changes[j].Date
+	})
+
+	// Move changes from before last year into the yearly archives
+	if err := rotateHistory(history); err != nil {
+		return fmt.Errorf("failed to rotate version history: %w", err)
+	}
+	if err := writeVersionHistory(versionHistoryJSON, history); err != nil {
+		return err
+	}
+
+	if err := saveFirstSeen(firstSeen); err != nil {
+		return err
+	}
+
+	checkpoint.SchemaVersion = schema.Current(schema.HistoryCheckpoint)
+	checkpoint.GeneratorVersion = buildinfo.GeneratorVersion()
+	checkpoint.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	checkpointData, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	if err := os.WriteFile(checkpointJSON, checkpointData, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// rotateHistory moves the changes from before last year (relative to the
+// newest change) out of history into data/version_history-YYYY.json, one
+// archive per year, so the current file stays bounded without losing anything
+func rotateHistory(history *datafile.VersionHistory) error {
+	if len(history.Changes) == 0 {
+		return nil
+	}
+	newest, err := time.Parse(time.RFC3339, history.Changes[0].Date)
+	if err != nil {
+		return nil
+	}
+	keepFrom := newest.UTC().Year() - 1
+
+	kept := []datafile.VersionChange{}
+	archived := make(map[int][]datafile.VersionChange)
+	for _, c := range history.Changes {
+		t, err := time.Parse(time.RFC3339, c.Date)
+		if err != nil || t.UTC().Year() >= keepFrom {
+			kept = append(kept, c)
+			continue
+		}
+		archived[t.UTC().Year()] = append(archived[t.UTC().Year()], c)
+	}
+
+	for year, changes := range archived {
+		path := fmt.Sprintf(versionHistoryArchive, year)
+		archive, err := loadVersionHistoryFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		added := mergeChanges(archive, changes)
+		sort.SliceStable(archive.Changes, func(i, j int) bool {
+			return archive.Changes[i].Date > archive.Changes[j].Date
+		})
+		if err := writeVersionHistory(path, archive); err != nil {
+			return err
+		}
+		fmt.Printf("🗄️  Archived %d change(s) from %d to %s\n", added, year, path)
+	}
+
+	history.Changes = kept
+	return nil
+}
+
+func writeVersionHistory(path string, history *datafile.VersionHistory) error {
+	history.SchemaVersion = schema.Current(schema.VersionHistory)
+	history.GeneratorVersion = buildinfo.GeneratorVersion()
+	jsonData, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal version history: %w", err)
+	}
+
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write version history: %w", err)
+	}
+
+	return nil
+}
+
+// changeKey identifies a version change for deduplication. The date is
+// reduced to its UTC day so a change recorded by both the hourly tracker and
+// a history rebuild (which uses the commit time) is only stored once.
+func changeKey(c datafile.VersionChange) string {
+	day := c.Date
+	if t, err := time.Parse(time.RFC3339, c.Date); err == nil {
+		day = t.UTC().Format("2006-01-02")
+	}
+	return c.Slug + "|" + c.OldVersion + "|" + c.NewVersion + "|" + day
+}
+
+// mergeChanges adds incoming changes to history, skipping any already present,
+// and returns the number of changes added
+func mergeChanges(history *datafile.VersionHistory, incoming []datafile.VersionChange) int {
+	seen := make(map[string]bool, len(history.Changes))
+	for _, c := range history.Changes {
+		seen[changeKey(c)] = true
+	}
+
+	added := 0
+	for _, c := range incoming {
+		key := changeKey(c)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		history.Changes = append(history.Changes, c)
+		added++
+	}
+
+	return added
+}
+
+// loadFirstSeen returns the recorded first-seen dates keyed by slug
+func loadFirstSeen() (map[string]datafile.AppFirstSeen, error) {
+	firstSeen := make(map[string]datafile.AppFirstSeen)
+
+	data, err := os.ReadFile(firstSeenJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return firstSeen, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.FirstSeen, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileData datafile.FirstSeen
+	if err := json.Unmarshal(data, &fileData); err != nil {
+		return nil, err
+	}
+
+	for _, app := range fileData.Apps {
+		firstSeen[app.Slug] = app
+	}
+
+	return firstSeen, nil
+}
+
+func saveFirstSeen(firstSeen map[string]datafile.AppFirstSeen) error {
+	fileData := datafile.FirstSeen{
+		SchemaVersion:    schema.Current(schema.FirstSeen),
+		GeneratorVersion: buildinfo.GeneratorVersion(),
+		Apps:             make([]datafile.AppFirstSeen, 0, len(firstSeen)),
+		LastUpdated:      time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, app := range firstSeen {
+		fileData.Apps = append(fileData.Apps, app)
+	}
+
+	// Sort by first-seen date, then slug, so the file diffs cleanly
+	sort.Slice(fileData.Apps, func(i, j int) bool {
+		if fileData.Apps[i].FirstSeen != fileData.Apps[j].FirstSeen {
+			return fileData.Apps[i].FirstSeen < fileData.Apps[j].FirstSeen
+		}
+		return fileData.Apps[i].Slug < fileData.Apps[j].Slug
+	})
+
+	jsonData, err := json.MarshalIndent(fileData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal first-seen dates: %w", err)
+	}
+
+	if err := os.WriteFile(firstSeenJSON, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write first-seen dates: %w", err)
+	}
+
+	return nil
+}
+
+func loadCheckpoint() (*historyCheckpoint, error) {
+	data, err := os.ReadFile(checkpointJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &historyCheckpoint{}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.HistoryCheckpoint, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint historyCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+func loadVersionHistory() (*datafile.VersionHistory, error) {
+	return loadVersionHistoryFile(versionHistoryJSON)
+}
+
+// loadVersionHistoryFile reads the current version history or a yearly archive
+func loadVersionHistoryFile(path string) (*datafile.VersionHistory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.VersionHistory{Changes: []datafile.VersionChange{}}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.VersionHistory, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var history datafile.VersionHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+type githubCommitWithSha struct {
+	Sha  string
+	Date string
+}
+
+func getAllCommitSHAs() ([]githubCommitWithSha, error) {
+	var commitSHAs []githubCommitWithSha
+
+	url := fmt.Sprintf("%s/repos/%s/%s/commits?path=%s&per_page=%d",
+		githubAPIBase, repoOwner, repoName, appsJSONPath, perPage)
+
+	err := ghClient.GetPages(url, func(page int, body []byte) error {
+		var githubCommits []github.Commit
+		if err := json.Unmarshal(body, &githubCommits); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for _, gc := range githubCommits {
+			commitTime, err := time.Parse(time.RFC3339, gc.Commit.Author.Date)
+			if err != nil {
+				continue
+			}
+			commitSHAs = append(commitSHAs, githubCommitWithSha{
+				Sha:  gc.Sha,
+				Date: commitTime.UTC().Format(time.RFC3339),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commits: %w", err)
+	}
+
+	// Reverse to process oldest first (so we can track changes forward in time)
+	for i, j := 0, len(commitSHAs)-1; i < j; i, j = i+1, j-1 {
+		commitSHAs[i], commitSHAs[j] = commitSHAs[j], commitSHAs[i]
+	}
+
+	return commitSHAs, nil
+}
+
+// getAppVersionsAtCommit fetches every app's version file at the given commit
+// using up to concurrency workers. Pacing is left to the shared client's
+// adaptive throttle.
+func getAppVersionsAtCommit(sha, commitDate string, concurrency int) (map[string]datafile.AppVersion, error) {
+	// Fetch apps.json at this commit
+	appsJSONURL := fmt.Sprintf("%s/%s/%s/%s/%s", githubRawBase, repoOwner, repoName, sha, appsJSONPath)
+	body, err := ghClient.Get(appsJSONURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch apps.json: %w", err)
+	}
+
+	var appsData struct {
+		Apps []struct {
+			Name     string `json:"name"`
+			Slug     string `json:"slug"`
+			Platform string `json:"platform"`
+		} `json:"apps"`
+	}
+	if err := json.Unmarshal(body, &appsData); err != nil {
+		return nil, fmt.Errorf("failed to parse apps.json: %w", err)
+	}
+
+	jobs := make(chan datafile.AppVersion)
+	results := make(chan datafile.AppVersion)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for info := range jobs {
+				// Try to fetch version at this commit; if it fails, still include the app
+				version, installerURL, err := fetchAppVersionAndURLAtCommit(sha, info.Slug, info.Platform)
+				if err == nil {
+					info.Version = version
+					info.InstallerURL = installerURL
+				}
+				results <- info
+			}
+		}()
+	}
+
+	go func() {
+		for _, app := range appsData.Apps {
+			jobs <- datafile.AppVersion{
+				Slug:     app.Slug,
+				Name:     app.Name,
+				Platform: app.Platform,
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	versions := make(map[string]datafile.AppVersion)
+	for info := range results {
+		versions[info.Slug] = info
+	}
+
+	return versions, nil
+}
+
+func fetchAppVersionAndURLAtCommit(sha, slug, platform string) (version string, installerURL string, err error) {
+	// Try to fetch version file at this commit
+	url := fmt.Sprintf("%s/%s/%s/%s/ee/maintained-apps/outputs/%s.json", githubRawBase, repoOwner, repoName, sha, slug)
+
+	body, err := ghClient.Get(url)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch version file: %w", err)
+	}
+
+	var versionData struct {
+		Versions []struct {
+			Version      string `json:"version"`
+			InstallerURL string `json:"installer_url"`
+		} `json:"versions"`
+	}
+	if err := json.Unmarshal(body, &versionData); err != nil {
+		return "", "", fmt.Errorf("failed to parse version JSON: %w", err)
+	}
+
+	if len(versionData.Versions) == 0 {
+		return "", "", fmt.Errorf("no versions found")
+	}
+
+	// Return the first (latest) version and installer URL
+	return versionData.Versions[0].Version, versionData.Versions[0].InstallerURL, nil
+}
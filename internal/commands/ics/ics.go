@@ -0,0 +1,192 @@
+// Package ics implements "fleet-tracker ics", which generates releases.ics;
+// generate_ics.go runs it on its own.
+package ics
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionHistoryJSON = "data/version_history.json"
+	outputICS          = "releases.ics"
+	siteURL            = "https://fmalibrary.com"
+)
+
+func generateICS() error {
+	fmt.Println("📅 Generating iCal release calendar...")
+
+	history, err := loadVersionHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load version history: %w", err)
+	}
+
+	// Sort changes by date (oldest first) so the calendar reads chronologically
+	changes := history.Changes
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Date < changes[j].Date
+	})
+
+	icsContent := generateICSContent(changes)
+
+	if err := os.WriteFile(outputICS, []byte(icsContent), 0644); err != nil {
+		return fmt.Errorf("failed to write calendar file: %w", err)
+	}
+
+	fmt.Printf("✅ Generated: %s\n", outputICS)
+	fmt.Printf("   📝 %d events in calendar\n", len(changes))
+
+	return nil
+}
+
+func loadVersionHistory() (*datafile.VersionHistory, error) {
+	data, err := os.ReadFile(versionHistoryJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.VersionHistory{Changes: []datafile.VersionChange{}}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.VersionHistory, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var history datafile.VersionHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+func generateICSContent(changes []datafile.VersionChange) string {
+	var sb strings.Builder
+
+	// RFC 5545 requires CRLF line endings
+	writeLine := func(line string) {
+		sb.WriteString(foldICSLine(line))
+		sb.WriteString("\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//fmalibrary.com//Fleet-maintained apps releases " + buildinfo.GeneratorVersion() + "//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	writeLine("X-WR-CALNAME:Fleet-maintained apps releases")
+	writeLine("X-WR-CALDESC:Version updates and new app additions for Fleet-maintained apps")
+
+	for _, change := range changes {
+		t, err := time.Parse(time.RFC3339, change.Date)
+		if err != nil {
+			continue
+		}
+		t = t.UTC()
+
+		var summary, description string
+		if change.OldVersion == "" {
+			// New app added
+			summary = fmt.Sprintf("New App: %s %s (%s)", change.AppName, change.NewVersion, getPlatformLabel(change.Platform))
+			description = fmt.Sprintf("%s has been added to the Fleet-maintained apps library with version %s.", change.AppName, change.NewVersion)
+		} else if change.NewVersion == "" {
+			// App removed from the library
+			summary = fmt.Sprintf("Removed: %s (%s)", change.AppName, getPlatformLabel(change.Platform))
+			description = fmt.Sprintf("%s has been removed from the Fleet-maintained apps library. The last version Fleet maintained was %s.", change.AppName, change.OldVersion)
+		} else {
+			// Version update
+			summary = fmt.Sprintf("%s %s → %s (%s)", change.AppName, change.OldVersion, change.NewVersion, getPlatformLabel(change.Platform))
+			description = fmt.Sprintf("%s has been updated from version %s to %s.", change.AppName, change.OldVersion, change.NewVersion)
+		}
+		if change.InstallerURL != "" {
+			description += "\nInstaller: " + change.InstallerURL
+		}
+
+		uid := fmt.Sprintf("%s-%s-%s-%s@fmalibrary.com", strings.ReplaceAll(change.Slug, "/", "-"), change.OldVersion, change.NewVersion, t.Format("20060102T150405Z"))
+
+		// All-day events: DTEND is exclusive, so it is the following day
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + escapeICSText(uid))
+		writeLine("DTSTAMP:" + t.Format("20060102T150405Z"))
+		writeLine("DTSTART;VALUE=DATE:" + t.Format("20060102"))
+		writeLine("DTEND;VALUE=DATE:" + t.AddDate(0, 0, 1).Format("20060102"))
+		writeLine("SUMMARY:" + escapeICSText(summary))
+		writeLine("DESCRIPTION:" + escapeICSText(description))
+		writeLine("URL:" + siteURL)
+		writeLine("TRANSP:TRANSPARENT")
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+
+	return sb.String()
+}
+
+func getPlatformLabel(platform string) string {
+	if platform == "darwin" {
+		return "Mac"
+	}
+	return "Windows"
+}
+
+// escapeICSText escapes a value for use in an iCalendar TEXT property
+func escapeICSText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// foldICSLine folds a content line so no line exceeds 75 octets, without
+// splitting multi-byte UTF-8 characters
+func foldICSLine(line string) string {
+	const maxOctets = 75
+	if len(line) <= maxOctets {
+		return line
+	}
+
+	var sb strings.Builder
+	lineLen := 0
+	for _, r := range line {
+		runeLen := len(string(r))
+		if lineLen+runeLen > maxOctets {
+			sb.WriteString("\r\n ")
+			lineLen = 1 // Continuation lines start with a space
+		}
+		sb.WriteRune(r)
+		lineLen += runeLen
+	}
+	return sb.String()
+}
+
+// Main runs fleet-tracker ics with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("ics", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateICS(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,275 @@
+// Package intune implements "fleet-tracker intune", which generates Intune
+// detection rules; generate_intune.go runs it on its own.
+package intune
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	securityInfoJSON = "data/app_security_info.json"
+	outputIntuneDir  = "intune"
+	win32LobAppType  = "#microsoft.graph.win32LobApp"
+)
+
+// detectionRule is a Microsoft Graph win32LobAppRule: either a product code
+// rule or a file system rule, with ruleType "detection"
+type detectionRule struct {
+	ODataType              string `json:"@odata.type"`
+	RuleType               string `json:"ruleType"`
+	ProductCode            string `json:"productCode,omitempty"`
+	ProductVersionOperator string `json:"productVersionOperator,omitempty"`
+	ProductVersion         string `json:"productVersion,omitempty"`
+	Path                   string `json:"path,omitempty"`
+	FileOrFolderName       string `json:"fileOrFolderName,omitempty"`
+	Check32BitOn64System   *bool  `json:"check32BitOn64System,omitempty"`
+	OperationType          string `json:"operationType,omitempty"`
+	Operator               string `json:"operator,omitempty"`
+	ComparisonValue        string `json:"comparisonValue,omitempty"`
+}
+
+// detectionRules is a win32LobApp PATCH body that only sets its rules
+type detectionRules struct {
+	ODataType string          `json:"@odata.type"`
+	Rules     []detectionRule `json:"rules"`
+}
+
+// installRoot is where a top-level folder of an administrative install lands
+// on a 64-bit client
+type installRoot struct {
+	intunePath string // Environment variable Intune expands
+	is32Bit    bool   // Intune must expand intunePath as a 32-bit app would
+	powershell string // The same folder in PowerShell
+}
+
+// installRoots maps the lowercase top-level folders msiexec /a creates, named
+// after the MSI's standard directories or their default source names, to
+// where they install
+var installRoots = map[string]installRoot{
+	"programfiles64folder": {"%ProgramFiles%", false, "$env:ProgramFiles"},
+	"pfiles64":             {"%ProgramFiles%", false, "$env:ProgramFiles"},
+	"program files":        {"%ProgramFiles%", false, "$env:ProgramFiles"},
+	"programfilesfolder":   {"%ProgramFiles%", true, "${env:ProgramFiles(x86)}"},
+	"pfiles":               {"%ProgramFiles%", true, "${env:ProgramFiles(x86)}"},
+	"program files (x86)":  {"%ProgramFiles%", true, "${env:ProgramFiles(x86)}"},
+	"commonfiles64folder":  {"%CommonProgramFiles%", false, "$env:CommonProgramFiles"},
+	"commonfilesfolder":    {"%CommonProgramFiles%", true, "${env:CommonProgramFiles(x86)}"},
+	"localappdatafolder":   {"%LOCALAPPDATA%", false, "$env:LOCALAPPDATA"},
+	"appdatafolder":        {"%APPDATA%", false, "$env:APPDATA"},
+}
+
+// installedFile is the main executable's location once installed
+type installedFile struct {
+	root   installRoot
+	folder string // Backslash-separated, below root
+	name   string
+}
+
+func generateIntune() error {
+	fmt.Println("🪟 Generating Intune detection rules...")
+
+	security, err := loadSecurityInfo()
+	if err != nil {
+		return fmt.Errorf("failed to load security info: %w", err)
+	}
+
+	if err := os.MkdirAll(outputIntuneDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Remove the previous files so apps that left the catalog don't linger
+	for _, pattern := range []string{"*-windows.json", "*-windows.ps1"} {
+		stale, err := filepath.Glob(filepath.Join(outputIntuneDir, pattern))
+		if err != nil {
+			return fmt.Errorf("failed to list existing files: %w", err)
+		}
+		for _, file := range stale {
+			if err := os.Remove(file); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", file, err)
+			}
+		}
+	}
+
+	written, skipped := 0, 0
+	for _, entry := range security.Apps {
+		if !strings.HasSuffix(entry.Slug, "/windows") {
+			continue
+		}
+
+		file := locateExecutable(entry.ExecutablePath)
+		if file == nil && entry.ProductCode == "" {
+			skipped++
+			continue
+		}
+
+		base := filepath.Join(outputIntuneDir, strings.ReplaceAll(entry.Slug, "/", "-"))
+		rules, err := json.MarshalIndent(detectionRules{ODataType: win32LobAppType, Rules: rulesFor(entry, file)}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal rules for %s: %w", entry.Slug, err)
+		}
+		if err := os.WriteFile(base+".json", append(rules, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write %s.json: %w", base, err)
+		}
+		if err := os.WriteFile(base+".ps1", []byte(detectionScript(entry, file)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s.ps1: %w", base, err)
+		}
+		written++
+	}
+
+	fmt.Printf("✅ Generated: detection rules for %d apps in %s/\n", written, outputIntuneDir)
+	fmt.Printf("   ⏭️  %d Windows apps without a recorded product code or install path\n", skipped)
+
+	return nil
+}
+
+// locateExecutable maps an executable path recorded relative to the MSI
+// install point to where it is installed, or nil when the path is unknown or
+// its top-level folder isn't one of installRoots
+func locateExecutable(executablePath string) *installedFile {
+	parts := strings.Split(executablePath, "/")
+	if len(parts) < 2 {
+		return nil
+	}
+	root, ok := installRoots[strings.ToLower(parts[0])]
+	if !ok {
+		return nil
+	}
+	return &installedFile{
+		root:   root,
+		folder: strings.Join(parts[1:len(parts)-1], `\`),
+		name:   parts[len(parts)-1],
+	}
+}
+
+// rulesFor prefers a file version rule: product codes of many MSIs change
+// with every release, so an app that updated itself would no longer be
+// detected by the code collected for this version
+func rulesFor(entry datafile.AppSecurityInfo, file *installedFile) []detectionRule {
+	if file != nil {
+		rule := detectionRule{
+			ODataType:            "#microsoft.graph.win32LobAppFileSystemRule",
+			RuleType:             "detection",
+			Path:                 strings.TrimSuffix(file.root.intunePath+`\`+file.folder, `\`),
+			FileOrFolderName:     file.name,
+			Check32BitOn64System: &file.root.is32Bit,
+			OperationType:        "exists",
+			Operator:             "notConfigured",
+		}
+		if entry.FileVersion != "" {
+			rule.OperationType = "version"
+			rule.Operator = "greaterThanOrEqual"
+			rule.ComparisonValue = entry.FileVersion
+		}
+		return []detectionRule{rule}
+	}
+
+	rule := detectionRule{
+		ODataType:              "#microsoft.graph.win32LobAppProductCodeRule",
+		RuleType:               "detection",
+		ProductCode:            entry.ProductCode,
+		ProductVersionOperator: "notConfigured",
+	}
+	if entry.ProductVersion != "" {
+		rule.ProductVersionOperator = "greaterThanOrEqual"
+		rule.ProductVersion = entry.ProductVersion
+	}
+	return []detectionRule{rule}
+}
+
+// detectionScript renders a custom detection script for the same check as
+// rulesFor, which also requires a valid signature from the recorded
+// publisher when the executable's location is known
+func detectionScript(entry datafile.AppSecurityInfo, file *installedFile) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s %s (Windows) - Intune detection script\n", entry.Name, entry.Version))
+	sb.WriteString("# Generated by generate_intune.go from data/app_security_info.json. Intune treats\n")
+	sb.WriteString("# the app as installed when this writes to stdout and exits 0.\n")
+
+	if file != nil {
+		relative := strings.TrimPrefix(file.folder+`\`+file.name, `\`)
+		sb.WriteString(fmt.Sprintf("$file = Join-Path %s %s\n", file.root.powershell, psQuote(relative)))
+		sb.WriteString("if (-not (Test-Path -LiteralPath $file)) { exit 1 }\n")
+		sb.WriteString("$info = (Get-Item -LiteralPath $file).VersionInfo\n")
+		sb.WriteString("$version = [version]('{0}.{1}.{2}.{3}' -f $info.FileMajorPart, $info.FileMinorPart, $info.FileBuildPart, $info.FilePrivatePart)\n")
+		if entry.FileVersion != "" {
+			sb.WriteString(fmt.Sprintf("if ($version -lt [version]%s) { exit 1 }\n", psQuote(entry.FileVersion)))
+		}
+		if entry.Publisher != "" && (entry.SignatureStatus == "" || entry.SignatureStatus == "valid") {
+			sb.WriteString("$signature = Get-AuthenticodeSignature -LiteralPath $file\n")
+			sb.WriteString(fmt.Sprintf("if ($signature.Status -ne 'Valid' -or $signature.SignerCertificate.Subject -ne %s) { exit 1 }\n", psQuote(entry.Publisher)))
+		}
+		sb.WriteString(fmt.Sprintf("Write-Output \"%s $version\"\n", psDoubleQuoted(entry.Name)))
+		sb.WriteString("exit 0\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("$code = %s\n", psQuote(entry.ProductCode)))
+	sb.WriteString("$uninstall = 'HKLM:\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Uninstall', 'HKLM:\\SOFTWARE\\WOW6432Node\\Microsoft\\Windows\\CurrentVersion\\Uninstall'\n")
+	sb.WriteString("$key = $uninstall | ForEach-Object { Join-Path $_ $code } | Where-Object { Test-Path -LiteralPath $_ } | Select-Object -First 1\n")
+	sb.WriteString("if (-not $key) { exit 1 }\n")
+	sb.WriteString("$version = (Get-ItemProperty -LiteralPath $key).DisplayVersion\n")
+	if entry.ProductVersion != "" {
+		sb.WriteString(fmt.Sprintf("if ([version]$version -lt [version]%s) { exit 1 }\n", psQuote(entry.ProductVersion)))
+	}
+	sb.WriteString(fmt.Sprintf("Write-Output \"%s $version\"\n", psDoubleQuoted(entry.Name)))
+	sb.WriteString("exit 0\n")
+	return sb.String()
+}
+
+// psQuote returns s as a single-quoted PowerShell string
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// psDoubleQuoted escapes s for use inside a double-quoted PowerShell string
+func psDoubleQuoted(s string) string {
+	return strings.NewReplacer("`", "``", "$", "`$", `"`, "`\"").Replace(s)
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// Main runs fleet-tracker intune with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("intune", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateIntune(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
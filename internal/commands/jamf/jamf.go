@@ -0,0 +1,165 @@
+// Package jamf implements "fleet-tracker jamf", which generates Jamf extension
+// attributes; generate_jamf.go runs it on its own.
+package jamf
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	securityInfoJSON = "data/app_security_info.json"
+	outputJamfDir    = "jamf"
+)
+
+func generateJamf() error {
+	fmt.Println("🧾 Generating Jamf extension attribute scripts...")
+
+	security, err := loadSecurityInfo()
+	if err != nil {
+		return fmt.Errorf("failed to load security info: %w", err)
+	}
+
+	if err := os.MkdirAll(outputJamfDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Remove the previous scripts so apps that left the catalog don't linger
+	stale, err := filepath.Glob(filepath.Join(outputJamfDir, "*-darwin.sh"))
+	if err != nil {
+		return fmt.Errorf("failed to list existing files: %w", err)
+	}
+	for _, file := range stale {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+
+	written, skipped := 0, 0
+	for _, entry := range security.Apps {
+		if !strings.HasSuffix(entry.Slug, "/darwin") {
+			continue
+		}
+		if entry.TeamID == "" || (entry.BundlePath == "" && entry.BundleID == "") {
+			skipped++
+			continue
+		}
+
+		file := filepath.Join(outputJamfDir, strings.ReplaceAll(entry.Slug, "/", "-")+".sh")
+		if err := os.WriteFile(file, []byte(extensionAttribute(entry)), 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		written++
+	}
+
+	fmt.Printf("✅ Generated: %d extension attributes in %s/\n", written, outputJamfDir)
+	fmt.Printf("   ⏭️  %d Mac apps without a recorded Team ID or bundle\n", skipped)
+
+	return nil
+}
+
+// extensionAttribute renders a Jamf Pro extension attribute script that
+// reports whether the installed app is signed by the recorded Team ID and is
+// the tracked version. The app is looked up at its recorded path, falling
+// back to Spotlight by bundle identifier for apps installed elsewhere.
+func extensionAttribute(entry datafile.AppSecurityInfo) string {
+	version := entry.BundleVersion
+	if version == "" {
+		version = entry.Version
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(fmt.Sprintf("# %s %s (Mac) - Jamf Pro extension attribute\n", entry.Name, entry.Version))
+	sb.WriteString("# Generated by generate_jamf.go from data/app_security_info.json. Reports\n")
+	sb.WriteString("# \"Match\", \"Not installed\", \"Invalid signature\", \"Team ID mismatch: <id>\" or\n")
+	sb.WriteString("# \"Version mismatch: <version>\"; use a String data type.\n")
+	sb.WriteString("\n")
+	sb.WriteString("expected_team_id=" + shQuote(entry.TeamID) + "\n")
+	sb.WriteString("expected_version=" + shQuote(version) + "\n")
+	sb.WriteString("app_path=" + shQuote(entry.BundlePath) + "\n")
+	if entry.BundleID != "" {
+		sb.WriteString("bundle_id=" + shQuote(entry.BundleID) + "\n")
+		sb.WriteString("\n")
+		sb.WriteString("if [ ! -d \"$app_path\" ]; then\n")
+		sb.WriteString("  app_path=$(/usr/bin/mdfind \"kMDItemCFBundleIdentifier == '$bundle_id'\" | /usr/bin/head -n 1)\n")
+		sb.WriteString("fi\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString("if [ -z \"$app_path\" ] || [ ! -d \"$app_path\" ]; then\n")
+	sb.WriteString("  echo \"<result>Not installed</result>\"\n")
+	sb.WriteString("  exit 0\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("\n")
+	sb.WriteString("if ! /usr/bin/codesign --verify \"$app_path\" 2>/dev/null; then\n")
+	sb.WriteString("  echo \"<result>Invalid signature</result>\"\n")
+	sb.WriteString("  exit 0\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("\n")
+	sb.WriteString("team_id=$(/usr/bin/codesign -dv \"$app_path\" 2>&1 | /usr/bin/awk -F= '/^TeamIdentifier=/ { print $2 }')\n")
+	sb.WriteString("if [ \"$team_id\" != \"$expected_team_id\" ]; then\n")
+	sb.WriteString("  echo \"<result>Team ID mismatch: ${team_id:-none}</result>\"\n")
+	sb.WriteString("  exit 0\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("\n")
+	sb.WriteString("version=$(/usr/bin/defaults read \"$app_path/Contents/Info\" CFBundleShortVersionString 2>/dev/null)\n")
+	sb.WriteString("if [ \"$version\" != \"$expected_version\" ]; then\n")
+	sb.WriteString("  echo \"<result>Version mismatch: ${version:-unknown}</result>\"\n")
+	sb.WriteString("  exit 0\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("\n")
+	sb.WriteString("echo \"<result>Match</result>\"\n")
+
+	return sb.String()
+}
+
+// shQuote returns s as a single-quoted shell word
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// Main runs fleet-tracker jamf with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("jamf", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateJamf(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
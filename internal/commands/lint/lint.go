@@ -0,0 +1,278 @@
+// Package lint implements "fleet-tracker lint", which checks apps.json and the
+// data files for consistency problems; lint.go runs it on its own.
+package lint
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/httpcache"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionsJSON       = "data/app_versions.json"
+	securityInfoJSON   = "data/app_security_info.json"
+	versionHistoryJSON = "data/version_history.json"
+)
+
+// ghClient is shared by all GitHub requests so they are authenticated and retried consistently
+var ghClient = github.NewClient()
+
+// appsJSONURL honors GITHUB_API_URL and GITHUB_RAW_URL like main.go
+var appsJSONURL = github.RawBase() + "/fleetdm/fleet/main/ee/maintained-apps/outputs/apps.json"
+
+type upstreamApp struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Platform string `json:"platform"`
+}
+
+// lintReport collects problems grouped by check
+type lintReport struct {
+	checks []string
+	issues map[string][]string
+}
+
+func newLintReport() *lintReport {
+	return &lintReport{issues: make(map[string][]string)}
+}
+
+func (r *lintReport) check(name string) {
+	r.checks = append(r.checks, name)
+}
+
+func (r *lintReport) add(check, format string, args ...interface{}) {
+	r.issues[check] = append(r.issues[check], fmt.Sprintf(format, args...))
+}
+
+func (r *lintReport) count() int {
+	total := 0
+	for _, issues := range r.issues {
+		total += len(issues)
+	}
+	return total
+}
+
+func (r *lintReport) print() {
+	for _, check := range r.checks {
+		issues := r.issues[check]
+		if len(issues) == 0 {
+			fmt.Printf("✅ %s\n", check)
+			continue
+		}
+		fmt.Printf("❌ %s (%d)\n", check, len(issues))
+		for _, issue := range issues {
+			fmt.Printf("   - %s\n", issue)
+		}
+	}
+}
+
+// Main runs fleet-tracker lint with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	appsJSONPath := fs.String("apps-json", "", "read apps.json from this file instead of fetching it from fleetdm/fleet")
+	common.Register(fs)
+	fs.Parse(args)
+
+	ghClient.SetToken(common.GitHubToken)
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	httpcache.InstallFromEnv()
+
+	fmt.Println("🔍 Linting Fleet-maintained apps data")
+	fmt.Println("=====================================")
+	fmt.Println()
+
+	report, err := runLint(*appsJSONPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	report.print()
+
+	if n := report.count(); n > 0 {
+		fmt.Printf("\n❌ Found %d problem(s)\n", n)
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ No problems found")
+}
+
+func runLint(appsJSONPath string) (*lintReport, error) {
+	upstream, err := loadUpstreamApps(ghClient, appsJSONPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load apps.json: %w", err)
+	}
+
+	versions, err := loadJSONFile[datafile.AppVersions](versionsJSON, schema.AppVersions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load app versions: %w", err)
+	}
+
+	security, err := loadJSONFile[datafile.SecurityInfo](securityInfoJSON, schema.SecurityInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load security info: %w", err)
+	}
+
+	history, err := loadJSONFile[datafile.VersionHistory](versionHistoryJSON, schema.VersionHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load version history: %w", err)
+	}
+
+	report := newLintReport()
+
+	// Duplicate slugs
+	const dupCheck = "No duplicate slugs"
+	report.check(dupCheck)
+	for _, slug := range duplicates(upstreamSlugs(upstream)) {
+		report.add(dupCheck, "apps.json: %s", slug)
+	}
+	var versionSlugs []string
+	for _, app := range versions.Apps {
+		versionSlugs = append(versionSlugs, app.Slug)
+	}
+	for _, slug := range duplicates(versionSlugs) {
+		report.add(dupCheck, "%s: %s", versionsJSON, slug)
+	}
+
+	published := make(map[string]bool)
+	for _, app := range upstream {
+		published[app.Slug] = true
+	}
+
+	// Apps missing versions
+	const versionCheck = "All published apps have a tracked version"
+	report.check(versionCheck)
+	tracked := make(map[string]datafile.AppVersion)
+	for _, app := range versions.Apps {
+		tracked[app.Slug] = app
+	}
+	for _, slug := range sortedKeys(published) {
+		app, exists := tracked[slug]
+		if !exists {
+			report.add(versionCheck, "%s is not in %s", slug, versionsJSON)
+		} else if app.Version == "" {
+			report.add(versionCheck, "%s has no version", slug)
+		}
+	}
+
+	// Security entries for slugs no longer published
+	const securityCheck = "No security info for unpublished apps"
+	report.check(securityCheck)
+	for _, app := range security.Apps {
+		if !published[app.Slug] {
+			report.add(securityCheck, "%s (%s)", app.Slug, app.Version)
+		}
+	}
+
+	// History entries for slugs that were never tracked
+	const historyCheck = "No orphaned version history entries"
+	report.check(historyCheck)
+	orphaned := make(map[string]int)
+	for _, change := range history.Changes {
+		if change.Slug == "" {
+			report.add(historyCheck, "change on %s has no slug", change.Date)
+			continue
+		}
+		if !published[change.Slug] {
+			if _, exists := tracked[change.Slug]; !exists {
+				orphaned[change.Slug]++
+			}
+		}
+	}
+	for _, slug := range sortedKeys(orphaned) {
+		report.add(historyCheck, "%s (%d entries)", slug, orphaned[slug])
+	}
+
+	return report, nil
+}
+
+func loadUpstreamApps(client *github.Client, path string) ([]upstreamApp, error) {
+	var body []byte
+	var err error
+	if path != "" {
+		body, err = os.ReadFile(path)
+	} else {
+		body, err = client.Get(appsJSONURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Apps []upstreamApp `json:"apps"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	return data.Apps, nil
+}
+
+// loadJSONFile decodes a data file of the given schema kind, upgrading older
+// files and returning the zero value if it doesn't exist
+func loadJSONFile[T any](path, kind string) (*T, error) {
+	var v T
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &v, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(kind, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func upstreamSlugs(apps []upstreamApp) []string {
+	slugs := make([]string, 0, len(apps))
+	for _, app := range apps {
+		slugs = append(slugs, app.Slug)
+	}
+	return slugs
+}
+
+// duplicates returns the values that occur more than once, sorted
+func duplicates(values []string) []string {
+	seen := make(map[string]int)
+	for _, v := range values {
+		seen[v]++
+	}
+
+	var dups []string
+	for v, n := range seen {
+		if n > 1 {
+			dups = append(dups, v)
+		}
+	}
+	sort.Strings(dups)
+	return dups
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
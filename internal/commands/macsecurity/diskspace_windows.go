@@ -0,0 +1,9 @@
+package macsecurity
+
+import "errors"
+
+// freeDiskSpace lets fleet-tracker, which links both collectors, build on
+// Windows, where it runs the Windows collector instead
+func freeDiskSpace(path string) (uint64, error) {
+	return 0, errors.New("the macOS collector doesn't run on Windows")
+}
@@ -0,0 +1,3846 @@
+// Package macsecurity implements "fleet-tracker security", which collects
+// security info on macOS apps; cmd/collect-security-info/main.go runs it on
+// its own.
+package macsecurity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/summary"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/throttle"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/tlsinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/tracing"
+)
+
+const (
+	securityVersionsJSON = "data/app_versions.json"
+	securityInfoJSON     = "data/app_security_info.json"
+	installerMirrorsJSON = "data/installer_mirrors.json"
+	hashDriftJSONL       = "data/hash_drift.jsonl"
+	tempDir              = "/tmp/fleet-app-install"
+	applicationsDir      = "/Applications"
+	diskSpaceMargin      = 1 << 30 // Free space required on top of the installer size
+	rosettaRuntime       = "/Library/Apple/usr/libexec/oah/libRosettaRuntime"
+)
+
+// pppcServices maps the usage description keys a PPPC profile can preapprove
+// or deny to the Services key of com.apple.TCC.configuration-profile-policy
+var pppcServices = map[string]string{
+	"NSAppleEventsUsageDescription":          "AppleEvents",
+	"NSBluetoothAlwaysUsageDescription":      "BluetoothAlways",
+	"NSCalendarsUsageDescription":            "Calendar",
+	"NSCameraUsageDescription":               "Camera",
+	"NSContactsUsageDescription":             "AddressBook",
+	"NSDesktopFolderUsageDescription":        "SystemPolicyDesktopFolder",
+	"NSDocumentsFolderUsageDescription":      "SystemPolicyDocumentsFolder",
+	"NSDownloadsFolderUsageDescription":      "SystemPolicyDownloadsFolder",
+	"NSMicrophoneUsageDescription":           "Microphone",
+	"NSNetworkVolumesUsageDescription":       "SystemPolicyNetworkVolumes",
+	"NSPhotoLibraryUsageDescription":         "Photos",
+	"NSRemindersUsageDescription":            "Reminders",
+	"NSRemovableVolumesUsageDescription":     "SystemPolicyRemovableVolumes",
+	"NSSpeechRecognitionUsageDescription":    "SpeechRecognition",
+	"NSSystemAdministrationUsageDescription": "SystemPolicySysAdminFiles",
+}
+
+// Persistence kinds
+const (
+	persistenceLaunchDaemon = "launch-daemon"
+	persistenceLaunchAgent  = "launch-agent"
+	persistenceLoginItem    = "login-item"
+)
+
+// Anomaly types
+const (
+	anomalyUnchangedBinary = "unchanged-binary"
+	anomalyComponentSigner = "component-signer-changed"
+)
+
+// defaultMaxVersions bounds --all-versions to the most recent published versions per app
+const defaultMaxVersions = 5
+
+// Tracing is enabled when an OTLP endpoint is configured (see internal/tracing).
+// appSpan is the span of the app currently being processed, so helpers deep in
+// the install path can attach their stages to it.
+var (
+	tracer  = tracing.NewFromEnv("collect-security-info")
+	appSpan *tracing.Span
+)
+
+// installerMirrors maps an app slug, or "*" for every app, to fallback
+// installer URLs tried when the published one fails (see loadInstallerMirrors)
+var installerMirrors map[string][]string
+
+// runCtx is cancelled on SIGINT/SIGTERM so in-flight downloads and installer
+// commands abort instead of running on while progress is saved
+var runCtx, cancelRun = context.WithCancel(context.Background())
+
+// downloadRateLimit caps installer downloads in bytes per second (0 = unlimited)
+var downloadRateLimit int64
+
+// collectComponents enables hashing the frameworks and dylibs of each app
+var collectComponents bool
+
+// companionApps holds the other top-level .app bundles the current installer
+// shipped (an uninstaller, suite components), copied next to the main app
+// so they can be collected too
+var companionApps []string
+
+// launchDirs are the launchd directories installers drop jobs in; "~" is the
+// runner user's home
+var launchDirs = []struct{ dir, kind string }{
+	{"/Library/LaunchDaemons", persistenceLaunchDaemon},
+	{"/Library/LaunchAgents", persistenceLaunchAgent},
+	{"~/Library/LaunchAgents", persistenceLaunchAgent},
+}
+
+// mountedDMGs holds the mount points currently attached, so an interruption
+// can detach them before exiting
+var (
+	mountsMu    sync.Mutex
+	mountedDMGs = make(map[string]bool)
+)
+
+// notaryTicketURL is the public CloudKit endpoint Gatekeeper queries for
+// notarization tickets, keyed by code directory hash
+var notaryTicketURL = "https://api.apple-cloudkit.com/database/1/com.apple.gk.ticket-delivery/production/public/records/lookup"
+
+// runner executes every external tool the collector uses apart from git;
+// tests swap it for a fake so parsing and control flow run off macOS
+var runner CommandRunner = execRunner{}
+
+// Main runs fleet-tracker security with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("security", flag.ExitOnError)
+	testMode := fs.Bool("test", false, "process only the first app")
+	verifyOnly := fs.Bool("verify-only", false, "re-download and re-hash current installers without installing them")
+	allVersions := fs.Bool("all-versions", false, "also collect security info for older published versions")
+	maxVersions := fs.Int("max-versions", defaultMaxVersions, "with --all-versions, the most recent published versions examined per app (including the latest)")
+	maxBandwidth := fs.String("max-bandwidth", "", "limit installer downloads to this many bytes per second, e.g. 500K or 5M (default unlimited)")
+	fs.BoolVar(&collectComponents, "components", false, "also hash and read the signing IDs of the frameworks and dylibs in Contents/Frameworks")
+	resultsPath := fs.String("results", "", "write whether each app was collected to this JSON file, for fleet-tracker track-failures")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("🔒 Collecting macOS App Security Information")
+	fmt.Println("============================================")
+	fmt.Println()
+
+	rate, err := throttle.ParseRate(*maxBandwidth)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: invalid --max-bandwidth: %v\n", err)
+		os.Exit(1)
+	}
+	if rate > 0 {
+		downloadRateLimit = rate
+		fmt.Printf("🐢 Limiting installer downloads to %s/s\n", *maxBandwidth)
+	}
+
+	installerMirrors, err = loadInstallerMirrors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error loading %s: %v\n", installerMirrorsJSON, err)
+		os.Exit(1)
+	}
+
+	// Load current app versions
+	versions, err := loadAppVersions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error loading app versions: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Load existing security info
+	existingSecurity, err := loadSecurityInfo()
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Error loading existing security info: %v (will reprocess all apps)\n", err)
+	}
+	existingMap := make(map[string]datafile.AppSecurityInfo)
+	existingVersionsMap := make(map[string]datafile.AppSecurityInfo)
+	if existingSecurity != nil {
+		for _, app := range existingSecurity.Apps {
+			existingMap[app.Slug] = app
+		}
+		for _, info := range existingSecurity.Versions {
+			existingVersionsMap[versionKey(info.Slug, info.Version)] = info
+		}
+		fmt.Printf("📋 Loaded %d existing security info entries\n", len(existingMap))
+	} else {
+		fmt.Printf("📋 No existing security info found (starting fresh)\n")
+	}
+
+	// Re-download and re-hash installers without installing anything
+	if *verifyOnly {
+		os.Exit(verifyInstallerHashes(versions, existingMap))
+	}
+
+	// Filter to macOS apps only
+	var macApps []datafile.AppVersion
+	for _, app := range versions.Apps {
+		if app.Platform == "darwin" && app.InstallerURL != "" {
+			// Check if we need to update this app
+			existing, exists := existingMap[app.Slug]
+			if !exists || existing.Version != app.Version {
+				macApps = append(macApps, app)
+			}
+		}
+	}
+
+	// Older published versions that have no security info yet
+	var olderVersions []datafile.AppVersion
+	if *allVersions {
+		olderVersions = findOlderVersions(versions.Apps, "darwin", *maxVersions, existingVersionsMap)
+	}
+
+	if len(macApps) == 0 && len(olderVersions) == 0 {
+		fmt.Println("✅ All macOS apps are up to date. No security info collection needed.")
+		return
+	}
+
+	// Check for test mode (limit to first app)
+	if *testMode {
+		if len(macApps) > 0 {
+			fmt.Printf("🧪 TEST MODE: Processing only first app: %s\n\n", macApps[0].Name)
+			macApps = macApps[:1]
+			olderVersions = nil
+		} else {
+			fmt.Printf("🧪 TEST MODE: Processing only first older version: %s (%s)\n\n", olderVersions[0].Name, olderVersions[0].Version)
+			olderVersions = olderVersions[:1]
+		}
+	}
+
+	fmt.Printf("📦 Found %d macOS apps to process\n", len(macApps))
+	if *allVersions {
+		fmt.Printf("📦 Found %d older published versions to process (up to %d versions per app)\n", len(olderVersions), *maxVersions)
+	}
+	fmt.Println()
+
+	// Create temp directory
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error creating temp directory: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Set up signal handling to save on interruption
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	// Track collected security info
+	collectedSecurity := make(map[string]datafile.AppSecurityInfo)
+	collectedVersions := make(map[string]datafile.AppSecurityInfo)
+	processedSlugs := make(map[string]bool)
+	processedCount := 0
+
+	// Save function that merges with existing data
+	saveSecurityInfo := func() error {
+		// Merge collected data with existing data
+		finalSecurityMap := make(map[string]datafile.AppSecurityInfo)
+
+		// Add existing apps that weren't processed
+		// Preserve ALL existing entries regardless of platform to avoid wiping out other platform's data
+		for slug, existing := range existingMap {
+			if !processedSlugs[slug] {
+				// Extract base slug (remove /darwin or /windows suffix)
+				baseSlug := slug
+				if idx := strings.LastIndex(slug, "/"); idx != -1 {
+					baseSlug = slug[:idx]
+				}
+
+				// Check if this app still exists in current versions (any platform)
+				// The slug in versions includes platform (e.g., "010-editor/darwin"), so check if any version
+				// has a slug that starts with the base slug
+				found := false
+				for _, v := range versions.Apps {
+					if strings.HasPrefix(v.Slug, baseSlug+"/") {
+						found = true
+						break
+					}
+				}
+				if found {
+					finalSecurityMap[slug] = existing
+				}
+			}
+		}
+
+		// Add newly collected data
+		for slug, info := range collectedSecurity {
+			finalSecurityMap[slug] = info
+		}
+
+		// Convert map to sorted slice
+		var finalSecurityList []datafile.AppSecurityInfo
+		for _, app := range finalSecurityMap {
+			finalSecurityList = append(finalSecurityList, app)
+		}
+		sort.Slice(finalSecurityList, func(i, j int) bool {
+			return finalSecurityList[i].Slug < finalSecurityList[j].Slug
+		})
+
+		// Previous versions are kept while their app is still listed, whichever platform collected them
+		listedSlugs := make(map[string]bool)
+		for _, v := range versions.Apps {
+			listedSlugs[v.Slug] = true
+		}
+		finalVersionsMap := make(map[string]datafile.AppSecurityInfo)
+		for key, existing := range existingVersionsMap {
+			if listedSlugs[existing.Slug] {
+				finalVersionsMap[key] = existing
+			}
+		}
+		for key, info := range collectedVersions {
+			finalVersionsMap[key] = info
+		}
+		var finalVersionsList []datafile.AppSecurityInfo
+		for _, info := range finalVersionsMap {
+			finalVersionsList = append(finalVersionsList, info)
+		}
+		sort.Slice(finalVersionsList, func(i, j int) bool {
+			if finalVersionsList[i].Slug != finalVersionsList[j].Slug {
+				return finalVersionsList[i].Slug < finalVersionsList[j].Slug
+			}
+			return finalVersionsList[i].Version < finalVersionsList[j].Version
+		})
+
+		// Save to file
+		securityData := datafile.SecurityInfo{
+			SchemaVersion:    schema.Current(schema.SecurityInfo),
+			GeneratorVersion: buildinfo.GeneratorVersion(),
+			LastUpdated:      time.Now().UTC().Format(time.RFC3339),
+			Apps:             finalSecurityList,
+			Versions:         finalVersionsList,
+		}
+
+		jsonData, err := json.MarshalIndent(securityData, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling security info: %w", err)
+		}
+
+		if err := os.WriteFile(securityInfoJSON, jsonData, 0644); err != nil {
+			return fmt.Errorf("writing security info: %w", err)
+		}
+
+		return nil
+	}
+
+	runSpan := tracer.Start("collect-security-info", nil)
+	runSpan.SetAttr("apps.count", len(macApps))
+	if tracer.Enabled() {
+		fmt.Println("📡 Exporting traces via OTLP")
+	}
+
+	// Rows for the GitHub Actions step summary, and the outcome of each app
+	// for the --results file
+	var updatedApps, failedApps, anomalyApps [][]string
+	var results []datafile.CollectionResult
+	writeSummary := func(status string) {
+		if err := writeStepSummary(status, processedCount, len(macApps), updatedApps, failedApps, anomalyApps); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to write step summary: %v\n", err)
+		}
+		if err := writeResults(*resultsPath, results); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to write results: %v\n", err)
+		}
+	}
+
+	// Handle interruptions
+	go func() {
+		<-sigChan
+		fmt.Printf("\n⚠️  Interruption detected. Saving progress...\n")
+		cancelRun()
+		detachAllDMGs()
+		cleanupTempFiles()
+		writeSummary("⚠️ Interrupted")
+		runSpan.End(fmt.Errorf("interrupted"))
+		tracer.Flush()
+		if err := saveSecurityInfo(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error saving on interruption: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Progress saved. Processed %d/%d apps before interruption.\n", processedCount, len(macApps))
+		os.Exit(0)
+	}()
+
+	// Process each app
+	for i, app := range macApps {
+		fmt.Printf("[%d/%d] Processing %s (%s)...\n", i+1, len(macApps), app.Name, app.Version)
+
+		appSpan = runSpan.Start("app")
+		appSpan.SetAttr("app.slug", app.Slug)
+		appSpan.SetAttr("app.version", app.Version)
+
+		securityInfo, err := collectSecurityInfoForApp(app)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to collect security info: %v\n", err)
+			failedApps = append(failedApps, []string{app.Name, app.Version, err.Error()})
+			results = append(results, datafile.CollectionResult{Slug: app.Slug, Name: app.Name, Version: app.Version, Error: err.Error()})
+			appSpan.End(err)
+			if err := tracer.Flush(); err != nil {
+				fmt.Printf("  ⚠️  Warning: Failed to export traces: %v\n", err)
+			}
+			// Keep existing info if available
+			if existing, exists := existingMap[app.Slug]; exists {
+				collectedSecurity[app.Slug] = existing
+				processedSlugs[app.Slug] = true
+			}
+			// Save progress even on failure
+			if err := saveSecurityInfo(); err != nil {
+				fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to save progress: %v\n", err)
+			}
+			continue
+		}
+
+		// A new version with byte-identical binaries usually means the upstream metadata is wrong
+		if anomaly := detectUnchangedBinary(existingMap[app.Slug], securityInfo); anomaly != nil {
+			fmt.Printf("  ⚠️  Anomaly: %s\n", anomaly.Detail)
+			securityInfo.Anomalies = append(securityInfo.Anomalies, *anomaly)
+			anomalyApps = append(anomalyApps, []string{app.Name, anomaly.Detail, upstreamHistoryLink(app.Slug)})
+		}
+		// A bundled library signed by someone else than before may have been swapped
+		for _, anomaly := range detectComponentSignerChanges(existingMap[app.Slug], securityInfo) {
+			fmt.Printf("  ⚠️  Anomaly: %s\n", anomaly.Detail)
+			securityInfo.Anomalies = append(securityInfo.Anomalies, anomaly)
+			anomalyApps = append(anomalyApps, []string{app.Name, anomaly.Detail, upstreamHistoryLink(app.Slug)})
+		}
+
+		// Keep the superseded version's hashes for incident response
+		if previous, exists := existingMap[app.Slug]; exists && previous.Version != "" && previous.Version != securityInfo.Version {
+			collectedVersions[versionKey(previous.Slug, previous.Version)] = previous
+		}
+
+		collectedSecurity[app.Slug] = securityInfo
+		processedSlugs[app.Slug] = true
+		processedCount++
+		updatedApps = append(updatedApps, []string{app.Name, existingMap[app.Slug].Version, app.Version, upstreamHistoryLink(app.Slug)})
+		results = append(results, datafile.CollectionResult{Slug: app.Slug, Name: app.Name, Version: app.Version})
+
+		// Save incrementally after each successful collection
+		saveSpan := appSpan.Start("save")
+		err = saveSecurityInfo()
+		saveSpan.End(err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to save progress: %v\n", err)
+		} else {
+			fmt.Printf("  💾 Progress saved (%d/%d apps)\n", processedCount, len(macApps))
+		}
+
+		// Commit changes periodically (every 10 apps or on first/last app) to preserve progress
+		shouldCommit := processedCount == 1 || processedCount%10 == 0 || processedCount == len(macApps)
+		if shouldCommit {
+			if err := commitProgress(processedCount, len(macApps)); err != nil {
+				fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to commit progress: %v\n", err)
+			} else {
+				fmt.Printf("  📝 Progress committed to repo (%d/%d apps)\n", processedCount, len(macApps))
+			}
+		}
+
+		// Clean up after each app to save disk space
+		cleanupTempFiles()
+
+		appSpan.End(nil)
+		if err := tracer.Flush(); err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to export traces: %v\n", err)
+		}
+	}
+
+	// Older published versions, so orgs pinning a previous release still get hashes
+	olderCount := 0
+	for i, app := range olderVersions {
+		if _, retained := collectedVersions[versionKey(app.Slug, app.Version)]; retained {
+			fmt.Printf("[%d/%d] Skipping %s %s (retained from the previous run)\n", i+1, len(olderVersions), app.Name, app.Version)
+			continue
+		}
+		fmt.Printf("[%d/%d] Processing %s (older version %s)...\n", i+1, len(olderVersions), app.Name, app.Version)
+
+		appSpan = runSpan.Start("version")
+		appSpan.SetAttr("app.slug", app.Slug)
+		appSpan.SetAttr("app.version", app.Version)
+
+		securityInfo, err := collectSecurityInfoForApp(app)
+		appSpan.End(err)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to collect security info: %v\n", err)
+			failedApps = append(failedApps, []string{app.Name, app.Version, err.Error()})
+		} else {
+			collectedVersions[versionKey(app.Slug, app.Version)] = securityInfo
+			olderCount++
+			if err := saveSecurityInfo(); err != nil {
+				fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to save progress: %v\n", err)
+			} else {
+				fmt.Printf("  💾 Progress saved (%d/%d older versions)\n", olderCount, len(olderVersions))
+			}
+		}
+
+		cleanupTempFiles()
+		if err := tracer.Flush(); err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to export traces: %v\n", err)
+		}
+	}
+
+	// Final save (redundant but ensures everything is saved)
+	if err := saveSecurityInfo(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error saving final security info: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Final commit
+	if err := commitProgress(processedCount, len(macApps)); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to commit final progress: %v\n", err)
+	}
+
+	runSpan.End(nil)
+	if err := tracer.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to export traces: %v\n", err)
+	}
+
+	writeSummary("✅ Completed")
+
+	fmt.Printf("\n✅ Successfully processed %d/%d apps\n", processedCount, len(macApps))
+	fmt.Printf("✅ Security info saved to: %s\n", securityInfoJSON)
+}
+
+// writeStepSummary reports the run outcome in the GitHub Actions job summary
+func writeStepSummary(status string, processed, total int, updated, failed, anomalies [][]string) error {
+	if !summary.Enabled() {
+		return nil
+	}
+
+	var b summary.Builder
+	b.Heading(2, "🔒 macOS App Security Info")
+	b.Line("**Status:** %s  ", status)
+	b.Line("**Apps processed:** %d/%d", processed, total)
+	b.Line("")
+
+	b.Heading(3, fmt.Sprintf("Updated (%d)", len(updated)))
+	if len(updated) == 0 {
+		b.Line("No apps updated.\n")
+	} else {
+		b.Table([]string{"App", "Previous version", "Version", "Upstream diff"}, updated)
+	}
+
+	if len(anomalies) > 0 {
+		b.Heading(3, fmt.Sprintf("Anomalies (%d)", len(anomalies)))
+		b.Table([]string{"App", "Anomaly", "Upstream diff"}, anomalies)
+	}
+
+	b.Heading(3, fmt.Sprintf("Failures (%d)", len(failed)))
+	if len(failed) == 0 {
+		b.Line("None.\n")
+	} else {
+		b.Table([]string{"App", "Version", "Reason"}, failed)
+	}
+
+	return b.Write()
+}
+
+// writeResults writes the outcome of every app processed so far to path, so
+// track_failures.go can file an issue for each failing app and close it once
+// the app is collected again
+func writeResults(path string, results []datafile.CollectionResult) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(datafile.CollectionResults{Apps: results}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// detectUnchangedBinary flags a version bump whose binary hash is identical to
+// the previously collected version's
+func detectUnchangedBinary(previous, current datafile.AppSecurityInfo) *datafile.Anomaly {
+	if previous.Version == "" || previous.Version == current.Version {
+		return nil
+	}
+	if current.Sha256 == "" || previous.Sha256 != current.Sha256 {
+		return nil
+	}
+
+	return &datafile.Anomaly{
+		Type:       anomalyUnchangedBinary,
+		Detail:     fmt.Sprintf("version bump %s → %s without binary change (SHA-256 %s)", previous.Version, current.Version, current.Sha256),
+		DetectedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// detectComponentSignerChanges flags bundled frameworks and dylibs whose
+// signing ID or team changed since the previously collected version. New
+// hashes are expected with every release; a new signer is not.
+func detectComponentSignerChanges(previous, current datafile.AppSecurityInfo) []datafile.Anomaly {
+	if previous.Version == "" || previous.Version == current.Version {
+		return nil
+	}
+
+	before := make(map[string]datafile.Component, len(previous.Components))
+	for _, c := range previous.Components {
+		before[c.Path] = c
+	}
+
+	var anomalies []datafile.Anomaly
+	for _, c := range current.Components {
+		old, ok := before[c.Path]
+		if !ok || (old.SigningID == c.SigningID && old.TeamID == c.TeamID) {
+			continue
+		}
+		detail := fmt.Sprintf("%s signer changed in %s → %s: %s (%s) → %s (%s)", c.Path, previous.Version, current.Version,
+			orNone(old.SigningID), orNone(old.TeamID), orNone(c.SigningID), orNone(c.TeamID))
+		anomalies = append(anomalies, datafile.Anomaly{
+			Type:       anomalyComponentSigner,
+			Detail:     detail,
+			DetectedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	return anomalies
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// findOlderVersions lists the published versions after the latest one, up to
+// maxVersions per app in total, that have no security info yet
+func findOlderVersions(apps []datafile.AppVersion, platform string, maxVersions int, existing map[string]datafile.AppSecurityInfo) []datafile.AppVersion {
+	var older []datafile.AppVersion
+	for _, app := range apps {
+		if app.Platform != platform {
+			continue
+		}
+		for i, published := range app.PublishedVersions {
+			if i == 0 {
+				continue // The latest version is collected by the main pass
+			}
+			if i >= maxVersions {
+				break
+			}
+			if published.InstallerURL == "" || published.Version == app.Version {
+				continue
+			}
+			if _, exists := existing[versionKey(app.Slug, published.Version)]; exists {
+				continue
+			}
+			older = append(older, datafile.AppVersion{
+				Slug:         app.Slug,
+				Name:         app.Name,
+				Platform:     app.Platform,
+				Version:      published.Version,
+				InstallerURL: published.InstallerURL,
+			})
+		}
+	}
+	return older
+}
+
+// versionKey identifies the security info of one version of an app
+func versionKey(slug, version string) string {
+	return slug + "@" + version
+}
+
+// upstreamHistoryLink links to the commit history of an app's manifest in fleetdm/fleet
+func upstreamHistoryLink(slug string) string {
+	return fmt.Sprintf("[history](https://github.com/fleetdm/fleet/commits/main/ee/maintained-apps/outputs/%s.json)", slug)
+}
+
+// verifyInstallerHashes re-downloads the installer of every app whose recorded
+// version is still current and compares its hash with the recorded one, to
+// catch vendors republishing different bytes under the same version and URL.
+// It returns the process exit code: 1 when any installer drifted.
+func verifyInstallerHashes(versions *datafile.AppVersions, existingMap map[string]datafile.AppSecurityInfo) int {
+	fmt.Println("🔎 Verify-only mode: re-hashing current installers (no installation)")
+	fmt.Println()
+
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error creating temp directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(tempDir)
+
+	var drifted, failed [][]string
+	var driftEvents []datafile.HashDrift
+	verified, skipped := 0, 0
+	for _, app := range versions.Apps {
+		if app.Platform != "darwin" || app.InstallerURL == "" {
+			continue
+		}
+		existing, exists := existingMap[app.Slug]
+		if !exists || existing.Version != app.Version || existing.InstallerSha256 == "" {
+			skipped++ // nothing recorded for this version yet
+			continue
+		}
+
+		// Re-download the installer that was hashed, which may be a variant or a mirror
+		installerURL := app.InstallerURL
+		if existing.InstallerURL != "" {
+			installerURL = existing.InstallerURL
+		}
+
+		fmt.Printf("📦 %s (%s)\n", app.Name, app.Version)
+		installerPath, _, err := downloadInstaller(installerURL, app.Slug)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to download installer: %v\n", err)
+			failed = append(failed, []string{app.Name, app.Version, err.Error()})
+			continue
+		}
+		sha256, err := calculateSHA256(installerPath)
+		os.Remove(installerPath)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to hash installer: %v\n", err)
+			failed = append(failed, []string{app.Name, app.Version, err.Error()})
+			continue
+		}
+
+		verified++
+		if sha256 != existing.InstallerSha256 {
+			fmt.Printf("  ❌ Hash drift: recorded %s, now %s\n", existing.InstallerSha256, sha256)
+			drifted = append(drifted, []string{app.Name, app.Version, existing.InstallerSha256, sha256, installerURL})
+			driftEvents = append(driftEvents, datafile.HashDrift{
+				DetectedAt:     time.Now().UTC().Format(time.RFC3339),
+				Slug:           app.Slug,
+				Name:           app.Name,
+				Version:        app.Version,
+				RecordedSha256: existing.InstallerSha256,
+				CurrentSha256:  sha256,
+				InstallerURL:   installerURL,
+			})
+			continue
+		}
+		fmt.Printf("  ✅ Matches recorded hash\n")
+	}
+
+	fmt.Printf("\n📊 Verified %d installers: %d drifted, %d failed to download, %d skipped (no recorded hash)\n", verified, len(drifted), len(failed), skipped)
+	if err := recordHashDrift(driftEvents); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to record hash drift: %v\n", err)
+	}
+
+	if summary.Enabled() {
+		var b summary.Builder
+		b.Heading(2, "🔎 macOS Installer Hash Verification")
+		b.Line("**Verified:** %d  ", verified)
+		b.Line("**Skipped (no recorded hash):** %d", skipped)
+		b.Line("")
+		b.Heading(3, fmt.Sprintf("Hash drift (%d)", len(drifted)))
+		if len(drifted) == 0 {
+			b.Line("None.\n")
+		} else {
+			b.Table([]string{"App", "Version", "Recorded SHA-256", "Current SHA-256", "Installer URL"}, drifted)
+		}
+		if len(failed) > 0 {
+			b.Heading(3, fmt.Sprintf("Download failures (%d)", len(failed)))
+			b.Table([]string{"App", "Version", "Reason"}, failed)
+		}
+		if err := b.Write(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to write step summary: %v\n", err)
+		}
+	}
+
+	if len(drifted) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// recordHashDrift appends one JSON line per drifted installer to hashDriftJSONL
+func recordHashDrift(events []datafile.HashDrift) error {
+	if len(events) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(hashDriftJSONL, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+func commitProgress(processedCount, totalApps int) error {
+	// Check if we're in a git repository and have changes
+	if err := exec.Command("git", "rev-parse", "--git-dir").Run(); err != nil {
+		// Not in a git repo, skip commit
+		return nil
+	}
+
+	// Check if there are changes
+	statusCmd := exec.Command("git", "status", "--porcelain", securityInfoJSON)
+	output, err := statusCmd.Output()
+	if err != nil {
+		return fmt.Errorf("checking git status: %w", err)
+	}
+
+	if len(output) == 0 {
+		// No changes, nothing to commit
+		return nil
+	}
+
+	// Configure git (if not already configured), keeping the identity and
+	// signing key the workflow set up so progress commits are signed too
+	for key, value := range map[string]string{"user.email": "action@github.com", "user.name": "GitHub Action"} {
+		if exec.Command("git", "config", key).Run() != nil {
+			exec.Command("git", "config", "--local", key, value).Run()
+		}
+	}
+
+	// Add the file
+	if err := exec.Command("git", "add", securityInfoJSON).Run(); err != nil {
+		return fmt.Errorf("git add: %w", err)
+	}
+
+	// Commit
+	commitMsg := fmt.Sprintf("Update macOS app security info - %d/%d apps processed", processedCount, totalApps)
+	if err := exec.Command("git", "commit", "-m", commitMsg).Run(); err != nil {
+		// If commit fails (e.g., no changes), that's okay
+		return nil
+	}
+
+	// Push (non-blocking - if it fails, that's okay, next run will push)
+	go func() {
+		exec.Command("git", "push").Run()
+	}()
+
+	return nil
+}
+
+func loadAppVersions() (*datafile.AppVersions, error) {
+	data, err := os.ReadFile(securityVersionsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{Apps: []datafile.AppSecurityInfo{}}, nil
+		}
+		return nil, err
+	}
+
+	// Check if file contains HTML (common issue if file was overwritten)
+	dataStr := string(data)
+	if strings.HasPrefix(strings.TrimSpace(dataStr), "<") {
+		return nil, fmt.Errorf("file appears to contain HTML instead of JSON (starts with '<')")
+	}
+
+	// Upgrade older files before merging; invalid JSON falls through to the detailed error below
+	if json.Valid(data) {
+		data, err = schema.Upgrade(schema.SecurityInfo, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		// Provide more context about the error
+		preview := dataStr
+		if len(preview) > 200 {
+			preview = preview[:200] + "..."
+		}
+		return nil, fmt.Errorf("failed to parse JSON (file may be corrupted or contain non-JSON content). Preview: %q. Error: %w", preview, err)
+	}
+
+	return &security, nil
+}
+
+func collectSecurityInfoForApp(app datafile.AppVersion) (datafile.AppSecurityInfo, error) {
+	var securityInfo datafile.AppSecurityInfo
+
+	// Collect the installer the runner can actually run first, so the main
+	// hashes describe a binary that was installed natively
+	if selected := forRunnerArch(app, runnerArch()); selected.InstallerURL != app.InstallerURL {
+		fmt.Printf("  🧬 Using the %s installer to match the runner\n", selected.Arch)
+		app = selected
+	}
+
+	// Download installer
+	span := appSpan.Start("download")
+	installerPath, installerURL, downloadTLS, err := downloadWithMirrors(app.InstallerURL, app.Slug, app.Version)
+	span.End(err)
+	if err != nil {
+		return securityInfo, fmt.Errorf("failed to download installer: %w", err)
+	}
+	defer os.Remove(installerPath)
+	for _, warning := range downloadTLS.Warnings() {
+		fmt.Printf("  ⚠️  Warning: Installer %s\n", warning)
+	}
+	installerSha256, err := calculateSHA256(installerPath)
+	if err != nil {
+		return securityInfo, fmt.Errorf("failed to hash installer: %w", err)
+	}
+	var installerSize int64
+	if info, err := os.Stat(installerPath); err == nil {
+		installerSize = info.Size()
+	}
+
+	// Install app, noting what was registered to run at boot or login first
+	// so what the installer adds can be told apart
+	before := snapshotPersistence()
+	span = appSpan.Start("install")
+	appPath, err := installApp(installerPath, app)
+	span.End(err)
+	if err != nil {
+		return securityInfo, explainRosetta(fmt.Errorf("failed to install app: %w", err), false)
+	}
+	persistence, err := addedPersistence(before)
+	if err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to check for login items: %v\n", err)
+	}
+	if len(persistence) > 0 {
+		fmt.Printf("  🚀 Installer added %d launch daemons, launch agents or login items\n", len(persistence))
+		removeLaunchJobs(persistence)
+	}
+
+	// Special handling for Teleport Suite - it installs multiple apps
+	if app.Name == "Teleport Suite" {
+		suiteInfo, err := collectTeleportSuiteSecurityInfo(app)
+		suiteInfo.InstallerSha256 = installerSha256
+		suiteInfo.InstallerSize = installerSize
+		suiteInfo.InstallerURL = installerURL
+		suiteInfo.DownloadTLS = downloadTLS
+		suiteInfo.Persistence = persistence
+		return suiteInfo, err
+	}
+
+	// Verify the app exists
+	if appPath == "" {
+		return securityInfo, fmt.Errorf("installApp returned empty path")
+	}
+	if _, err := os.Stat(appPath); err != nil {
+		return securityInfo, fmt.Errorf("installed app not found at %s: %w", appPath, err)
+	}
+
+	// Wait longer to ensure app is fully installed and ready (santactl can take time)
+	time.Sleep(3 * time.Second)
+
+	// Hash each slice so universal binaries can be matched per architecture
+	slices, err := executableSlices(bundleExecutable(appPath))
+	if err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to read architectures: %v\n", err)
+	}
+	rosetta := requiresRosetta(slices)
+	if rosetta != nil && *rosetta {
+		fmt.Printf("  🦾 x86_64 only: requires Rosetta on Apple Silicon\n")
+	}
+
+	// Run santactl fileinfo
+	span = appSpan.Start("santactl")
+	santactlOutput, err := runSantactl(appPath)
+	span.End(err)
+	if err != nil {
+		// Try to uninstall even if santactl failed
+		uninstallApp(app)
+		return securityInfo, explainRosetta(fmt.Errorf("failed to run santactl: %w", err), rosetta != nil && *rosetta)
+	}
+
+	// Parse santactl output
+	span = appSpan.Start("parse")
+	securityInfo, err = parseSantactlOutput(santactlOutput, app)
+	span.End(err)
+	if err != nil {
+		uninstallApp(app)
+		return securityInfo, fmt.Errorf("failed to parse santactl output: %w", err)
+	}
+	securityInfo.InstallerSha256 = installerSha256
+	securityInfo.InstallerSize = installerSize
+	securityInfo.InstallerURL = installerURL
+	securityInfo.DownloadTLS = downloadTLS
+	securityInfo.Arch = app.Arch
+	securityInfo.Architectures = slices
+	securityInfo.RequiresRosetta = rosetta
+	securityInfo.Persistence = persistence
+
+	bundleID, err := bundleIdentifier(appPath)
+	if err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to read bundle identifier: %v\n", err)
+	}
+	securityInfo.BundleID = bundleID
+	securityInfo.BundlePath = appPath
+	if securityInfo.BundleVersion, err = bundleShortVersion(appPath); err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to read bundle version: %v\n", err)
+	}
+	if securityInfo.PrivacyUsage, err = privacyUsageDescriptions(appPath); err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to read privacy usage descriptions: %v\n", err)
+	}
+
+	applySignature(&securityInfo, readSignature(appPath))
+
+	stapled := hasStapledTicket(appPath)
+	securityInfo.Stapled = &stapled
+	if securityInfo.Cdhash != "" {
+		notarizedAt, err := notarizationDate(securityInfo.Cdhash)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to look up notarization ticket: %v\n", err)
+		}
+		securityInfo.NotarizedAt = notarizedAt
+	}
+
+	if collectComponents {
+		components, err := bundleComponents(appPath)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to read bundled frameworks: %v\n", err)
+		}
+		securityInfo.Components = components
+		fmt.Printf("  🧩 Hashed %d bundled frameworks and dylibs\n", len(components))
+	}
+
+	// Installers that ship more than one app are recorded suite-style, the
+	// main app first
+	if len(companionApps) > 0 {
+		mainApp := securityInfo
+		mainApp.Name = strings.TrimSuffix(filepath.Base(appPath), ".app")
+		mainApp.InstallerSha256, mainApp.InstallerSize, mainApp.DownloadTLS, mainApp.Components, mainApp.Persistence = "", 0, nil, nil, nil
+		securityInfo.Apps = append([]datafile.AppSecurityInfo{mainApp}, collectCompanionApps(app)...)
+	}
+
+	// Success message
+	fmt.Printf("  🔐 Extracted security info\n")
+
+	// Uninstall app
+	if err := uninstallApp(app); err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to uninstall app: %v\n", err)
+	}
+	removeCompanionApps()
+
+	// Separate Apple Silicon / Intel installers of the same version
+	for _, variant := range app.Variants {
+		fmt.Printf("  🧬 Collecting %s variant...\n", archLabel(variant.Arch))
+		variantApp := app
+		variantApp.InstallerURL = variant.InstallerURL
+		variantApp.Arch = variant.Arch
+		variantApp.Variants = nil
+		variantInfo, err := collectSecurityInfoForApp(variantApp)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to collect %s variant: %v\n", archLabel(variant.Arch), err)
+			continue
+		}
+		securityInfo.Variants = append(securityInfo.Variants, variantInfo)
+	}
+
+	return securityInfo, nil
+}
+
+// executableSlices lists the architectures of a Mach-O executable with the
+// SHA-256 of each slice; a thin binary yields a single slice hashing the whole file
+func executableSlices(executable string) ([]datafile.ArchSlice, error) {
+	output, err := newCommand(context.Background(), "lipo", "-archs", executable).Output()
+	if err != nil {
+		return nil, fmt.Errorf("lipo -archs: %w", err)
+	}
+	archs := strings.Fields(string(output))
+	if len(archs) == 0 {
+		return nil, nil
+	}
+
+	if len(archs) == 1 {
+		sum, err := calculateSHA256(executable)
+		if err != nil {
+			return nil, err
+		}
+		return []datafile.ArchSlice{{Arch: archs[0], Sha256: sum}}, nil
+	}
+
+	slices := make([]datafile.ArchSlice, 0, len(archs))
+	for _, arch := range archs {
+		thinPath := filepath.Join(tempDir, "slice-"+arch)
+		if err := newCommand(context.Background(), "lipo", executable, "-thin", arch, "-output", thinPath).Run(); err != nil {
+			return slices, fmt.Errorf("lipo -thin %s: %w", arch, err)
+		}
+		sum, err := calculateSHA256(thinPath)
+		os.Remove(thinPath)
+		if err != nil {
+			return slices, err
+		}
+		slices = append(slices, datafile.ArchSlice{Arch: arch, Sha256: sum})
+	}
+	return slices, nil
+}
+
+// bundleComponents hashes the binary of every framework and dylib directly in
+// the app's Contents/Frameworks and reads its code signature
+func bundleComponents(appPath string) ([]datafile.Component, error) {
+	dir := filepath.Join(appPath, "Contents", "Frameworks")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var components []datafile.Component
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+
+		binary := path
+		switch {
+		case strings.HasSuffix(name, ".framework"):
+			// The top-level binary is a symlink into Versions/Current
+			binary, err = filepath.EvalSymlinks(filepath.Join(path, strings.TrimSuffix(name, ".framework")))
+			if err != nil {
+				fmt.Printf("  ⚠️  Warning: No binary in %s: %v\n", name, err)
+				continue
+			}
+		case strings.HasSuffix(name, ".dylib"):
+		default:
+			continue
+		}
+
+		sum, err := calculateSHA256(binary)
+		if err != nil {
+			return components, fmt.Errorf("failed to hash %s: %w", name, err)
+		}
+		signingID, teamID := codeSignature(path)
+		components = append(components, datafile.Component{
+			Path:      filepath.Join("Contents", "Frameworks", name),
+			Sha256:    sum,
+			SigningID: signingID,
+			TeamID:    teamID,
+		})
+	}
+	return components, nil
+}
+
+// persistenceSnapshot is what was registered to run at boot or login at
+// one point in time
+type persistenceSnapshot struct {
+	launchPlists map[string]string // Plist path to persistence kind
+	loginItems   map[string]bool   // App paths
+	loginErr     error             // Why login items couldn't be listed
+}
+
+func snapshotPersistence() persistenceSnapshot {
+	snapshot := persistenceSnapshot{launchPlists: make(map[string]string)}
+	for _, launchDir := range launchDirs {
+		dir := launchDir.dir
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				continue
+			}
+			dir = filepath.Join(home, rest)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".plist") {
+				snapshot.launchPlists[filepath.Join(dir, entry.Name())] = launchDir.kind
+			}
+		}
+	}
+	snapshot.loginItems, snapshot.loginErr = loginItems()
+	return snapshot
+}
+
+// addedPersistence lists the launchd jobs and login items that appeared since
+// before, launchd jobs first. Login items are skipped when either snapshot
+// couldn't list them.
+func addedPersistence(before persistenceSnapshot) ([]datafile.PersistenceItem, error) {
+	after := snapshotPersistence()
+
+	var plists []string
+	for path := range after.launchPlists {
+		if _, ok := before.launchPlists[path]; !ok {
+			plists = append(plists, path)
+		}
+	}
+	sort.Strings(plists)
+
+	var items []datafile.PersistenceItem
+	for _, path := range plists {
+		item := datafile.PersistenceItem{Kind: after.launchPlists[path], Path: path}
+		item.Label, _ = plistValue(path, "Label")
+		if item.Program, _ = plistValue(path, "Program"); item.Program == "" {
+			item.Program, _ = plistValue(path, "ProgramArguments.0")
+		}
+		items = append(items, item)
+	}
+
+	if before.loginErr != nil {
+		return items, before.loginErr
+	}
+	if after.loginErr != nil {
+		return items, after.loginErr
+	}
+	var logins []string
+	for path := range after.loginItems {
+		if !before.loginItems[path] {
+			logins = append(logins, path)
+		}
+	}
+	sort.Strings(logins)
+	for _, path := range logins {
+		items = append(items, datafile.PersistenceItem{Kind: persistenceLoginItem, Path: path})
+	}
+	return items, nil
+}
+
+// loginItems returns the apps System Events lists as the runner user's
+// login items
+func loginItems() (map[string]bool, error) {
+	output, err := newCommand(context.Background(), "osascript", "-e", `tell application "System Events" to get the path of every login item`).Output()
+	if err != nil {
+		return nil, fmt.Errorf("osascript: %w", err)
+	}
+	items := make(map[string]bool)
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), ", ") {
+		if path != "" {
+			items[path] = true
+		}
+	}
+	return items, nil
+}
+
+// removeLaunchJobs unloads and deletes the launchd jobs in items, so they
+// neither keep running on the runner nor hide the same jobs from the next
+// install's diff. Login items are left, they only start at the next login.
+func removeLaunchJobs(items []datafile.PersistenceItem) {
+	for _, item := range items {
+		switch item.Kind {
+		case persistenceLaunchDaemon:
+			newCommand(context.Background(), "sudo", "launchctl", "bootout", "system", item.Path).Run()
+		case persistenceLaunchAgent:
+			newCommand(context.Background(), "launchctl", "bootout", fmt.Sprintf("gui/%d", os.Getuid()), item.Path).Run()
+		default:
+			continue
+		}
+		if err := os.Remove(item.Path); err != nil {
+			newCommand(context.Background(), "sudo", "rm", "-f", item.Path).Run()
+		}
+	}
+}
+
+// requiresRosetta reports whether an executable with these slices only runs
+// on Apple Silicon under Rosetta, or nil when the slices couldn't be read
+func requiresRosetta(slices []datafile.ArchSlice) *bool {
+	if len(slices) == 0 {
+		return nil
+	}
+	x86Only := true
+	for _, slice := range slices {
+		if strings.HasPrefix(slice.Arch, "arm64") {
+			x86Only = false
+		}
+	}
+	return &x86Only
+}
+
+// explainRosetta adds the likely cause to err when the runner is Apple
+// Silicon without Rosetta and the app is x86_64-only, or a tool failed with
+// "Bad CPU type" (an Intel-only installer script or helper), which otherwise
+// surfaces as an opaque exit status
+func explainRosetta(err error, x86Only bool) error {
+	if runtime.GOARCH != "arm64" {
+		return err
+	}
+	if !x86Only && !strings.Contains(err.Error(), "Bad CPU type") {
+		return err
+	}
+	if _, statErr := os.Stat(rosettaRuntime); statErr == nil {
+		return err
+	}
+	return fmt.Errorf("%w (x86_64-only code on an Apple Silicon runner without Rosetta; install it with softwareupdate --install-rosetta --agree-to-license)", err)
+}
+
+// collectCompanionApps collects the apps in companionApps, skipping any that
+// santactl can't read
+func collectCompanionApps(app datafile.AppVersion) []datafile.AppSecurityInfo {
+	var apps []datafile.AppSecurityInfo
+	for _, appPath := range companionApps {
+		name := strings.TrimSuffix(filepath.Base(appPath), ".app")
+		output, err := runSantactl(appPath)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to run santactl on %s: %v\n", name, err)
+			continue
+		}
+		info, err := parseSantactlOutput(output, datafile.AppVersion{
+			Slug:    app.Slug + "/" + name,
+			Name:    name,
+			Version: app.Version,
+		})
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to parse santactl output for %s: %v\n", name, err)
+			continue
+		}
+		info.Name = name
+		info.BundleID, _ = bundleIdentifier(appPath)
+		applySignature(&info, readSignature(appPath))
+		apps = append(apps, info)
+		fmt.Printf("  🔐 Extracted security info for %s\n", name)
+	}
+	return apps
+}
+
+// removeCompanionApps deletes the apps copied by copyCompanionApps
+func removeCompanionApps() {
+	for _, appPath := range companionApps {
+		if err := os.RemoveAll(appPath); err != nil {
+			newCommand(context.Background(), "sudo", "rm", "-rf", appPath).Run()
+		}
+	}
+	companionApps = nil
+}
+
+// bundleIdentifier returns the CFBundleIdentifier of an app bundle
+func bundleIdentifier(appPath string) (string, error) {
+	return infoPlistValue(appPath, "CFBundleIdentifier")
+}
+
+// bundleShortVersion returns the CFBundleShortVersionString of an app bundle,
+// the version Munki and osquery compare
+func bundleShortVersion(appPath string) (string, error) {
+	return infoPlistValue(appPath, "CFBundleShortVersionString")
+}
+
+// infoPlistValue returns a string key of an app bundle's Info.plist
+func infoPlistValue(appPath, key string) (string, error) {
+	return plistValue(filepath.Join(appPath, "Contents", "Info.plist"), key)
+}
+
+// privacyUsageDescriptions returns the top-level NS*UsageDescription keys of
+// an app bundle's Info.plist, sorted by key. Privacy permissions without a
+// usage description (Accessibility, Screen Recording) can't be read this way.
+func privacyUsageDescriptions(appPath string) ([]datafile.PrivacyUsage, error) {
+	infoPlist := filepath.Join(appPath, "Contents", "Info.plist")
+	output, err := newCommand(context.Background(), "plutil", "-convert", "xml1", "-o", "-", infoPlist).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", infoPlist, err)
+	}
+
+	// Walk the top-level dict, pairing each <key> with the <string> after it
+	var usages []datafile.PrivacyUsage
+	decoder := xml.NewDecoder(bytes.NewReader(output))
+	decoder.Strict = false
+	depth, key := 0, ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", infoPlist, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth != 3 {
+				continue
+			}
+			var text string
+			if err := decoder.DecodeElement(&text, &t); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", infoPlist, err)
+			}
+			depth--
+			switch {
+			case t.Name.Local == "key":
+				key = text
+				continue
+			case t.Name.Local == "string" && strings.HasPrefix(key, "NS") && strings.HasSuffix(key, "UsageDescription"):
+				usages = append(usages, datafile.PrivacyUsage{Key: key, Service: pppcServices[key], Description: strings.TrimSpace(text)})
+			}
+			key = ""
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Key < usages[j].Key })
+	return usages, nil
+}
+
+// plistValue returns a string key of a property list; key paths like
+// ProgramArguments.0 reach into arrays. plutil reads both XML and binary
+// property lists.
+func plistValue(path, key string) (string, error) {
+	output, err := newCommand(context.Background(), "plutil", "-extract", key, "raw", "-o", "-", path).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// signatureDetails is what codesign reports about an app's signature
+type signatureDetails struct {
+	Sandboxed       *bool
+	HardenedRuntime *bool
+	Format          string
+	SigningTime     string
+	LeafCertificate string
+}
+
+// readSignature reports whether the app is sandboxed (it has the
+// com.apple.security.app-sandbox entitlement), whether it opts into the
+// hardened runtime (the runtime flag of its code directory), the signature
+// format, when it was signed and the leaf certificate's common name. The
+// flags are nil when codesign can't read the signature.
+func readSignature(appPath string) signatureDetails {
+	var details signatureDetails
+
+	var stderr bytes.Buffer
+	cmd := newCommand(context.Background(), "codesign", "-dv", "--verbose=4", appPath)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		output := stderr.String()
+		hardened := hasRuntimeFlag(output)
+		details.HardenedRuntime = &hardened
+		details.Format = codesignField(output, "Format")
+		details.SigningTime = signingTime(output)
+		details.LeafCertificate = codesignField(output, "Authority")
+	}
+
+	entitlements, err := newCommand(context.Background(), "codesign", "-d", "--entitlements", "-", "--xml", appPath).Output()
+	if err == nil {
+		sandbox := hasEntitlement(entitlements, "com.apple.security.app-sandbox")
+		details.Sandboxed = &sandbox
+	}
+
+	return details
+}
+
+// applySignature copies what readSignature found into info
+func applySignature(info *datafile.AppSecurityInfo, signature signatureDetails) {
+	info.Sandboxed = signature.Sandboxed
+	info.HardenedRuntime = signature.HardenedRuntime
+	info.SignatureFormat = signature.Format
+	info.SigningTime = signature.SigningTime
+	info.LeafCertificate = signature.LeafCertificate
+}
+
+// codesignField returns the first Key=value line of codesign -dv output. The
+// Authority lines run from the leaf certificate up to the root, so the first
+// one is the signer.
+func codesignField(output, key string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if value, ok := strings.CutPrefix(line, key+"="); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// signingTimeLayouts are the date formats codesign prints, depending on the
+// runner's locale
+var signingTimeLayouts = []string{
+	"Jan 2, 2006 at 3:04:05 PM",
+	"Jan 2, 2006 at 15:04:05",
+	"2 Jan 2006 at 15:04:05",
+}
+
+// signingTime returns when the app was signed: the secure timestamp
+// ("Timestamp=") when the signature has one, otherwise the CMS signing time
+// ("Signed Time="). codesign prints it in the runner's time zone; it is
+// converted to RFC 3339 UTC, or kept as printed when the format is unknown.
+func signingTime(output string) string {
+	value := codesignField(output, "Timestamp")
+	if value == "" {
+		value = codesignField(output, "Signed Time")
+	}
+	if value == "" {
+		return ""
+	}
+	// Newer macOS releases put a narrow no-break space before AM/PM
+	normalized := strings.ReplaceAll(value, "\u202f", " ")
+	for _, layout := range signingTimeLayouts {
+		if t, err := time.ParseInLocation(layout, normalized, time.Local); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return value
+}
+
+// hasStapledTicket reports whether xcrun stapler has attached a notarization
+// ticket to the bundle, which it stores as Contents/CodeResources
+func hasStapledTicket(appPath string) bool {
+	info, err := os.Stat(filepath.Join(appPath, "Contents", "CodeResources"))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// notaryTicketLookup is the response of a CloudKit records/lookup request
+type notaryTicketLookup struct {
+	Records []struct {
+		RecordName      string `json:"recordName"`
+		ServerErrorCode string `json:"serverErrorCode"`
+		Reason          string `json:"reason"`
+		Created         struct {
+			Timestamp int64 `json:"timestamp"` // Milliseconds since the epoch
+		} `json:"created"`
+	} `json:"records"`
+}
+
+// notarizationDate returns when Apple issued the notarization ticket for a
+// code directory hash, by looking the ticket up the way Gatekeeper does
+// online. It is empty when the app isn't notarized. Stapled tickets are
+// copies of the same record, so this also dates them.
+func notarizationDate(cdhash string) (string, error) {
+	// Record names are 2/<hash type>/<cdhash>, hash type 2 being SHA-256
+	body, err := json.Marshal(map[string]any{
+		"records": []map[string]string{{"recordName": "2/2/" + strings.ToLower(cdhash)}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(runCtx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notaryTicketURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ticket lookup returned status %d", resp.StatusCode)
+	}
+
+	var lookup notaryTicketLookup
+	if err := json.NewDecoder(resp.Body).Decode(&lookup); err != nil {
+		return "", fmt.Errorf("failed to parse ticket lookup: %w", err)
+	}
+	if len(lookup.Records) == 0 {
+		return "", fmt.Errorf("ticket lookup returned no records")
+	}
+
+	record := lookup.Records[0]
+	switch {
+	case record.ServerErrorCode == "NOT_FOUND":
+		return "", nil
+	case record.ServerErrorCode != "":
+		return "", fmt.Errorf("ticket lookup failed: %s %s", record.ServerErrorCode, record.Reason)
+	case record.Created.Timestamp == 0:
+		return "", fmt.Errorf("ticket record has no creation time")
+	}
+	return time.UnixMilli(record.Created.Timestamp).UTC().Format(time.RFC3339), nil
+}
+
+// hasRuntimeFlag reports whether codesign -dv output lists the runtime flag,
+// e.g. "CodeDirectory v=20500 size=1234 flags=0x10000(runtime) hashes=..."
+func hasRuntimeFlag(output string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "CodeDirectory ") {
+			continue
+		}
+		_, flags, ok := strings.Cut(line, "flags=")
+		if !ok {
+			continue
+		}
+		start, end := strings.Index(flags, "("), strings.Index(flags, ")")
+		if start < 0 || end < start {
+			continue
+		}
+		for _, flag := range strings.Split(flags[start+1:end], ",") {
+			if flag == "runtime" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasEntitlement reports whether an entitlements plist sets key to true
+func hasEntitlement(plist []byte, key string) bool {
+	compact := strings.Join(strings.Fields(string(plist)), "")
+	return strings.Contains(compact, "<key>"+key+"</key><true/>")
+}
+
+// codeSignature returns the signing identifier and team of a bundle or
+// binary from codesign -dv, or empty strings when it isn't signed
+func codeSignature(path string) (signingID, teamID string) {
+	var stderr bytes.Buffer
+	cmd := newCommand(context.Background(), "codesign", "-dv", path)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", ""
+	}
+
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Identifier":
+			signingID = value
+		case "TeamIdentifier":
+			if value != "not set" {
+				teamID = value
+			}
+		}
+	}
+	return signingID, teamID
+}
+
+// runnerArch is the runner's architecture as installer URLs name it
+func runnerArch() string {
+	if runtime.GOARCH == "arm64" {
+		return "arm64"
+	}
+	return "x86_64"
+}
+
+// forRunnerArch returns app with the installer built for arch as the main one
+// and the others as variants. Universal and unlabeled installers run on
+// either architecture, so they stay first.
+func forRunnerArch(app datafile.AppVersion, arch string) datafile.AppVersion {
+	if app.Arch == "" || app.Arch == "universal" || app.Arch == arch {
+		return app
+	}
+	for i, variant := range app.Variants {
+		if variant.Arch != arch {
+			continue
+		}
+		selected := app
+		selected.InstallerURL = variant.InstallerURL
+		selected.Arch = variant.Arch
+		selected.Variants = append([]datafile.PublishedVersion{}, app.Variants[:i]...)
+		selected.Variants = append(selected.Variants, app.Variants[i+1:]...)
+		selected.Variants = append(selected.Variants, datafile.PublishedVersion{
+			Version:      app.Version,
+			InstallerURL: app.InstallerURL,
+			Arch:         app.Arch,
+		})
+		return selected
+	}
+	return app
+}
+
+func archLabel(arch string) string {
+	if arch == "" {
+		return "other architecture"
+	}
+	return arch
+}
+
+func collectTeleportSuiteSecurityInfo(app datafile.AppVersion) (datafile.AppSecurityInfo, error) {
+	var suiteInfo datafile.AppSecurityInfo
+	suiteInfo.Slug = app.Slug
+	suiteInfo.Name = app.Name
+	suiteInfo.Version = app.Version
+	suiteInfo.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+
+	// Wait for installation to complete
+	time.Sleep(3 * time.Second)
+
+	// Find both tsh.app and tctl.app
+	tshPath := filepath.Join(applicationsDir, "tsh.app")
+	tctlPath := filepath.Join(applicationsDir, "tctl.app")
+
+	var apps []datafile.AppSecurityInfo
+
+	// Collect security info for tsh.app
+	if _, err := os.Stat(tshPath); err == nil {
+		fmt.Printf("  📦 Found tsh.app, collecting security info...\n")
+		time.Sleep(2 * time.Second)
+		santactlOutput, err := runSantactl(tshPath)
+		if err == nil {
+			tshInfo, err := parseSantactlOutput(santactlOutput, datafile.AppVersion{
+				Slug:    app.Slug + "/tsh",
+				Name:    "tsh",
+				Version: app.Version,
+			})
+			if err == nil {
+				tshInfo.Name = "tsh"
+				tshInfo.BundleID, _ = bundleIdentifier(tshPath)
+				apps = append(apps, tshInfo)
+				fmt.Printf("  🔐 Extracted security info for tsh\n")
+			}
+		}
+	}
+
+	// Collect security info for tctl.app
+	if _, err := os.Stat(tctlPath); err == nil {
+		fmt.Printf("  📦 Found tctl.app, collecting security info...\n")
+		time.Sleep(2 * time.Second)
+		santactlOutput, err := runSantactl(tctlPath)
+		if err == nil {
+			tctlInfo, err := parseSantactlOutput(santactlOutput, datafile.AppVersion{
+				Slug:    app.Slug + "/tctl",
+				Name:    "tctl",
+				Version: app.Version,
+			})
+			if err == nil {
+				tctlInfo.Name = "tctl"
+				tctlInfo.BundleID, _ = bundleIdentifier(tctlPath)
+				apps = append(apps, tctlInfo)
+				fmt.Printf("  🔐 Extracted security info for tctl\n")
+			}
+		}
+	}
+
+	if len(apps) == 0 {
+		uninstallApp(app)
+		return suiteInfo, fmt.Errorf("could not find tsh.app or tctl.app after installation")
+	}
+
+	suiteInfo.Apps = apps
+
+	// Uninstall apps
+	if err := uninstallApp(app); err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to uninstall app: %v\n", err)
+	}
+
+	return suiteInfo, nil
+}
+
+// loadInstallerMirrors reads installerMirrorsJSON, an object mapping app
+// slugs (or "*" for every app) to fallback URL templates in which {version}
+// and {filename} (the last path segment of the published URL) are replaced.
+// A missing file means no mirrors.
+func loadInstallerMirrors() (map[string][]string, error) {
+	data, err := os.ReadFile(installerMirrorsJSON)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var mirrors map[string][]string
+	if err := json.Unmarshal(data, &mirrors); err != nil {
+		return nil, fmt.Errorf("failed to parse mirrors: %w", err)
+	}
+	return mirrors, nil
+}
+
+// mirrorURLs expands the fallback URLs for an installer, the app's own
+// mirrors before the ones for every app
+func mirrorURLs(mirrors map[string][]string, slug, version, installerURL string) []string {
+	filename, _, _ := strings.Cut(installerURL, "?")
+	filename = filename[strings.LastIndex(filename, "/")+1:]
+	replacer := strings.NewReplacer("{version}", version, "{filename}", filename)
+
+	var urls []string
+	for _, template := range append(mirrors[slug], mirrors["*"]...) {
+		if mirror := replacer.Replace(template); mirror != installerURL {
+			urls = append(urls, mirror)
+		}
+	}
+	return urls
+}
+
+// downloadWithMirrors downloads the published installer and, when that fails
+// (a 404, a timeout, a download that stays truncated), each mirror in turn.
+// It returns the URL the installer came from.
+func downloadWithMirrors(installerURL, slug, version string) (string, string, *tlsinfo.Info, error) {
+	filename, downloadTLS, err := downloadInstaller(installerURL, slug)
+	if err == nil {
+		return filename, installerURL, downloadTLS, nil
+	}
+
+	mirrors := mirrorURLs(installerMirrors, slug, version, installerURL)
+	for _, mirror := range mirrors {
+		if runCtx.Err() != nil {
+			break
+		}
+		fmt.Printf("  🪞 %v; trying mirror %s\n", err, mirror)
+		filename, downloadTLS, mirrorErr := downloadInstaller(mirror, slug)
+		if mirrorErr == nil {
+			return filename, mirror, downloadTLS, nil
+		}
+		fmt.Printf("  ⚠️  Warning: Mirror failed: %v\n", mirrorErr)
+	}
+	if len(mirrors) > 0 {
+		return "", "", nil, fmt.Errorf("%w (%d mirrors also failed)", err, len(mirrors))
+	}
+	return "", "", nil, err
+}
+
+// Truncated downloads are retried up to maxDownloadAttempts times in all
+const maxDownloadAttempts = 3
+
+// errTruncated marks a download that ended before Content-Length bytes arrived
+var errTruncated = errors.New("download truncated")
+
+// downloadInstaller downloads url into tempDir, retrying when the transfer is
+// cut short so a partial image doesn't surface later as a confusing mount or
+// extraction failure
+func downloadInstaller(url, slug string) (string, *tlsinfo.Info, error) {
+	fmt.Printf("  📥 Downloading installer...\n")
+	for attempt := 1; ; attempt++ {
+		filename, downloadTLS, err := downloadInstallerOnce(url, slug)
+		if !errors.Is(err, errTruncated) || attempt >= maxDownloadAttempts {
+			return filename, downloadTLS, err
+		}
+		fmt.Printf("  🔁 %v, retrying (%d/%d)...\n", err, attempt+1, maxDownloadAttempts)
+		select {
+		case <-time.After(time.Duration(attempt) * 2 * time.Second):
+		case <-runCtx.Done():
+			return "", nil, runCtx.Err()
+		}
+	}
+}
+
+// downloadInstallerOnce makes a single download attempt, checking the bytes
+// written against Content-Length when the server sent one
+func downloadInstallerOnce(url, slug string) (string, *tlsinfo.Info, error) {
+
+	req, err := http.NewRequestWithContext(runCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := doWithRetryAfter(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("failed to download: status %d", resp.StatusCode)
+	}
+
+	downloadTLS := tlsinfo.FromResponse(resp)
+
+	// Skip the app rather than filling the runner's disk halfway through the run
+	if err := checkDiskSpace(resp.ContentLength); err != nil {
+		return "", nil, err
+	}
+
+	// Determine file extension from URL or Content-Type header
+	ext := getInstallerExtension(url, resp.Header.Get("Content-Type"))
+	if ext == "" {
+		ext = ".dmg" // Default to DMG
+	}
+
+	filename := filepath.Join(tempDir, fmt.Sprintf("%s%s", strings.ReplaceAll(slug, "/", "_"), ext))
+	out, err := os.Create(filename)
+	if err != nil {
+		return "", nil, err
+	}
+	defer out.Close()
+
+	var body io.Reader = resp.Body
+	if downloadRateLimit > 0 {
+		body = throttle.NewReader(body, downloadRateLimit)
+	}
+	written, err := io.Copy(out, body)
+	if errors.Is(err, io.ErrUnexpectedEOF) || (err == nil && resp.ContentLength > 0 && written != resp.ContentLength) {
+		err = fmt.Errorf("%w: got %d of %d bytes", errTruncated, written, resp.ContentLength)
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(filename) // Clean up partial download
+		return "", nil, err
+	}
+	out.Close() // Close before checking file type
+
+	// Verify file was actually written and has content
+	if info, err := os.Stat(filename); err != nil || info.Size() == 0 {
+		if err == nil {
+			os.Remove(filename)
+			return "", nil, fmt.Errorf("downloaded file is empty")
+		}
+		return "", nil, fmt.Errorf("downloaded file not found: %w", err)
+	}
+
+	// Verify and correct file type by checking actual file content
+	actualExt, err := detectActualFileType(filename)
+	if err == nil && actualExt != "" && actualExt != ext {
+		// File type doesn't match extension, rename it
+		newFilename := strings.TrimSuffix(filename, ext) + actualExt
+		if err := os.Rename(filename, newFilename); err != nil {
+			return filename, downloadTLS, nil // Return original filename
+		}
+		return newFilename, downloadTLS, nil
+	}
+
+	return filename, downloadTLS, nil
+}
+
+// Downloads honor Retry-After at most this many times, for waits up to maxRetryAfter
+const (
+	maxDownloadWaits = 3
+	maxRetryAfter    = 5 * time.Minute
+)
+
+// doWithRetryAfter sends req and, when the server answers 429 or 503 (or a 403
+// with Retry-After, as GitHub's release CDN does for secondary rate limits),
+// waits as asked and sends it again instead of failing the app
+func doWithRetryAfter(req *http.Request) (*http.Response, error) {
+	for waits := 0; ; waits++ {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		wait, ok := retryAfter(resp)
+		if !ok || waits >= maxDownloadWaits || wait > maxRetryAfter {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		fmt.Printf("  ⏳ %s asked to retry in %s...\n", req.URL.Host, wait.Round(time.Second))
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryAfter returns the Retry-After delay of a throttled response, in
+// seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusForbidden:
+	default:
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return max(time.Duration(seconds)*time.Second, time.Second), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), time.Second), true
+	}
+	return 0, false
+}
+
+// checkDiskSpace makes sure the download directory and the install location can
+// take an installer of size bytes (-1 when the server didn't say) plus a margin
+func checkDiskSpace(size int64) error {
+	required := uint64(diskSpaceMargin)
+	if size > 0 {
+		required += uint64(size)
+	}
+
+	for _, dir := range []string{tempDir, applicationsDir} {
+		free, err := freeDiskSpace(dir)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Could not check free space on %s: %v\n", dir, err)
+			continue
+		}
+		if free < required {
+			return fmt.Errorf("insufficient disk space on %s: %s free, %s needed", dir, formatSize(free), formatSize(required))
+		}
+	}
+	return nil
+}
+
+func formatSize(bytes uint64) string {
+	return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+}
+
+func calculateSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// detectActualFileType uses the `file` command to determine the actual file type
+func detectActualFileType(filepath string) (string, error) {
+	cmd := newCommand(context.Background(), "file", filepath)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	fileType := strings.ToLower(string(output))
+
+	// Check for PKG (xar archive)
+	if strings.Contains(fileType, "xar archive") || strings.Contains(fileType, "pkg") {
+		return ".pkg", nil
+	}
+
+	// Check for DMG
+	if strings.Contains(fileType, "disk image") || strings.Contains(fileType, "dmg") || strings.Contains(fileType, "udif") {
+		return ".dmg", nil
+	}
+
+	// Check for ZIP (handle various formats: "Zip archive", "Zip archive data", etc.)
+	if strings.Contains(fileType, "zip archive") || strings.Contains(fileType, "zip") ||
+		strings.Contains(fileType, "compressed") && !strings.Contains(fileType, "dmg") {
+		return ".zip", nil
+	}
+
+	return "", nil // Unknown type, keep original extension
+}
+
+// getInstallerExtension determines the installer file extension from URL and Content-Type
+func getInstallerExtension(url, contentType string) string {
+	// First, try to get extension from Content-Type header
+	if contentType != "" {
+		if strings.Contains(contentType, "application/x-apple-diskimage") || strings.Contains(contentType, "application/octet-stream") {
+			// Check URL for .dmg
+			if strings.Contains(strings.ToLower(url), ".dmg") {
+				return ".dmg"
+			}
+		}
+		if strings.Contains(contentType, "application/zip") {
+			return ".zip"
+		}
+		if strings.Contains(contentType, "application/x-pkg") || strings.Contains(contentType, "application/x-installer") {
+			return ".pkg"
+		}
+	}
+
+	// Parse URL to find extension, but be smart about version numbers
+	// Remove query string and fragment
+	urlPath := url
+	if idx := strings.Index(urlPath, "?"); idx != -1 {
+		urlPath = urlPath[:idx]
+	}
+	if idx := strings.Index(urlPath, "#"); idx != -1 {
+		urlPath = urlPath[:idx]
+	}
+
+	// Look for known installer extensions in the URL
+	// Check in reverse order (zip, pkg, dmg) to prioritize nested extensions like .pkg.zip
+	// This ensures "Pritunl.pkg.zip" is detected as .zip, not .pkg
+	knownExts := []string{".zip", ".pkg", ".dmg"}
+	urlPathLower := strings.ToLower(urlPath)
+
+	// First, check for suffix matches (most common case)
+	for _, knownExt := range knownExts {
+		if strings.HasSuffix(urlPathLower, knownExt) {
+			return knownExt
+		}
+	}
+
+	// Also check if extension appears in the URL (for cases where it's not at the end)
+	// But only if we didn't find a suffix match
+	for _, knownExt := range knownExts {
+		idx := strings.Index(urlPathLower, knownExt)
+		if idx != -1 {
+			// Check if there's a version-like pattern after it (e.g., .dmg.1.2.3)
+			remaining := urlPathLower[idx+len(knownExt):]
+			if len(remaining) > 0 && (remaining[0] == '.' || remaining[0] == '?' || remaining[0] == '#') {
+				return knownExt
+			}
+		}
+	}
+
+	// If no known extension found, try filepath.Ext but filter out version numbers
+	ext := filepath.Ext(urlPath)
+	// If extension looks like a version number (starts with digit or has multiple dots), ignore it
+	if ext != "" {
+		extLower := strings.ToLower(ext)
+		// Check if it's a known extension
+		for _, knownExt := range knownExts {
+			if extLower == knownExt {
+				return extLower
+			}
+		}
+		// If extension starts with a digit or has multiple parts, it's likely a version number
+		if len(ext) > 1 && (ext[1] >= '0' && ext[1] <= '9') {
+			// Try to find a real extension before this
+			base := strings.TrimSuffix(urlPath, ext)
+			realExt := filepath.Ext(base)
+			if realExt != "" {
+				realExtLower := strings.ToLower(realExt)
+				for _, knownExt := range knownExts {
+					if realExtLower == knownExt {
+						return realExtLower
+					}
+				}
+			}
+		}
+	}
+
+	return "" // Will default to .dmg
+}
+
+func installApp(installerPath string, app datafile.AppVersion) (string, error) {
+	fmt.Printf("  📦 Installing app...\n")
+	removeCompanionApps() // Left behind when the previous app failed
+
+	// First, verify the actual file type (in case it was misnamed)
+	actualExt, err := detectActualFileType(installerPath)
+	if err == nil && actualExt != "" {
+		currentExt := strings.ToLower(filepath.Ext(installerPath))
+		if actualExt != currentExt {
+			// File type doesn't match extension, rename it
+			newPath := strings.TrimSuffix(installerPath, currentExt) + actualExt
+			if err := os.Rename(installerPath, newPath); err == nil {
+				installerPath = newPath
+			}
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(installerPath))
+	var appPath string
+
+	switch ext {
+	case ".dmg":
+		appPath, err = installFromDMG(installerPath, app)
+		// If DMG fails and error suggests it's not a DMG, try as ZIP
+		if err != nil && (strings.Contains(err.Error(), "not recognized") ||
+			strings.Contains(err.Error(), "Zip archive")) {
+			// Rename and try as ZIP
+			zipPath := strings.TrimSuffix(installerPath, ".dmg") + ".zip"
+			if renameErr := os.Rename(installerPath, zipPath); renameErr == nil {
+				appPath, err = installFromZIP(zipPath, app)
+			}
+		}
+	case ".pkg":
+		// Verify PKG file exists and has content before attempting installation
+		info, err := os.Stat(installerPath)
+		if err != nil {
+			return "", fmt.Errorf("PKG file not found: %s (%w)", installerPath, err)
+		}
+		if info.Size() == 0 {
+			return "", fmt.Errorf("PKG file is empty: %s", installerPath)
+		}
+		appPath, err = installFromPKG(installerPath, app)
+		// If PKG installation returns empty path, it might actually be a ZIP containing a PKG
+		// Try treating it as a ZIP (e.g., Pritunl.pkg.zip)
+		if err != nil && (appPath == "" || strings.Contains(err.Error(), "empty path")) {
+			zipPath := strings.TrimSuffix(installerPath, ".pkg") + ".zip"
+			if renameErr := os.Rename(installerPath, zipPath); renameErr == nil {
+				appPath, err = installFromZIP(zipPath, app)
+			}
+		}
+	case ".zip":
+		appPath, err = installFromZIP(installerPath, app)
+	default:
+		return "", fmt.Errorf("unsupported installer type: %s", ext)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	// Wait a moment for installation to complete
+	time.Sleep(2 * time.Second)
+
+	// Remove quarantine attributes (macOS adds these when downloading files)
+	removeQuarantineAttributes(appPath) // Ignore errors
+
+	return appPath, nil
+}
+
+func installFromDMG(dmgPath string, app datafile.AppVersion) (string, error) {
+	// Verify DMG file exists and is readable
+	if info, err := os.Stat(dmgPath); err != nil {
+		return "", fmt.Errorf("DMG file not found or not readable: %w", err)
+	} else if info.Size() == 0 {
+		return "", fmt.Errorf("DMG file is empty (size: 0 bytes)")
+	}
+
+	// Trace the mount on its own; any return before it succeeds marks it failed
+	mountSpan := appSpan.Start("mount")
+	defer mountSpan.End(fmt.Errorf("failed to mount DMG"))
+
+	// Clean up any existing mount point
+	mountPoint := filepath.Join(tempDir, "mnt")
+	os.RemoveAll(mountPoint)
+	if err := os.MkdirAll(mountPoint, 0755); err != nil {
+		return "", fmt.Errorf("failed to create mount point: %w", err)
+	}
+
+	// Try mounting with explicit mountpoint (using -noverify like in workflow)
+	// First attempt: try with auto-accept EULA by piping "Y"
+	cmd := newCommand(runCtx, "hdiutil", "attach", dmgPath, "-mountpoint", mountPoint, "-nobrowse", "-noverify", "-noautoopen", "-quiet")
+	cmd.Stdin = strings.NewReader("Y\n") // Auto-accept EULA if present
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	if err != nil {
+		// If explicit mountpoint fails, try letting hdiutil choose the mount point (with EULA acceptance)
+		cmd2 := newCommand(runCtx, "hdiutil", "attach", dmgPath, "-nobrowse", "-noverify", "-noautoopen", "-quiet")
+		cmd2.Stdin = strings.NewReader("Y\n") // Auto-accept EULA if present
+		var stdout2 bytes.Buffer
+		var stderr2 bytes.Buffer
+		cmd2.Stdout = &stdout2
+		cmd2.Stderr = &stderr2
+		err2 := cmd2.Run()
+
+		if err2 != nil {
+			// Both methods failed, try one more time without -quiet to get actual error (with EULA acceptance)
+			cmd3 := newCommand(runCtx, "hdiutil", "attach", dmgPath, "-nobrowse", "-noverify", "-noautoopen")
+			cmd3.Stdin = strings.NewReader("Y\n") // Auto-accept EULA if present
+			var stdout3 bytes.Buffer
+			var stderr3 bytes.Buffer
+			cmd3.Stdout = &stdout3
+			cmd3.Stderr = &stderr3
+			err3 := cmd3.Run()
+
+			// Check if the error is due to EULA (output contains "EULA" or "license" or "agreement")
+			output3 := stdout3.String() + stderr3.String()
+			if strings.Contains(strings.ToLower(output3), "eula") || strings.Contains(strings.ToLower(output3), "license") || strings.Contains(strings.ToLower(output3), "agreement") || strings.Contains(strings.ToLower(output3), "end-user") {
+				// EULA detected, try using shell command to pipe "Y" to hdiutil
+
+				// Try with explicit mountpoint first
+				shellCmd := fmt.Sprintf("echo 'Y' | hdiutil attach '%s' -mountpoint '%s' -nobrowse -noverify -noautoopen -quiet 2>&1", dmgPath, mountPoint)
+				cmd4 := newCommand(runCtx, "sh", "-c", shellCmd)
+				var stdout4 bytes.Buffer
+				var stderr4 bytes.Buffer
+				cmd4.Stdout = &stdout4
+				cmd4.Stderr = &stderr4
+				err4 := cmd4.Run()
+
+				if err4 != nil {
+					// Try without explicit mountpoint
+					shellCmd2 := fmt.Sprintf("echo 'Y' | hdiutil attach '%s' -nobrowse -noverify -noautoopen -quiet 2>&1", dmgPath)
+					cmd5 := newCommand(runCtx, "sh", "-c", shellCmd2)
+					var stdout5 bytes.Buffer
+					var stderr5 bytes.Buffer
+					cmd5.Stdout = &stdout5
+					cmd5.Stderr = &stderr5
+					err5 := cmd5.Run()
+
+					if err5 == nil {
+						// Success, parse mount point
+						output := stdout5.String()
+						if output == "" {
+							output = stderr5.String()
+						}
+						lines := strings.Split(output, "\n")
+						for _, line := range lines {
+							fields := strings.Fields(line)
+							if len(fields) >= 2 && strings.HasPrefix(fields[1], "/Volumes/") {
+								detectedMount := fields[1]
+								// Verify it's not a system volume
+								if !strings.Contains(strings.ToLower(detectedMount), "macintosh") &&
+									!strings.Contains(strings.ToLower(detectedMount), "system") &&
+									!strings.Contains(strings.ToLower(detectedMount), "recovery") {
+									mountPoint = detectedMount
+									break
+								}
+							}
+						}
+						// If we still don't have a mount point, try to find recently mounted volumes
+						if mountPoint == filepath.Join(tempDir, "mnt") {
+							volumes, _ := filepath.Glob("/Volumes/*")
+							var latestVolume string
+							var latestTime time.Time
+							systemVolumes := map[string]bool{
+								"/Volumes/Macintosh HD": true,
+								"/Volumes/Preboot":      true,
+								"/Volumes/Recovery":     true,
+								"/Volumes/Update":       true,
+								"/Volumes/VM":           true,
+							}
+							for _, vol := range volumes {
+								// Skip system volumes
+								if systemVolumes[vol] {
+									continue
+								}
+								// Skip volumes that look like system volumes
+								volBase := filepath.Base(vol)
+								if strings.Contains(strings.ToLower(volBase), "macintosh") ||
+									strings.Contains(strings.ToLower(volBase), "system") ||
+									strings.Contains(strings.ToLower(volBase), "recovery") {
+									continue
+								}
+								if info, err := os.Stat(vol); err == nil && info.IsDir() {
+									if info.ModTime().After(latestTime) {
+										latestTime = info.ModTime()
+										latestVolume = vol
+									}
+								}
+							}
+							if latestVolume != "" {
+								mountPoint = latestVolume
+							} else {
+								return "", fmt.Errorf("failed to mount DMG: could not determine mount point after EULA acceptance")
+							}
+						}
+						// Verify the mount point is actually a DMG mount (not a system volume)
+						if strings.Contains(strings.ToLower(mountPoint), "macintosh") {
+							return "", fmt.Errorf("failed to mount DMG: detected system volume instead of DMG mount point: %s", mountPoint)
+						}
+						goto verifyMount
+					}
+				} else {
+					// Method 4 succeeded with explicit mountpoint
+					goto verifyMount
+				}
+			}
+
+			// Collect all error messages
+			errorMsgs := []string{}
+			if stderr.String() != "" {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("method1-stderr: %s", strings.TrimSpace(stderr.String())))
+			}
+			if stdout.String() != "" {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("method1-stdout: %s", strings.TrimSpace(stdout.String())))
+			}
+			if stderr2.String() != "" {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("method2-stderr: %s", strings.TrimSpace(stderr2.String())))
+			}
+			if stdout2.String() != "" {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("method2-stdout: %s", strings.TrimSpace(stdout2.String())))
+			}
+			if stderr3.String() != "" {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("method3-stderr: %s", strings.TrimSpace(stderr3.String())))
+			}
+			if stdout3.String() != "" {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("method3-stdout: %s", strings.TrimSpace(stdout3.String())))
+			}
+
+			errorMsg := "unknown error"
+			if len(errorMsgs) > 0 {
+				errorMsg = strings.Join(errorMsgs, "; ")
+			} else {
+				// Last resort: check exit codes
+				errorMsg = fmt.Sprintf("hdiutil failed with exit codes: %v, %v, %v", err, err2, err3)
+			}
+
+			return "", fmt.Errorf("failed to mount DMG: %s", errorMsg)
+		}
+
+		// Method 2 succeeded, parse output to find mount point
+		output := stdout2.String()
+		if output == "" {
+			output = stderr2.String() // Sometimes hdiutil outputs to stderr
+		}
+		// Parse output to find mount point
+		// hdiutil attach output format: /dev/diskXsY	/Volumes/MountName
+		lines := strings.Split(output, "\n")
+		for _, line := range lines {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && strings.HasPrefix(fields[1], "/Volumes/") {
+				mountPoint = fields[1]
+				break
+			}
+		}
+		// If we still don't have a mount point, try to find recently mounted volumes
+		if mountPoint == filepath.Join(tempDir, "mnt") {
+			// List volumes and find the one that matches
+			volumes, _ := filepath.Glob("/Volumes/*")
+			// Use the most recently modified volume as a fallback, but exclude system volumes
+			var latestVolume string
+			var latestTime time.Time
+			systemVolumes := map[string]bool{
+				"/Volumes/Macintosh HD": true,
+				"/Volumes/Preboot":      true,
+				"/Volumes/Recovery":     true,
+				"/Volumes/Update":       true,
+				"/Volumes/VM":           true,
+			}
+			for _, vol := range volumes {
+				// Skip system volumes
+				if systemVolumes[vol] {
+					continue
+				}
+				// Skip volumes that look like system volumes (contain "Macintosh" or are common system names)
+				volBase := filepath.Base(vol)
+				if strings.Contains(strings.ToLower(volBase), "macintosh") ||
+					strings.Contains(strings.ToLower(volBase), "system") ||
+					strings.Contains(strings.ToLower(volBase), "recovery") {
+					continue
+				}
+				if info, err := os.Stat(vol); err == nil && info.IsDir() {
+					if info.ModTime().After(latestTime) {
+						latestTime = info.ModTime()
+						latestVolume = vol
+					}
+				}
+			}
+			if latestVolume != "" {
+				mountPoint = latestVolume
+			} else {
+				return "", fmt.Errorf("failed to mount DMG: could not determine mount point")
+			}
+		}
+	} else {
+		// Method 1 succeeded, check if mount point is valid
+		if _, err := os.Stat(mountPoint); err != nil {
+			// Mount succeeded but mount point doesn't exist, try parsing stdout
+			output := stdout.String()
+			if output == "" {
+				output = stderr.String()
+			}
+			lines := strings.Split(output, "\n")
+			for _, line := range lines {
+				fields := strings.Fields(line)
+				if len(fields) >= 2 && strings.HasPrefix(fields[1], "/Volumes/") {
+					mountPoint = fields[1]
+					break
+				}
+			}
+		}
+	}
+
+verifyMount:
+	// Verify mount point exists and is accessible
+	if _, err := os.Stat(mountPoint); err != nil {
+		return "", fmt.Errorf("failed to mount DMG: mount point not accessible: %s", mountPoint)
+	}
+	mountSpan.End(nil)
+	trackDMG(mountPoint)
+
+	defer func() {
+		// Detach using the actual mount point
+		newCommand(context.Background(), "hdiutil", "detach", mountPoint, "-quiet", "-force").Run()
+		untrackDMG(mountPoint)
+	}()
+
+	// First, look for .app bundle in mounted DMG - prioritize .app bundles over PKG installers
+	// Some DMGs (like Wireshark) contain both .app bundles AND PKG installers (for CLI tools)
+	appBundle := findDMGAppBundle(mountPoint, app)
+
+	// If we found an .app bundle, copy it directly (skip PKG search)
+	if appBundle != "" {
+		// Verify app bundle resolves inside the mount point (safety check against copying through aliases)
+		if !resolvesWithin(mountPoint, appBundle) {
+			return "", fmt.Errorf("app bundle path %s is outside mount point %s (possible symlink issue)", appBundle, mountPoint)
+		}
+
+		// Copy .app to Applications
+		appName := filepath.Base(appBundle)
+		destPath := filepath.Join(applicationsDir, appName)
+
+		// Verify source exists
+		if _, err := os.Stat(appBundle); err != nil {
+			return "", fmt.Errorf("app bundle not found at %s: %w", appBundle, err)
+		}
+
+		// Verify source bundle structure is valid (check for required bundle components)
+		infoPlistPath := filepath.Join(appBundle, "Contents", "Info.plist")
+		if _, err := os.Stat(infoPlistPath); err != nil {
+			return "", fmt.Errorf("source app bundle appears invalid (missing Info.plist): %s", appBundle)
+		}
+
+		// Verify source bundle with codesign before copying
+		verifyCmd := newCommand(context.Background(), "codesign", "-dv", appBundle)
+		var verifyStderr bytes.Buffer
+		verifyCmd.Stderr = &verifyStderr
+		if err := verifyCmd.Run(); err != nil {
+			verifyOutput := strings.TrimSpace(verifyStderr.String())
+			// If it says "bundle format unrecognized", the source is already corrupted
+			if strings.Contains(verifyOutput, "bundle format unrecognized") {
+				return "", fmt.Errorf("source app bundle is corrupted on DMG mount point: %s (codesign: %s)", appBundle, verifyOutput)
+			}
+			// Other codesign errors are OK (unsigned apps, etc.), but log them
+		}
+
+		// Remove existing app if present (use more thorough cleanup)
+		os.RemoveAll(destPath)
+		// Wait a moment for filesystem to sync
+		time.Sleep(500 * time.Millisecond)
+
+		// Use ditto to copy app bundle (preserves resource forks, extended attributes, symlinks, and bundle structure)
+		// ditto is specifically designed for copying macOS app bundles correctly
+		cmd = newCommand(runCtx, "ditto", appBundle, destPath)
+		var dittoStderr bytes.Buffer
+		var dittoStdout bytes.Buffer
+		cmd.Stderr = &dittoStderr
+		cmd.Stdout = &dittoStdout
+		if err := cmd.Run(); err != nil {
+			// If ditto fails, try using Go's file operations as fallback
+			if err := copyDirectory(appBundle, destPath); err != nil {
+				return "", fmt.Errorf("failed to copy app (ditto failed: %s, fallback failed: %w)", strings.TrimSpace(dittoStderr.String()), err)
+			}
+		}
+
+		// Verify copy succeeded and bundle structure is intact
+		if _, err := os.Stat(destPath); err != nil {
+			return "", fmt.Errorf("copy appeared to succeed but destination not found: %w", err)
+		}
+
+		// Verify destination bundle structure
+		destInfoPlistPath := filepath.Join(destPath, "Contents", "Info.plist")
+		if _, err := os.Stat(destInfoPlistPath); err != nil {
+			return "", fmt.Errorf("copied app bundle appears invalid (missing Info.plist): %s", destPath)
+		}
+
+		// Verify destination bundle with codesign
+		destVerifyCmd := newCommand(context.Background(), "codesign", "-dv", destPath)
+		var destVerifyStderr bytes.Buffer
+		destVerifyCmd.Stderr = &destVerifyStderr
+		if err := destVerifyCmd.Run(); err != nil {
+			verifyOutput := strings.TrimSpace(destVerifyStderr.String())
+			// If it says "bundle format unrecognized", the copy corrupted the bundle
+			if strings.Contains(verifyOutput, "bundle format unrecognized") {
+				return "", fmt.Errorf("copied app bundle is corrupted: %s (codesign: %s). Source may be corrupted or copy failed.", destPath, verifyOutput)
+			}
+			// Other codesign errors are OK (unsigned apps, etc.)
+		}
+
+		copyCompanionApps(mountPoint, appBundle)
+
+		return destPath, nil
+	}
+
+	// Only if no .app bundle found, check for PKG installer as fallback
+	// Skip PKGs that are inside .app bundles (those are not installers)
+	var pkgFile string
+	_ = filepath.Walk(mountPoint, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if strings.HasSuffix(strings.ToLower(path), ".pkg") && info != nil && !info.IsDir() {
+			// Skip PKGs that are inside .app bundles (e.g., CloudConfig.pkg inside VNC Viewer.app)
+			pathLower := strings.ToLower(path)
+			if strings.Contains(pathLower, ".app/") {
+				return nil // Skip PKGs inside app bundles
+			}
+			// Verify it's actually a file, not a symlink to one outside the image
+			if info.Mode().IsRegular() {
+				pkgFile = path
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+
+	// If we found a PKG, verify it exists and install it
+	if pkgFile != "" {
+		// Verify PKG file exists and is readable
+		if _, err := os.Stat(pkgFile); err != nil {
+			fmt.Printf("  ⚠️  Warning: PKG file not found or not accessible: %s, trying to find .app bundle instead\n", pkgFile)
+			pkgFile = "" // Clear it so we look for .app bundle instead
+		} else {
+			fmt.Printf("  📦 Found PKG installer in DMG, installing...\n")
+			// Install the PKG with -allowUntrusted and -verbose for better error reporting
+			installCmd := newCommand(runCtx, "sudo", "installer", "-pkg", pkgFile, "-target", "/", "-allowUntrusted", "-verbose")
+			var installStderr bytes.Buffer
+			var installStdout bytes.Buffer
+			installCmd.Stderr = &installStderr
+			installCmd.Stdout = &installStdout
+			if err := installCmd.Run(); err != nil {
+				stderrStr := strings.TrimSpace(installStderr.String())
+				stdoutStr := strings.TrimSpace(installStdout.String())
+				errorDetails := []string{}
+				if stderrStr != "" {
+					errorDetails = append(errorDetails, fmt.Sprintf("stderr: %s", stderrStr))
+				}
+				if stdoutStr != "" {
+					errorDetails = append(errorDetails, fmt.Sprintf("stdout: %s", stdoutStr))
+				}
+				errorMsg := strings.Join(errorDetails, "; ")
+				if errorMsg != "" {
+					return "", fmt.Errorf("failed to install PKG from DMG: %w (%s)", err, errorMsg)
+				}
+				return "", fmt.Errorf("failed to install PKG from DMG: %w", err)
+			}
+
+			// Wait for installation to complete
+			time.Sleep(5 * time.Second)
+
+			// Now find the installed app in /Applications
+			appPath, err := findInstalledApp(app)
+			if err != nil {
+				// Try to find recently modified apps as fallback
+				var recentApps []string
+				cutoffTime := time.Now().Add(-10 * time.Minute)
+				_ = filepath.Walk(applicationsDir, func(path string, info os.FileInfo, err error) error {
+					if err != nil {
+						return nil
+					}
+					if strings.HasSuffix(path, ".app") && info != nil && info.IsDir() {
+						if info.ModTime().After(cutoffTime) {
+							recentApps = append(recentApps, filepath.Base(path))
+						}
+					}
+					return nil
+				})
+				if len(recentApps) == 1 {
+					candidatePath := filepath.Join(applicationsDir, recentApps[0])
+					if _, err := os.Stat(candidatePath); err == nil {
+						return candidatePath, nil
+					}
+				}
+				// Check if app exists (may have been installed previously)
+				for _, variation := range []string{app.Name + ".app", strings.ReplaceAll(app.Name, " ", "") + ".app"} {
+					candidatePath := filepath.Join(applicationsDir, variation)
+					if _, err := os.Stat(candidatePath); err == nil {
+						return candidatePath, nil
+					}
+				}
+				return "", fmt.Errorf("could not find installed app '%s' after PKG installation from DMG: %w", app.Name, err)
+			}
+			return appPath, nil
+		}
+	}
+
+	// If we get here, neither .app bundle nor PKG installer was found
+	// List contents for debugging
+	var contents []string
+	filepath.Walk(mountPoint, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info != nil {
+			relPath, _ := filepath.Rel(mountPoint, path)
+			if relPath != "." {
+				contents = append(contents, relPath)
+			}
+		}
+		return nil
+	})
+	return "", fmt.Errorf("could not find .app bundle or .pkg installer in DMG. Contents: %v", contents[:min(10, len(contents))])
+}
+
+func findInstalledApp(app datafile.AppVersion) (string, error) {
+	// Wait a bit longer for installation to fully complete
+	time.Sleep(2 * time.Second)
+
+	// Try to find the installed app by name variations
+	variations := []string{
+		app.Name + ".app",
+		strings.ReplaceAll(app.Name, " ", "") + ".app",
+		strings.ReplaceAll(app.Name, " ", "_") + ".app",
+		strings.ReplaceAll(app.Name, " ", "-") + ".app",
+		// Adobe-specific variations
+		strings.TrimSuffix(app.Name, " DC") + ".app",
+		strings.TrimSuffix(app.Name, " Pro DC") + ".app",
+		strings.TrimSuffix(app.Name, " Pro") + ".app",
+		// Remove common suffixes
+		strings.TrimSuffix(app.Name, " Desktop") + ".app",
+		strings.TrimSuffix(app.Name, " Suite") + ".app",
+		strings.TrimSuffix(app.Name, " Viewer") + ".app",
+		strings.TrimSuffix(app.Name, " Client") + ".app",
+	}
+
+	// For multi-word names, try just the first word (e.g., "Box Drive" -> "Box.app")
+	nameWords := strings.Fields(app.Name)
+	if len(nameWords) > 1 {
+		variations = append(variations, nameWords[0]+".app")
+		// Also try first two words
+		if len(nameWords) > 2 {
+			variations = append(variations, strings.Join(nameWords[:2], " ")+".app")
+			variations = append(variations, strings.Join(nameWords[:2], "")+".app")
+		}
+		// Try last word (e.g., "Podman Desktop" -> "Desktop.app" is unlikely, but try it)
+		// Actually skip this, it's too generic
+	}
+
+	for _, variation := range variations {
+		appPath := filepath.Join(applicationsDir, variation)
+		if _, err := os.Stat(appPath); err == nil {
+			return appPath, nil
+		}
+	}
+
+	// If not found by name, search for any .app that might match
+	var foundApp string
+	var bestMatch string
+	bestMatchScore := 0
+
+	_ = filepath.Walk(applicationsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		// Look for apps that contain key words from the app name
+		if strings.HasSuffix(path, ".app") && info != nil && info.IsDir() {
+			appBaseName := strings.ToLower(strings.TrimSuffix(filepath.Base(path), ".app"))
+			nameLower := strings.ToLower(app.Name)
+
+			// Exact match (case-insensitive)
+			if appBaseName == nameLower {
+				foundApp = path
+				return filepath.SkipDir
+			}
+
+			// Check if app name contains key words
+			keyWords := strings.Fields(nameLower)
+			matchScore := 0
+			for _, word := range keyWords {
+				if len(word) > 2 { // Lower threshold to catch "Box"
+					if strings.Contains(appBaseName, word) {
+						matchScore++
+					}
+				}
+			}
+
+			// Also check if the app name starts with any key word
+			if len(keyWords) > 0 && strings.HasPrefix(appBaseName, strings.ToLower(keyWords[0])) {
+				matchScore += 2 // Higher score for prefix match
+			}
+
+			if matchScore > bestMatchScore {
+				bestMatch = path
+				bestMatchScore = matchScore
+			}
+		}
+		return nil
+	})
+
+	if foundApp != "" {
+		return foundApp, nil
+	}
+
+	if bestMatch != "" && bestMatchScore > 0 {
+		return bestMatch, nil
+	}
+
+	// Last resort: check recently modified apps (within last 5 minutes)
+	// This helps catch apps that were just installed but have unexpected names
+	var recentApps []string
+	cutoffTime := time.Now().Add(-5 * time.Minute)
+	_ = filepath.Walk(applicationsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if strings.HasSuffix(path, ".app") && info != nil && info.IsDir() {
+			// Check if modified in last 5 minutes
+			if info.ModTime().After(cutoffTime) {
+				recentApps = append(recentApps, path) // Store full path, not just name
+			}
+		}
+		return nil
+	})
+
+	if len(recentApps) > 0 {
+		// Filter out helper apps and look for the main app
+		var mainApps []string
+		for _, appPath := range recentApps {
+			appName := filepath.Base(appPath)
+			appLower := strings.ToLower(appName)
+			// Skip helper apps, code helpers, etc.
+			if strings.Contains(appLower, "helper") ||
+				strings.Contains(appLower, "plugin") ||
+				strings.Contains(appLower, "renderer") ||
+				strings.Contains(appLower, "gpu") {
+				continue
+			}
+			mainApps = append(mainApps, appPath)
+		}
+
+		// If we have main apps, try them
+		if len(mainApps) > 0 {
+			for _, appPath := range mainApps {
+				if _, err := os.Stat(appPath); err == nil {
+					// Check if it matches the app name we're looking for
+					appName := filepath.Base(appPath)
+					appNameLower := strings.ToLower(strings.TrimSuffix(appName, ".app"))
+					searchNameLower := strings.ToLower(app.Name)
+					if strings.Contains(appNameLower, searchNameLower) ||
+						strings.Contains(searchNameLower, appNameLower) ||
+						len(mainApps) == 1 {
+						return appPath, nil
+					}
+				}
+			}
+		}
+
+		// If we found recently modified apps but they're command-line tools (not GUI apps),
+		// try to use the first one if it's the only option
+		if len(recentApps) == 1 || (len(recentApps) == 2 &&
+			(strings.Contains(strings.ToLower(recentApps[0]), "tctl") ||
+				strings.Contains(strings.ToLower(recentApps[0]), "tsh"))) {
+			// Try using the first recently modified app
+			appPath := filepath.Join(applicationsDir, recentApps[0])
+			if _, err := os.Stat(appPath); err == nil {
+				return appPath, nil
+			}
+		}
+		if len(recentApps) > 0 {
+			return "", fmt.Errorf("could not find installed app '%s' after PKG installation. Recently modified apps: %v", app.Name, recentApps[:min(5, len(recentApps))])
+		}
+		return "", fmt.Errorf("could not find installed app '%s' after PKG installation. No recently modified apps found", app.Name)
+	}
+
+	return "", fmt.Errorf("could not find installed app '%s' after PKG installation", app.Name)
+}
+
+// copyDirectory recursively copies a directory tree from src to dst
+func copyDirectory(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Calculate relative path from source
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		// Construct destination path
+		dstPath := filepath.Join(dst, relPath)
+
+		if info.IsDir() {
+			// Create directory
+			return os.MkdirAll(dstPath, info.Mode())
+		} else {
+			// Create parent directory if needed
+			if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+				return err
+			}
+
+			// Copy file
+			srcFile, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer srcFile.Close()
+
+			dstFile, err := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+			if err != nil {
+				return err
+			}
+			defer dstFile.Close()
+
+			_, err = io.Copy(dstFile, srcFile)
+			return err
+		}
+	})
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func installFromPKG(pkgPath string, app datafile.AppVersion) (string, error) {
+	// Verify PKG file exists and is readable
+	if _, err := os.Stat(pkgPath); err != nil {
+		return "", fmt.Errorf("PKG file not found or not accessible: %s (%w)", pkgPath, err)
+	}
+
+	// Install PKG with -allowUntrusted and -verbose for better error reporting
+	cmd := newCommand(runCtx, "sudo", "installer", "-pkg", pkgPath, "-target", "/", "-allowUntrusted", "-verbose")
+	var stderr bytes.Buffer
+	var stdout bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		stderrStr := strings.TrimSpace(stderr.String())
+		stdoutStr := strings.TrimSpace(stdout.String())
+		errorDetails := []string{}
+		if stderrStr != "" {
+			errorDetails = append(errorDetails, fmt.Sprintf("stderr: %s", stderrStr))
+		}
+		if stdoutStr != "" {
+			errorDetails = append(errorDetails, fmt.Sprintf("stdout: %s", stdoutStr))
+		}
+		errorMsg := strings.Join(errorDetails, "; ")
+		if errorMsg != "" {
+			return "", fmt.Errorf("failed to install PKG: %w (%s)", err, errorMsg)
+		}
+		return "", fmt.Errorf("failed to install PKG: %w", err)
+	}
+
+	// Wait longer for installation to complete (PKG installs can take time)
+	time.Sleep(5 * time.Second)
+
+	// Find the installed app
+	appPath, err := findInstalledApp(app)
+	if err != nil {
+		// If we can't find the app, list what was recently installed for debugging
+		fmt.Printf("  ⚠️  Could not find installed app '%s', listing recently modified apps in /Applications:\n", app.Name)
+		var recentApps []string
+		cutoffTime := time.Now().Add(-5 * time.Minute)
+		_ = filepath.Walk(applicationsDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if strings.HasSuffix(path, ".app") && info != nil && info.IsDir() {
+				if info.ModTime().After(cutoffTime) {
+					recentApps = append(recentApps, filepath.Base(path))
+				}
+			}
+			return nil
+		})
+		if len(recentApps) == 1 {
+			candidatePath := filepath.Join(applicationsDir, recentApps[0])
+			if _, err := os.Stat(candidatePath); err == nil {
+				return candidatePath, nil
+			}
+		}
+		return "", fmt.Errorf("could not find installed app after PKG installation: %w", err)
+	}
+	return appPath, nil
+}
+
+func installFromZIP(zipPath string, app datafile.AppVersion) (string, error) {
+	// Extract ZIP using ditto (preserves resource forks, extended attributes, symlinks, and macOS bundle structure)
+	// ditto -xk means: -x = extract, -k = source is a ZIP archive
+	extractDir := filepath.Join(tempDir, "extracted")
+	os.RemoveAll(extractDir) // Clean up any previous extraction
+	if err := os.MkdirAll(extractDir, 0755); err != nil {
+		return "", err
+	}
+
+	cmd := newCommand(runCtx, "ditto", "-xk", zipPath, extractDir)
+	var stderr bytes.Buffer
+	var stdout bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		errorMsg := strings.TrimSpace(stderr.String())
+		if errorMsg == "" {
+			errorMsg = strings.TrimSpace(stdout.String())
+		}
+		if errorMsg == "" {
+			errorMsg = "unknown error"
+		}
+		return "", fmt.Errorf("failed to extract ZIP with ditto: %s (%w)", errorMsg, err)
+	}
+
+	// First, check if ZIP contains a .pkg file (some ZIPs contain installers, not apps)
+	var pkgFile string
+	_ = filepath.Walk(extractDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if strings.HasSuffix(strings.ToLower(path), ".pkg") && info != nil && !info.IsDir() {
+			// Skip PKGs that are inside .app bundles (e.g., CloudConfig.pkg inside VNC Viewer.app)
+			pathLower := strings.ToLower(path)
+			if strings.Contains(pathLower, ".app/") {
+				return nil // Skip PKGs inside app bundles
+			}
+			// Verify it's actually a file and exists
+			if stat, err := os.Stat(path); err == nil && stat.Mode().IsRegular() {
+				pkgFile = path
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+
+	// If we found a PKG, verify it exists and install it
+	if pkgFile != "" {
+		// Verify PKG file exists and is readable
+		if _, err := os.Stat(pkgFile); err != nil {
+			fmt.Printf("  ⚠️  Warning: PKG file not found or not accessible: %s, trying to find .app bundle instead\n", pkgFile)
+			pkgFile = "" // Clear it so we look for .app bundle instead
+		} else {
+			fmt.Printf("  📦 Found PKG installer in ZIP, installing...\n")
+			// Install the PKG with -allowUntrusted and -verbose for better error reporting
+			installCmd := newCommand(runCtx, "sudo", "installer", "-pkg", pkgFile, "-target", "/", "-allowUntrusted", "-verbose")
+			var installStderr bytes.Buffer
+			var installStdout bytes.Buffer
+			installCmd.Stderr = &installStderr
+			installCmd.Stdout = &installStdout
+			if err := installCmd.Run(); err != nil {
+				stderrStr := strings.TrimSpace(installStderr.String())
+				stdoutStr := strings.TrimSpace(installStdout.String())
+				errorDetails := []string{}
+				if stderrStr != "" {
+					errorDetails = append(errorDetails, fmt.Sprintf("stderr: %s", stderrStr))
+				}
+				if stdoutStr != "" {
+					errorDetails = append(errorDetails, fmt.Sprintf("stdout: %s", stdoutStr))
+				}
+				errorMsg := strings.Join(errorDetails, "; ")
+				if errorMsg != "" {
+					return "", fmt.Errorf("failed to install PKG from ZIP: %w (%s)", err, errorMsg)
+				}
+				return "", fmt.Errorf("failed to install PKG from ZIP: %w", err)
+			}
+
+			// Wait longer for installation to complete (PKG installs can take time)
+			time.Sleep(5 * time.Second)
+
+			// Now find the installed app in /Applications
+			appPath, err := findInstalledApp(app)
+			if err != nil {
+				// If we can't find the app, list what was recently installed for debugging
+				fmt.Printf("  ⚠️  Could not find installed app '%s' after PKG installation from ZIP, listing recently modified apps in /Applications:\n", app.Name)
+				var recentApps []string
+				var allApps []string
+				cutoffTime := time.Now().Add(-10 * time.Minute) // Check last 10 minutes
+				_ = filepath.Walk(applicationsDir, func(path string, info os.FileInfo, err error) error {
+					if err != nil {
+						return nil
+					}
+					if strings.HasSuffix(path, ".app") && info != nil && info.IsDir() {
+						allApps = append(allApps, filepath.Base(path))
+						if info.ModTime().After(cutoffTime) {
+							recentApps = append(recentApps, filepath.Base(path))
+						}
+					}
+					return nil
+				})
+				if len(recentApps) == 1 {
+					candidatePath := filepath.Join(applicationsDir, recentApps[0])
+					if _, err := os.Stat(candidatePath); err == nil {
+						return candidatePath, nil
+					}
+				}
+				// Check if app exists (may have been installed previously)
+				for _, variation := range []string{app.Name + ".app", strings.ReplaceAll(app.Name, " ", "") + ".app"} {
+					candidatePath := filepath.Join(applicationsDir, variation)
+					if _, err := os.Stat(candidatePath); err == nil {
+						return candidatePath, nil
+					}
+				}
+				return "", fmt.Errorf("could not find installed app '%s' after PKG installation from ZIP: %w", app.Name, err)
+			}
+			return appPath, nil
+		}
+	}
+
+	// Otherwise, look for .app bundle in extracted ZIP - try multiple strategies
+	var appBundle string
+
+	// Strategy 1: Look for .app bundles by walking the directory tree,
+	// preferring the one named after the app when there are several
+	if bundles := topLevelAppBundles(extractDir); len(bundles) > 0 {
+		appBundle = preferredAppBundle(bundles, app)
+	}
+
+	// Strategy 2: If not found, try looking for common app names
+	if appBundle == "" {
+		commonNames := []string{
+			app.Name + ".app",
+			strings.ReplaceAll(app.Name, " ", "") + ".app",
+			strings.ReplaceAll(app.Name, " ", "_") + ".app",
+			strings.ReplaceAll(app.Name, " ", "-") + ".app",
+		}
+
+		// Also try first word of multi-word names
+		nameParts := strings.Fields(app.Name)
+		if len(nameParts) > 1 {
+			commonNames = append(commonNames, nameParts[0]+".app")
+		}
+
+		for _, name := range commonNames {
+			candidate := filepath.Join(extractDir, name)
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				appBundle = candidate
+				break
+			}
+		}
+	}
+
+	// Strategy 3: Look in common subdirectories (some ZIPs have apps in subfolders)
+	if appBundle == "" {
+		commonDirs := []string{"Applications", "Contents", "Install", "Installers"}
+		for _, dir := range commonDirs {
+			searchPath := filepath.Join(extractDir, dir)
+			if _, err := os.Stat(searchPath); err == nil {
+				_ = filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+					if err != nil {
+						return nil
+					}
+					if strings.HasSuffix(path, ".app") && info != nil && info.IsDir() {
+						appBundle = path
+						return filepath.SkipDir
+					}
+					return nil
+				})
+				if appBundle != "" {
+					break
+				}
+			}
+		}
+	}
+
+	if appBundle == "" {
+		// List contents for debugging
+		var contents []string
+		filepath.Walk(extractDir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info != nil {
+				relPath, _ := filepath.Rel(extractDir, path)
+				if relPath != "." && relPath != "" {
+					contents = append(contents, relPath)
+				}
+			}
+			return nil
+		})
+		maxContents := min(20, len(contents))
+		return "", fmt.Errorf("could not find .app bundle or .pkg installer in ZIP. Contents: %v", contents[:maxContents])
+	}
+
+	// Copy .app to Applications
+	appName := filepath.Base(appBundle)
+	destPath := filepath.Join(applicationsDir, appName)
+
+	// Verify source exists
+	if _, err := os.Stat(appBundle); err != nil {
+		return "", fmt.Errorf("app bundle not found at %s: %w", appBundle, err)
+	}
+
+	// Verify source bundle structure is valid (check for required bundle components)
+	infoPlistPath := filepath.Join(appBundle, "Contents", "Info.plist")
+	if _, err := os.Stat(infoPlistPath); err != nil {
+		return "", fmt.Errorf("source app bundle appears invalid (missing Info.plist): %s", appBundle)
+	}
+
+	// Verify source bundle with codesign before copying
+	verifyCmd := newCommand(context.Background(), "codesign", "-dv", appBundle)
+	var verifyStderr bytes.Buffer
+	verifyCmd.Stderr = &verifyStderr
+	if err := verifyCmd.Run(); err != nil {
+		verifyOutput := strings.TrimSpace(verifyStderr.String())
+		// If it says "bundle format unrecognized", the source is already corrupted
+		if strings.Contains(verifyOutput, "bundle format unrecognized") {
+			return "", fmt.Errorf("source app bundle is corrupted on DMG mount point: %s (codesign: %s)", appBundle, verifyOutput)
+		}
+		// Other codesign errors are OK (unsigned apps, etc.), but log them
+	}
+
+	// Remove existing app if present (use more thorough cleanup)
+	os.RemoveAll(destPath)
+	// Wait a moment for filesystem to sync
+	time.Sleep(500 * time.Millisecond)
+
+	// Use ditto to copy app bundle (preserves resource forks, extended attributes, symlinks, and bundle structure)
+	// ditto is specifically designed for copying macOS app bundles correctly
+	cmd = newCommand(runCtx, "ditto", appBundle, destPath)
+	var dittoStderr bytes.Buffer
+	var dittoStdout bytes.Buffer
+	cmd.Stderr = &dittoStderr
+	cmd.Stdout = &dittoStdout
+	if err := cmd.Run(); err != nil {
+		// If ditto fails, try using Go's file operations as fallback
+		fmt.Printf("  ⚠️  Warning: ditto command failed: %v, trying alternative copy method...\n", strings.TrimSpace(dittoStderr.String()))
+
+		// Use filepath.Walk to copy directory tree
+		if err := copyDirectory(appBundle, destPath); err != nil {
+			return "", fmt.Errorf("failed to copy app (ditto failed: %s, fallback failed: %w)", strings.TrimSpace(dittoStderr.String()), err)
+		}
+	}
+
+	// Verify copy succeeded and bundle structure is intact
+	if _, err := os.Stat(destPath); err != nil {
+		return "", fmt.Errorf("copy appeared to succeed but destination not found: %w", err)
+	}
+
+	// Verify destination bundle structure
+	destInfoPlistPath := filepath.Join(destPath, "Contents", "Info.plist")
+	if _, err := os.Stat(destInfoPlistPath); err != nil {
+		return "", fmt.Errorf("copied app bundle appears invalid (missing Info.plist): %s", destPath)
+	}
+
+	// Verify destination bundle with codesign
+	destVerifyCmd := newCommand(context.Background(), "codesign", "-dv", destPath)
+	var destVerifyStderr bytes.Buffer
+	destVerifyCmd.Stderr = &destVerifyStderr
+	if err := destVerifyCmd.Run(); err != nil {
+		verifyOutput := strings.TrimSpace(destVerifyStderr.String())
+		// If it says "bundle format unrecognized", the copy corrupted the bundle
+		if strings.Contains(verifyOutput, "bundle format unrecognized") {
+			return "", fmt.Errorf("copied app bundle is corrupted: %s (codesign: %s). Source may be corrupted or copy failed.", destPath, verifyOutput)
+		}
+		// Other codesign errors are OK (unsigned apps, etc.)
+	}
+
+	copyCompanionApps(extractDir, appBundle)
+
+	return destPath, nil
+}
+
+// removeQuarantineAttributes removes macOS quarantine extended attributes from an app
+// This is critical for santactl to work properly in CI environments where files
+// are downloaded via http.Get() and may have quarantine flags set
+func removeQuarantineAttributes(appPath string) error {
+	// Remove quarantine attribute recursively for .app bundles
+	if strings.HasSuffix(appPath, ".app") {
+		cmd := newCommand(context.Background(), "xattr", "-dr", "com.apple.quarantine", appPath)
+		if err := cmd.Run(); err != nil {
+			// If recursive removal fails, try non-recursive
+			cmd = newCommand(context.Background(), "xattr", "-d", "com.apple.quarantine", appPath)
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("failed to remove quarantine: %w", err)
+			}
+		}
+	} else {
+		// For executables, just remove from the file itself
+		cmd := newCommand(context.Background(), "xattr", "-d", "com.apple.quarantine", appPath)
+		if err := cmd.Run(); err != nil {
+			// Ignore errors if attribute doesn't exist
+			return nil
+		}
+	}
+	return nil
+}
+
+// findDMGAppBundle returns the .app bundle in a mounted image, or "" when
+// there is none. Symlinks are never followed: most images carry an
+// "Applications" link to /Applications for drag-and-drop installs, and
+// walking through it (or through an alias named like the app) would pick up
+// apps installed on the runner instead of the one in the image.
+func findDMGAppBundle(mountPoint string, app datafile.AppVersion) string {
+	// Strategy 1: Look for .app bundles by walking the directory tree,
+	// preferring the one named after the app when there are several
+	if bundles := topLevelAppBundles(mountPoint); len(bundles) > 0 {
+		return preferredAppBundle(bundles, app)
+	}
+
+	// Strategy 2: If not found, try looking for common app names
+	commonNames := []string{
+		app.Name + ".app",
+		strings.ReplaceAll(app.Name, " ", "") + ".app",
+		strings.ReplaceAll(app.Name, " ", "_") + ".app",
+	}
+	for _, name := range commonNames {
+		candidate := filepath.Join(mountPoint, name)
+		if info, err := os.Lstat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+	}
+
+	// Strategy 3: Look in common subdirectories (some DMGs have apps in
+	// subfolders), skipping the usual Applications symlink
+	var appBundle string
+	commonDirs := []string{"Applications", "Contents", "Install"}
+	for _, dir := range commonDirs {
+		searchPath := filepath.Join(mountPoint, dir)
+		info, err := os.Lstat(searchPath)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		_ = filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil || isSymlink(info) {
+				return nil
+			}
+			if strings.HasSuffix(path, ".app") && info.IsDir() {
+				appBundle = path
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if appBundle != "" {
+			return appBundle
+		}
+	}
+
+	return ""
+}
+
+// topLevelAppBundles lists the .app bundles under root that aren't nested in
+// another bundle (helpers inside Contents/ belong to their app), in walk
+// order. Symlinks are not followed.
+func topLevelAppBundles(root string) []string {
+	var bundles []string
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || isSymlink(info) {
+			// Continue walking even if we hit permission errors
+			return nil
+		}
+		if path != root && strings.HasSuffix(path, ".app") && info.IsDir() {
+			bundles = append(bundles, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return bundles
+}
+
+// preferredAppBundle picks the bundle named after the app from several, or
+// the first one
+func preferredAppBundle(bundles []string, app datafile.AppVersion) string {
+	for _, bundle := range bundles {
+		name := strings.TrimSuffix(filepath.Base(bundle), ".app")
+		if strings.EqualFold(name, app.Name) || strings.EqualFold(name, strings.ReplaceAll(app.Name, " ", "")) {
+			return bundle
+		}
+	}
+	return bundles[0]
+}
+
+// copyCompanionApps copies every top-level bundle under root other than
+// appBundle to /Applications and records them in companionApps. A companion
+// that fails to copy is skipped with a warning.
+func copyCompanionApps(root, appBundle string) {
+	for _, bundle := range topLevelAppBundles(root) {
+		if bundle == appBundle {
+			continue
+		}
+		destPath := filepath.Join(applicationsDir, filepath.Base(bundle))
+		os.RemoveAll(destPath)
+		if err := newCommand(runCtx, "ditto", bundle, destPath).Run(); err != nil {
+			if err := copyDirectory(bundle, destPath); err != nil {
+				fmt.Printf("  ⚠️  Warning: Failed to copy %s: %v\n", filepath.Base(bundle), err)
+				continue
+			}
+		}
+		removeQuarantineAttributes(destPath) // Ignore errors
+		companionApps = append(companionApps, destPath)
+	}
+	if len(companionApps) > 0 {
+		fmt.Printf("  📦 Also installed %d other app(s) from the installer\n", len(companionApps))
+	}
+}
+
+// isSymlink reports whether info, as returned by Lstat, describes a symlink
+func isSymlink(info os.FileInfo) bool {
+	return info != nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// resolvesWithin reports whether path, with every symlink resolved, is inside
+// root
+func resolvesWithin(root, path string) bool {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(resolvedRoot, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// bundleExecutable returns the main executable of a .app bundle, or appPath
+// itself when it isn't a bundle or no executable can be found
+func bundleExecutable(appPath string) string {
+	// If appPath is a .app bundle, try to find the executable inside
+	targetPath := appPath
+	if strings.HasSuffix(appPath, ".app") {
+		// Check if it's a directory (app bundle)
+		if info, err := os.Stat(appPath); err == nil && info.IsDir() {
+			// Try to find the executable in Contents/MacOS/
+			// First, try to read Info.plist to get the executable name
+			infoPlistPath := filepath.Join(appPath, "Contents", "Info.plist")
+			executableName := ""
+			if data, err := os.ReadFile(infoPlistPath); err == nil {
+				// Simple parsing to find CFBundleExecutable
+				content := string(data)
+				if idx := strings.Index(content, "<key>CFBundleExecutable</key>"); idx != -1 {
+					// Find the next <string> tag
+					if strIdx := strings.Index(content[idx:], "<string>"); strIdx != -1 {
+						start := idx + strIdx + 8
+						if endIdx := strings.Index(content[start:], "</string>"); endIdx != -1 {
+							executableName = strings.TrimSpace(content[start : start+endIdx])
+						}
+					}
+				}
+			}
+
+			// If we found the executable name, use it; otherwise try common names
+			if executableName != "" {
+				executablePath := filepath.Join(appPath, "Contents", "MacOS", executableName)
+				if _, err := os.Stat(executablePath); err == nil && !strings.HasPrefix(executableName, "._") {
+					targetPath = executablePath
+				}
+			} else {
+				// Try common executable names
+				appName := strings.TrimSuffix(filepath.Base(appPath), ".app")
+				commonNames := []string{appName}
+				for _, name := range commonNames {
+					executablePath := filepath.Join(appPath, "Contents", "MacOS", name)
+					if _, err := os.Stat(executablePath); err == nil && !strings.HasPrefix(name, "._") {
+						targetPath = executablePath
+						break
+					}
+				}
+			}
+
+			// If we still don't have an executable, try listing Contents/MacOS/
+			if targetPath == appPath {
+				macosDir := filepath.Join(appPath, "Contents", "MacOS")
+				if entries, err := os.ReadDir(macosDir); err == nil {
+					// Find the first non-resource-fork file (skip files starting with ._)
+					for _, entry := range entries {
+						if strings.HasPrefix(entry.Name(), "._") {
+							continue // Skip macOS resource fork files
+						}
+						executablePath := filepath.Join(macosDir, entry.Name())
+						if info, err := os.Stat(executablePath); err == nil && !info.IsDir() {
+							targetPath = executablePath
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return targetPath
+}
+
+func runSantactl(appPath string) ([]byte, error) {
+	// Santa reports on the bundle's main executable
+	targetPath := bundleExecutable(appPath)
+
+	// Verify the app/executable exists before running santactl
+	if _, err := os.Stat(targetPath); err != nil {
+		// If executable doesn't exist, try .app bundle path
+		if targetPath != appPath && strings.HasSuffix(appPath, ".app") {
+			if _, err := os.Stat(appPath); err == nil {
+				targetPath = appPath
+			}
+		}
+	}
+
+	// Verify target exists
+	if _, err := os.Stat(targetPath); err != nil {
+		return nil, fmt.Errorf("target path does not exist: %s", targetPath)
+	}
+
+	// Remove quarantine from target path if it's different from app path
+	if targetPath != appPath {
+		removeQuarantineAttributes(targetPath) // Ignore errors
+	}
+
+	// Wait to ensure app is fully installed and registered
+	time.Sleep(5 * time.Second)
+
+	// Try .app bundle path first (this is what works locally per user feedback)
+	// Retry up to 3 times with increasing delays if we get empty results
+	maxRetries := 3
+	var output []byte
+	var err error
+
+	// Determine which path to try first
+	tryAppPath := strings.HasSuffix(appPath, ".app")
+	pathsToTry := []string{}
+	if tryAppPath && targetPath != appPath {
+		pathsToTry = append(pathsToTry, appPath)
+	}
+	pathsToTry = append(pathsToTry, targetPath)
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		for _, pathToTry := range pathsToTry {
+			// On retries, try to register the app with codesign
+			if attempt > 1 && strings.HasSuffix(pathToTry, ".app") {
+				macosDir := filepath.Join(pathToTry, "Contents", "MacOS")
+				if entries, err := os.ReadDir(macosDir); err == nil {
+					for _, entry := range entries {
+						if !strings.HasPrefix(entry.Name(), "._") && !entry.IsDir() {
+							execPath := filepath.Join(macosDir, entry.Name())
+							newCommand(context.Background(), "codesign", "-dv", execPath).Run()
+							time.Sleep(1 * time.Second)
+							break
+						}
+					}
+				}
+			}
+
+			cmd := newCommand(context.Background(), "santactl", "fileinfo", "--json", pathToTry)
+			var stdout bytes.Buffer
+			var stderr bytes.Buffer
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			err = cmd.Run()
+			output = stdout.Bytes()
+
+			outputStr := strings.TrimSpace(string(output))
+
+			if len(outputStr) > 0 && outputStr != "[]" && outputStr != "null" {
+				var testArray []interface{}
+				if json.Unmarshal(output, &testArray) == nil && len(testArray) > 0 {
+					return output, nil
+				}
+			}
+
+			// If we got empty array, try the executable path directly as a fallback
+			if outputStr == "[]" && strings.HasSuffix(pathToTry, ".app") && attempt >= 2 {
+				// Try finding and using the executable path directly
+				macosDir := filepath.Join(pathToTry, "Contents", "MacOS")
+				if entries, err := os.ReadDir(macosDir); err == nil {
+					for _, entry := range entries {
+						if !strings.HasPrefix(entry.Name(), "._") && !entry.IsDir() {
+							execPath := filepath.Join(macosDir, entry.Name())
+							cmd2 := newCommand(context.Background(), "santactl", "fileinfo", "--json", execPath)
+							var stdout2 bytes.Buffer
+							var stderr2 bytes.Buffer
+							cmd2.Stdout = &stdout2
+							cmd2.Stderr = &stderr2
+							if err2 := cmd2.Run(); err2 == nil {
+								output2 := stdout2.Bytes()
+								outputStr2 := strings.TrimSpace(string(output2))
+								if outputStr2 != "[]" && len(outputStr2) > 0 {
+									var testArray2 []interface{}
+									if json.Unmarshal(output2, &testArray2) == nil && len(testArray2) > 0 {
+										return output2, nil
+									}
+								}
+							}
+							break
+						}
+					}
+				}
+			}
+
+			// If we got empty array, try text format as fallback
+			if outputStr == "[]" {
+				cmdText := newCommand(context.Background(), "santactl", "fileinfo", pathToTry)
+				var stdoutText bytes.Buffer
+				cmdText.Stdout = &stdoutText
+				if errText := cmdText.Run(); errText == nil {
+					textOutput := stdoutText.Bytes()
+					if len(textOutput) > 0 {
+						parsedData, parseErr := parseSantactlTextOutput(textOutput, pathToTry)
+						if parseErr == nil && (parsedData["SHA-256"] != "" || parsedData["CDHash"] != "") {
+							return convertTextToJSON(parsedData), nil
+						}
+					}
+				}
+
+				if attempt < maxRetries {
+					time.Sleep(5 * time.Second)
+					break // Break out of path loop to retry
+				}
+			} else if len(outputStr) > 0 {
+				// Got something but it's not valid, continue to next path
+				continue
+			}
+		}
+
+		// If we've exhausted all retries, break
+		if attempt >= maxRetries {
+			break
+		}
+	}
+
+	// Final fallback: if we got empty arrays from JSON, try text format one last time
+	if len(output) > 0 {
+		outputStr := strings.TrimSpace(string(output))
+		if outputStr == "[]" && strings.HasSuffix(appPath, ".app") {
+			cmdText := newCommand(context.Background(), "santactl", "fileinfo", appPath)
+			var stdoutText bytes.Buffer
+			cmdText.Stdout = &stdoutText
+			if errText := cmdText.Run(); errText == nil {
+				textOutput := stdoutText.Bytes()
+				if len(textOutput) > 0 {
+					parsedData, parseErr := parseSantactlTextOutput(textOutput, appPath)
+					if parseErr == nil && (parsedData["SHA-256"] != "" || parsedData["CDHash"] != "") {
+						return convertTextToJSON(parsedData), nil
+					}
+				}
+			}
+		}
+	}
+
+	if err != nil {
+		// Even if command fails, check if we got valid JSON output
+		// Sometimes santactl returns valid JSON but exits with non-zero code
+		if len(output) > 0 {
+			// Try to parse to see if it's valid JSON
+			var testArray []interface{}
+			if json.Unmarshal(output, &testArray) == nil && len(testArray) > 0 {
+				return output, nil
+			}
+		}
+		outputStr := strings.TrimSpace(string(output))
+		return nil, fmt.Errorf("santactl failed after %d attempts: %w (output: %s)",
+			maxRetries, err, outputStr[:min(200, len(outputStr))])
+	}
+
+	return output, nil
+}
+
+// parseSantactlTextOutput parses text output from santactl (without --json flag)
+// Format example:
+//
+//	SHA-256                : eadb726f24b005cb2a5d1a6271ea41288bd6af7379ed3eee0d7921140652d55a
+//	Team ID                : JP58VMK957
+//	Signing ID             : JP58VMK957:com.kapeli.dashdoc
+//	CDHash                 : 026e1e6b906106e60c668c66903386748432cea3
+func parseSantactlTextOutput(output []byte, path string) (map[string]string, error) {
+	result := make(map[string]string)
+	text := string(output)
+	lines := strings.Split(text, "\n")
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Look for key-value pairs with colon separator
+		// Format: "Field Name            : value"
+		if idx := strings.Index(line, ":"); idx > 0 {
+			key := strings.TrimSpace(line[:idx])
+			value := strings.TrimSpace(line[idx+1:])
+
+			if value == "" {
+				continue
+			}
+
+			// Normalize key names (case-insensitive matching)
+			keyLower := strings.ToLower(key)
+			if keyLower == "sha-256" || (strings.Contains(keyLower, "sha") && strings.Contains(keyLower, "256")) {
+				result["SHA-256"] = value
+			} else if keyLower == "cdhash" || strings.Contains(keyLower, "cd hash") {
+				result["CDHash"] = value
+			} else if keyLower == "signing id" || keyLower == "signingid" {
+				result["Signing ID"] = value
+			} else if keyLower == "team id" || keyLower == "teamid" {
+				result["Team ID"] = value
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// convertTextToJSON converts parsed text data to JSON format expected by parseSantactlOutput
+func convertTextToJSON(data map[string]string) []byte {
+	// Create a JSON array with one object, matching santactl's JSON output format
+	jsonObj := map[string]interface{}{}
+
+	if sha256, ok := data["SHA-256"]; ok && sha256 != "" {
+		jsonObj["SHA-256"] = sha256
+	}
+	if cdhash, ok := data["CDHash"]; ok && cdhash != "" {
+		jsonObj["CDHash"] = cdhash
+	}
+	if signingID, ok := data["Signing ID"]; ok && signingID != "" {
+		jsonObj["Signing ID"] = signingID
+	}
+	if teamID, ok := data["Team ID"]; ok && teamID != "" {
+		jsonObj["Team ID"] = teamID
+	}
+
+	jsonArray := []map[string]interface{}{jsonObj}
+	jsonBytes, _ := json.Marshal(jsonArray)
+	return jsonBytes
+}
+
+func parseSantactlOutput(output []byte, app datafile.AppVersion) (datafile.AppSecurityInfo, error) {
+	// Check if output is empty
+	outputStr := strings.TrimSpace(string(output))
+	if outputStr == "" || outputStr == "[]" || outputStr == "null" {
+		return datafile.AppSecurityInfo{}, fmt.Errorf("santactl returned empty output (app may not be signed or may be unsigned)")
+	}
+
+	// santactl returns an array of file info objects
+	var santactlArray []map[string]interface{}
+	if err := json.Unmarshal(output, &santactlArray); err != nil {
+		// Try to provide more context in error message
+		outputPreview := outputStr
+		if len(outputPreview) > 500 {
+			outputPreview = outputPreview[:500] + "..."
+		}
+		return datafile.AppSecurityInfo{}, fmt.Errorf("failed to parse santactl JSON: %w (output preview: %s)", err, outputPreview)
+	}
+
+	if len(santactlArray) == 0 {
+		return datafile.AppSecurityInfo{}, fmt.Errorf("santactl returned empty array (app may not be signed or may be unsigned)")
+	}
+
+	// Use the first entry (main executable)
+	santactlData := santactlArray[0]
+
+	// Check if the entry has actual signing data (ignore "Rule" field which is just a warning)
+	// Even if daemon can't communicate, santactl can still return signing info
+	hasSigningData := false
+	if _, ok := santactlData["SHA-256"].(string); ok {
+		hasSigningData = true
+	}
+	if _, ok := santactlData["CDHash"].(string); ok {
+		hasSigningData = true
+	}
+	if _, ok := santactlData["Signing ID"].(string); ok {
+		hasSigningData = true
+	}
+	if _, ok := santactlData["Team ID"].(string); ok {
+		hasSigningData = true
+	}
+
+	// If we have a "Rule" field but no signing data, it's an error
+	if rule, hasRule := santactlData["Rule"].(string); hasRule && !hasSigningData {
+		return datafile.AppSecurityInfo{}, fmt.Errorf("santactl returned error: %s (app may not be signed or may be unsigned)", rule)
+	}
+
+	securityInfo := datafile.AppSecurityInfo{
+		Slug:        app.Slug,
+		Name:        app.Name,
+		Version:     app.Version,
+		LastUpdated: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	// Extract SHA-256 (note: santactl uses "SHA-256" with hyphen)
+	if sha256, ok := santactlData["SHA-256"].(string); ok {
+		securityInfo.Sha256 = sha256
+	}
+
+	// Extract CDHash
+	if cdhash, ok := santactlData["CDHash"].(string); ok {
+		securityInfo.Cdhash = cdhash
+	}
+
+	// Extract Signing ID and Team ID
+	// These might be in different fields depending on santactl version
+	if signingID, ok := santactlData["Signing ID"].(string); ok {
+		securityInfo.SigningID = signingID
+	}
+	if teamID, ok := santactlData["Team ID"].(string); ok {
+		securityInfo.TeamID = teamID
+	}
+
+	// Also check for nested signing info
+	if signingInfo, ok := santactlData["SigningInfo"].(map[string]interface{}); ok {
+		if signingID, ok := signingInfo["SigningID"].(string); ok {
+			securityInfo.SigningID = signingID
+		}
+		if teamID, ok := signingInfo["TeamID"].(string); ok {
+			securityInfo.TeamID = teamID
+		}
+	}
+
+	return securityInfo, nil
+}
+
+func uninstallApp(app datafile.AppVersion) error {
+	fmt.Printf("  🗑️  Uninstalling app...\n")
+
+	// Special handling for Teleport Suite - remove both apps
+	if app.Name == "Teleport Suite" {
+		tshPath := filepath.Join(applicationsDir, "tsh.app")
+		tctlPath := filepath.Join(applicationsDir, "tctl.app")
+
+		// Try regular removal first
+		os.RemoveAll(tshPath)
+		os.RemoveAll(tctlPath)
+
+		// If regular removal fails, try with sudo
+		if _, err := os.Stat(tshPath); err == nil {
+			fmt.Printf("  🔐 Using sudo to remove protected files...\n")
+			newCommand(context.Background(), "sudo", "rm", "-rf", tshPath).Run()
+		}
+		if _, err := os.Stat(tctlPath); err == nil {
+			fmt.Printf("  🔐 Using sudo to remove protected files...\n")
+			newCommand(context.Background(), "sudo", "rm", "-rf", tctlPath).Run()
+		}
+
+		return nil
+	}
+
+	// Find the installed app (use same logic as installation)
+	appPath, err := findInstalledApp(app)
+	if err != nil {
+		// App not found, consider it already uninstalled
+		return nil
+	}
+
+	// Try regular removal first
+	if err := os.RemoveAll(appPath); err == nil {
+		return nil
+	}
+
+	// If regular removal fails (permission denied), try with sudo
+	fmt.Printf("  🔐 Using sudo to remove protected files...\n")
+	cmd := newCommand(context.Background(), "sudo", "rm", "-rf", appPath)
+	if err := cmd.Run(); err != nil {
+		// Even if sudo fails, try to remove what we can
+		// Some apps have files that can't be deleted, which is okay
+		fmt.Printf("  ⚠️  Some files may remain (this is usually okay)\n")
+		return nil // Don't fail the whole process if uninstall has issues
+	}
+
+	return nil
+}
+
+// CommandRunner runs an external command (hdiutil, santactl, codesign,
+// ditto, installer, xattr, lipo, file) and reports how it exited
+type CommandRunner interface {
+	Run(cmd *command) error
+}
+
+// command describes one invocation, mirroring the parts of exec.Cmd the
+// collector relies on
+type command struct {
+	ctx    context.Context
+	Name   string
+	Args   []string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func newCommand(ctx context.Context, name string, args ...string) *command {
+	return &command{ctx: ctx, Name: name, Args: args}
+}
+
+// Run executes the command through runner
+func (c *command) Run() error {
+	return runner.Run(c)
+}
+
+// Output runs the command and returns its standard output
+func (c *command) Output() ([]byte, error) {
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	err := c.Run()
+	return stdout.Bytes(), err
+}
+
+// execRunner runs commands with os/exec
+type execRunner struct{}
+
+func (execRunner) Run(c *command) error {
+	cmd := exec.CommandContext(c.ctx, c.Name, c.Args...)
+	cmd.Stdin = c.Stdin
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	return cmd.Run()
+}
+
+func trackDMG(mountPoint string) {
+	mountsMu.Lock()
+	defer mountsMu.Unlock()
+	mountedDMGs[mountPoint] = true
+}
+
+func untrackDMG(mountPoint string) {
+	mountsMu.Lock()
+	defer mountsMu.Unlock()
+	delete(mountedDMGs, mountPoint)
+}
+
+// detachAllDMGs force-detaches every DMG still attached; used on interruption
+func detachAllDMGs() {
+	mountsMu.Lock()
+	defer mountsMu.Unlock()
+	for mountPoint := range mountedDMGs {
+		fmt.Printf("  💿 Detaching %s\n", mountPoint)
+		newCommand(context.Background(), "hdiutil", "detach", mountPoint, "-quiet", "-force").Run()
+		delete(mountedDMGs, mountPoint)
+	}
+}
+
+func cleanupTempFiles() {
+	// Clean up any remaining temp files
+	os.RemoveAll(tempDir)
+	os.MkdirAll(tempDir, 0755)
+}
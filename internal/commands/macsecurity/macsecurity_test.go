@@ -0,0 +1,556 @@
+package macsecurity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+)
+
+// fakeResult is the canned outcome of one command line
+type fakeResult struct {
+	stdout string
+	stderr string
+	err    error
+	// effect runs before the command "exits", e.g. to create an output file
+	effect func(args []string) error
+}
+
+// fakeRunner answers commands from a table keyed by the full command line
+// and records every call; unknown commands fail
+type fakeRunner struct {
+	results map[string]fakeResult
+	calls   []string
+}
+
+func (f *fakeRunner) Run(c *command) error {
+	line := strings.Join(append([]string{c.Name}, c.Args...), " ")
+	f.calls = append(f.calls, line)
+
+	result, ok := f.results[line]
+	if !ok {
+		return fmt.Errorf("unexpected command: %s", line)
+	}
+	if result.effect != nil {
+		if err := result.effect(c.Args); err != nil {
+			return err
+		}
+	}
+	if c.Stdout != nil {
+		io.WriteString(c.Stdout, result.stdout)
+	}
+	if c.Stderr != nil {
+		io.WriteString(c.Stderr, result.stderr)
+	}
+	return result.err
+}
+
+// useFakeRunner installs a fakeRunner for the duration of the test
+func useFakeRunner(t *testing.T, results map[string]fakeResult) *fakeRunner {
+	t.Helper()
+
+	fake := &fakeRunner{results: results}
+	previous := runner
+	runner = fake
+	t.Cleanup(func() { runner = previous })
+	return fake
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestExecutableSlicesThinBinary(t *testing.T) {
+	executable := filepath.Join(t.TempDir(), "Tool")
+	if err := os.WriteFile(executable, []byte("arm64 only"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	useFakeRunner(t, map[string]fakeResult{
+		"lipo -archs " + executable: {stdout: "arm64\n"},
+	})
+
+	slices, err := executableSlices(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []datafile.ArchSlice{{Arch: "arm64", Sha256: sha256Hex("arm64 only")}}
+	if !reflect.DeepEqual(slices, want) {
+		t.Errorf("slices = %+v, want %+v", slices, want)
+	}
+}
+
+func TestExecutableSlicesUniversalBinary(t *testing.T) {
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	executable := filepath.Join(t.TempDir(), "Tool")
+	// lipo -thin <arch> -output <path>: write a fake slice named after the arch
+	thin := func(args []string) error {
+		return os.WriteFile(args[len(args)-1], []byte("slice "+args[2]), 0644)
+	}
+	useFakeRunner(t, map[string]fakeResult{
+		"lipo -archs " + executable: {stdout: "x86_64 arm64\n"},
+		"lipo " + executable + " -thin x86_64 -output " + filepath.Join(tempDir, "slice-x86_64"): {effect: thin},
+		"lipo " + executable + " -thin arm64 -output " + filepath.Join(tempDir, "slice-arm64"):   {effect: thin},
+	})
+
+	slices, err := executableSlices(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []datafile.ArchSlice{
+		{Arch: "x86_64", Sha256: sha256Hex("slice x86_64")},
+		{Arch: "arm64", Sha256: sha256Hex("slice arm64")},
+	}
+	if !reflect.DeepEqual(slices, want) {
+		t.Errorf("slices = %+v, want %+v", slices, want)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "slice-arm64")); !os.IsNotExist(err) {
+		t.Error("thin slice was not removed after hashing")
+	}
+}
+
+func TestExecutableSlicesLipoFailure(t *testing.T) {
+	useFakeRunner(t, map[string]fakeResult{
+		"lipo -archs /missing": {err: errors.New("exit status 1")},
+	})
+
+	if _, err := executableSlices("/missing"); err == nil || !strings.Contains(err.Error(), "lipo -archs") {
+		t.Errorf("err = %v, want a lipo -archs error", err)
+	}
+}
+
+func TestDetectActualFileType(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"/tmp/x: xar archive compressed TOC: 4537, SHA-1 checksum", ".pkg"},
+		{"/tmp/x: zlib compressed data", ".zip"},
+		{"/tmp/x: Apple Disk Image (UDIF)", ".dmg"},
+		{"/tmp/x: Zip archive data, at least v2.0 to extract", ".zip"},
+		{"/tmp/x: Mach-O 64-bit executable arm64", ""},
+	}
+	for _, tt := range tests {
+		useFakeRunner(t, map[string]fakeResult{"file /tmp/x": {stdout: tt.output}})
+		got, err := detectActualFileType("/tmp/x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("detectActualFileType(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveQuarantineFallsBackToNonRecursive(t *testing.T) {
+	fake := useFakeRunner(t, map[string]fakeResult{
+		"xattr -dr com.apple.quarantine /Applications/Foo.app": {err: errors.New("exit status 1")},
+		"xattr -d com.apple.quarantine /Applications/Foo.app":  {},
+	})
+
+	if err := removeQuarantineAttributes("/Applications/Foo.app"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"xattr -dr com.apple.quarantine /Applications/Foo.app",
+		"xattr -d com.apple.quarantine /Applications/Foo.app",
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("calls = %q, want %q", fake.calls, want)
+	}
+}
+
+func TestRemoveQuarantineIgnoresMissingAttributeOnExecutable(t *testing.T) {
+	useFakeRunner(t, map[string]fakeResult{
+		"xattr -d com.apple.quarantine /usr/local/bin/foo": {err: errors.New("No such xattr")},
+	})
+
+	if err := removeQuarantineAttributes("/usr/local/bin/foo"); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
+
+func TestInstallFromPKGReportsInstallerOutput(t *testing.T) {
+	pkg := filepath.Join(t.TempDir(), "Foo.pkg")
+	if err := os.WriteFile(pkg, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	useFakeRunner(t, map[string]fakeResult{
+		"sudo installer -pkg " + pkg + " -target / -allowUntrusted -verbose": {
+			stderr: "installer: Error - the package is damaged",
+			err:    errors.New("exit status 1"),
+		},
+	})
+
+	_, err := installFromPKG(pkg, datafile.AppVersion{Slug: "foo/darwin", Name: "Foo"})
+	if err == nil || !strings.Contains(err.Error(), "stderr: installer: Error - the package is damaged") {
+		t.Errorf("err = %v, want it to include the installer's stderr", err)
+	}
+}
+
+func TestBundleComponents(t *testing.T) {
+	app := filepath.Join(t.TempDir(), "Foo.app")
+	frameworks := filepath.Join(app, "Contents", "Frameworks")
+	current := filepath.Join(frameworks, "Sparkle.framework", "Versions", "B")
+	if err := os.MkdirAll(current, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(current, "Sparkle"), []byte("sparkle"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("Versions/B/Sparkle", filepath.Join(frameworks, "Sparkle.framework", "Sparkle")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(frameworks, "libfoo.dylib"), []byte("libfoo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(frameworks, "README.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	useFakeRunner(t, map[string]fakeResult{
+		"codesign -dv " + filepath.Join(frameworks, "Sparkle.framework"): {
+			stderr: "Executable=" + filepath.Join(current, "Sparkle") + "\nIdentifier=org.sparkle-project.Sparkle\nTeamIdentifier=BJ4HAAB9B3\n",
+		},
+		"codesign -dv " + filepath.Join(frameworks, "libfoo.dylib"): {
+			stderr: "Identifier=libfoo\nTeamIdentifier=not set\n",
+		},
+	})
+
+	components, err := bundleComponents(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []datafile.Component{
+		{Path: "Contents/Frameworks/Sparkle.framework", Sha256: sha256Hex("sparkle"), SigningID: "org.sparkle-project.Sparkle", TeamID: "BJ4HAAB9B3"},
+		{Path: "Contents/Frameworks/libfoo.dylib", Sha256: sha256Hex("libfoo"), SigningID: "libfoo"},
+	}
+	if !reflect.DeepEqual(components, want) {
+		t.Errorf("components = %+v, want %+v", components, want)
+	}
+}
+
+func TestDetectComponentSignerChanges(t *testing.T) {
+	previous := datafile.AppSecurityInfo{Version: "1.0", Components: []datafile.Component{
+		{Path: "Contents/Frameworks/Sparkle.framework", Sha256: "a", SigningID: "org.sparkle-project.Sparkle", TeamID: "BJ4HAAB9B3"},
+		{Path: "Contents/Frameworks/libfoo.dylib", Sha256: "b", SigningID: "libfoo"},
+	}}
+	current := datafile.AppSecurityInfo{Version: "1.1", Components: []datafile.Component{
+		{Path: "Contents/Frameworks/Sparkle.framework", Sha256: "c", SigningID: "org.sparkle-project.Sparkle", TeamID: "BJ4HAAB9B3"},
+		{Path: "Contents/Frameworks/libfoo.dylib", Sha256: "d", SigningID: "libfoo", TeamID: "ZZZZZZZZZZ"},
+		{Path: "Contents/Frameworks/libnew.dylib", Sha256: "e"},
+	}}
+
+	anomalies := detectComponentSignerChanges(previous, current)
+	if len(anomalies) != 1 || anomalies[0].Type != anomalyComponentSigner || !strings.Contains(anomalies[0].Detail, "libfoo.dylib") {
+		t.Errorf("anomalies = %+v, want one signer change for libfoo.dylib", anomalies)
+	}
+}
+
+func TestBundleIdentifier(t *testing.T) {
+	useFakeRunner(t, map[string]fakeResult{
+		"plutil -extract CFBundleIdentifier raw -o - /Applications/Foo.app/Contents/Info.plist": {stdout: "com.example.foo\n"},
+	})
+
+	got, err := bundleIdentifier("/Applications/Foo.app")
+	if err != nil || got != "com.example.foo" {
+		t.Errorf("bundleIdentifier() = %q, %v; want com.example.foo", got, err)
+	}
+	if _, err := bundleIdentifier("/Applications/Bar.app"); err == nil {
+		t.Error("bundleIdentifier() succeeded for an app without Info.plist")
+	}
+}
+
+func TestPrivacyUsageDescriptions(t *testing.T) {
+	useFakeRunner(t, map[string]fakeResult{
+		"plutil -convert xml1 -o - /Applications/Foo.app/Contents/Info.plist": {stdout: `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>com.example.foo</string>
+	<key>NSMicrophoneUsageDescription</key>
+	<string>Foo needs the microphone for calls &amp; meetings.</string>
+	<key>NSServices</key>
+	<array>
+		<dict>
+			<key>NSCameraUsageDescription</key>
+			<string>Not a top-level key</string>
+		</dict>
+	</array>
+	<key>NSCameraUsageDescription</key>
+	<string> Foo uses the camera for video. </string>
+	<key>NSLocationUsageDescription</key>
+	<string>Foo shows nearby rooms.</string>
+</dict>
+</plist>
+`},
+	})
+
+	got, err := privacyUsageDescriptions("/Applications/Foo.app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []datafile.PrivacyUsage{
+		{Key: "NSCameraUsageDescription", Service: "Camera", Description: "Foo uses the camera for video."},
+		{Key: "NSLocationUsageDescription", Description: "Foo shows nearby rooms."},
+		{Key: "NSMicrophoneUsageDescription", Service: "Microphone", Description: "Foo needs the microphone for calls & meetings."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("privacyUsageDescriptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAddedPersistence(t *testing.T) {
+	daemons, agents := t.TempDir(), t.TempDir()
+	saved := launchDirs
+	launchDirs = []struct{ dir, kind string }{{daemons, persistenceLaunchDaemon}, {agents, persistenceLaunchAgent}}
+	t.Cleanup(func() { launchDirs = saved })
+	if err := os.WriteFile(filepath.Join(daemons, "com.example.existing.plist"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const listLoginItems = `osascript -e tell application "System Events" to get the path of every login item`
+	fake := useFakeRunner(t, map[string]fakeResult{
+		listLoginItems: {stdout: "/Applications/Existing.app\n"},
+	})
+	before := snapshotPersistence()
+
+	daemon := filepath.Join(daemons, "com.example.helper.plist")
+	agent := filepath.Join(agents, "com.example.agent.plist")
+	for _, path := range []string{daemon, agent} {
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake.results[listLoginItems] = fakeResult{stdout: "/Applications/Existing.app, /Applications/Foo.app\n"}
+	fake.results["plutil -extract Label raw -o - "+daemon] = fakeResult{stdout: "com.example.helper\n"}
+	fake.results["plutil -extract Program raw -o - "+daemon] = fakeResult{stdout: "/Library/PrivilegedHelperTools/com.example.helper\n"}
+	fake.results["plutil -extract Label raw -o - "+agent] = fakeResult{stdout: "com.example.agent\n"}
+	fake.results["plutil -extract ProgramArguments.0 raw -o - "+agent] = fakeResult{stdout: "/Applications/Foo.app/Contents/MacOS/agent\n"}
+
+	items, err := addedPersistence(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []datafile.PersistenceItem{
+		{Kind: persistenceLaunchDaemon, Path: daemon, Label: "com.example.helper", Program: "/Library/PrivilegedHelperTools/com.example.helper"},
+		{Kind: persistenceLaunchAgent, Path: agent, Label: "com.example.agent", Program: "/Applications/Foo.app/Contents/MacOS/agent"},
+		{Kind: persistenceLoginItem, Path: "/Applications/Foo.app"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("addedPersistence() = %+v, want %+v", items, want)
+	}
+}
+
+func TestReadSignature(t *testing.T) {
+	useFakeRunner(t, map[string]fakeResult{
+		"codesign -dv --verbose=4 /Applications/Foo.app": {
+			stderr: "Identifier=com.example.foo\n" +
+				"Format=app bundle with Mach-O universal (x86_64 arm64)\n" +
+				"CodeDirectory v=20500 size=1234 flags=0x10000(runtime) hashes=27+7 location=embedded\n" +
+				"Authority=Developer ID Application: Example Inc. (ABCDE12345)\n" +
+				"Authority=Developer ID Certification Authority\n" +
+				"Authority=Apple Root CA\n" +
+				"Timestamp=Mar 4, 2025 at 9:15:30\u202fPM\n",
+		},
+		"codesign -d --entitlements - --xml /Applications/Foo.app": {
+			stdout: "<?xml version=\"1.0\"?><plist version=\"1.0\"><dict>\n\t<key>com.apple.security.app-sandbox</key>\n\t<true/>\n</dict></plist>",
+		},
+		"codesign -dv --verbose=4 /Applications/Bar.app": {
+			stderr: "CodeDirectory v=20400 size=1234 flags=0x0(none) hashes=27+7 location=embedded\nSigned Time=5 Mar 2025 at 08:00:00\n",
+		},
+		"codesign -d --entitlements - --xml /Applications/Bar.app": {err: errors.New("code object is not signed at all")},
+	})
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+
+	foo := readSignature("/Applications/Foo.app")
+	if foo.Sandboxed == nil || !*foo.Sandboxed || foo.HardenedRuntime == nil || !*foo.HardenedRuntime {
+		t.Errorf("Foo.app: sandboxed = %v, hardened = %v, want both true", foo.Sandboxed, foo.HardenedRuntime)
+	}
+	if foo.Format != "app bundle with Mach-O universal (x86_64 arm64)" {
+		t.Errorf("Foo.app: format = %q", foo.Format)
+	}
+	if foo.LeafCertificate != "Developer ID Application: Example Inc. (ABCDE12345)" {
+		t.Errorf("Foo.app: leaf certificate = %q", foo.LeafCertificate)
+	}
+	if foo.SigningTime != "2025-03-04T21:15:30Z" {
+		t.Errorf("Foo.app: signing time = %q, want 2025-03-04T21:15:30Z", foo.SigningTime)
+	}
+
+	bar := readSignature("/Applications/Bar.app")
+	if bar.Sandboxed != nil || bar.HardenedRuntime == nil || *bar.HardenedRuntime {
+		t.Errorf("Bar.app: sandboxed = %v, hardened = %v, want unknown and false", bar.Sandboxed, bar.HardenedRuntime)
+	}
+	if bar.SigningTime != "2025-03-05T08:00:00Z" || bar.LeafCertificate != "" {
+		t.Errorf("Bar.app: signing time = %q, leaf certificate = %q", bar.SigningTime, bar.LeafCertificate)
+	}
+}
+
+func TestNotarizationDate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"2/2/aaaa"`) {
+			fmt.Fprint(w, `{"records":[{"recordName":"2/2/aaaa","recordType":"DeveloperIDTicket","created":{"timestamp":1736188930000}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records":[{"recordName":"2/2/bbbb","reason":"Record not found","serverErrorCode":"NOT_FOUND"}]}`)
+	}))
+	defer server.Close()
+	defer func(url string) { notaryTicketURL = url }(notaryTicketURL)
+	notaryTicketURL = server.URL
+
+	got, err := notarizationDate("AAAA")
+	if err != nil || got != "2025-01-06T18:42:10Z" {
+		t.Errorf("notarizationDate(AAAA) = %q, %v; want 2025-01-06T18:42:10Z", got, err)
+	}
+
+	got, err = notarizationDate("bbbb")
+	if err != nil || got != "" {
+		t.Errorf("notarizationDate(bbbb) = %q, %v; want not notarized", got, err)
+	}
+}
+
+func TestFindDMGAppBundleSkipsSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(outside, "Foo.app", "Contents"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mountPoint := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(mountPoint, "Applications")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "Foo.app"), filepath.Join(mountPoint, "Foo.app")); err != nil {
+		t.Fatal(err)
+	}
+	app := datafile.AppVersion{Name: "Foo"}
+
+	if got := findDMGAppBundle(mountPoint, app); got != "" {
+		t.Fatalf("findDMGAppBundle() = %q through a symlink, want none", got)
+	}
+
+	bundle := filepath.Join(mountPoint, "Payload", "Foo.app")
+	if err := os.MkdirAll(filepath.Join(bundle, "Contents"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := findDMGAppBundle(mountPoint, app); got != bundle {
+		t.Errorf("findDMGAppBundle() = %q, want %q", got, bundle)
+	}
+	if !resolvesWithin(mountPoint, bundle) || resolvesWithin(mountPoint, filepath.Join(mountPoint, "Foo.app")) {
+		t.Error("resolvesWithin() doesn't resolve symlinks out of the mount point")
+	}
+}
+
+func TestTopLevelAppBundles(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		"Foo.app/Contents/Library/LoginItems/Foo Helper.app/Contents",
+		"Extras/Uninstall Foo.app/Contents",
+		"Bar.app/Contents",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundles := topLevelAppBundles(root)
+	want := []string{
+		filepath.Join(root, "Bar.app"),
+		filepath.Join(root, "Extras", "Uninstall Foo.app"),
+		filepath.Join(root, "Foo.app"),
+	}
+	if !reflect.DeepEqual(bundles, want) {
+		t.Fatalf("topLevelAppBundles() = %v, want %v", bundles, want)
+	}
+	if got := preferredAppBundle(bundles, datafile.AppVersion{Name: "Foo"}); got != want[2] {
+		t.Errorf("preferredAppBundle(Foo) = %q, want %q", got, want[2])
+	}
+	if got := preferredAppBundle(bundles, datafile.AppVersion{Name: "Baz"}); got != want[0] {
+		t.Errorf("preferredAppBundle(Baz) = %q, want the first bundle", got)
+	}
+}
+
+func TestRequiresRosetta(t *testing.T) {
+	tests := []struct {
+		slices []datafile.ArchSlice
+		want   string
+	}{
+		{nil, "unknown"},
+		{[]datafile.ArchSlice{{Arch: "x86_64"}}, "true"},
+		{[]datafile.ArchSlice{{Arch: "x86_64"}, {Arch: "arm64"}}, "false"},
+		{[]datafile.ArchSlice{{Arch: "arm64e"}}, "false"},
+	}
+	for _, tt := range tests {
+		got := "unknown"
+		if rosetta := requiresRosetta(tt.slices); rosetta != nil {
+			got = fmt.Sprint(*rosetta)
+		}
+		if got != tt.want {
+			t.Errorf("requiresRosetta(%v) = %s, want %s", tt.slices, got, tt.want)
+		}
+	}
+}
+
+func TestForRunnerArch(t *testing.T) {
+	app := datafile.AppVersion{
+		Version:      "1.0",
+		InstallerURL: "https://example.com/foo-x64.dmg",
+		Arch:         "x86_64",
+		Variants:     []datafile.PublishedVersion{{Version: "1.0", InstallerURL: "https://example.com/foo-arm64.dmg", Arch: "arm64"}},
+	}
+
+	got := forRunnerArch(app, "arm64")
+	if got.InstallerURL != "https://example.com/foo-arm64.dmg" || got.Arch != "arm64" {
+		t.Errorf("forRunnerArch(arm64) picked %s (%s)", got.InstallerURL, got.Arch)
+	}
+	want := []datafile.PublishedVersion{{Version: "1.0", InstallerURL: "https://example.com/foo-x64.dmg", Arch: "x86_64"}}
+	if !reflect.DeepEqual(got.Variants, want) {
+		t.Errorf("forRunnerArch(arm64) variants = %v, want %v", got.Variants, want)
+	}
+	if len(app.Variants) != 1 || app.Variants[0].Arch != "arm64" {
+		t.Errorf("forRunnerArch modified the original variants: %v", app.Variants)
+	}
+
+	if got := forRunnerArch(app, "x86_64"); got.InstallerURL != app.InstallerURL {
+		t.Errorf("forRunnerArch(x86_64) = %s, want the main installer", got.InstallerURL)
+	}
+	app.Arch = "universal"
+	if got := forRunnerArch(app, "arm64"); got.InstallerURL != app.InstallerURL {
+		t.Errorf("forRunnerArch kept %s for a universal installer, want the main installer", got.InstallerURL)
+	}
+}
+
+func TestMirrorURLs(t *testing.T) {
+	mirrors := map[string][]string{
+		"foo/darwin": {"https://mirror.example.com/foo/{version}/{filename}"},
+		"*":          {"https://cache.example.com/{filename}", "https://cdn.example.com/Foo-1.2.dmg"},
+	}
+
+	got := mirrorURLs(mirrors, "foo/darwin", "1.2", "https://cdn.example.com/Foo-1.2.dmg?download=1")
+	want := []string{
+		"https://mirror.example.com/foo/1.2/Foo-1.2.dmg",
+		"https://cache.example.com/Foo-1.2.dmg",
+		"https://cdn.example.com/Foo-1.2.dmg",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mirrorURLs() = %v, want %v", got, want)
+	}
+
+	got = mirrorURLs(mirrors, "bar/darwin", "3.0", "https://cache.example.com/Bar.dmg")
+	if want := []string{"https://cdn.example.com/Foo-1.2.dmg"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mirrorURLs() = %v, want %v with the published URL skipped", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+// Package mergesecurity implements "fleet-tracker merge-security", which
+// merges sharded security info results; merge_security.go runs it on its own.
+package mergesecurity
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const securityInfoJSON = "data/app_security_info.json"
+
+// fragment is an app_security_info.json written by one shard. Entries are
+// kept as raw JSON so fields this script doesn't know about survive the merge.
+type fragment struct {
+	LastUpdated string            `json:"lastUpdated"`
+	Apps        []json.RawMessage `json:"apps"`
+	Versions    []json.RawMessage `json:"versions,omitempty"`
+}
+
+// entryHeader is the part of an entry the merge is keyed and ordered by
+type entryHeader struct {
+	Slug        string `json:"slug"`
+	Version     string `json:"version"`
+	LastUpdated string `json:"lastUpdated"`
+}
+
+// mergedEntry is the entry kept for a key so far, and the fragment it came from
+type mergedEntry struct {
+	header  entryHeader
+	updated time.Time
+	raw     json.RawMessage
+	source  string
+}
+
+// securityInfoFile is written with the same field order as the collectors
+type securityInfoFile struct {
+	SchemaVersion    int               `json:"schemaVersion"`
+	GeneratorVersion string            `json:"generatorVersion,omitempty"`
+	LastUpdated      string            `json:"lastUpdated"`
+	Apps             []json.RawMessage `json:"apps"`
+	Versions         []json.RawMessage `json:"versions,omitempty"`
+}
+
+// mergeSecurity combines the fragments at paths into output. Current entries
+// are keyed by slug and older versions by slug and version; when fragments
+// disagree, the entry with the newest lastUpdated wins.
+func mergeSecurity(paths []string, output string) error {
+	fmt.Printf("🧩 Merging %d security info fragments...\n", len(paths))
+
+	apps := make(map[string]*mergedEntry)
+	versions := make(map[string]*mergedEntry)
+	var lastUpdated time.Time
+	conflicts := 0
+
+	for _, path := range paths {
+		frag, err := loadFragment(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		if t, err := time.Parse(time.RFC3339, frag.LastUpdated); err == nil && t.After(lastUpdated) {
+			lastUpdated = t
+		}
+
+		for _, list := range []struct {
+			entries  []json.RawMessage
+			merged   map[string]*mergedEntry
+			versions bool
+		}{{frag.Apps, apps, false}, {frag.Versions, versions, true}} {
+			seen := make(map[string]bool)
+			for i, raw := range list.entries {
+				entry, err := parseEntry(raw)
+				if err != nil {
+					return fmt.Errorf("%s: entry %d: %w", path, i, err)
+				}
+				key := entry.header.Slug
+				if list.versions {
+					key += "@" + entry.header.Version
+				}
+				if seen[key] {
+					return fmt.Errorf("%s: %s is listed twice", path, key)
+				}
+				seen[key] = true
+				entry.source = path
+
+				existing, ok := list.merged[key]
+				switch {
+				case !ok:
+					list.merged[key] = entry
+				case sameEntry(existing.raw, entry.raw):
+					// The same entry, e.g. one no shard touched
+				case entry.updated.After(existing.updated):
+					list.merged[key] = entry
+					conflicts++
+				case entry.updated.Equal(existing.updated):
+					fmt.Printf("  ⚠️  %s differs in %s and %s with the same lastUpdated, keeping %s\n", key, existing.source, path, existing.source)
+					conflicts++
+				default:
+					conflicts++
+				}
+			}
+		}
+		fmt.Printf("  📄 %s: %d apps, %d older versions\n", path, len(frag.Apps), len(frag.Versions))
+	}
+
+	merged := securityInfoFile{
+		SchemaVersion:    schema.Current(schema.SecurityInfo),
+		GeneratorVersion: buildinfo.GeneratorVersion(),
+		LastUpdated:      lastUpdated.UTC().Format(time.RFC3339),
+		Apps:             sortedEntries(apps),
+		Versions:         sortedEntries(versions),
+	}
+	if merged.Apps == nil {
+		merged.Apps = []json.RawMessage{}
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal merged security info: %w", err)
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Printf("✅ Wrote %s: %d apps, %d older versions (%d conflicts resolved by lastUpdated)\n", output, len(merged.Apps), len(merged.Versions), conflicts)
+	return nil
+}
+
+// parseEntry validates an entry and reads the fields the merge needs
+func parseEntry(raw json.RawMessage) (*mergedEntry, error) {
+	var header entryHeader
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, err
+	}
+	if header.Slug == "" {
+		return nil, fmt.Errorf("missing slug")
+	}
+	if header.Version == "" {
+		return nil, fmt.Errorf("%s: missing version", header.Slug)
+	}
+	updated, err := time.Parse(time.RFC3339, header.LastUpdated)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid lastUpdated %q", header.Slug, header.LastUpdated)
+	}
+
+	// Compact so identical entries compare equal however they were indented
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return nil, err
+	}
+	return &mergedEntry{header: header, updated: updated, raw: compact.Bytes()}, nil
+}
+
+// sameEntry reports whether two entries hold the same values, however their
+// strings happen to be escaped
+func sameEntry(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// sortedEntries orders entries by slug, then version, as the collectors do
+func sortedEntries(merged map[string]*mergedEntry) []json.RawMessage {
+	entries := make([]*mergedEntry, 0, len(merged))
+	for _, entry := range merged {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].header.Slug != entries[j].header.Slug {
+			return entries[i].header.Slug < entries[j].header.Slug
+		}
+		return entries[i].header.Version < entries[j].header.Version
+	})
+
+	var list []json.RawMessage
+	for _, entry := range entries {
+		list = append(list, entry.raw)
+	}
+	return list
+}
+
+func loadFragment(path string) (*fragment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var frag fragment
+	if err := json.Unmarshal(data, &frag); err != nil {
+		return nil, err
+	}
+
+	return &frag, nil
+}
+
+// Main runs fleet-tracker merge-security with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("merge-security", flag.ExitOnError)
+	output := fs.String("output", securityInfoJSON, "file to write the merged security info to")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: fleet-tracker merge-security [--output path] <fragment.json>...")
+		os.Exit(2)
+	}
+
+	if err := mergeSecurity(fs.Args(), *output); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,328 @@
+// Package munki implements "fleet-tracker munki", which generates Munki
+// pkginfo files; generate_munki.go runs it on its own.
+package munki
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionsJSON     = "data/app_versions.json"
+	securityInfoJSON = "data/app_security_info.json"
+	appsMetadataJSON = "data/apps_metadata.json"
+	outputMunkiDir   = "munki"
+	munkiCatalog     = "testing"
+)
+
+// plistDict is a plist dictionary; keys are written sorted, as makepkginfo does
+type plistDict map[string]any
+
+func generateMunki() error {
+	fmt.Println("📦 Generating Munki pkginfo files...")
+
+	versions, err := loadVersions()
+	if err != nil {
+		return fmt.Errorf("failed to load app versions: %w", err)
+	}
+	security, err := loadSecurityInfo()
+	if err != nil {
+		return fmt.Errorf("failed to load security info: %w", err)
+	}
+	metadata, err := loadMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to load app metadata: %w", err)
+	}
+
+	// Only security info for the current version describes its installer
+	entries := make(map[string]datafile.AppSecurityInfo)
+	for _, entry := range security.Apps {
+		entries[entry.Slug+"@"+entry.Version] = entry
+	}
+
+	if err := os.MkdirAll(outputMunkiDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Remove the previous files so apps that left the catalog don't linger
+	stale, err := filepath.Glob(filepath.Join(outputMunkiDir, "*-darwin.plist"))
+	if err != nil {
+		return fmt.Errorf("failed to list existing files: %w", err)
+	}
+	for _, file := range stale {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+
+	written, verified, skipped := 0, 0, 0
+	for _, app := range versions.Apps {
+		if app.Platform != "darwin" {
+			continue
+		}
+		entry, ok := entries[app.Slug+"@"+app.Version]
+		if !ok || entry.BundlePath == "" || entry.BundleID == "" {
+			skipped++
+			continue
+		}
+		installerURL := app.InstallerURL
+		if entry.InstallerURL != "" {
+			installerURL = entry.InstallerURL
+		}
+		ext := installerType(installerURL)
+		if ext != "dmg" && ext != "pkg" {
+			fmt.Printf("  ⚠️  Skipping %s: Munki can't install %s\n", app.Slug, describeInstaller(ext))
+			skipped++
+			continue
+		}
+
+		file := filepath.Join(outputMunkiDir, strings.ReplaceAll(app.Slug, "/", "-")+".plist")
+		pkginfo := pkginfoFor(app, entry, metadata[app.Slug], installerURL, ext)
+		if err := os.WriteFile(file, []byte(renderPlist(pkginfo)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+
+		written++
+		if entry.InstallerSha256 != "" {
+			verified++
+		}
+	}
+
+	fmt.Printf("✅ Generated: %d pkginfo files in %s/\n", written, outputMunkiDir)
+	fmt.Printf("   🔒 %d with a verified installer hash\n", verified)
+	fmt.Printf("   ⏭️  %d Mac apps without a recorded bundle or a Munki-compatible installer\n", skipped)
+
+	return nil
+}
+
+// pkginfoFor builds the pkginfo of app's current version. Clients download
+// the installer straight from the vendor through PackageCompleteURL, and the
+// installs array lets Munki detect the app however it was installed.
+func pkginfoFor(app datafile.AppVersion, entry datafile.AppSecurityInfo, meta datafile.AppMetadata, installerURL, ext string) plistDict {
+	bundleVersion := entry.BundleVersion
+	if bundleVersion == "" {
+		bundleVersion = app.Version
+	}
+
+	pkginfo := plistDict{
+		"name":                    munkiName(app.Name),
+		"display_name":            app.Name,
+		"version":                 app.Version,
+		"catalogs":                []any{munkiCatalog},
+		"installer_item_location": path.Base(urlPath(installerURL)),
+		"PackageCompleteURL":      installerURL,
+		"installs": []any{plistDict{
+			"type":                       "application",
+			"path":                       entry.BundlePath,
+			"CFBundleIdentifier":         entry.BundleID,
+			"CFBundleShortVersionString": bundleVersion,
+			"version_comparison_key":     "CFBundleShortVersionString",
+		}},
+		"notes": "Generated by generate_munki.go from the Fleet-maintained app " + app.Slug,
+	}
+	if entry.InstallerSha256 != "" {
+		pkginfo["installer_item_hash"] = entry.InstallerSha256
+	}
+	if entry.InstallerSize > 0 {
+		pkginfo["installer_item_size"] = (entry.InstallerSize + 1023) / 1024 // KiB, as munkiimport records it
+	}
+	if meta.Description != "" {
+		pkginfo["description"] = meta.Description
+	}
+	if len(meta.Categories) > 0 {
+		pkginfo["category"] = meta.Categories[0]
+	}
+
+	if ext == "dmg" {
+		pkginfo["installer_type"] = "copy_from_dmg"
+		pkginfo["items_to_copy"] = []any{plistDict{
+			"source_item":      path.Base(entry.BundlePath),
+			"destination_path": path.Dir(entry.BundlePath),
+		}}
+		pkginfo["uninstallable"] = true
+		pkginfo["uninstall_method"] = "remove_copied_items"
+	}
+
+	return pkginfo
+}
+
+// munkiName turns a display name like "Google Chrome" into "GoogleChrome",
+// the form Munki repos conventionally use for item names
+func munkiName(name string) string {
+	return strings.Join(strings.Fields(name), "")
+}
+
+// urlPath returns the path of installerURL, or the URL itself when it
+// doesn't parse
+func urlPath(installerURL string) string {
+	parsed, err := url.Parse(installerURL)
+	if err != nil {
+		return installerURL
+	}
+	return parsed.Path
+}
+
+// renderPlist renders dict as an XML property list
+func renderPlist(dict plistDict) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	sb.WriteString(`<plist version="1.0">` + "\n")
+	writePlistValue(&sb, dict, "")
+	sb.WriteString("</plist>\n")
+	return sb.String()
+}
+
+func writePlistValue(sb *strings.Builder, value any, indent string) {
+	switch v := value.(type) {
+	case plistDict:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		sb.WriteString(indent + "<dict>\n")
+		for _, key := range keys {
+			sb.WriteString(indent + "\t<key>" + escapeXML(key) + "</key>\n")
+			writePlistValue(sb, v[key], indent+"\t")
+		}
+		sb.WriteString(indent + "</dict>\n")
+	case []any:
+		sb.WriteString(indent + "<array>\n")
+		for _, item := range v {
+			writePlistValue(sb, item, indent+"\t")
+		}
+		sb.WriteString(indent + "</array>\n")
+	case string:
+		sb.WriteString(indent + "<string>" + escapeXML(v) + "</string>\n")
+	case int64:
+		sb.WriteString(fmt.Sprintf("%s<integer>%d</integer>\n", indent, v))
+	case bool:
+		if v {
+			sb.WriteString(indent + "<true/>\n")
+		} else {
+			sb.WriteString(indent + "<false/>\n")
+		}
+	}
+}
+
+func escapeXML(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+// installerType returns the lowercase file extension of the installer URL's
+// path, or "" when it doesn't end in one (e.g. a redirecting download link)
+func installerType(installerURL string) string {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(urlPath(installerURL)), "."))
+	switch ext {
+	case "pkg", "dmg", "zip":
+		return ext
+	}
+	return ""
+}
+
+func describeInstaller(ext string) string {
+	if ext == "" {
+		return "an installer URL without a file extension"
+	}
+	return "." + ext + " installers"
+}
+
+func loadVersions() (*datafile.AppVersions, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// loadMetadata returns each app's description and categories from
+// data/apps_metadata.json, or nothing when generate_html.go hasn't written it
+func loadMetadata() (map[string]datafile.AppMetadata, error) {
+	data, err := os.ReadFile(appsMetadataJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppsMetadata, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata datafile.AppsMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+
+	apps := make(map[string]datafile.AppMetadata, len(metadata.Apps))
+	for _, app := range metadata.Apps {
+		apps[app.Slug] = app
+	}
+	return apps, nil
+}
+
+// Main runs fleet-tracker munki with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("munki", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateMunki(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
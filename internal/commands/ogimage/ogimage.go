@@ -0,0 +1,302 @@
+// Package ogimage implements "fleet-tracker og-image", which renders
+// og-image.png; generate_og_image.go runs it on its own.
+package ogimage
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+)
+
+const (
+	csvFile       = "data/apps_growth.csv"
+	templatePNG   = "cloud-city.png"
+	outputOGImage = "og-image.png"
+
+	sparklineDays = 90 // The sparkline covers this many trailing days
+	recentDays    = 30 // "+N in the last 30 days"
+)
+
+// glyphs is a 5x7 bitmap font covering the characters drawn on the image;
+// text is upper-cased before drawing
+var glyphs = map[rune][7]string{
+	'A': {"01110", "10001", "10001", "11111", "10001", "10001", "10001"},
+	'B': {"11110", "10001", "10001", "11110", "10001", "10001", "11110"},
+	'C': {"01110", "10001", "10000", "10000", "10000", "10001", "01110"},
+	'D': {"11100", "10010", "10001", "10001", "10001", "10010", "11100"},
+	'E': {"11111", "10000", "10000", "11110", "10000", "10000", "11111"},
+	'F': {"11111", "10000", "10000", "11110", "10000", "10000", "10000"},
+	'G': {"01110", "10001", "10000", "10111", "10001", "10001", "01111"},
+	'H': {"10001", "10001", "10001", "11111", "10001", "10001", "10001"},
+	'I': {"01110", "00100", "00100", "00100", "00100", "00100", "01110"},
+	'J': {"00111", "00010", "00010", "00010", "00010", "10010", "01100"},
+	'K': {"10001", "10010", "10100", "11000", "10100", "10010", "10001"},
+	'L': {"10000", "10000", "10000", "10000", "10000", "10000", "11111"},
+	'M': {"10001", "11011", "10101", "10101", "10001", "10001", "10001"},
+	'N': {"10001", "10001", "11001", "10101", "10011", "10001", "10001"},
+	'O': {"01110", "10001", "10001", "10001", "10001", "10001", "01110"},
+	'P': {"11110", "10001", "10001", "11110", "10000", "10000", "10000"},
+	'Q': {"01110", "10001", "10001", "10001", "10101", "10010", "01101"},
+	'R': {"11110", "10001", "10001", "11110", "10100", "10010", "10001"},
+	'S': {"01111", "10000", "10000", "01110", "00001", "00001", "11110"},
+	'T': {"11111", "00100", "00100", "00100", "00100", "00100", "00100"},
+	'U': {"10001", "10001", "10001", "10001", "10001", "10001", "01110"},
+	'V': {"10001", "10001", "10001", "10001", "10001", "01010", "00100"},
+	'W': {"10001", "10001", "10001", "10101", "10101", "10101", "01010"},
+	'X': {"10001", "10001", "01010", "00100", "01010", "10001", "10001"},
+	'Y': {"10001", "10001", "10001", "01010", "00100", "00100", "00100"},
+	'Z': {"11111", "00001", "00010", "00100", "01000", "10000", "11111"},
+	'0': {"01110", "10001", "10011", "10101", "11001", "10001", "01110"},
+	'1': {"00100", "01100", "00100", "00100", "00100", "00100", "01110"},
+	'2': {"01110", "10001", "00001", "00010", "00100", "01000", "11111"},
+	'3': {"11111", "00010", "00100", "00010", "00001", "10001", "01110"},
+	'4': {"00010", "00110", "01010", "10010", "11111", "00010", "00010"},
+	'5': {"11111", "10000", "11110", "00001", "00001", "10001", "01110"},
+	'6': {"00110", "01000", "10000", "11110", "10001", "10001", "01110"},
+	'7': {"11111", "00001", "00010", "00100", "01000", "01000", "01000"},
+	'8': {"01110", "10001", "10001", "01110", "10001", "10001", "01110"},
+	'9': {"01110", "10001", "10001", "01111", "00001", "00010", "01100"},
+	'+': {"00000", "00100", "00100", "11111", "00100", "00100", "00000"},
+	'-': {"00000", "00000", "00000", "11111", "00000", "00000", "00000"},
+	',': {"00000", "00000", "00000", "00000", "01100", "00100", "01000"},
+	'.': {"00000", "00000", "00000", "00000", "00000", "01100", "01100"},
+	' ': {"00000", "00000", "00000", "00000", "00000", "00000", "00000"},
+}
+
+var (
+	panelColor     = color.NRGBA{R: 25, G: 33, B: 71, A: 235} // Fleet's dark navy
+	textColor      = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	mutedTextColor = color.NRGBA{R: 200, G: 205, B: 225, A: 255}
+	lineColor      = color.NRGBA{R: 0, G: 200, B: 170, A: 255}
+)
+
+// growthPoint is one day of apps_growth.csv
+type growthPoint struct {
+	date  time.Time
+	count int
+}
+
+func generateOGImage() error {
+	fmt.Println("🖼️  Generating social preview image...")
+
+	points, err := loadGrowthPoints()
+	if err != nil {
+		return fmt.Errorf("failed to load CSV data: %w", err)
+	}
+	if len(points) == 0 {
+		return fmt.Errorf("%s has no data", csvFile)
+	}
+
+	file, err := os.Open(templatePNG)
+	if err != nil {
+		return fmt.Errorf("failed to open template: %w", err)
+	}
+	template, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", templatePNG, err)
+	}
+
+	img := image.NewRGBA(template.Bounds())
+	draw.Draw(img, img.Bounds(), template, template.Bounds().Min, draw.Src)
+
+	latest := points[len(points)-1]
+	recent := latest.count - countOn(points, latest.date.AddDate(0, 0, -recentDays))
+
+	// The panel sits over the clouds on the left, below the cliff
+	b := img.Bounds()
+	panel := image.Rect(b.Min.X+60, b.Max.Y-500, b.Min.X+980, b.Max.Y-40)
+	draw.Draw(img, panel, image.NewUniform(panelColor), image.Point{}, draw.Over)
+
+	x, y := panel.Min.X+50, panel.Min.Y+45
+	drawText(img, x, y, 6, "Fleet-maintained apps", mutedTextColor)
+	countText := strconv.Itoa(latest.count)
+	drawText(img, x, y+75, 20, countText, textColor)
+	drawText(img, x+textWidth(countText, 20)+40, y+75+140-56, 8, "apps", textColor)
+
+	drawSparkline(img, image.Rect(x, y+240, panel.Max.X-50, y+290), trailing(points, sparklineDays), lineColor)
+
+	drawText(img, x, y+315, 4, fmt.Sprintf("%+d in the last %d days", recent, recentDays), mutedTextColor)
+	drawText(img, x, y+360, 4, "Updated "+latest.date.Format("Jan 2, 2006"), mutedTextColor)
+
+	out, err := os.Create(outputOGImage)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outputOGImage, err)
+	}
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(out, img); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to encode %s: %w", outputOGImage, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputOGImage, err)
+	}
+
+	fmt.Printf("✅ Generated %s\n", outputOGImage)
+	fmt.Printf("   📊 %d apps as of %s\n", latest.count, latest.date.Format("2006-01-02"))
+
+	return nil
+}
+
+func loadGrowthPoints() ([]growthPoint, error) {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var points []growthPoint
+	for i := 1; i < len(records); i++ {
+		row := records[i]
+		if len(row) < 2 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", row[0])
+		if err != nil {
+			continue
+		}
+		count, err := strconv.Atoi(row[1])
+		if err != nil {
+			continue
+		}
+		points = append(points, growthPoint{date: date, count: count})
+	}
+
+	return points, nil
+}
+
+// countOn returns the app count on the last day at or before date, or the
+// first recorded count when date precedes the data
+func countOn(points []growthPoint, date time.Time) int {
+	count := points[0].count
+	for _, p := range points {
+		if p.date.After(date) {
+			break
+		}
+		count = p.count
+	}
+	return count
+}
+
+// trailing returns the points within days of the last one
+func trailing(points []growthPoint, days int) []growthPoint {
+	since := points[len(points)-1].date.AddDate(0, 0, -days)
+	for i, p := range points {
+		if !p.date.Before(since) {
+			return points[i:]
+		}
+	}
+	return points
+}
+
+// drawText draws s with its top-left corner at (x, y), each font pixel
+// scaled to a scale×scale square
+func drawText(img draw.Image, x, y, scale int, s string, c color.Color) {
+	for _, r := range strings.ToUpper(s) {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs[' ']
+		}
+		for row, bits := range glyph {
+			for col, bit := range bits {
+				if bit == '1' {
+					rect := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale)
+					draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Over)
+				}
+			}
+		}
+		x += 6 * scale
+	}
+}
+
+// textWidth is the width drawText covers for s, without the trailing gap
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (6*n - 1) * scale
+}
+
+// drawSparkline plots the counts across area, scaled between their minimum
+// and maximum, as a line a few pixels thick
+func drawSparkline(img draw.Image, area image.Rectangle, points []growthPoint, c color.Color) {
+	if len(points) == 0 {
+		return
+	}
+
+	low, high := points[0].count, points[0].count
+	for _, p := range points {
+		if p.count < low {
+			low = p.count
+		}
+		if p.count > high {
+			high = p.count
+		}
+	}
+
+	position := func(i int) (float64, float64) {
+		x := float64(area.Min.X)
+		if len(points) > 1 {
+			x += float64(i) * float64(area.Dx()) / float64(len(points)-1)
+		}
+		y := float64(area.Max.Y)
+		if high > low {
+			y -= float64(points[i].count-low) * float64(area.Dy()) / float64(high-low)
+		} else {
+			y -= float64(area.Dy()) / 2
+		}
+		return x, y
+	}
+
+	const thickness = 6
+	dot := image.NewUniform(c)
+	for i := 0; i < len(points); i++ {
+		x0, y0 := position(i)
+		x1, y1 := x0, y0
+		if i+1 < len(points) {
+			x1, y1 = position(i + 1)
+		}
+		steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))) + 1
+		for s := 0; s <= steps; s++ {
+			t := float64(s) / float64(steps)
+			px := int(x0 + (x1-x0)*t)
+			py := int(y0 + (y1-y0)*t)
+			rect := image.Rect(px-thickness/2, py-thickness/2, px+thickness/2, py+thickness/2)
+			draw.Draw(img, rect, dot, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// Main runs fleet-tracker og-image with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("og-image", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateOGImage(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
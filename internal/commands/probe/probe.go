@@ -0,0 +1,211 @@
+// Package probe implements "fleet-tracker probe", which checks that every
+// installer URL responds; probe_installers.go runs it on its own.
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionsJSON     = "data/app_versions.json"
+	installerUptime  = "data/installer_uptime.jsonl"
+	probeUserAgent   = "fleet-apps-growth-tracker-probe"
+	defaultRetention = 90 // days of probes kept in installer_uptime.jsonl
+)
+
+// Main runs fleet-tracker probe with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("probe", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 8, "number of installers probed in parallel")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout per probe")
+	retentionDays := fs.Int("retention-days", defaultRetention, "drop probes older than this many days")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := probeInstallers(*concurrency, *timeout, *retentionDays); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func probeInstallers(concurrency int, timeout time.Duration, retentionDays int) error {
+	fmt.Println("📡 Probing installer URLs...")
+
+	apps, err := loadProbeApps()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   📦 %d apps with installer URLs\n", len(apps))
+
+	client := &http.Client{Timeout: timeout}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	results := make([]datafile.ProbeResult, len(apps))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = probeInstaller(client, apps[i])
+				results[i].Time = now
+			}
+		}()
+	}
+	for i := range apps {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if !result.OK {
+			failed++
+			reason := result.Error
+			if reason == "" {
+				reason = fmt.Sprintf("status %d", result.Status)
+			}
+			fmt.Printf("   ❌ %s: %s\n", result.Slug, reason)
+		}
+	}
+
+	if err := appendProbeResults(results, time.Duration(retentionDays)*24*time.Hour); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Probed %d installers (%d unavailable)\n", len(results), failed)
+	fmt.Printf("   📝 Appended to %s\n", installerUptime)
+
+	return nil
+}
+
+func loadProbeApps() ([]datafile.AppVersion, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", versionsJSON, err)
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s: %w", versionsJSON, err)
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", versionsJSON, err)
+	}
+
+	var apps []datafile.AppVersion
+	for _, app := range versions.Apps {
+		if app.InstallerURL != "" {
+			apps = append(apps, app)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Slug < apps[j].Slug })
+
+	return apps, nil
+}
+
+// probeInstaller checks one installer URL; 2xx and 3xx final statuses count as available
+func probeInstaller(client *http.Client, app datafile.AppVersion) datafile.ProbeResult {
+	result := datafile.ProbeResult{Slug: app.Slug}
+	start := time.Now()
+
+	resp, err := probeRequest(client, http.MethodHead, app.InstallerURL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotImplemented) {
+		// Some CDNs reject HEAD; ask for a single byte instead
+		resp.Body.Close()
+		resp, err = probeRequest(client, http.MethodGet, app.InstallerURL)
+	}
+	result.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	result.OK = resp.StatusCode >= 200 && resp.StatusCode < 400
+
+	return result
+}
+
+func probeRequest(client *http.Client, method, url string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", probeUserAgent)
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+	return client.Do(req)
+}
+
+// appendProbeResults appends results to installer_uptime.jsonl, dropping
+// entries older than retention so the file doesn't grow without bound
+func appendProbeResults(results []datafile.ProbeResult, retention time.Duration) error {
+	cutoff := time.Now().Add(-retention)
+
+	var buf bytes.Buffer
+	if file, err := os.Open(installerUptime); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			var existing datafile.ProbeResult
+			if err := json.Unmarshal(line, &existing); err != nil {
+				continue // skip corrupt lines
+			}
+			if t, err := time.Parse(time.RFC3339, existing.Time); err == nil && t.Before(cutoff) {
+				continue
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", installerUptime, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", installerUptime, err)
+	}
+
+	for _, result := range results {
+		line, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal probe result: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(installerUptime, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", installerUptime, err)
+	}
+
+	return nil
+}
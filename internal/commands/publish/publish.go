@@ -0,0 +1,268 @@
+// Package publish implements "fleet-tracker publish", which syncs the site to
+// a bucket; publish.go runs it on its own.
+package publish
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/objectstore"
+)
+
+// publishedPaths lists what gets synced: the generated site, the data and
+// script archive directories, the fleetctl snippets, the Intune detection
+// rules, the Munki pkginfo files, the Jamf extension attributes and a static
+// api/ export when present.
+// Directories are synced recursively.
+var publishedPaths = []string{
+	"data",
+	"archive",
+	"api",
+	"fleetctl",
+	"intune",
+	"munki",
+	"jamf",
+	"feed.xml",
+	"advisory.xml",
+	"releases.ics",
+	"changelog.html",
+	"CHANGELOG.md",
+	"SHA256SUMS",
+	"cloud-city.png",
+	"og-image.png",
+	"index.html",
+}
+
+// contentTypes overrides mime.TypeByExtension for the files published here
+var contentTypes = map[string]string{
+	".html":  "text/html; charset=utf-8",
+	".json":  "application/json",
+	".jsonl": "application/x-ndjson",
+	".csv":   "text/csv; charset=utf-8",
+	".xml":   "application/rss+xml; charset=utf-8",
+	".ics":   "text/calendar; charset=utf-8",
+	".yml":   "application/yaml",
+	".md":    "text/markdown; charset=utf-8",
+	".ps1":   "text/plain; charset=utf-8",
+	".plist": "application/xml",
+	".sh":    "text/x-shellscript; charset=utf-8",
+}
+
+// contentEncodings maps the extensions of the variants fleet-tracker compress
+// writes to their Content-Encoding; a variant keeps the Content-Type of the
+// file it compresses, so index.html.gz is still text/html
+var contentEncodings = map[string]string{
+	".gz": "gzip",
+}
+
+// localFile is a file that should exist in the bucket
+type localFile struct {
+	Key  string // Slash-separated path relative to the checkout
+	Path string
+	MD5  string
+}
+
+// syncPlan is what a sync uploads and deletes
+type syncPlan struct {
+	Upload    []localFile
+	Delete    []string
+	Unchanged int
+}
+
+// Main runs fleet-tracker publish with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	bucketURI := fs.String("bucket", os.Getenv("PUBLISH_BUCKET"), "bucket to sync to, e.g. s3://my-bucket/prefix or gs://my-bucket")
+	endpoint := fs.String("endpoint", os.Getenv("PUBLISH_ENDPOINT"), "S3-compatible endpoint URL (default: AWS S3 for s3://, storage.googleapis.com for gs://)")
+	region := fs.String("region", "", "bucket region (default: AWS_REGION or us-east-1)")
+	cacheControl := fs.String("cache-control", "public, max-age=300", "Cache-Control header set on uploaded objects")
+	deleteExtra := fs.Bool("delete", false, "delete objects under the prefix that no longer exist locally")
+	dryRun := fs.Bool("dry-run", false, "print what would change without uploading or deleting")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *bucketURI == "" {
+		fmt.Fprintln(os.Stderr, "❌ Error: --bucket (or PUBLISH_BUCKET) is required")
+		os.Exit(1)
+	}
+
+	if err := publish(*bucketURI, *endpoint, *region, *cacheControl, *deleteExtra, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func publish(bucketURI, endpoint, region, cacheControl string, deleteExtra, dryRun bool) error {
+	bucket, err := objectstore.Open(bucketURI, endpoint, region)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("☁️  Syncing outputs to %s\n", bucket)
+
+	files, err := collectPublishedFiles()
+	if err != nil {
+		return fmt.Errorf("failed to collect files: %w", err)
+	}
+
+	remote, err := bucket.List()
+	if err != nil {
+		return err
+	}
+
+	plan := planSync(files, remote, deleteExtra)
+	fmt.Printf("   📝 %d to upload, %d unchanged, %d to delete\n", len(plan.Upload), plan.Unchanged, len(plan.Delete))
+
+	for _, file := range plan.Upload {
+		if dryRun {
+			fmt.Printf("  ⬆️  %s (dry run)\n", file.Key)
+			continue
+		}
+		body, err := os.ReadFile(file.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.Path, err)
+		}
+		if err := bucket.Put(file.Key, body, contentType(file.Key), contentEncoding(file.Key), cacheControl); err != nil {
+			return err
+		}
+		fmt.Printf("  ⬆️  %s\n", file.Key)
+	}
+
+	for _, key := range plan.Delete {
+		if dryRun {
+			fmt.Printf("  🗑️  %s (dry run)\n", key)
+			continue
+		}
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+		fmt.Printf("  🗑️  %s\n", key)
+	}
+
+	fmt.Println("✅ Sync complete")
+	return nil
+}
+
+// collectPublishedFiles expands publishedPaths, skipping entries that don't
+// exist and dotfiles such as data/.cache
+func collectPublishedFiles() ([]localFile, error) {
+	var files []localFile
+	for _, root := range publishedPaths {
+		info, err := os.Stat(root)
+		if os.IsNotExist(err) {
+			continue
+		}
+		// A file's .gz variant goes just before it, so index.html stays last
+		if err == nil && !info.IsDir() {
+			if _, err := os.Stat(root + ".gz"); err == nil {
+				file, err := hashFile(root + ".gz")
+				if err != nil {
+					return nil, err
+				}
+				files = append(files, file)
+			}
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(d.Name(), ".") && path != root {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			file, err := hashFile(path)
+			if err != nil {
+				return err
+			}
+			files = append(files, file)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func hashFile(path string) (localFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return localFile{}, err
+	}
+	sum := md5.Sum(data)
+	return localFile{Key: filepath.ToSlash(path), Path: path, MD5: hex.EncodeToString(sum[:])}, nil
+}
+
+// planSync compares the local files with the bucket listing. Uploads keep the
+// order of publishedPaths, so index.html goes last and never points at data
+// that hasn't been uploaded yet.
+func planSync(files []localFile, remote []objectstore.Object, deleteExtra bool) syncPlan {
+	etags := make(map[string]string, len(remote))
+	for _, object := range remote {
+		etags[object.Key] = object.ETag
+	}
+
+	var plan syncPlan
+	local := make(map[string]bool, len(files))
+	for _, file := range files {
+		local[file.Key] = true
+		if etag, ok := etags[file.Key]; ok && etag == file.MD5 {
+			plan.Unchanged++
+			continue
+		}
+		plan.Upload = append(plan.Upload, file)
+	}
+
+	if deleteExtra {
+		for key := range etags {
+			if !local[key] {
+				plan.Delete = append(plan.Delete, key)
+			}
+		}
+		sort.Strings(plan.Delete)
+	}
+
+	return plan
+}
+
+func contentType(key string) string {
+	ext := strings.ToLower(filepath.Ext(key))
+	if _, ok := contentEncodings[ext]; ok {
+		return contentType(strings.TrimSuffix(key, filepath.Ext(key)))
+	}
+	if t, ok := contentTypes[ext]; ok {
+		return t
+	}
+	if ext == "" {
+		return "text/plain; charset=utf-8"
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// contentEncoding returns the Content-Encoding of a precompressed variant, or
+// "" for everything else
+func contentEncoding(key string) string {
+	return contentEncodings[strings.ToLower(filepath.Ext(key))]
+}
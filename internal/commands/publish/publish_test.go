@@ -0,0 +1,25 @@
+package publish
+
+import "testing"
+
+func TestContentHeaders(t *testing.T) {
+	tests := []struct {
+		key      string
+		typ      string
+		encoding string
+	}{
+		{"index.html", "text/html; charset=utf-8", ""},
+		{"index.html.gz", "text/html; charset=utf-8", "gzip"},
+		{"data/apps_growth.csv.gz", "text/csv; charset=utf-8", "gzip"},
+		{"api/apps/zoom/darwin.JSON.GZ", "application/json", "gzip"},
+		{"SHA256SUMS", "text/plain; charset=utf-8", ""},
+	}
+	for _, tt := range tests {
+		if got := contentType(tt.key); got != tt.typ {
+			t.Errorf("contentType(%q) = %q, want %q", tt.key, got, tt.typ)
+		}
+		if got := contentEncoding(tt.key); got != tt.encoding {
+			t.Errorf("contentEncoding(%q) = %q, want %q", tt.key, got, tt.encoding)
+		}
+	}
+}
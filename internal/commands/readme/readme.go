@@ -0,0 +1,254 @@
+// Package readme implements "fleet-tracker readme", which generates README.md;
+// generate_readme.go runs it on its own.
+package readme
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/milestone"
+)
+
+const (
+	csvFile     = "data/apps_growth.csv"
+	readmeFile  = "README.md"
+	chartWidth  = 800
+	chartHeight = 400
+)
+
+func generateREADME() error {
+	fmt.Println("📝 Generating README with embedded charts...")
+
+	data, err := loadCSVForREADME()
+	if err != nil {
+		return fmt.Errorf("failed to load CSV data: %w", err)
+	}
+
+	readmeContent := generateREADMEContent(data)
+
+	if err := os.WriteFile(readmeFile, []byte(readmeContent), 0644); err != nil {
+		return fmt.Errorf("failed to write README file: %w", err)
+	}
+
+	fmt.Printf("✅ Generated %s\n", readmeFile)
+	return nil
+}
+
+type readmeData struct {
+	totalApps        int
+	totalGrowth      int
+	daysSpan         int
+	avgPerMonth      float64
+	growthEvents     int
+	firstDate        string
+	lastDate         string
+	growthMilestones []struct {
+		date  string
+		count int
+		added int
+	}
+
+	projected []milestone.Milestone // Upcoming milestones (see milestone.Project)
+}
+
+func loadCSVForREADME() (*readmeData, error) {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+
+	data := &readmeData{
+		growthMilestones: make([]struct {
+			date  string
+			count int
+			added int
+		}, 0),
+	}
+
+	var dates []string
+	var counts []int
+	var firstDateParsed, lastDateParsed time.Time
+
+	for i := 1; i < len(records); i++ {
+		row := records[i]
+		if len(row) < 3 {
+			continue
+		}
+
+		dateStr := row[0]
+		var count, added int
+		fmt.Sscanf(row[1], "%d", &count)
+		fmt.Sscanf(row[2], "%d", &added)
+
+		if i == 1 {
+			data.firstDate = dateStr
+			firstDateParsed, _ = time.Parse("2006-01-02", dateStr)
+		}
+		data.lastDate = dateStr
+		lastDateParsed, _ = time.Parse("2006-01-02", dateStr)
+
+		dates = append(dates, dateStr)
+		counts = append(counts, count)
+
+		if added > 0 {
+			data.growthMilestones = append(data.growthMilestones, struct {
+				date  string
+				count int
+				added int
+			}{
+				date:  dateStr,
+				count: count,
+				added: added,
+			})
+		}
+	}
+
+	if len(counts) > 0 {
+		data.totalApps = counts[len(counts)-1]
+		data.totalGrowth = data.totalApps - counts[0]
+		data.daysSpan = int(lastDateParsed.Sub(firstDateParsed).Hours() / 24)
+		data.avgPerMonth = float64(data.totalGrowth) / (float64(data.daysSpan) / 30.44)
+		data.growthEvents = len(data.growthMilestones)
+		data.projected = milestone.Project(dates, counts)
+	}
+
+	return data, nil
+}
+
+func generateREADMEContent(data *readmeData) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Fleet Maintained Apps Growth Tracker\n\n")
+	sb.WriteString("A standalone repository that tracks and visualizes the growth of Fleet-maintained applications over time. ")
+	sb.WriteString("This project automatically pulls data from the [fleetdm/fleet](https://github.com/fleetdm/fleet) repository ")
+	sb.WriteString("and generates interactive visualizations.\n\n")
+
+	sb.WriteString("## 🌐 View Live Dashboard\n\n")
+	sb.WriteString("👉 **[View Interactive Dashboard](https://allenhouchins.github.io/fleet-maintained-apps-growth-tracker/)**\n\n")
+	sb.WriteString("The dashboard provides real-time statistics, interactive charts, and detailed growth metrics.\n\n")
+
+	// Stats
+	sb.WriteString("## 📊 Statistics\n\n")
+	sb.WriteString("| Metric | Value |\n")
+	sb.WriteString("|--------|-------|\n")
+	sb.WriteString(fmt.Sprintf("| Total apps | %d |\n", data.totalApps))
+	sb.WriteString(fmt.Sprintf("| Growth since %s | +%d |\n", formatDateForTable(data.firstDate), data.totalGrowth))
+	sb.WriteString(fmt.Sprintf("| Days tracked | %d |\n", data.daysSpan))
+	sb.WriteString(fmt.Sprintf("| Average growth per month | %.1f apps |\n", data.avgPerMonth))
+	for _, m := range data.projected {
+		sb.WriteString(fmt.Sprintf("| Projected to reach %d apps | ~%s |\n", m.Count, formatDateForTable(m.Date)))
+	}
+	sb.WriteString(fmt.Sprintf("\n_As of %s. Projections extrapolate the growth rate over the last %d days and are recalculated on every run._\n\n", formatDateForTable(data.lastDate), milestone.WindowDays))
+
+	// How it works
+	sb.WriteString("## 🔧 How It Works\n\n")
+	sb.WriteString("1. **Data Collection**: A Go script uses the GitHub API to fetch commit history and file content for `ee/maintained-apps/outputs/apps.json` without cloning the repository\n")
+	sb.WriteString("2. **Data Processing**: The script generates a continuous daily CSV file with app counts\n")
+	sb.WriteString("3. **Visualization**: An HTML file with embedded Chart.js creates interactive charts\n")
+	sb.WriteString("4. **Automation**: GitHub Actions runs daily at 12:00 PM UTC to update the data\n\n")
+
+	// Files
+	sb.WriteString("## 📁 Files\n\n")
+	sb.WriteString("- `main.go` - Fetches data from fleetdm/fleet and generates CSV\n")
+	sb.WriteString("- `generate_html.go` - Generates interactive HTML visualization\n")
+	sb.WriteString("- `generate_readme.go` - Generates this README with embedded charts\n")
+	sb.WriteString("- `generate_changelog.go` - Generates `changelog.html`, a weekly history of new apps and version bumps, and `CHANGELOG.md`, a daily one\n")
+	sb.WriteString("- `generate_fleetctl.go` - Generates `fleetctl/<slug>.yml`, a Fleet GitOps software package file per app with the verified installer hash, plus the consolidated `fleetctl/software.yml` snippet\n")
+	sb.WriteString("- `generate_intune.go` - Generates `intune/<slug>.json` and `.ps1`, Intune Win32 detection rules from the MSI product codes, install paths and file versions the Windows collector records\n")
+	sb.WriteString("- `generate_munki.go` - Generates `munki/<slug>.plist`, a Munki pkginfo per Mac app with the vendor's installer URL, the verified installer hash and an `installs` array\n")
+	sb.WriteString("- `generate_jamf.go` - Generates `jamf/<slug>.sh`, a Jamf Pro extension attribute per Mac app reporting whether the installed app's Team ID and version match the tracked ones\n")
+	sb.WriteString("- `generate_ics.go` - Generates the `releases.ics` calendar of version changes and new apps\n")
+	sb.WriteString("- `generate_advisory.go` - Generates `advisory.xml`, a feed of signer changes, hash drift, script changes and invalid signatures without the routine version bumps\n")
+	sb.WriteString("- `generate_og_image.go` - Renders `og-image.png`, the link preview image, with the current app count and a growth sparkline\n")
+	sb.WriteString("- `generate_checksums.go` - Generates the `SHA256SUMS` manifest for the published data files, feeds and HTML\n")
+	sb.WriteString("- `compress_outputs.go` - Writes gzip-precompressed `.gz` copies of the pages, feeds and data files for servers that serve them directly\n")
+	sb.WriteString("- `publish.go` - Syncs the site and data files to an S3 or GCS bucket for CDN hosting (`go run publish.go --bucket s3://bucket/prefix`)\n")
+	sb.WriteString("- `snapshot.go` - Packs the datasets into the tarball attached to each monthly `data-YYYY.MM` release\n")
+	sb.WriteString("- `report.go` - Renders a monthly markdown summary under `reports/` (`go run report.go monthly [--month YYYY-MM]`), or a diff of the catalog between two dates (`go run report.go compare --from YYYY-MM-DD --to YYYY-MM-DD`)\n")
+	sb.WriteString("- `lint.go` - Checks apps.json and the data files for consistency problems\n")
+	sb.WriteString("- `doctor.go` - Diagnoses the generated data files (`go run doctor.go data [--fix]`) or the collector prerequisites (`go run doctor.go env`), and reports data freshness (`go run doctor.go status`)\n")
+	sb.WriteString("- `verify.go` - Checks a downloaded installer or installed app against the published hashes and Team ID or publisher (`go run verify.go --slug <slug> --file <path>`)\n")
+	sb.WriteString("- `serve.go` - Serves the site and a local JSON API (`go run serve.go`)\n")
+	sb.WriteString("- `probe_installers.go` - Checks every installer URL daily and records availability\n")
+	sb.WriteString("- `crossref_packages.go` - Records each Windows app's winget and Chocolatey packages and whether Fleet's version lags them\n")
+	sb.WriteString("- `upstream_lag.go` - Records how many days Fleet's version of each app trails the vendor's latest release, for the dashboard's freshness leaderboard\n")
+	sb.WriteString("- `track_failures.go` - Files an issue for each app the security info collectors fail on and closes it once the app is collected again\n")
+	sb.WriteString("- `merge_security.go` - Merges the security info files of a sharded collection run into one (`go run merge_security.go <fragment.json>...`)\n")
+	sb.WriteString("- `cmd/fleet-tracker/` - A single binary running every script as a subcommand (`fleet-tracker collect`, `html`, `rss`, `readme`, `history`, `security`, ...)\n")
+	sb.WriteString("- `data/apps_growth.csv` - Generated CSV data file\n")
+	sb.WriteString("- `archive/` - The install/uninstall scripts and queries of every version observed, as Fleet shipped them\n")
+	sb.WriteString("- `.github/workflows/update-data.yml` - GitHub Actions workflow for daily updates\n\n")
+
+	// Local development
+	sb.WriteString("## 💻 Local Development\n\n")
+	sb.WriteString("### Prerequisites\n\n")
+	sb.WriteString("- Go 1.21+\n\n")
+	sb.WriteString("### Setup\n\n")
+	sb.WriteString("```bash\n")
+	sb.WriteString("# Clone repository\n")
+	sb.WriteString("git clone <your-repo-url>\n")
+	sb.WriteString("cd fleet-apps-growth-tracker\n\n")
+	sb.WriteString("# Generate data\n")
+	sb.WriteString("go run main.go\n\n")
+	sb.WriteString("# Generate HTML\n")
+	sb.WriteString("go run generate_html.go\n\n")
+	sb.WriteString("# Generate README\n")
+	sb.WriteString("go run generate_readme.go\n\n")
+	sb.WriteString("# Open index.html in your browser\n")
+	sb.WriteString("open index.html\n")
+	sb.WriteString("```\n\n")
+
+	// Data source
+	sb.WriteString("## 📚 Data Source\n\n")
+	sb.WriteString("This project pulls data from:\n")
+	sb.WriteString("- **Repository**: [fleetdm/fleet](https://github.com/fleetdm/fleet)\n")
+	sb.WriteString("- **File**: `ee/maintained-apps/outputs/apps.json`\n")
+	sb.WriteString("- **Method**: GitHub API (no repository cloning required)\n\n")
+
+	// License
+	sb.WriteString("## 📄 License\n\n")
+	sb.WriteString("MIT License - feel free to use this project for tracking other repositories!\n")
+
+	return sb.String()
+}
+
+func formatDateForTable(dateStr string) string {
+	t, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return dateStr
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// Main runs fleet-tracker readme with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("readme", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateREADME(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
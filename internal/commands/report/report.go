@@ -0,0 +1,818 @@
+// Package report implements "fleet-tracker report", which writes monthly and
+// comparison reports; report.go runs it on its own.
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	growthCSV             = "data/apps_growth.csv"
+	versionHistoryJSON    = "data/version_history.json"
+	versionHistoryArchive = "data/version_history-%d.json"
+	securityInfoJSON      = "data/app_security_info.json"
+	versionsJSON          = "data/app_versions.json"
+	firstSeenJSON         = "data/app_first_seen.json"
+	reportsDir            = "reports"
+	busiestDaysShown      = 5 // Days listed under "Busiest update days"
+	appsPerDayShown       = 4 // Apps named per busy day before "and N more"
+)
+
+// catalogComparison is the difference between the catalog at two dates
+type catalogComparison struct {
+	From            string                `json:"from"`
+	To              string                `json:"to"`
+	FromCount       int                   `json:"fromCount"`
+	ToCount         int                   `json:"toCount"`
+	Added           []datafile.AppVersion `json:"added"`
+	Removed         []datafile.AppVersion `json:"removed"`
+	Updated         []versionDelta        `json:"updated"`
+	PlatformChanges []platformChange      `json:"platformChanges"`
+}
+
+// versionDelta is an app in the catalog at both dates with another version
+type versionDelta struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Platform    string `json:"platform"`
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+	Updates     int    `json:"updates"` // Version changes recorded in between
+}
+
+// platformChange is an app that gained or lost a platform, e.g. a Mac app
+// that got a Windows build
+type platformChange struct {
+	App           string   `json:"app"` // Slug without the platform
+	Name          string   `json:"name"`
+	FromPlatforms []string `json:"fromPlatforms"`
+	ToPlatforms   []string `json:"toPlatforms"`
+}
+
+// monthlyGrowth summarizes apps_growth.csv over one month
+type monthlyGrowth struct {
+	StartCount   int
+	EndCount     int
+	Added        int
+	Removed      int
+	EndMac       int
+	EndWindows   int
+	HasStartData bool
+}
+
+// Main runs fleet-tracker report with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		printReportUsage()
+		os.Exit(2)
+	}
+
+	args = fs.Args()
+	switch args[0] {
+	case "monthly":
+		fs := flag.NewFlagSet("monthly", flag.ExitOnError)
+		month := fs.String("month", "", "month to report on as YYYY-MM (default: the previous month)")
+		fs.Parse(args[1:])
+
+		start, err := reportMonth(*month, time.Now().UTC())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(2)
+		}
+		if err := generateMonthlyReport(start); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "compare":
+		fs := flag.NewFlagSet("compare", flag.ExitOnError)
+		from := fs.String("from", "", "earlier date as YYYY-MM-DD")
+		to := fs.String("to", time.Now().UTC().Format("2006-01-02"), "later date as YYYY-MM-DD")
+		fs.Parse(args[1:])
+
+		if err := checkCompareDates(*from, *to); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(2)
+		}
+		if err := generateComparison(*from, *to); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		printReportUsage()
+		os.Exit(2)
+	}
+}
+
+func printReportUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: fleet-tracker report monthly [--month YYYY-MM]")
+	fmt.Fprintln(os.Stderr, "       fleet-tracker report compare --from YYYY-MM-DD [--to YYYY-MM-DD]")
+}
+
+// reportMonth returns the first day of the month to report on: the given
+// YYYY-MM, or the month before now
+func reportMonth(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	t, err := time.Parse("2006-01", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --month %q (want YYYY-MM)", value)
+	}
+	return t, nil
+}
+
+// checkCompareDates requires from and to to be YYYY-MM-DD dates, from first
+func checkCompareDates(from, to string) error {
+	for _, d := range []struct{ flag, value string }{{"--from", from}, {"--to", to}} {
+		if _, err := time.Parse("2006-01-02", d.value); err != nil {
+			return fmt.Errorf("invalid %s %q (want YYYY-MM-DD)", d.flag, d.value)
+		}
+	}
+	if from >= to {
+		return fmt.Errorf("--from %s must be before --to %s", from, to)
+	}
+	return nil
+}
+
+func generateMonthlyReport(start time.Time) error {
+	end := start.AddDate(0, 1, 0)
+	fmt.Printf("📰 Generating report for %s...\n", start.Format("January 2006"))
+
+	growth, err := loadMonthlyGrowth(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to load growth data: %w", err)
+	}
+
+	history, err := loadVersionHistory(start.Year())
+	if err != nil {
+		return fmt.Errorf("failed to load version history: %w", err)
+	}
+	var newApps, updates []datafile.VersionChange
+	for _, change := range history.Changes {
+		if !inMonth(change.Date, start, end) {
+			continue
+		}
+		if change.OldVersion == "" {
+			newApps = append(newApps, change)
+		} else if change.NewVersion != "" {
+			updates = append(updates, change)
+		}
+	}
+	sort.SliceStable(newApps, func(i, j int) bool {
+		return newApps[i].Date < newApps[j].Date
+	})
+
+	security, err := loadSecurityInfo()
+	if err != nil {
+		return fmt.Errorf("failed to load security info: %w", err)
+	}
+	signingChanges := findSigningChanges(security, start, end)
+
+	content := renderMonthlyReport(start, growth, newApps, updates, signingChanges)
+
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", reportsDir, err)
+	}
+	path := filepath.Join(reportsDir, start.Format("2006-01")+".md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	fmt.Printf("✅ Generated: %s\n", path)
+	fmt.Printf("   📝 %d new apps, %d version updates, %d signing changes\n", len(newApps), len(updates), len(signingChanges))
+	return nil
+}
+
+func inMonth(date string, start, end time.Time) bool {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return false
+	}
+	return !t.Before(start) && t.Before(end)
+}
+
+// loadMonthlyGrowth reads the counts at the end of the previous month and of
+// the reported month, plus the additions and removals in between
+func loadMonthlyGrowth(start, end time.Time) (*monthlyGrowth, error) {
+	file, err := os.Open(growthCSV)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	growth := &monthlyGrowth{}
+	first, last := start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02")
+	for i, row := range records {
+		if i == 0 || len(row) < 5 {
+			continue
+		}
+		date := row[0]
+		count, _ := strconv.Atoi(row[1])
+		switch {
+		case date < first:
+			growth.StartCount = count
+			growth.HasStartData = true
+		case date <= last:
+			added, _ := strconv.Atoi(row[2])
+			growth.Added += added
+			if len(row) >= 6 {
+				removed, _ := strconv.Atoi(row[5])
+				growth.Removed += removed
+			}
+			growth.EndCount = count
+			growth.EndMac, _ = strconv.Atoi(row[3])
+			growth.EndWindows, _ = strconv.Atoi(row[4])
+		}
+	}
+
+	return growth, nil
+}
+
+// loadVersionHistory reads the current version history plus the archive of
+// year, where main.go rotates changes from before last year
+func loadVersionHistory(year int) (*datafile.VersionHistory, error) {
+	history := &datafile.VersionHistory{Changes: []datafile.VersionChange{}}
+	for _, path := range []string{versionHistoryJSON, fmt.Sprintf(versionHistoryArchive, year)} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		data, err = schema.Upgrade(schema.VersionHistory, data)
+		if err != nil {
+			return nil, err
+		}
+
+		var file datafile.VersionHistory
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		history.Changes = append(history.Changes, file.Changes...)
+	}
+
+	return history, nil
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// findSigningChanges lists apps collected during the month whose signing
+// identity differs from the previous version on record, whose Windows
+// signature isn't valid, or that had an anomaly detected
+func findSigningChanges(security *datafile.SecurityInfo, start, end time.Time) []string {
+	// The newest older version of each app, to compare against
+	previous := make(map[string]datafile.AppSecurityInfo)
+	for _, v := range security.Versions {
+		if p, ok := previous[v.Slug]; !ok || v.LastUpdated > p.LastUpdated {
+			previous[v.Slug] = v
+		}
+	}
+
+	var changes []string
+	for _, app := range security.Apps {
+		if !inMonth(app.LastUpdated, start, end) {
+			continue
+		}
+		label := fmt.Sprintf("**%s** %s", app.Name, app.Version)
+
+		if p, ok := previous[app.Slug]; ok {
+			for _, field := range []struct{ name, old, new string }{
+				{"Team ID", p.TeamID, app.TeamID},
+				{"Signing ID", p.SigningID, app.SigningID},
+				{"Publisher", p.Publisher, app.Publisher},
+				{"Issuer", p.Issuer, app.Issuer},
+			} {
+				if field.old != "" && field.new != "" && field.old != field.new {
+					changes = append(changes, fmt.Sprintf("%s: %s changed from `%s` (%s) to `%s`", label, field.name, field.old, p.Version, field.new))
+				}
+			}
+		}
+
+		if app.SignatureStatus != "" && app.SignatureStatus != "valid" {
+			detail := ""
+			if app.SignatureDetail != "" {
+				detail = " - " + app.SignatureDetail
+			}
+			changes = append(changes, fmt.Sprintf("%s: signature is `%s`%s", label, app.SignatureStatus, detail))
+		}
+
+		for _, anomaly := range app.Anomalies {
+			if inMonth(anomaly.DetectedAt, start, end) {
+				changes = append(changes, fmt.Sprintf("%s: %s (%s)", label, anomaly.Detail, anomaly.Type))
+			}
+		}
+	}
+
+	sort.Strings(changes)
+	return changes
+}
+
+// busiestDays returns the days with the most version updates, most first
+func busiestDays(updates []datafile.VersionChange) []string {
+	byDay := make(map[string][]string)
+	for _, change := range updates {
+		day := change.Date
+		if t, err := time.Parse(time.RFC3339, change.Date); err == nil {
+			day = t.UTC().Format("2006-01-02")
+		}
+		byDay[day] = append(byDay[day], change.AppName)
+	}
+
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		if len(byDay[days[i]]) != len(byDay[days[j]]) {
+			return len(byDay[days[i]]) > len(byDay[days[j]])
+		}
+		return days[i] < days[j]
+	})
+	if len(days) > busiestDaysShown {
+		days = days[:busiestDaysShown]
+	}
+
+	lines := make([]string, 0, len(days))
+	for _, day := range days {
+		names := uniqueSorted(byDay[day])
+		shown := names
+		more := ""
+		if len(names) > appsPerDayShown {
+			shown = names[:appsPerDayShown]
+			more = fmt.Sprintf(" and %d more", len(names)-appsPerDayShown)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d updates (%s%s)", formatDay(day), len(byDay[day]), strings.Join(shown, ", "), more))
+	}
+	return lines
+}
+
+// uniqueSorted returns names sorted with duplicates (e.g. the Mac and Windows
+// builds of the same app) removed
+func uniqueSorted(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var unique []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+func renderMonthlyReport(start time.Time, growth *monthlyGrowth, newApps, updates []datafile.VersionChange, signingChanges []string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Fleet-maintained apps: %s\n\n", start.Format("January 2006")))
+
+	net := growth.EndCount - growth.StartCount
+	if !growth.HasStartData {
+		net = growth.Added - growth.Removed
+	}
+	sb.WriteString(fmt.Sprintf("The library ended the month with **%d apps** (%d Mac, %d Windows), a net change of **%+d**: %d added and %d removed. Apps shipped **%d version updates**.\n\n",
+		growth.EndCount, growth.EndMac, growth.EndWindows, net, growth.Added, growth.Removed, len(updates)))
+
+	sb.WriteString("## New apps\n\n")
+	if len(newApps) == 0 {
+		sb.WriteString("No new apps this month.\n\n")
+	}
+	for _, change := range newApps {
+		sb.WriteString(fmt.Sprintf("- **%s** %s (%s), added %s\n", change.AppName, change.NewVersion, getPlatformLabel(change.Platform), formatDay(change.Date)))
+	}
+	if len(newApps) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Busiest update days\n\n")
+	days := busiestDays(updates)
+	if len(days) == 0 {
+		sb.WriteString("No version updates this month.\n\n")
+	}
+	for _, line := range days {
+		sb.WriteString("- " + line + "\n")
+	}
+	if len(days) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Notable signing changes\n\n")
+	if len(signingChanges) == 0 {
+		sb.WriteString("No signing changes detected this month.\n\n")
+	}
+	for _, line := range signingChanges {
+		sb.WriteString("- " + line + "\n")
+	}
+	if len(signingChanges) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("_Generated from the data files at https://fmalibrary.com_\n")
+
+	return sb.String()
+}
+
+func getPlatformLabel(platform string) string {
+	if platform == "darwin" {
+		return "Mac"
+	}
+	return "Windows"
+}
+
+// formatDay formats an RFC 3339 timestamp or YYYY-MM-DD date as "Jan 2"
+func formatDay(value string) string {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC().Format("Jan 2")
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Format("Jan 2")
+	}
+	return value
+}
+
+func generateComparison(from, to string) error {
+	fmt.Printf("🔀 Comparing the catalog on %s with %s...\n", from, to)
+
+	versions, err := loadVersions()
+	if err != nil {
+		return fmt.Errorf("failed to load app versions: %w", err)
+	}
+	history, err := loadAllVersionHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load version history: %w", err)
+	}
+	firstSeen, err := loadFirstSeen()
+	if err != nil {
+		return fmt.Errorf("failed to load first-seen dates: %w", err)
+	}
+
+	changes := catalogChanges(history.Changes)
+	before := catalogAsOf(versions.Apps, changes, firstSeen, from)
+	after := catalogAsOf(versions.Apps, changes, firstSeen, to)
+	comparison := compareCatalogs(before, after, changes, from, to)
+
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", reportsDir, err)
+	}
+	base := filepath.Join(reportsDir, fmt.Sprintf("compare-%s-%s", from, to))
+	markdown := renderComparison(comparison)
+	if err := os.WriteFile(base+".md", []byte(markdown), 0644); err != nil {
+		return fmt.Errorf("failed to write %s.md: %w", base, err)
+	}
+	data, err := json.MarshalIndent(comparison, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal comparison: %w", err)
+	}
+	if err := os.WriteFile(base+".json", append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s.json: %w", base, err)
+	}
+
+	fmt.Println()
+	fmt.Print(markdown)
+	fmt.Println()
+	fmt.Printf("✅ Generated: %s.md and %s.json\n", base, base)
+	fmt.Printf("   📝 %d added, %d removed, %d updated, %d platform changes\n",
+		len(comparison.Added), len(comparison.Removed), len(comparison.Updated), len(comparison.PlatformChanges))
+	return nil
+}
+
+// catalogChanges returns changes oldest first, each change recorded more than
+// once (e.g. by both main.go and build_history.go) kept at its earliest date
+func catalogChanges(changes []datafile.VersionChange) []datafile.VersionChange {
+	earliest := make(map[string]int)
+	var unique []datafile.VersionChange
+	for _, change := range changes {
+		if len(change.Date) < len("2006-01-02") {
+			continue
+		}
+		key := change.Slug + "|" + change.OldVersion + "|" + change.NewVersion
+		if i, ok := earliest[key]; ok {
+			if change.Date < unique[i].Date {
+				unique[i].Date = change.Date
+			}
+			continue
+		}
+		earliest[key] = len(unique)
+		unique = append(unique, change)
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return unique[i].Date < unique[j].Date
+	})
+	return unique
+}
+
+// catalogAsOf rebuilds the catalog at the end of date the way the dashboard's
+// time slider does: every later change is undone, newest first, and apps
+// first seen after date are dropped
+func catalogAsOf(current []datafile.AppVersion, changes []datafile.VersionChange, firstSeen map[string]string, date string) map[string]datafile.AppVersion {
+	apps := make(map[string]datafile.AppVersion, len(current))
+	for _, app := range current {
+		apps[app.Slug] = app
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.Date[:len("2006-01-02")] <= date {
+			break
+		}
+		switch {
+		case change.NewVersion == "":
+			// Removed later, so it was still in the catalog
+			apps[change.Slug] = datafile.AppVersion{Slug: change.Slug, Name: change.AppName, Platform: change.Platform, Version: change.OldVersion}
+		case change.OldVersion == "":
+			delete(apps, change.Slug)
+		default:
+			if app, ok := apps[change.Slug]; ok {
+				app.Version = change.OldVersion
+				apps[change.Slug] = app
+			}
+		}
+	}
+	for slug, seen := range firstSeen {
+		if seen > date {
+			delete(apps, slug)
+		}
+	}
+	return apps
+}
+
+func compareCatalogs(before, after map[string]datafile.AppVersion, changes []datafile.VersionChange, from, to string) *catalogComparison {
+	comparison := &catalogComparison{
+		From:            from,
+		To:              to,
+		FromCount:       len(before),
+		ToCount:         len(after),
+		Added:           []datafile.AppVersion{},
+		Removed:         []datafile.AppVersion{},
+		Updated:         []versionDelta{},
+		PlatformChanges: []platformChange{},
+	}
+
+	updates := make(map[string]int)
+	for _, change := range changes {
+		day := change.Date[:len("2006-01-02")]
+		if day > from && day <= to && change.OldVersion != "" && change.NewVersion != "" {
+			updates[change.Slug]++
+		}
+	}
+
+	for slug, app := range after {
+		old, ok := before[slug]
+		switch {
+		case !ok:
+			comparison.Added = append(comparison.Added, app)
+		case old.Version != app.Version:
+			comparison.Updated = append(comparison.Updated, versionDelta{
+				Slug:        slug,
+				Name:        app.Name,
+				Platform:    app.Platform,
+				FromVersion: old.Version,
+				ToVersion:   app.Version,
+				Updates:     updates[slug],
+			})
+		}
+	}
+	for slug, app := range before {
+		if _, ok := after[slug]; !ok {
+			comparison.Removed = append(comparison.Removed, app)
+		}
+	}
+
+	// An app's platforms are its slugs sharing the part before the platform
+	platforms := func(apps map[string]datafile.AppVersion) (map[string][]string, map[string]string) {
+		byApp, names := make(map[string][]string), make(map[string]string)
+		for slug, app := range apps {
+			key, _, _ := strings.Cut(slug, "/")
+			byApp[key] = append(byApp[key], app.Platform)
+			names[key] = app.Name
+		}
+		for _, list := range byApp {
+			sort.Strings(list)
+		}
+		return byApp, names
+	}
+	beforePlatforms, _ := platforms(before)
+	afterPlatforms, names := platforms(after)
+	for key, list := range afterPlatforms {
+		old, ok := beforePlatforms[key]
+		if ok && strings.Join(old, ",") != strings.Join(list, ",") {
+			comparison.PlatformChanges = append(comparison.PlatformChanges, platformChange{
+				App:           key,
+				Name:          names[key],
+				FromPlatforms: old,
+				ToPlatforms:   list,
+			})
+		}
+	}
+
+	byName := func(apps []datafile.AppVersion) {
+		sort.Slice(apps, func(i, j int) bool {
+			if apps[i].Name != apps[j].Name {
+				return apps[i].Name < apps[j].Name
+			}
+			return apps[i].Slug < apps[j].Slug
+		})
+	}
+	byName(comparison.Added)
+	byName(comparison.Removed)
+	sort.Slice(comparison.Updated, func(i, j int) bool {
+		if comparison.Updated[i].Name != comparison.Updated[j].Name {
+			return comparison.Updated[i].Name < comparison.Updated[j].Name
+		}
+		return comparison.Updated[i].Slug < comparison.Updated[j].Slug
+	})
+	sort.Slice(comparison.PlatformChanges, func(i, j int) bool {
+		return comparison.PlatformChanges[i].Name < comparison.PlatformChanges[j].Name
+	})
+
+	return comparison
+}
+
+func renderComparison(c *catalogComparison) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Fleet-maintained apps: %s to %s\n\n", c.From, c.To))
+	sb.WriteString(fmt.Sprintf("The library went from **%d apps** to **%d apps** (%+d): %d added, %d removed and %d with a new version.\n\n",
+		c.FromCount, c.ToCount, c.ToCount-c.FromCount, len(c.Added), len(c.Removed), len(c.Updated)))
+
+	sb.WriteString("## Added\n\n")
+	if len(c.Added) == 0 {
+		sb.WriteString("No apps added.\n")
+	}
+	for _, app := range c.Added {
+		sb.WriteString(fmt.Sprintf("- **%s** %s (%s)\n", app.Name, app.Version, getPlatformLabel(app.Platform)))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Removed\n\n")
+	if len(c.Removed) == 0 {
+		sb.WriteString("No apps removed.\n")
+	}
+	for _, app := range c.Removed {
+		sb.WriteString(fmt.Sprintf("- **%s** %s (%s)\n", app.Name, app.Version, getPlatformLabel(app.Platform)))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Platform changes\n\n")
+	if len(c.PlatformChanges) == 0 {
+		sb.WriteString("No apps gained or lost a platform.\n")
+	}
+	for _, change := range c.PlatformChanges {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s → %s\n", change.Name, platformLabels(change.FromPlatforms), platformLabels(change.ToPlatforms)))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Version changes\n\n")
+	if len(c.Updated) == 0 {
+		sb.WriteString("No version changes.\n\n")
+	} else {
+		sb.WriteString("| App | Platform | " + c.From + " | " + c.To + " | Updates |\n")
+		sb.WriteString("|-----|----------|------|------|---------|\n")
+		for _, delta := range c.Updated {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %d |\n", delta.Name, getPlatformLabel(delta.Platform), delta.FromVersion, delta.ToVersion, delta.Updates))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("_Generated from the data files at https://fmalibrary.com_\n")
+
+	return sb.String()
+}
+
+func platformLabels(platforms []string) string {
+	labels := make([]string, len(platforms))
+	for i, platform := range platforms {
+		labels[i] = getPlatformLabel(platform)
+	}
+	return strings.Join(labels, ", ")
+}
+
+func loadVersions() (*datafile.AppVersions, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+// loadAllVersionHistory reads the current version history and every yearly
+// archive
+func loadAllVersionHistory() (*datafile.VersionHistory, error) {
+	archives, err := filepath.Glob(strings.Replace(versionHistoryArchive, "%d", "*", 1))
+	if err != nil {
+		return nil, err
+	}
+
+	history := &datafile.VersionHistory{Changes: []datafile.VersionChange{}}
+	for _, path := range append([]string{versionHistoryJSON}, archives...) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		data, err = schema.Upgrade(schema.VersionHistory, data)
+		if err != nil {
+			return nil, err
+		}
+
+		var file datafile.VersionHistory
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		history.Changes = append(history.Changes, file.Changes...)
+	}
+
+	return history, nil
+}
+
+// loadFirstSeen returns the YYYY-MM-DD each app was first seen, from
+// app_first_seen.json when build_history.go has written it
+func loadFirstSeen() (map[string]string, error) {
+	data, err := os.ReadFile(firstSeenJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.FirstSeen, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var file datafile.FirstSeen
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	firstSeen := make(map[string]string, len(file.Apps))
+	for _, app := range file.Apps {
+		if len(app.FirstSeen) >= len("2006-01-02") {
+			firstSeen[app.Slug] = app.FirstSeen[:len("2006-01-02")]
+		}
+	}
+	return firstSeen, nil
+}
@@ -0,0 +1,294 @@
+// Package rss implements "fleet-tracker rss", which generates feed.xml;
+// generate_rss.go runs it on its own.
+package rss
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionsJSON       = "data/app_versions.json"
+	versionHistoryJSON = "data/version_history.json"
+	outputRSS          = "feed.xml"
+	siteURL            = "https://fmalibrary.com"
+)
+
+func generateRSS() error {
+	fmt.Println("📡 Generating RSS feed...")
+
+	// Load current versions
+	currentVersions, err := loadVersions()
+	if err != nil {
+		return fmt.Errorf("failed to load current versions: %w", err)
+	}
+
+	// Load version history
+	history, err := loadVersionHistory()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to load version history: %v\n", err)
+		history = &datafile.VersionHistory{Changes: []datafile.VersionChange{}}
+	}
+
+	// Drop repeated entries before they become duplicate feed items
+	changes := dedupeChanges(history.Changes)
+
+	// Assign GUIDs while changes are still in chronological order
+	guids := assignGUIDs(changes)
+
+	// Sort changes by date (newest first)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Date > changes[j].Date
+	})
+
+	// Limit to last 500 changes for RSS feed
+	if len(changes) > 500 {
+		changes = changes[:500]
+	}
+
+	// Generate RSS feed
+	rssContent := generateRSSContent(currentVersions, changes, guids)
+
+	if err := os.WriteFile(outputRSS, []byte(rssContent), 0644); err != nil {
+		return fmt.Errorf("failed to write RSS file: %w", err)
+	}
+
+	fmt.Printf("✅ Generated: %s\n", outputRSS)
+	fmt.Printf("   📝 %d version updates, additions and removals in feed\n", len(changes))
+
+	return nil
+}
+
+func loadVersions() (*datafile.AppVersions, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+func loadVersionHistory() (*datafile.VersionHistory, error) {
+	data, err := os.ReadFile(versionHistoryJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.VersionHistory{Changes: []datafile.VersionChange{}}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.VersionHistory, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var history datafile.VersionHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// dedupeChanges returns the changes in chronological order with identical
+// consecutive changes for the same app removed (e.g. the same bump recorded
+// by both the daily tracker and a history rebuild)
+func dedupeChanges(changes []datafile.VersionChange) []datafile.VersionChange {
+	sorted := make([]datafile.VersionChange, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+
+	lastBySlug := make(map[string]datafile.VersionChange)
+	result := make([]datafile.VersionChange, 0, len(sorted))
+	for _, change := range sorted {
+		if last, exists := lastBySlug[change.Slug]; exists &&
+			last.OldVersion == change.OldVersion && last.NewVersion == change.NewVersion {
+			continue
+		}
+		lastBySlug[change.Slug] = change
+		result = append(result, change)
+	}
+
+	return result
+}
+
+// assignGUIDs builds a stable GUID for each change, keyed by change identity.
+// The first occurrence of a slug/old/new combination keeps the original
+// slug-old-new GUID so existing subscribers don't see it again; later
+// occurrences (a version that was reverted and re-published) get the change
+// date appended so readers treat them as new items.
+func assignGUIDs(changes []datafile.VersionChange) map[datafile.VersionChange]string {
+	guids := make(map[datafile.VersionChange]string, len(changes))
+	seen := make(map[string]bool)
+	for _, change := range changes {
+		guid, ok := guids[change]
+		if !ok {
+			guid = baseGUID(change)
+		}
+		if seen[guid] {
+			dateComponent := change.Date
+			if t, err := time.Parse(time.RFC3339, change.Date); err == nil {
+				dateComponent = t.UTC().Format("20060102T150405Z")
+			}
+			guids[change] = guid + "-" + dateComponent
+			continue
+		}
+		seen[guid] = true
+		guids[change] = guid
+	}
+	return guids
+}
+
+// baseGUID is slug-old-new, or slug-old-removed for an app that was removed
+func baseGUID(change datafile.VersionChange) string {
+	if change.NewVersion == "" {
+		return fmt.Sprintf("%s-%s-removed", change.Slug, change.OldVersion)
+	}
+	return fmt.Sprintf("%s-%s-%s", change.Slug, change.OldVersion, change.NewVersion)
+}
+
+func generateRSSContent(currentVersions *datafile.AppVersions, changes []datafile.VersionChange, guids map[datafile.VersionChange]string) string {
+	lastBuildDate := time.Now().UTC().Format(time.RFC1123Z)
+	if currentVersions != nil && currentVersions.LastUpdated != "" {
+		if t, err := time.Parse(time.RFC3339, currentVersions.LastUpdated); err == nil {
+			lastBuildDate = t.UTC().Format(time.RFC1123Z)
+		}
+	}
+
+	rss := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Fleet-maintained apps</title>
+    <link>` + siteURL + `</link>
+    <description>Track version updates and new app additions for Fleet-maintained apps. Get notified when apps are updated with new versions, when new apps are added to the library or when apps are removed from it.</description>
+    <language>en-us</language>
+    <generator>fleet-apps-growth-tracker ` + escapeXML(buildinfo.GeneratorVersion()) + `</generator>
+    <lastBuildDate>` + lastBuildDate + `</lastBuildDate>
+    <atom:link href="` + siteURL + `/feed.xml" rel="self" type="application/rss+xml"/>
+    <image>
+      <url>` + siteURL + `/cloud-city.png</url>
+      <title>Fleet-maintained apps</title>
+      <link>` + siteURL + `</link>
+    </image>
+`
+
+	// Add items for each version change
+	for _, change := range changes {
+		var title, description string
+		if change.OldVersion == "" {
+			// New app added
+			title = fmt.Sprintf("New App: %s %s (%s)", change.AppName, change.NewVersion, getPlatformLabel(change.Platform))
+			description = fmt.Sprintf("%s has been added to the Fleet-maintained apps library with version %s on %s.", change.AppName, change.NewVersion, formatDate(change.Date))
+		} else if change.NewVersion == "" {
+			// App removed from the library
+			title = fmt.Sprintf("Removed: %s (%s)", change.AppName, getPlatformLabel(change.Platform))
+			description = fmt.Sprintf("%s has been removed from the Fleet-maintained apps library on %s. The last version Fleet maintained was %s.", change.AppName, formatDate(change.Date), change.OldVersion)
+		} else {
+			// Version update
+			title = fmt.Sprintf("%s %s → %s (%s)", change.AppName, change.OldVersion, change.NewVersion, getPlatformLabel(change.Platform))
+			description = fmt.Sprintf("%s has been updated from version %s to %s on %s.", change.AppName, change.OldVersion, change.NewVersion, formatDate(change.Date))
+		}
+
+		if change.InstallerURL != "" {
+			description += fmt.Sprintf(" <a href=\"%s\">Download installer</a>", escapeXML(change.InstallerURL))
+		}
+
+		// Parse date for pubDate
+		pubDate := lastBuildDate
+		if t, err := time.Parse(time.RFC3339, change.Date); err == nil {
+			pubDate = t.UTC().Format(time.RFC1123Z)
+		}
+
+		guid, ok := guids[change]
+		if !ok {
+			guid = baseGUID(change)
+		}
+
+		rss += `    <item>
+      <title>` + escapeXML(title) + `</title>
+      <link>` + siteURL + `</link>
+      <description>` + escapeXML(description) + `</description>
+      <pubDate>` + pubDate + `</pubDate>
+      <guid isPermaLink="false">` + escapeXML(guid) + `</guid>
+    </item>
+`
+	}
+
+	rss += `  </channel>
+</rss>`
+
+	return rss
+}
+
+func getPlatformLabel(platform string) string {
+	if platform == "darwin" {
+		return "Mac"
+	}
+	return "Windows"
+}
+
+func formatDate(dateStr string) string {
+	if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
+		return t.Format("January 2, 2006")
+	}
+	return dateStr
+}
+
+func escapeXML(s string) string {
+	result := ""
+	for _, r := range s {
+		switch r {
+		case '<':
+			result += "&lt;"
+		case '>':
+			result += "&gt;"
+		case '&':
+			result += "&amp;"
+		case '"':
+			result += "&quot;"
+		case '\'':
+			result += "&apos;"
+		default:
+			result += string(r)
+		}
+	}
+	return result
+}
+
+// Main runs fleet-tracker rss with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("rss", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generateRSS(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
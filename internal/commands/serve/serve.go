@@ -0,0 +1,1186 @@
+// Package serve implements "fleet-tracker serve", which serves the site
+// locally; serve.go runs it on its own.
+package serve
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/graphql"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	growthCSV          = "data/apps_growth.csv"
+	versionsJSON       = "data/app_versions.json"
+	versionHistoryJSON = "data/version_history.json"
+	securityInfoJSON   = "data/app_security_info.json"
+)
+
+// record is a single app entry, kept generic so every field in the data files is served as-is
+type record map[string]interface{}
+
+type recordFile struct {
+	LastUpdated string   `json:"lastUpdated"`
+	Apps        []record `json:"apps"`
+}
+
+type historyFile struct {
+	LastUpdated string   `json:"lastUpdated"`
+	Changes     []record `json:"changes"`
+}
+
+// growthPoint is a row of apps_growth.csv. Added and Removed are NetDelta
+// split by sign, not counts of the apps added and removed that day.
+type growthPoint struct {
+	Date     string `json:"date"`
+	Count    int    `json:"appCount"`
+	Added    int    `json:"added"`
+	Mac      int    `json:"macCount"`
+	Windows  int    `json:"windowsCount"`
+	Removed  int    `json:"removed"`
+	NetDelta int    `json:"netChange"`
+}
+
+// errNotFound marks lookups for slugs that aren't in the data
+var errNotFound = errors.New("not found")
+
+// server serves the generated site and JSON API from dir
+type server struct {
+	dir    string
+	maxAge int
+	events *watcher // nil when watching is disabled
+}
+
+// watchedFiles lists the files whose changes are pushed to /api/events; globs are expanded
+var watchedFiles = []string{
+	"data/*.csv",
+	"data/*.json",
+	"data/*.jsonl",
+	"index.html",
+	"feed.xml",
+	"advisory.xml",
+	"releases.ics",
+	"changelog.html",
+}
+
+// staticFiles and staticDirs are the parts of the checkout the site is made
+// of; everything else, such as the Go sources and go.mod, is never served
+var (
+	staticFiles = map[string]bool{
+		"/":               true,
+		"/index.html":     true,
+		"/changelog.html": true,
+		"/feed.xml":       true,
+		"/advisory.xml":   true,
+		"/releases.ics":   true,
+		"/og-image.png":   true,
+	}
+	staticDirs = []string{"/data", "/archive"}
+)
+
+// Main runs fleet-tracker serve with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on (use :8080 to accept connections from other hosts)")
+	maxAge := fs.Int("max-age", 60, "Cache-Control max-age in seconds")
+	watchInterval := fs.Duration("watch-interval", 2*time.Second, "how often to check the data files for changes (0 disables /api/events)")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	s := &server{dir: ".", maxAge: *maxAge}
+	if *watchInterval > 0 {
+		s.events = newWatcher(s.dir, *watchInterval)
+		go s.events.run()
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/apps", s.handleApps)
+	mux.HandleFunc("/api/apps/", s.handleApp)
+	mux.HandleFunc("/api/growth", s.handleGrowth)
+	mux.HandleFunc("/api/security/", s.handleSecurity)
+	mux.HandleFunc("/graphql", s.handleGraphQL)
+	mux.HandleFunc("/graphql/schema", s.handleGraphQLSchema)
+	mux.HandleFunc("/api/events", s.handleEvents)
+	mux.Handle("/", s.staticHandler())
+
+	fmt.Printf("🌐 Serving %s on http://%s\n", common.Dir, displayAddr(*addr))
+	fmt.Println("   📡 /api/apps, /api/apps/{slug}, /api/growth, /api/security/{slug}")
+	fmt.Println("   🔎 /graphql (schema at /graphql/schema)")
+	if s.events != nil {
+		fmt.Printf("   🔔 /api/events (checking for changes every %s)\n", *watchInterval)
+	}
+
+	if err := http.ListenAndServe(*addr, logRequests(mux)); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+// handleApps lists the published apps, optionally filtered by ?platform=
+func (s *server) handleApps(w http.ResponseWriter, r *http.Request) {
+	data, modTime, err := s.loadRecords(versionsJSON, schema.AppVersions)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	if platform := r.URL.Query().Get("platform"); platform != "" {
+		var filtered []record
+		for _, app := range data.Apps {
+			if app["platform"] == platform {
+				filtered = append(filtered, app)
+			}
+		}
+		data.Apps = filtered
+	}
+	if data.Apps == nil {
+		data.Apps = []record{}
+	}
+
+	s.writeJSON(w, r, modTime, map[string]interface{}{
+		"lastUpdated": data.LastUpdated,
+		"count":       len(data.Apps),
+		"apps":        data.Apps,
+	})
+}
+
+// handleApp returns one app together with its version history
+func (s *server) handleApp(w http.ResponseWriter, r *http.Request) {
+	slug := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/apps/"), "/")
+
+	data, modTime, err := s.loadRecords(versionsJSON, schema.AppVersions)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	app := findRecord(data.Apps, slug)
+	if app == nil {
+		s.writeError(w, fmt.Errorf("app %q %w", slug, errNotFound))
+		return
+	}
+
+	history, historyModTime, err := s.loadHistory()
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if historyModTime.After(modTime) {
+		modTime = historyModTime
+	}
+
+	changes := []record{}
+	for _, change := range history.Changes {
+		if change["slug"] == slug {
+			changes = append(changes, change)
+		}
+	}
+
+	s.writeJSON(w, r, modTime, map[string]interface{}{
+		"app":     app,
+		"history": changes,
+	})
+}
+
+// handleGrowth returns the growth time series from apps_growth.csv
+func (s *server) handleGrowth(w http.ResponseWriter, r *http.Request) {
+	path := filepath.Join(s.dir, growthCSV)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		s.writeError(w, fmt.Errorf("failed to read %s: %w", growthCSV, err))
+		return
+	}
+
+	points, err := loadGrowth(path)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, r, info.ModTime(), map[string]interface{}{
+		"points": points,
+	})
+}
+
+// handleSecurity returns the security info collected for one app
+func (s *server) handleSecurity(w http.ResponseWriter, r *http.Request) {
+	slug := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/security/"), "/")
+
+	data, modTime, err := s.loadRecords(securityInfoJSON, schema.SecurityInfo)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	info := findRecord(data.Apps, slug)
+	if info == nil {
+		s.writeError(w, fmt.Errorf("security info for %q %w", slug, errNotFound))
+		return
+	}
+
+	s.writeJSON(w, r, modTime, info)
+}
+
+// handleGraphQL executes a query sent as JSON in a POST body, or as the
+// query, variables and operationName parameters of a GET
+func (s *server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				s.writeGraphQL(w, r, http.StatusBadRequest, time.Time{}, graphQLError("invalid variables: %v", err))
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeGraphQL(w, r, http.StatusBadRequest, time.Time{}, graphQLError("invalid request body: %v", err))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		s.writeGraphQL(w, r, http.StatusMethodNotAllowed, time.Time{}, graphQLError("method %s not allowed", r.Method))
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		s.writeGraphQL(w, r, http.StatusBadRequest, time.Time{}, graphQLError("missing query"))
+		return
+	}
+
+	ds := &dataset{server: s}
+	resp := newGraphQLSchema(ds).Execute(req)
+
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	s.writeGraphQL(w, r, status, ds.modTime, resp)
+}
+
+// handleGraphQLSchema returns the schema in SDL form
+func (s *server) handleGraphQLSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", s.maxAge))
+	fmt.Fprint(w, newGraphQLSchema(&dataset{server: s}).SDL())
+}
+
+// writeGraphQL writes a GraphQL response; successful GETs get the same caching
+// headers as the REST endpoints, since the query is part of the URL
+func (s *server) writeGraphQL(w http.ResponseWriter, r *http.Request, status int, modTime time.Time, resp *graphql.Response) {
+	if status == http.StatusOK && r.Method != http.MethodPost {
+		s.writeJSON(w, r, modTime, resp)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func graphQLError(format string, args ...interface{}) *graphql.Response {
+	return &graphql.Response{Errors: []*graphql.Error{{Message: fmt.Sprintf(format, args...)}}}
+}
+
+// handleEvents streams a "change" event whenever watched files are written,
+// so a locally hosted dashboard can refresh itself
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if s.events == nil {
+		s.writeError(w, fmt.Errorf("live updates are disabled (--watch-interval 0): %w", errNotFound))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, fmt.Errorf("streaming is not supported by this connection"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	events := s.events.subscribe()
+	defer s.events.unsubscribe(events)
+
+	// Ask clients to reconnect after a few seconds if the server restarts
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			fmt.Fprintf(w, "id: %d\nevent: change\ndata: %s\n\n", event.ID, event.Data)
+			flusher.Flush()
+		case <-heartbeat.C:
+			// Comment lines keep proxies from closing an idle connection
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// staticHandler serves the generated site: the files in staticFiles and
+// staticDirs, hiding dotfiles such as data/.cache
+func (s *server) staticHandler() http.Handler {
+	files := http.FileServer(http.Dir(s.dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isStaticPath(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		for _, part := range strings.Split(r.URL.Path, "/") {
+			if strings.HasPrefix(part, ".") {
+				http.NotFound(w, r)
+				return
+			}
+		}
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", s.maxAge))
+		if s.servePrecompressed(w, r) {
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+// isStaticPath reports whether urlPath is part of the generated site
+func isStaticPath(urlPath string) bool {
+	name := path.Clean("/" + urlPath)
+	if staticFiles[name] {
+		return true
+	}
+	for _, dir := range staticDirs {
+		if name == dir || strings.HasPrefix(name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// servePrecompressed answers with the .gz variant written by fleet-tracker
+// compress when the client accepts gzip and the variant is at least as new as
+// the file itself; it reports whether it did
+func (s *server) servePrecompressed(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	name := path.Clean(r.URL.Path)
+	if strings.HasSuffix(r.URL.Path, "/") {
+		name = path.Join(name, "index.html")
+	}
+	source := filepath.Join(s.dir, filepath.FromSlash(name))
+	sourceInfo, err := os.Stat(source)
+	if err != nil || sourceInfo.IsDir() {
+		return false
+	}
+	gzInfo, err := os.Stat(source + ".gz")
+	if err != nil || gzInfo.ModTime().Before(sourceInfo.ModTime()) {
+		return false
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		return false
+	}
+
+	file, err := os.Open(source + ".gz")
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	if contentType := mime.TypeByExtension(filepath.Ext(source)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	http.ServeContent(w, r, name, gzInfo.ModTime(), file)
+	return true
+}
+
+func (s *server) loadRecords(name, kind string) (recordFile, time.Time, error) {
+	var data recordFile
+	modTime, err := s.loadJSON(name, kind, &data)
+	return data, modTime, err
+}
+
+func (s *server) loadHistory() (historyFile, time.Time, error) {
+	var data historyFile
+	modTime, err := s.loadJSON(versionHistoryJSON, schema.VersionHistory, &data)
+	if errors.Is(err, os.ErrNotExist) {
+		return historyFile{}, time.Time{}, nil
+	}
+	return data, modTime, err
+}
+
+// loadJSON reads a data file, upgrading it to the current schema, and returns its modification time
+func (s *server) loadJSON(name, kind string, v interface{}) (time.Time, error) {
+	path := filepath.Join(s.dir, name)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	raw, err = schema.Upgrade(kind, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to upgrade %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return info.ModTime(), nil
+}
+
+// dataset loads the data files on first use during a single GraphQL request
+type dataset struct {
+	server   *server
+	versions *recordFile
+	history  *historyFile
+	security *recordFile
+	growth   []growthPoint
+	modTime  time.Time // newest modification time of the files read
+}
+
+func (d *dataset) touch(modTime time.Time) {
+	if modTime.After(d.modTime) {
+		d.modTime = modTime
+	}
+}
+
+func (d *dataset) apps() ([]record, error) {
+	if d.versions == nil {
+		data, modTime, err := d.server.loadRecords(versionsJSON, schema.AppVersions)
+		if err != nil {
+			return nil, err
+		}
+		d.versions = &data
+		d.touch(modTime)
+	}
+	return d.versions.Apps, nil
+}
+
+func (d *dataset) changes() ([]record, error) {
+	if d.history == nil {
+		data, modTime, err := d.server.loadHistory()
+		if err != nil {
+			return nil, err
+		}
+		d.history = &data
+		d.touch(modTime)
+	}
+	return d.history.Changes, nil
+}
+
+func (d *dataset) securityInfo() ([]record, error) {
+	if d.security == nil {
+		data, modTime, err := d.server.loadRecords(securityInfoJSON, schema.SecurityInfo)
+		if errors.Is(err, os.ErrNotExist) {
+			data, err = recordFile{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.security = &data
+		d.touch(modTime)
+	}
+	return d.security.Apps, nil
+}
+
+func (d *dataset) growthPoints() ([]growthPoint, error) {
+	if d.growth == nil {
+		path := filepath.Join(d.server.dir, growthCSV)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", growthCSV, err)
+		}
+		if d.growth, err = loadGrowth(path); err != nil {
+			return nil, err
+		}
+		d.touch(info.ModTime())
+	}
+	return d.growth, nil
+}
+
+// dateRange filters by the from/to arguments (YYYY-MM-DD or RFC 3339, both inclusive)
+type dateRange struct {
+	from, to time.Time
+}
+
+func newDateRange(args map[string]interface{}, fromArg, toArg string) (dateRange, error) {
+	var r dateRange
+	if v, ok := args[fromArg].(string); ok {
+		t, _, err := parseDateArg(v)
+		if err != nil {
+			return r, fmt.Errorf("invalid %s: %w", fromArg, err)
+		}
+		r.from = t
+	}
+	if v, ok := args[toArg].(string); ok {
+		t, dateOnly, err := parseDateArg(v)
+		if err != nil {
+			return r, fmt.Errorf("invalid %s: %w", toArg, err)
+		}
+		if dateOnly {
+			t = t.Add(24*time.Hour - time.Nanosecond) // include the whole day
+		}
+		r.to = t
+	}
+	return r, nil
+}
+
+func parseDateArg(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// contains reports whether the date or timestamp value falls in the range;
+// unparseable values only match an unbounded range
+func (r dateRange) contains(value string) bool {
+	if r.from.IsZero() && r.to.IsZero() {
+		return true
+	}
+	t, _, err := parseDateArg(value)
+	if err != nil {
+		return false
+	}
+	return (r.from.IsZero() || !t.Before(r.from)) && (r.to.IsZero() || !t.After(r.to))
+}
+
+// slugPlatform returns the platform suffix of a slug such as "1password/darwin"
+func slugPlatform(slug string) string {
+	if i := strings.LastIndex(slug, "/"); i >= 0 {
+		return slug[i+1:]
+	}
+	return ""
+}
+
+func recordString(rec interface{}, key string) string {
+	if r, ok := rec.(record); ok {
+		s, _ := r[key].(string)
+		return s
+	}
+	return ""
+}
+
+// filterChanges returns the version changes matching the slug, platform, date
+// range and limit arguments, newest first
+func filterChanges(changes []record, args map[string]interface{}) ([]record, error) {
+	dates, err := newDateRange(args, "from", "to")
+	if err != nil {
+		return nil, err
+	}
+	slug, _ := args["slug"].(string)
+	platform, _ := args["platform"].(string)
+	limit, _ := args["limit"].(int)
+
+	result := []record{}
+	for _, change := range changes {
+		if slug != "" && change["slug"] != slug {
+			continue
+		}
+		if platform != "" && slugPlatform(recordString(change, "slug")) != platform {
+			continue
+		}
+		if !dates.contains(recordString(change, "date")) {
+			continue
+		}
+		result = append(result, change)
+	}
+
+	// RFC 3339 timestamps in UTC sort chronologically as strings
+	sort.SliceStable(result, func(i, j int) bool {
+		return recordString(result[i], "date") > recordString(result[j], "date")
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// newGraphQLSchema describes the datasets as a GraphQL schema resolved against ds
+func newGraphQLSchema(ds *dataset) *graphql.Schema {
+	changeArgs := map[string]string{"from": "String", "to": "String", "limit": "Int"}
+
+	return &graphql.Schema{
+		Query: "Query",
+		Types: map[string]*graphql.Object{
+			"Query": {Fields: map[string]*graphql.FieldDef{
+				"lastUpdated": {
+					Type:        "String",
+					Description: "When app_versions.json was last regenerated",
+					Resolve: func(_ interface{}, _ map[string]interface{}) (interface{}, error) {
+						if _, err := ds.apps(); err != nil {
+							return nil, err
+						}
+						return ds.versions.LastUpdated, nil
+					},
+				},
+				"apps": {
+					Type:        "[App!]!",
+					Description: "Published apps; changedSince keeps apps with a version change on or after the date",
+					Args:        map[string]string{"platform": "String", "changedSince": "String", "slugs": "[String!]"},
+					Resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+						apps, err := ds.apps()
+						if err != nil {
+							return nil, err
+						}
+
+						var changed map[string]bool
+						if since, ok := args["changedSince"].(string); ok {
+							changes, err := ds.changes()
+							if err != nil {
+								return nil, err
+							}
+							recent, err := filterChanges(changes, map[string]interface{}{"from": since})
+							if err != nil {
+								return nil, fmt.Errorf("invalid changedSince: %w", err)
+							}
+							changed = make(map[string]bool)
+							for _, change := range recent {
+								changed[recordString(change, "slug")] = true
+							}
+						}
+
+						var slugs map[string]bool
+						if list, ok := args["slugs"].([]interface{}); ok {
+							slugs = make(map[string]bool)
+							for _, slug := range list {
+								if s, ok := slug.(string); ok {
+									slugs[s] = true
+								}
+							}
+						}
+
+						platform, _ := args["platform"].(string)
+						result := []record{}
+						for _, app := range apps {
+							slug := recordString(app, "slug")
+							if platform != "" && app["platform"] != platform {
+								continue
+							}
+							if changed != nil && !changed[slug] {
+								continue
+							}
+							if slugs != nil && !slugs[slug] {
+								continue
+							}
+							result = append(result, app)
+						}
+						return result, nil
+					},
+				},
+				"app": {
+					Type: "App",
+					Args: map[string]string{"slug": "String!"},
+					Resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+						apps, err := ds.apps()
+						if err != nil {
+							return nil, err
+						}
+						slug, _ := args["slug"].(string)
+						if app := findRecord(apps, slug); app != nil {
+							return app, nil
+						}
+						return nil, nil
+					},
+				},
+				"versionChanges": {
+					Type:        "[VersionChange!]!",
+					Description: "Version changes, newest first",
+					Args:        map[string]string{"slug": "String", "platform": "String", "from": "String", "to": "String", "limit": "Int"},
+					Resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+						changes, err := ds.changes()
+						if err != nil {
+							return nil, err
+						}
+						return filterChanges(changes, args)
+					},
+				},
+				"growth": {
+					Type:        "[GrowthPoint!]!",
+					Description: "Daily app counts from apps_growth.csv",
+					Args:        map[string]string{"from": "String", "to": "String"},
+					Resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+						points, err := ds.growthPoints()
+						if err != nil {
+							return nil, err
+						}
+						dates, err := newDateRange(args, "from", "to")
+						if err != nil {
+							return nil, err
+						}
+						result := []growthPoint{}
+						for _, point := range points {
+							if dates.contains(point.Date) {
+								result = append(result, point)
+							}
+						}
+						return result, nil
+					},
+				},
+				"securityInfo": {
+					Type:        "[SecurityInfo!]!",
+					Description: "Collected security info; updatedSince filters on when it was last collected",
+					Args:        map[string]string{"slug": "String", "platform": "String", "updatedSince": "String"},
+					Resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+						infos, err := ds.securityInfo()
+						if err != nil {
+							return nil, err
+						}
+						dates, err := newDateRange(args, "updatedSince", "")
+						if err != nil {
+							return nil, err
+						}
+						slug, _ := args["slug"].(string)
+						platform, _ := args["platform"].(string)
+
+						result := []record{}
+						for _, info := range infos {
+							infoSlug := recordString(info, "slug")
+							if slug != "" && infoSlug != slug {
+								continue
+							}
+							if platform != "" && slugPlatform(infoSlug) != platform {
+								continue
+							}
+							if !dates.contains(recordString(info, "lastUpdated")) {
+								continue
+							}
+							result = append(result, info)
+						}
+						return result, nil
+					},
+				},
+			}},
+			"App": {Fields: map[string]*graphql.FieldDef{
+				"slug":              {Type: "String!"},
+				"name":              {Type: "String!"},
+				"platform":          {Type: "String!"},
+				"version":           {Type: "String"},
+				"installerUrl":      {Type: "String"},
+				"arch":              {Type: "String", Description: "macOS: arm64, x86_64 or universal, when the installer URL says"},
+				"variants":          {Type: "[PublishedVersion!]", Description: "macOS: installers of the same version for other architectures"},
+				"publishedVersions": {Type: "[PublishedVersion!]", Description: "Every version Fleet lists, latest first"},
+				"flags":             {Type: "ManifestFlags", Description: "Metadata of the latest version beyond its installer"},
+				"scripts":           {Type: "ScriptHashes", Description: "Install and uninstall scripts of the latest version"},
+				"history": {
+					Type:        "[VersionChange!]!",
+					Description: "Version changes for this app, newest first",
+					Args:        changeArgs,
+					Resolve: func(source interface{}, args map[string]interface{}) (interface{}, error) {
+						changes, err := ds.changes()
+						if err != nil {
+							return nil, err
+						}
+						scoped := map[string]interface{}{"slug": recordString(source, "slug")}
+						for key, value := range args {
+							scoped[key] = value
+						}
+						return filterChanges(changes, scoped)
+					},
+				},
+				"security": {
+					Type: "SecurityInfo",
+					Resolve: func(source interface{}, _ map[string]interface{}) (interface{}, error) {
+						infos, err := ds.securityInfo()
+						if err != nil {
+							return nil, err
+						}
+						if info := findRecord(infos, recordString(source, "slug")); info != nil {
+							return info, nil
+						}
+						return nil, nil
+					},
+				},
+			}},
+			"PublishedVersion": {Fields: map[string]*graphql.FieldDef{
+				"version":      {Type: "String!"},
+				"installerUrl": {Type: "String"},
+				"arch":         {Type: "String"},
+				"scripts":      {Type: "ScriptHashes"},
+			}},
+			"ManifestFlags": {Fields: map[string]*graphql.FieldDef{
+				"defaultCategories": {Type: "[String!]"},
+				"selfService":       {Type: "Boolean"},
+				"automaticInstall":  {Type: "Boolean"},
+			}},
+			"ScriptHashes": {Description: "SHA-256 of the scripts Fleet runs", Fields: map[string]*graphql.FieldDef{
+				"install":   {Type: "String"},
+				"uninstall": {Type: "String"},
+			}},
+			"VersionChange": {Fields: map[string]*graphql.FieldDef{
+				"date":         {Type: "String!"},
+				"slug":         {Type: "String!"},
+				"appName":      {Type: "String"},
+				"platform":     {Type: "String"},
+				"oldVersion":   {Type: "String"},
+				"newVersion":   {Type: "String"},
+				"installerUrl": {Type: "String"},
+			}},
+			"GrowthPoint": {Fields: map[string]*graphql.FieldDef{
+				"date":         {Type: "String!"},
+				"appCount":     {Type: "Int!"},
+				"added":        {Type: "Int!"},
+				"removed":      {Type: "Int!"},
+				"netChange":    {Type: "Int!"},
+				"macCount":     {Type: "Int!"},
+				"windowsCount": {Type: "Int!"},
+			}},
+			"SecurityInfo": {Fields: map[string]*graphql.FieldDef{
+				"slug":         {Type: "String!"},
+				"name":         {Type: "String"},
+				"version":      {Type: "String"},
+				"sha256":       {Type: "String"},
+				"cdhash":       {Type: "String"},
+				"signingId":    {Type: "String"},
+				"teamId":       {Type: "String"},
+				"publisher":    {Type: "String"},
+				"issuer":       {Type: "String"},
+				"serialNumber": {Type: "String"},
+				"thumbprint":   {Type: "String"},
+				"timestamp":    {Type: "String"},
+				"lastUpdated":  {Type: "String"},
+				"downloadTls":  {Type: "DownloadTLS", Description: "How the installer was served"},
+				"apps":         {Type: "[SecurityInfo!]", Description: "Per-app details for suites that install several apps"},
+				"variants":     {Type: "[SecurityInfo!]", Description: "macOS: installers of the same version for other architectures"},
+
+				"installerUrl":    {Type: "String", Description: "Installer that was downloaded"},
+				"installerSha256": {Type: "String"},
+				"installerSize":   {Type: "Float", Description: "Bytes downloaded; a Float since installers can outgrow a 32-bit Int"},
+				"anomalies":       {Type: "[Anomaly!]", Description: "Problems detected when this version was collected"},
+
+				"arch":            {Type: "String"},
+				"architectures":   {Type: "[ArchSlice!]", Description: "macOS: slices of the main executable"},
+				"bundleId":        {Type: "String"},
+				"bundleVersion":   {Type: "String"},
+				"bundlePath":      {Type: "String"},
+				"requiresRosetta": {Type: "Boolean"},
+				"components":      {Type: "[Component!]", Description: "macOS: bundled frameworks and dylibs"},
+				"sandboxed":       {Type: "Boolean"},
+				"hardenedRuntime": {Type: "Boolean"},
+				"signatureFormat": {Type: "String"},
+				"signingTime":     {Type: "String"},
+				"leafCertificate": {Type: "String"},
+				"stapled":         {Type: "Boolean"},
+				"notarizedAt":     {Type: "String"},
+				"persistence":     {Type: "[PersistenceItem!]", Description: "macOS: launch daemons, launch agents and login items the installer added"},
+				"privacyUsage":    {Type: "[PrivacyUsage!]", Description: "macOS: privacy permissions the app asks for"},
+
+				"signatureStatus": {Type: "String", Description: "Windows: WinVerifyTrust verdict"},
+				"signatureDetail": {Type: "String"},
+				"certChain":       {Type: "[Certificate!]", Description: "Windows: Authenticode chain, leaf first"},
+				"payload":         {Type: "[PayloadFile!]", Description: "Windows: files an administrative MSI install extracts"},
+				"productCode":     {Type: "String"},
+				"productVersion":  {Type: "String"},
+				"fileVersion":     {Type: "String"},
+				"executablePath":  {Type: "String"},
+			}},
+			"Anomaly": {Fields: map[string]*graphql.FieldDef{
+				"type":       {Type: "String!"},
+				"detail":     {Type: "String"},
+				"detectedAt": {Type: "String"},
+			}},
+			"ArchSlice": {Fields: map[string]*graphql.FieldDef{
+				"arch":   {Type: "String!"},
+				"sha256": {Type: "String"},
+			}},
+			"Component": {Fields: map[string]*graphql.FieldDef{
+				"path":      {Type: "String!"},
+				"sha256":    {Type: "String"},
+				"signingId": {Type: "String"},
+				"teamId":    {Type: "String"},
+			}},
+			"PersistenceItem": {Fields: map[string]*graphql.FieldDef{
+				"kind":    {Type: "String!", Description: "launch-daemon, launch-agent or login-item"},
+				"path":    {Type: "String!"},
+				"label":   {Type: "String"},
+				"program": {Type: "String"},
+			}},
+			"PrivacyUsage": {Fields: map[string]*graphql.FieldDef{
+				"key":         {Type: "String!"},
+				"service":     {Type: "String"},
+				"description": {Type: "String"},
+			}},
+			"Certificate": {Fields: map[string]*graphql.FieldDef{
+				"subject":    {Type: "String"},
+				"thumbprint": {Type: "String"},
+				"notBefore":  {Type: "String"},
+				"notAfter":   {Type: "String"},
+			}},
+			"PayloadFile": {Fields: map[string]*graphql.FieldDef{
+				"path":   {Type: "String!"},
+				"sha256": {Type: "String"},
+				"size":   {Type: "Float"},
+			}},
+			"DownloadTLS": {Fields: map[string]*graphql.FieldDef{
+				"host":        {Type: "String!"},
+				"subject":     {Type: "String"},
+				"issuer":      {Type: "String"},
+				"notAfter":    {Type: "String"},
+				"plainHttp":   {Type: "Boolean"},
+				"expiresSoon": {Type: "Boolean", Description: "The certificate expires within 30 days of collection"},
+			}},
+		},
+	}
+}
+
+func loadGrowth(path string) ([]growthPoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV: %w", err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	points := []growthPoint{}
+	for i, row := range records {
+		if i == 0 || len(row) < 5 {
+			continue // header or malformed row
+		}
+
+		atoi := func(col int) int {
+			if col >= len(row) {
+				return 0
+			}
+			n, _ := strconv.Atoi(row[col])
+			return n
+		}
+
+		point := growthPoint{
+			Date:    row[0],
+			Count:   atoi(1),
+			Added:   atoi(2),
+			Mac:     atoi(3),
+			Windows: atoi(4),
+			Removed: atoi(5),
+		}
+		point.NetDelta = point.Added - point.Removed
+		if len(row) > 6 {
+			point.NetDelta = atoi(6)
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+func findRecord(records []record, slug string) record {
+	for _, rec := range records {
+		if rec["slug"] == slug {
+			return rec
+		}
+	}
+	return nil
+}
+
+// writeJSON writes v with caching headers, answering conditional requests with 304
+func (s *server) writeJSON(w http.ResponseWriter, r *http.Request, modTime time.Time, v interface{}) {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		s.writeError(w, fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", s.maxAge))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	// ServeContent handles ETag/If-None-Match, Last-Modified/If-Modified-Since and HEAD
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, "", modTime, bytes.NewReader(body))
+}
+
+func (s *server) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, errNotFound) || errors.Is(err, os.ErrNotExist) {
+		status = http.StatusNotFound
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// changeEvent is sent to subscribers when watched files change
+type changeEvent struct {
+	ID   int
+	Data []byte
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// watcher polls the watched files and fans change events out to subscribers.
+// Polling keeps it dependency-free and works the same on every platform.
+type watcher struct {
+	dir      string
+	interval time.Duration
+
+	mu          sync.Mutex
+	subscribers map[chan changeEvent]bool
+	lastID      int
+}
+
+func newWatcher(dir string, interval time.Duration) *watcher {
+	return &watcher{
+		dir:         dir,
+		interval:    interval,
+		subscribers: make(map[chan changeEvent]bool),
+	}
+}
+
+func (w *watcher) subscribe() chan changeEvent {
+	ch := make(chan changeEvent, 8)
+	w.mu.Lock()
+	w.subscribers[ch] = true
+	w.mu.Unlock()
+	return ch
+}
+
+func (w *watcher) unsubscribe(ch chan changeEvent) {
+	w.mu.Lock()
+	delete(w.subscribers, ch)
+	w.mu.Unlock()
+}
+
+// run checks the watched files every interval and broadcasts the ones that
+// were created, modified or removed since the last check
+func (w *watcher) run() {
+	previous := w.snapshot()
+	for range time.Tick(w.interval) {
+		current := w.snapshot()
+
+		var changed []string
+		for path, state := range current {
+			if old, ok := previous[path]; !ok || old != state {
+				changed = append(changed, path)
+			}
+		}
+		for path := range previous {
+			if _, ok := current[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+		previous = current
+
+		if len(changed) > 0 {
+			sort.Strings(changed)
+			w.broadcast(changed)
+		}
+	}
+}
+
+func (w *watcher) snapshot() map[string]fileState {
+	files := make(map[string]fileState)
+	for _, pattern := range watchedFiles {
+		matches, _ := filepath.Glob(filepath.Join(w.dir, pattern))
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(w.dir, path)
+			if err != nil {
+				rel = path
+			}
+			files[filepath.ToSlash(rel)] = fileState{size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+	return files
+}
+
+func (w *watcher) broadcast(files []string) {
+	data, err := json.Marshal(map[string]interface{}{
+		"files": files,
+		"time":  time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastID++
+	event := changeEvent{ID: w.lastID, Data: data}
+	for ch := range w.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// A slow client misses the event rather than blocking the others
+		}
+	}
+
+	fmt.Printf("   🔔 Changed: %s (%d subscriber(s))\n", strings.Join(files, ", "), len(w.subscribers))
+}
+
+// logRequests prints one line per request
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		fmt.Printf("   %s %s (%s)\n", r.Method, r.URL.Path, time.Since(start).Round(time.Millisecond))
+	})
+}
@@ -0,0 +1,150 @@
+// Package snapshot implements "fleet-tracker snapshot", which builds the
+// monthly data release tarball; snapshot.go runs it on its own.
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+)
+
+const snapshotsDir = "snapshots"
+
+// snapshotFiles lists the datasets included in a snapshot; globs are expanded
+var snapshotFiles = []string{
+	"data/*.csv",
+	"data/*.json",
+	"data/*.jsonl",
+	"data/scripts/*/*/*",
+	"archive/*/*/*/*",
+	"feed.xml",
+	"advisory.xml",
+	"releases.ics",
+	"CHANGELOG.md",
+	"fleetctl/*.yml",
+	"intune/*",
+	"munki/*.plist",
+	"jamf/*.sh",
+	"SHA256SUMS",
+}
+
+// Main runs fleet-tracker snapshot with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	month := fs.String("month", "", "month the snapshot closes as YYYY-MM (default: the previous month)")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	start, err := snapshotMonth(*month, time.Now().UTC())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(2)
+	}
+	if err := createSnapshot(start); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// snapshotMonth returns the first day of the month the snapshot closes: the
+// given YYYY-MM, or the month before now
+func snapshotMonth(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	t, err := time.Parse("2006-01", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --month %q (want YYYY-MM)", value)
+	}
+	return t, nil
+}
+
+// snapshotTag is the release tag for the month starting at start, e.g. data-2025.06
+func snapshotTag(start time.Time) string {
+	return "data-" + start.Format("2006.01")
+}
+
+func createSnapshot(start time.Time) error {
+	tag := snapshotTag(start)
+	fmt.Printf("📦 Creating snapshot %s...\n", tag)
+
+	var files []string
+	for _, pattern := range snapshotFiles {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("failed to expand %s: %w", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no datasets found; run from the repository root")
+	}
+	sort.Strings(files)
+
+	if err := os.MkdirAll(snapshotsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", snapshotsDir, err)
+	}
+	output := filepath.Join(snapshotsDir, tag+".tar.gz")
+	if err := writeArchive(output, tag, files, start.AddDate(0, 1, 0)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Printf("✅ Generated: %s\n", output)
+	fmt.Printf("   📝 %d files\n", len(files))
+
+	return nil
+}
+
+// writeArchive writes files under a top-level directory named root, with
+// every timestamp set to modTime
+func writeArchive(output, root string, files []string, modTime time.Time) error {
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	gz.ModTime = modTime
+	tw := tar.NewWriter(gz)
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name:    root + "/" + filepath.ToSlash(path),
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: modTime,
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,224 @@
+// Package trackfailures implements "fleet-tracker track-failures", which files
+// and closes collection failure issues; track_failures.go runs it on its own.
+package trackfailures
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+)
+
+const (
+	failureLabel = "collection-failure"
+
+	// The hidden comment that ties an issue to the app it tracks, so renaming
+	// the issue or the app doesn't lose the link
+	markerPrefix = "<!-- collection-failure: "
+	markerSuffix = " -->"
+)
+
+// trackedIssue is the part of a GitHub issue the tracker reads
+type trackedIssue struct {
+	Number      int             `json:"number"`
+	Body        string          `json:"body"`
+	PullRequest json.RawMessage `json:"pull_request,omitempty"`
+}
+
+var ghClient = github.NewClient()
+
+func trackFailures(resultsPath, repo string, dryRun bool) error {
+	fmt.Println("🩺 Updating collection failure issues...")
+
+	results, err := loadResults(resultsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", resultsPath, err)
+	}
+	if results == nil {
+		fmt.Printf("⏭️  No results at %s, the collector stopped before processing any app\n", resultsPath)
+		return nil
+	}
+
+	issues, err := openIssues(repo)
+	if err != nil {
+		return fmt.Errorf("failed to list open issues: %w", err)
+	}
+	fmt.Printf("   📋 %d apps in the results, %d open failure issues\n", len(results.Apps), len(issues))
+
+	filed, closed, ongoing := 0, 0, 0
+	for _, app := range results.Apps {
+		number, tracked := issues[app.Slug]
+		switch {
+		case app.Error != "" && !tracked:
+			fmt.Printf("  🐛 Filing an issue for %s %s\n", app.Slug, app.Version)
+			if !dryRun {
+				if err := fileIssue(repo, app); err != nil {
+					return fmt.Errorf("failed to file an issue for %s: %w", app.Slug, err)
+				}
+			}
+			filed++
+		case app.Error != "":
+			ongoing++
+		case tracked:
+			fmt.Printf("  ✅ Closing #%d, %s %s was collected\n", number, app.Slug, app.Version)
+			if !dryRun {
+				if err := closeIssue(repo, number, app); err != nil {
+					return fmt.Errorf("failed to close #%d: %w", number, err)
+				}
+			}
+			closed++
+		}
+	}
+
+	fmt.Printf("✅ Filed %d, closed %d, %d still failing\n", filed, closed, ongoing)
+	if dryRun {
+		fmt.Println("   (dry run, no issues were changed)")
+	}
+
+	return nil
+}
+
+// openIssues returns the number of the open failure issue of each app
+func openIssues(repo string) (map[string]int, error) {
+	issues := make(map[string]int)
+	url := fmt.Sprintf("%s/repos/%s/issues?state=open&labels=%s&per_page=100", github.APIBase(), repo, failureLabel)
+	err := ghClient.GetPages(url, func(page int, body []byte) error {
+		var list []trackedIssue
+		if err := json.Unmarshal(body, &list); err != nil {
+			return err
+		}
+		for _, issue := range list {
+			// The issues endpoint also lists pull requests
+			if issue.PullRequest != nil {
+				continue
+			}
+			if slug := issueSlug(issue.Body); slug != "" {
+				issues[slug] = issue.Number
+			}
+		}
+		return nil
+	})
+	return issues, err
+}
+
+// issueSlug returns the app slug recorded in an issue body's marker
+func issueSlug(body string) string {
+	_, rest, ok := strings.Cut(body, markerPrefix)
+	if !ok {
+		return ""
+	}
+	slug, _, ok := strings.Cut(rest, markerSuffix)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(slug)
+}
+
+func fileIssue(repo string, app datafile.CollectionResult) error {
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Collecting the security info of **%s** %s (`%s`) failed:\n\n", app.Name, app.Version, app.Slug))
+	body.WriteString("```\n" + app.Error + "\n```\n\n")
+	if run := runURL(); run != "" {
+		body.WriteString(fmt.Sprintf("First seen in %s. ", run))
+	}
+	body.WriteString("This issue is closed automatically once the app is collected again.\n\n")
+	body.WriteString(markerPrefix + app.Slug + markerSuffix + "\n")
+
+	payload, err := json.Marshal(map[string]any{
+		"title":  fmt.Sprintf("Security info collection failing: %s (%s)", app.Name, app.Slug),
+		"body":   body.String(),
+		"labels": []string{failureLabel},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = ghClient.Post(fmt.Sprintf("%s/repos/%s/issues", github.APIBase(), repo), payload)
+	return err
+}
+
+// closeIssue comments on issue number that app recovered, then closes it
+func closeIssue(repo string, number int, app datafile.CollectionResult) error {
+	comment := fmt.Sprintf("%s %s was collected successfully", app.Name, app.Version)
+	if run := runURL(); run != "" {
+		comment += " in " + run
+	}
+	comment += ", closing."
+
+	payload, err := json.Marshal(map[string]string{"body": comment})
+	if err != nil {
+		return err
+	}
+	issueURL := fmt.Sprintf("%s/repos/%s/issues/%d", github.APIBase(), repo, number)
+	if _, err := ghClient.Post(issueURL+"/comments", payload); err != nil {
+		return err
+	}
+
+	payload, err = json.Marshal(map[string]string{"state": "closed", "state_reason": "completed"})
+	if err != nil {
+		return err
+	}
+	_, err = ghClient.Patch(issueURL, payload)
+	return err
+}
+
+// runURL links the current GitHub Actions run, or is empty outside Actions
+func runURL() string {
+	server, repo, id := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID")
+	if server == "" || repo == "" || id == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/actions/runs/%s", server, repo, id)
+}
+
+// loadResults reads a collector's --results file, or returns nil when the
+// collector didn't write one
+func loadResults(path string) (*datafile.CollectionResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var results datafile.CollectionResults
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
+
+// Main runs fleet-tracker track-failures with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("track-failures", flag.ExitOnError)
+	resultsPath := fs.String("results", "", "results file written by a collector's --results")
+	repo := fs.String("repo", os.Getenv("GITHUB_REPOSITORY"), "repository the issues are filed in, as owner/name")
+	dryRun := fs.Bool("dry-run", false, "print the issues that would be filed and closed without changing them")
+	common.Register(fs)
+	fs.Parse(args)
+	if *resultsPath == "" || *repo == "" {
+		fmt.Fprintln(os.Stderr, "Usage: fleet-tracker track-failures --results <path> [--repo owner/name] [--dry-run]")
+		os.Exit(2)
+	}
+
+	ghClient.SetToken(common.GitHubToken)
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !ghClient.Authenticated() && !*dryRun {
+		fmt.Fprintln(os.Stderr, "❌ Error: GITHUB_TOKEN or --github-token is required to file and close issues")
+		os.Exit(1)
+	}
+
+	if err := trackFailures(*resultsPath, *repo, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
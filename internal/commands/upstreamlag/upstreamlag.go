@@ -0,0 +1,447 @@
+// Package upstreamlag implements "fleet-tracker upstream-lag", which compares
+// apps with their vendors' latest releases; upstream_lag.go runs it on its
+// own.
+package upstreamlag
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const (
+	versionsJSON        = "data/app_versions.json"
+	packageParityJSON   = "data/package_parity.json"
+	upstreamSourcesJSON = "data/upstream_sources.json"
+	upstreamLagJSON     = "data/upstream_lag.json"
+)
+
+// Where an app's upstream version comes from
+const (
+	sourceSparkle    = "sparkle"
+	sourceGitHub     = "github"
+	sourceWinget     = "winget"
+	sourceChocolatey = "chocolatey"
+)
+
+// upstreamSource is an entry of data/upstream_sources.json: the vendor's
+// Sparkle appcast URL or GitHub repository ("owner/repo") for one slug
+type upstreamSource struct {
+	Sparkle string `json:"sparkle,omitempty"`
+	GitHub  string `json:"github,omitempty"`
+}
+
+// upstreamRelease is the vendor's latest version, with the release date of
+// the oldest version newer than Fleet's when the source publishes dates
+type upstreamRelease struct {
+	Version      string
+	Source       string
+	ReleasedAt   time.Time // Latest version
+	NewerSinceAt time.Time // Oldest version newer than Fleet's
+}
+
+// sparkleAppcast is the part of a Sparkle appcast used here; the version may
+// be an element of the item or an attribute of its enclosure
+type sparkleAppcast struct {
+	Items []struct {
+		PubDate      string `xml:"pubDate"`
+		Channel      string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle channel"`
+		ShortVersion string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle shortVersionString"`
+		Version      string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle version"`
+		Enclosure    struct {
+			ShortVersion string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle shortVersionString,attr"`
+			Version      string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle version,attr"`
+		} `xml:"enclosure"`
+	} `xml:"channel>item"`
+}
+
+var (
+	ghClient   = github.NewClient()
+	httpClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+// Main runs fleet-tracker upstream-lag with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("upstream-lag", flag.ExitOnError)
+	common.Register(fs)
+	fs.Parse(args)
+
+	ghClient.SetToken(common.GitHubToken)
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := measureUpstreamLag(time.Now().UTC()); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func measureUpstreamLag(now time.Time) error {
+	fmt.Println("⏱️  Measuring upstream lag...")
+
+	apps, err := loadLagApps()
+	if err != nil {
+		return err
+	}
+	sources, err := loadUpstreamSources()
+	if err != nil {
+		return err
+	}
+	packages, err := loadPackageVersions()
+	if err != nil {
+		return err
+	}
+	previous, err := loadPreviousLag()
+	if err != nil {
+		return err
+	}
+
+	result := datafile.UpstreamLag{
+		SchemaVersion:    schema.Current(schema.UpstreamLag),
+		GeneratorVersion: buildinfo.GeneratorVersion(),
+		LastUpdated:      now.Format(time.RFC3339),
+		Apps:             []datafile.AppLag{},
+	}
+	behind := 0
+	for _, app := range apps {
+		if app.Version == "" {
+			continue
+		}
+
+		var release *upstreamRelease
+		if source, ok := sources[app.Slug]; ok {
+			release, err = fetchUpstreamRelease(source, app.Version)
+			if err != nil {
+				fmt.Printf("   ⚠️  %s: %v\n", app.Slug, err)
+			}
+		}
+		if release == nil {
+			release = packages[app.Slug]
+		}
+		if release == nil {
+			continue
+		}
+
+		lag := datafile.AppLag{
+			Slug:            app.Slug,
+			Name:            app.Name,
+			Platform:        app.Platform,
+			FleetVersion:    app.Version,
+			UpstreamVersion: release.Version,
+			Source:          release.Source,
+			Status:          lagStatus(app.Version, release.Version),
+		}
+		if !release.ReleasedAt.IsZero() {
+			lag.ReleasedAt = release.ReleasedAt.Format(time.RFC3339)
+		}
+		if lag.Status == lagBehind {
+			// Without release dates, Fleet counts as behind from the first run
+			// that saw a newer version
+			since := release.NewerSinceAt
+			if since.IsZero() {
+				since = now
+				if prev, ok := previous[app.Slug]; ok && prev.BehindSince != "" {
+					if t, err := time.Parse(time.RFC3339, prev.BehindSince); err == nil {
+						since = t
+					}
+				}
+			}
+			lag.BehindSince = since.Format(time.RFC3339)
+			lag.LagDays = int(now.Sub(since).Hours() / 24)
+			behind++
+		}
+		result.Apps = append(result.Apps, lag)
+	}
+
+	// Longest lag first
+	sort.SliceStable(result.Apps, func(i, j int) bool {
+		if result.Apps[i].LagDays != result.Apps[j].LagDays {
+			return result.Apps[i].LagDays > result.Apps[j].LagDays
+		}
+		return result.Apps[i].Slug < result.Apps[j].Slug
+	})
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal upstream lag: %w", err)
+	}
+	if err := os.WriteFile(upstreamLagJSON, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", upstreamLagJSON, err)
+	}
+
+	fmt.Printf("✅ Compared %d apps with their vendor's latest version; Fleet is behind on %d\n", len(result.Apps), behind)
+	fmt.Printf("   📝 Wrote %s\n", upstreamLagJSON)
+	return nil
+}
+
+func loadLagApps() ([]datafile.AppVersion, error) {
+	data, err := os.ReadFile(versionsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", versionsJSON, err)
+	}
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s: %w", versionsJSON, err)
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", versionsJSON, err)
+	}
+	sort.Slice(versions.Apps, func(i, j int) bool { return versions.Apps[i].Slug < versions.Apps[j].Slug })
+	return versions.Apps, nil
+}
+
+// loadUpstreamSources reads the optional per-slug sources in upstreamSourcesJSON
+func loadUpstreamSources() (map[string]upstreamSource, error) {
+	data, err := os.ReadFile(upstreamSourcesJSON)
+	if os.IsNotExist(err) {
+		return map[string]upstreamSource{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sources map[string]upstreamSource
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", upstreamSourcesJSON, err)
+	}
+	return sources, nil
+}
+
+// loadPackageVersions returns the winget (or, failing that, Chocolatey)
+// version crossref_packages.go recorded for each Windows app
+func loadPackageVersions() (map[string]*upstreamRelease, error) {
+	releases := make(map[string]*upstreamRelease)
+
+	data, err := os.ReadFile(packageParityJSON)
+	if os.IsNotExist(err) {
+		return releases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.PackageParity, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s: %w", packageParityJSON, err)
+	}
+
+	var parity datafile.PackageParity
+	if err := json.Unmarshal(data, &parity); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", packageParityJSON, err)
+	}
+	for _, app := range parity.Apps {
+		switch {
+		case app.Winget != nil:
+			releases[app.Slug] = &upstreamRelease{Version: app.Winget.Version, Source: sourceWinget}
+		case app.Chocolatey != nil:
+			releases[app.Slug] = &upstreamRelease{Version: app.Chocolatey.Version, Source: sourceChocolatey}
+		}
+	}
+	return releases, nil
+}
+
+// loadPreviousLag returns the last run's results by slug, to carry forward
+// when Fleet was first seen behind
+func loadPreviousLag() (map[string]datafile.AppLag, error) {
+	previous := make(map[string]datafile.AppLag)
+
+	data, err := os.ReadFile(upstreamLagJSON)
+	if os.IsNotExist(err) {
+		return previous, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err = schema.Upgrade(schema.UpstreamLag, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s: %w", upstreamLagJSON, err)
+	}
+
+	var lag datafile.UpstreamLag
+	if err := json.Unmarshal(data, &lag); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", upstreamLagJSON, err)
+	}
+	for _, app := range lag.Apps {
+		previous[app.Slug] = app
+	}
+	return previous, nil
+}
+
+// fetchUpstreamRelease reads the vendor's latest version from the app's source
+func fetchUpstreamRelease(source upstreamSource, fleetVersion string) (*upstreamRelease, error) {
+	switch {
+	case source.Sparkle != "":
+		return sparkleRelease(source.Sparkle, fleetVersion)
+	case source.GitHub != "":
+		return githubRelease(source.GitHub)
+	default:
+		return nil, fmt.Errorf("%s entry has no sparkle or github source", upstreamSourcesJSON)
+	}
+}
+
+// sparkleRelease finds the newest release in a Sparkle appcast, skipping
+// beta and other channels, and the oldest release newer than fleetVersion
+func sparkleRelease(appcastURL, fleetVersion string) (*upstreamRelease, error) {
+	resp, err := httpClient.Get(appcastURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("appcast returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var appcast sparkleAppcast
+	if err := xml.Unmarshal(body, &appcast); err != nil {
+		return nil, fmt.Errorf("failed to parse appcast: %w", err)
+	}
+
+	var release *upstreamRelease
+	var newer []time.Time
+	for _, item := range appcast.Items {
+		if item.Channel != "" {
+			continue
+		}
+		version := firstNonEmpty(item.ShortVersion, item.Enclosure.ShortVersion, item.Version, item.Enclosure.Version)
+		if version == "" {
+			continue
+		}
+		released := parsePubDate(item.PubDate)
+
+		if release == nil || compareVersions(version, release.Version) > 0 {
+			release = &upstreamRelease{Version: version, Source: sourceSparkle, ReleasedAt: released}
+		}
+		if compareVersions(version, fleetVersion) > 0 && !released.IsZero() {
+			newer = append(newer, released)
+		}
+	}
+	for _, released := range newer {
+		if release.NewerSinceAt.IsZero() || released.Before(release.NewerSinceAt) {
+			release.NewerSinceAt = released
+		}
+	}
+	if release == nil {
+		return nil, fmt.Errorf("appcast lists no releases")
+	}
+	return release, nil
+}
+
+// githubRelease reads the latest (non-prerelease) GitHub release of repo
+func githubRelease(repo string) (*upstreamRelease, error) {
+	body, err := ghClient.Get(fmt.Sprintf("%s/repos/%s/releases/latest", github.APIBase(), repo))
+	if err != nil {
+		return nil, err
+	}
+
+	var latest struct {
+		TagName     string `json:"tag_name"`
+		PublishedAt string `json:"published_at"`
+	}
+	if err := json.Unmarshal(body, &latest); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if latest.TagName == "" {
+		return nil, fmt.Errorf("%s has no releases", repo)
+	}
+
+	release := &upstreamRelease{Version: strings.TrimPrefix(latest.TagName, "v"), Source: sourceGitHub}
+	if t, err := time.Parse(time.RFC3339, latest.PublishedAt); err == nil {
+		release.ReleasedAt = t.UTC()
+		release.NewerSinceAt = release.ReleasedAt
+	}
+	return release, nil
+}
+
+// parsePubDate parses an RSS pubDate, or returns the zero time
+func parsePubDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Comparison results, from Fleet's point of view, as in package_parity.json
+const (
+	lagSame   = "same"
+	lagBehind = "fleet-behind"
+	lagAhead  = "fleet-ahead"
+)
+
+// lagStatus compares Fleet's version with the vendor's
+func lagStatus(fleet, upstream string) string {
+	switch c := compareVersions(fleet, upstream); {
+	case c < 0:
+		return lagBehind
+	case c > 0:
+		return lagAhead
+	default:
+		return lagSame
+	}
+}
+
+// compareVersions compares the numeric components of two version strings,
+// e.g. 6.10.2 > 6.9.14, ignoring anything that isn't a digit
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) []int {
+	var parts []int
+	for _, field := range strings.FieldsFunc(version, func(r rune) bool { return !unicode.IsDigit(r) }) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			n = 0
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
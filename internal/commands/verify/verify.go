@@ -0,0 +1,307 @@
+// Package verify implements "fleet-tracker verify", which checks an installer
+// or app against the recorded hashes; verify.go runs it on its own.
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+)
+
+const securityInfoJSON = "data/app_security_info.json"
+
+// hashMatch is the recorded hash a file matched, and the entry recording it
+type hashMatch struct {
+	entry *datafile.AppSecurityInfo
+	what  string // e.g. "installer of 6.3.0"
+}
+
+var (
+	plistExecutable = regexp.MustCompile(`<key>CFBundleExecutable</key>\s*<string>([^<]+)</string>`)
+	pkgTeamID       = regexp.MustCompile(`^\s*1\. Developer ID Installer: .*\(([A-Z0-9]{10})\)\s*$`)
+)
+
+// verifyFile checks path, an installer or an installed app, against the
+// security info recorded for slug and reports whether it passed
+func verifyFile(slug, path string) (bool, error) {
+	security, err := loadSecurityInfo()
+	if err != nil {
+		return false, fmt.Errorf("failed to load %s: %w", securityInfoJSON, err)
+	}
+
+	var entries []*datafile.AppSecurityInfo
+	for _, list := range [][]datafile.AppSecurityInfo{security.Apps, security.Versions} {
+		for i := range list {
+			if list[i].Slug == slug {
+				entries = append(entries, &list[i])
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return false, fmt.Errorf("no security info recorded for %s", slug)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	hashed := path
+	if info.IsDir() {
+		if hashed, err = bundleExecutable(path); err != nil {
+			return false, fmt.Errorf("failed to find the executable of %s: %w", path, err)
+		}
+	}
+
+	fmt.Printf("🔍 Verifying %s against %s (%d version(s) on record)\n\n", path, slug, len(entries))
+
+	sum, err := fileSHA256(hashed)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash %s: %w", hashed, err)
+	}
+
+	passed := true
+	match := findHash(entries, sum)
+	if match != nil {
+		fmt.Printf("   ✅ SHA-256 matches the %s\n", match.what)
+	} else {
+		fmt.Printf("   ❌ SHA-256 %s matches no recorded hash\n", sum)
+		passed = false
+	}
+
+	// Without a hash match, the signer is still compared with the current version's
+	expected := entries[0]
+	if match != nil {
+		expected = match.entry
+	}
+	if ok := checkSigner(expected, path); !ok {
+		passed = false
+	}
+
+	return passed, nil
+}
+
+// findHash looks for sum among the installer, executable, architecture slice
+// and payload hashes of entries, including their variants and suite apps
+func findHash(entries []*datafile.AppSecurityInfo, sum string) *hashMatch {
+	var search func(e *datafile.AppSecurityInfo, version, name string) *hashMatch
+	search = func(e *datafile.AppSecurityInfo, version, name string) *hashMatch {
+		label := fmt.Sprintf("%s %s", name, version)
+		switch {
+		case strings.EqualFold(e.InstallerSha256, sum):
+			return &hashMatch{e, "installer of " + label}
+		case strings.EqualFold(e.Sha256, sum):
+			return &hashMatch{e, "main executable of " + label}
+		}
+		for _, slice := range e.Architectures {
+			if strings.EqualFold(slice.Sha256, sum) {
+				return &hashMatch{e, fmt.Sprintf("%s slice of %s", slice.Arch, label)}
+			}
+		}
+		for _, file := range e.Payload {
+			if strings.EqualFold(file.Sha256, sum) {
+				return &hashMatch{e, fmt.Sprintf("%s installed by %s", file.Path, label)}
+			}
+		}
+		for i := range e.Variants {
+			if m := search(&e.Variants[i], version, name); m != nil {
+				return m
+			}
+		}
+		for i := range e.Apps {
+			if m := search(&e.Apps[i], version, e.Apps[i].Name); m != nil {
+				return m
+			}
+		}
+		return nil
+	}
+
+	for _, e := range entries {
+		if m := search(e, e.Version, e.Name); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// checkSigner compares the Team ID (macOS) or publisher (Windows) of path with
+// the recorded one, when the platform's tools are available to read it
+func checkSigner(expected *datafile.AppSecurityInfo, path string) bool {
+	switch {
+	case expected.TeamID != "":
+		if runtime.GOOS != "darwin" {
+			fmt.Println("   ⏭️  Team ID not checked (requires macOS)")
+			return true
+		}
+		teamID, err := macTeamID(path)
+		if err != nil {
+			fmt.Printf("   ❌ Team ID could not be read: %v\n", err)
+			return false
+		}
+		if teamID != expected.TeamID {
+			fmt.Printf("   ❌ Team ID %s, recorded %s\n", teamID, expected.TeamID)
+			return false
+		}
+		fmt.Printf("   ✅ Team ID %s matches\n", teamID)
+	case expected.Publisher != "":
+		if runtime.GOOS != "windows" {
+			fmt.Println("   ⏭️  Publisher not checked (requires Windows)")
+			return true
+		}
+		publisher, err := windowsPublisher(path)
+		if err != nil {
+			fmt.Printf("   ❌ Publisher could not be read: %v\n", err)
+			return false
+		}
+		if publisher != expected.Publisher {
+			fmt.Printf("   ❌ Publisher %q, recorded %q\n", publisher, expected.Publisher)
+			return false
+		}
+		fmt.Printf("   ✅ Publisher matches (%s)\n", publisher)
+	default:
+		fmt.Println("   ⏭️  No signer recorded")
+	}
+	return true
+}
+
+// macTeamID reads the Team ID signing path: from the Developer ID Installer
+// certificate for flat packages, from the code signature otherwise
+func macTeamID(path string) (string, error) {
+	if strings.HasSuffix(strings.ToLower(path), ".pkg") {
+		output, err := exec.Command("pkgutil", "--check-signature", path).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("pkgutil: %s", strings.TrimSpace(string(output)))
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if m := pkgTeamID.FindStringSubmatch(line); m != nil {
+				return m[1], nil
+			}
+		}
+		return "", fmt.Errorf("no Developer ID Installer certificate")
+	}
+
+	// codesign writes its details to stderr
+	output, err := exec.Command("codesign", "-dv", "--verbose=2", path).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("codesign: %s", strings.TrimSpace(string(output)))
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if teamID, ok := strings.CutPrefix(line, "TeamIdentifier="); ok && teamID != "not set" {
+			return strings.TrimSpace(teamID), nil
+		}
+	}
+	return "", fmt.Errorf("not signed with a Team ID")
+}
+
+// windowsPublisher returns the subject of the Authenticode certificate signing path
+func windowsPublisher(path string) (string, error) {
+	script := fmt.Sprintf("(Get-AuthenticodeSignature -LiteralPath '%s').SignerCertificate.Subject", strings.ReplaceAll(path, "'", "''"))
+	output, err := exec.Command("powershell", "-NoProfile", "-Command", script).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("Get-AuthenticodeSignature: %s", strings.TrimSpace(string(output)))
+	}
+	publisher := strings.TrimSpace(string(output))
+	if publisher == "" {
+		return "", fmt.Errorf("not signed")
+	}
+	return publisher, nil
+}
+
+// bundleExecutable returns the main executable of an app bundle, named by
+// CFBundleExecutable in its Info.plist (read with plutil when the plist is
+// binary) or, failing that, after the bundle
+func bundleExecutable(bundle string) (string, error) {
+	plist := filepath.Join(bundle, "Contents", "Info.plist")
+	name := ""
+	if data, err := os.ReadFile(plist); err == nil {
+		if m := plistExecutable.FindSubmatch(data); m != nil {
+			name = string(m[1])
+		} else if output, err := exec.Command("plutil", "-extract", "CFBundleExecutable", "raw", "-o", "-", plist).Output(); err == nil {
+			name = strings.TrimSpace(string(output))
+		}
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(bundle), ".app")
+	}
+
+	executable := filepath.Join(bundle, "Contents", "MacOS", name)
+	if _, err := os.Stat(executable); err != nil {
+		return "", err
+	}
+	return executable, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// Main runs fleet-tracker verify with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	slug := fs.String("slug", "", "app slug, e.g. zoom/darwin")
+	file := fs.String("file", "", "installer or installed app to verify")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *slug == "" || *file == "" {
+		fmt.Fprintln(os.Stderr, "Usage: fleet-tracker verify --slug <slug> --file <path>")
+		os.Exit(2)
+	}
+
+	passed, err := verifyFile(*slug, *file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !passed {
+		fmt.Println("\n❌ FAIL: doesn't match the published security info")
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ PASS: matches the published security info")
+}
@@ -0,0 +1,2251 @@
+// Package winsecurity implements "fleet-tracker security", which collects
+// security info on Windows apps; cmd/collect-security-info-windows/main.go
+// runs it on its own.
+package winsecurity
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/buildinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/schema"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/summary"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/throttle"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/tlsinfo"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/tracing"
+)
+
+const (
+	securityVersionsJSON = "data/app_versions.json"
+	securityInfoJSON     = "data/app_security_info.json"
+	installerMirrorsJSON = "data/installer_mirrors.json"
+	hashDriftJSONL       = "data/hash_drift.jsonl"
+	tempDir              = "C:\\temp\\fleet-app-install"
+	programFilesDir      = "C:\\Program Files"
+	programFilesX86Dir   = "C:\\Program Files (x86)"
+	diskSpaceMargin      = 1 << 30 // Free space required on top of the installer size
+)
+
+// Anomaly types
+const anomalyUnchangedBinary = "unchanged-binary"
+
+// defaultMaxVersions bounds --all-versions to the most recent published versions per app
+const defaultMaxVersions = 5
+
+// Tracing is enabled when an OTLP endpoint is configured (see internal/tracing).
+// appSpan is the span of the app currently being processed.
+var (
+	tracer  = tracing.NewFromEnv("collect-security-info-windows")
+	appSpan *tracing.Span
+)
+
+// installerMirrors maps an app slug, or "*" for every app, to fallback
+// installer URLs tried when the published one fails (see loadInstallerMirrors)
+var installerMirrors map[string][]string
+
+// runCtx is cancelled on SIGINT/SIGTERM so in-flight downloads and extractions
+// abort instead of running on while progress is saved
+var runCtx, cancelRun = context.WithCancel(context.Background())
+
+// downloadRateLimit caps installer downloads in bytes per second (0 = unlimited)
+var downloadRateLimit int64
+
+// useSandbox runs MSI extraction and EXE installers inside Windows Sandbox
+// (--sandbox), so services and registry changes never reach the runner
+var useSandbox bool
+
+// Main runs fleet-tracker security with the arguments that follow it
+func Main(common cli.Common, args []string) {
+	fs := flag.NewFlagSet("security", flag.ExitOnError)
+	testMode := fs.Bool("test", false, "process only the first app")
+	verifyOnly := fs.Bool("verify-only", false, "re-download and re-hash current installers without installing them")
+	allVersions := fs.Bool("all-versions", false, "also collect security info for older published versions")
+	maxVersions := fs.Int("max-versions", defaultMaxVersions, "with --all-versions, the most recent published versions examined per app (including the latest)")
+	maxBandwidth := fs.String("max-bandwidth", "", "limit installer downloads to this many bytes per second, e.g. 500K or 5M (default unlimited)")
+	fs.BoolVar(&useSandbox, "sandbox", false, "run MSI extraction and EXE installers inside Windows Sandbox and copy the installed files out, keeping the runner clean")
+	resultsPath := fs.String("results", "", "write whether each app was collected to this JSON file, for fleet-tracker track-failures")
+	common.Register(fs)
+	fs.Parse(args)
+
+	if err := common.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("🔒 Collecting Windows App Security Information")
+	fmt.Println("=============================================")
+	fmt.Println()
+
+	rate, err := throttle.ParseRate(*maxBandwidth)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: invalid --max-bandwidth: %v\n", err)
+		os.Exit(1)
+	}
+	if rate > 0 {
+		downloadRateLimit = rate
+		fmt.Printf("🐢 Limiting installer downloads to %s/s\n", *maxBandwidth)
+	}
+
+	installerMirrors, err = loadInstallerMirrors()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error loading %s: %v\n", installerMirrorsJSON, err)
+		os.Exit(1)
+	}
+
+	if useSandbox {
+		if _, err := exec.LookPath(sandboxExecutable); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: --sandbox needs Windows Sandbox (Enable-WindowsOptionalFeature -Online -FeatureName Containers-DisposableClientVM): %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🧪 Running installers inside Windows Sandbox\n")
+	}
+
+	// Load current app versions
+	versions, err := loadAppVersions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error loading app versions: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Load existing security info
+	existingSecurity, err := loadSecurityInfo()
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Error loading existing security info: %v (will reprocess all apps)\n", err)
+	}
+	existingMap := make(map[string]datafile.AppSecurityInfo)
+	existingVersionsMap := make(map[string]datafile.AppSecurityInfo)
+	if existingSecurity != nil {
+		for _, app := range existingSecurity.Apps {
+			existingMap[app.Slug] = app
+		}
+		for _, info := range existingSecurity.Versions {
+			existingVersionsMap[versionKey(info.Slug, info.Version)] = info
+		}
+		fmt.Printf("📋 Loaded %d existing security info entries\n", len(existingMap))
+	} else {
+		fmt.Printf("📋 No existing security info found (starting fresh)\n")
+	}
+
+	// Re-download and re-hash installers without installing anything
+	if *verifyOnly {
+		os.Exit(verifyInstallerHashes(versions, existingMap))
+	}
+
+	// Filter to Windows apps only
+	var windowsApps []datafile.AppVersion
+	for _, app := range versions.Apps {
+		if app.Platform == "windows" && app.InstallerURL != "" {
+			// Check if we need to update this app
+			existing, exists := existingMap[app.Slug]
+			if !exists || existing.Version != app.Version {
+				windowsApps = append(windowsApps, app)
+			}
+		}
+	}
+
+	// Older published versions that have no security info yet
+	var olderVersions []datafile.AppVersion
+	if *allVersions {
+		olderVersions = findOlderVersions(versions.Apps, "windows", *maxVersions, existingVersionsMap)
+	}
+
+	if len(windowsApps) == 0 && len(olderVersions) == 0 {
+		fmt.Println("✅ All Windows apps are up to date. No security info collection needed.")
+		return
+	}
+
+	// Check for test mode (limit to first app)
+	if *testMode {
+		if len(windowsApps) > 0 {
+			fmt.Printf("🧪 TEST MODE: Processing only first app: %s\n\n", windowsApps[0].Name)
+			windowsApps = windowsApps[:1]
+			olderVersions = nil
+		} else {
+			fmt.Printf("🧪 TEST MODE: Processing only first older version: %s (%s)\n\n", olderVersions[0].Name, olderVersions[0].Version)
+			olderVersions = olderVersions[:1]
+		}
+	}
+
+	fmt.Printf("📦 Found %d Windows apps to process\n", len(windowsApps))
+	if *allVersions {
+		fmt.Printf("📦 Found %d older published versions to process (up to %d versions per app)\n", len(olderVersions), *maxVersions)
+	}
+	fmt.Println()
+
+	// Create temp directory
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error creating temp directory: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Set up signal handling to save on interruption
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	// Track collected security info
+	collectedSecurity := make(map[string]datafile.AppSecurityInfo)
+	collectedVersions := make(map[string]datafile.AppSecurityInfo)
+	processedSlugs := make(map[string]bool)
+	processedCount := 0
+
+	// Save function that merges with existing data
+	saveSecurityInfo := func() error {
+		finalSecurityList, finalVersionsList := mergeSecurityInfo(existingMap, existingVersionsMap, collectedSecurity, collectedVersions, processedSlugs, versions.Apps)
+
+		// Save to file
+		securityData := datafile.SecurityInfo{
+			SchemaVersion:    schema.Current(schema.SecurityInfo),
+			GeneratorVersion: buildinfo.GeneratorVersion(),
+			LastUpdated:      time.Now().UTC().Format(time.RFC3339),
+			Apps:             finalSecurityList,
+			Versions:         finalVersionsList,
+		}
+
+		jsonData, err := json.MarshalIndent(securityData, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling security info: %w", err)
+		}
+
+		if err := os.WriteFile(securityInfoJSON, jsonData, 0644); err != nil {
+			return fmt.Errorf("writing security info: %w", err)
+		}
+
+		return nil
+	}
+
+	runSpan := tracer.Start("collect-security-info-windows", nil)
+	runSpan.SetAttr("apps.count", len(windowsApps))
+	if tracer.Enabled() {
+		fmt.Println("📡 Exporting traces via OTLP")
+	}
+
+	// Rows for the GitHub Actions step summary, and the outcome of each app
+	// for the --results file
+	var updatedApps, failedApps, anomalyApps [][]string
+	var results []datafile.CollectionResult
+	writeSummary := func(status string) {
+		if err := writeStepSummary(status, processedCount, len(windowsApps), updatedApps, failedApps, anomalyApps); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to write step summary: %v\n", err)
+		}
+		if err := writeResults(*resultsPath, results); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to write results: %v\n", err)
+		}
+	}
+
+	// Handle interruptions
+	go func() {
+		<-sigChan
+		fmt.Printf("\n⚠️  Interruption detected. Saving progress...\n")
+		cancelRun()
+		cleanupTempFiles()
+		writeSummary("⚠️ Interrupted")
+		runSpan.End(fmt.Errorf("interrupted"))
+		tracer.Flush()
+		if err := saveSecurityInfo(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error saving on interruption: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Progress saved. Processed %d/%d apps before interruption.\n", processedCount, len(windowsApps))
+		os.Exit(0)
+	}()
+
+	// Process each app
+	for i, app := range windowsApps {
+		fmt.Printf("[%d/%d] Processing %s (%s)...\n", i+1, len(windowsApps), app.Name, app.Version)
+
+		appSpan = runSpan.Start("app")
+		appSpan.SetAttr("app.slug", app.Slug)
+		appSpan.SetAttr("app.version", app.Version)
+
+		securityInfo, err := collectSecurityInfoForApp(app)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to collect security info: %v\n", err)
+			failedApps = append(failedApps, []string{app.Name, app.Version, err.Error()})
+			results = append(results, datafile.CollectionResult{Slug: app.Slug, Name: app.Name, Version: app.Version, Error: err.Error()})
+			appSpan.End(err)
+			if err := tracer.Flush(); err != nil {
+				fmt.Printf("  ⚠️  Warning: Failed to export traces: %v\n", err)
+			}
+			// Keep existing info if available
+			if existing, exists := existingMap[app.Slug]; exists {
+				collectedSecurity[app.Slug] = existing
+				processedSlugs[app.Slug] = true
+			}
+			// Save progress even on failure
+			if err := saveSecurityInfo(); err != nil {
+				fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to save progress: %v\n", err)
+			}
+			continue
+		}
+
+		// A new version with byte-identical binaries usually means the upstream metadata is wrong
+		if anomaly := detectUnchangedBinary(existingMap[app.Slug], securityInfo); anomaly != nil {
+			fmt.Printf("  ⚠️  Anomaly: %s\n", anomaly.Detail)
+			securityInfo.Anomalies = append(securityInfo.Anomalies, *anomaly)
+			anomalyApps = append(anomalyApps, []string{app.Name, anomaly.Detail, upstreamHistoryLink(app.Slug)})
+		}
+
+		// Keep the superseded version's hashes for incident response
+		if previous, exists := existingMap[app.Slug]; exists && previous.Version != "" && previous.Version != securityInfo.Version {
+			collectedVersions[versionKey(previous.Slug, previous.Version)] = previous
+		}
+
+		collectedSecurity[app.Slug] = securityInfo
+		processedSlugs[app.Slug] = true
+		processedCount++
+		updatedApps = append(updatedApps, []string{app.Name, existingMap[app.Slug].Version, app.Version, upstreamHistoryLink(app.Slug)})
+		results = append(results, datafile.CollectionResult{Slug: app.Slug, Name: app.Name, Version: app.Version})
+
+		// Save incrementally after each successful collection
+		saveSpan := appSpan.Start("save")
+		err = saveSecurityInfo()
+		saveSpan.End(err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to save progress: %v\n", err)
+		} else {
+			fmt.Printf("  💾 Progress saved (%d/%d apps)\n", processedCount, len(windowsApps))
+		}
+
+		// Commit changes periodically
+		shouldCommit := processedCount == 1 || processedCount%10 == 0 || processedCount == len(windowsApps)
+		if shouldCommit {
+			if err := commitProgress(processedCount, len(windowsApps)); err != nil {
+				fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to commit progress: %v\n", err)
+			} else {
+				fmt.Printf("  📝 Progress committed to repo (%d/%d apps)\n", processedCount, len(windowsApps))
+			}
+		}
+
+		// Clean up after each app
+		cleanupTempFiles()
+
+		appSpan.End(nil)
+		if err := tracer.Flush(); err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to export traces: %v\n", err)
+		}
+	}
+
+	// Older published versions, so orgs pinning a previous release still get hashes
+	olderCount := 0
+	for i, app := range olderVersions {
+		if _, retained := collectedVersions[versionKey(app.Slug, app.Version)]; retained {
+			fmt.Printf("[%d/%d] Skipping %s %s (retained from the previous run)\n", i+1, len(olderVersions), app.Name, app.Version)
+			continue
+		}
+		fmt.Printf("[%d/%d] Processing %s (older version %s)...\n", i+1, len(olderVersions), app.Name, app.Version)
+
+		appSpan = runSpan.Start("version")
+		appSpan.SetAttr("app.slug", app.Slug)
+		appSpan.SetAttr("app.version", app.Version)
+
+		securityInfo, err := collectSecurityInfoForApp(app)
+		appSpan.End(err)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to collect security info: %v\n", err)
+			failedApps = append(failedApps, []string{app.Name, app.Version, err.Error()})
+		} else {
+			collectedVersions[versionKey(app.Slug, app.Version)] = securityInfo
+			olderCount++
+			if err := saveSecurityInfo(); err != nil {
+				fmt.Fprintf(os.Stderr, "  ⚠️  Warning: Failed to save progress: %v\n", err)
+			} else {
+				fmt.Printf("  💾 Progress saved (%d/%d older versions)\n", olderCount, len(olderVersions))
+			}
+		}
+
+		cleanupTempFiles()
+		if err := tracer.Flush(); err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to export traces: %v\n", err)
+		}
+	}
+
+	// Final save
+	if err := saveSecurityInfo(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error saving final security info: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Final commit
+	if err := commitProgress(processedCount, len(windowsApps)); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to commit final progress: %v\n", err)
+	}
+
+	runSpan.End(nil)
+	if err := tracer.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to export traces: %v\n", err)
+	}
+
+	writeSummary("✅ Completed")
+
+	fmt.Printf("\n✅ Successfully processed %d/%d apps\n", processedCount, len(windowsApps))
+	fmt.Printf("✅ Security info saved to: %s\n", securityInfoJSON)
+}
+
+// writeStepSummary reports the run outcome in the GitHub Actions job summary
+// mergeSecurityInfo combines the collected apps and older versions with the
+// existing ones, sorted by slug (and version). Existing apps that weren't
+// processed are kept, whatever their platform, while any platform of the app
+// is still listed; existing versions are kept while their own slug is listed.
+func mergeSecurityInfo(existing, existingVersions, collected, collectedVersions map[string]datafile.AppSecurityInfo, processed map[string]bool, listed []datafile.AppVersion) ([]datafile.AppSecurityInfo, []datafile.AppSecurityInfo) {
+	finalSecurityMap := make(map[string]datafile.AppSecurityInfo)
+	for slug, info := range existing {
+		if processed[slug] {
+			continue
+		}
+		// The slugs in versions include the platform (e.g. "010-editor/windows"),
+		// so match on the base slug to keep the other platform's entries
+		baseSlug := slug
+		if idx := strings.LastIndex(slug, "/"); idx != -1 {
+			baseSlug = slug[:idx]
+		}
+		for _, v := range listed {
+			if strings.HasPrefix(v.Slug, baseSlug+"/") {
+				finalSecurityMap[slug] = info
+				break
+			}
+		}
+	}
+	for slug, info := range collected {
+		finalSecurityMap[slug] = info
+	}
+
+	var finalSecurityList []datafile.AppSecurityInfo
+	for _, app := range finalSecurityMap {
+		finalSecurityList = append(finalSecurityList, app)
+	}
+	sort.Slice(finalSecurityList, func(i, j int) bool {
+		return finalSecurityList[i].Slug < finalSecurityList[j].Slug
+	})
+
+	// Previous versions are kept while their app is still listed, whichever platform collected them
+	listedSlugs := make(map[string]bool)
+	for _, v := range listed {
+		listedSlugs[v.Slug] = true
+	}
+	finalVersionsMap := make(map[string]datafile.AppSecurityInfo)
+	for key, info := range existingVersions {
+		if listedSlugs[info.Slug] {
+			finalVersionsMap[key] = info
+		}
+	}
+	for key, info := range collectedVersions {
+		finalVersionsMap[key] = info
+	}
+	var finalVersionsList []datafile.AppSecurityInfo
+	for _, info := range finalVersionsMap {
+		finalVersionsList = append(finalVersionsList, info)
+	}
+	sort.Slice(finalVersionsList, func(i, j int) bool {
+		if finalVersionsList[i].Slug != finalVersionsList[j].Slug {
+			return finalVersionsList[i].Slug < finalVersionsList[j].Slug
+		}
+		return finalVersionsList[i].Version < finalVersionsList[j].Version
+	})
+
+	return finalSecurityList, finalVersionsList
+}
+
+func writeStepSummary(status string, processed, total int, updated, failed, anomalies [][]string) error {
+	if !summary.Enabled() {
+		return nil
+	}
+
+	var b summary.Builder
+	b.Heading(2, "🔒 Windows App Security Info")
+	b.Line("**Status:** %s  ", status)
+	b.Line("**Apps processed:** %d/%d", processed, total)
+	b.Line("")
+
+	b.Heading(3, fmt.Sprintf("Updated (%d)", len(updated)))
+	if len(updated) == 0 {
+		b.Line("No apps updated.\n")
+	} else {
+		b.Table([]string{"App", "Previous version", "Version", "Upstream diff"}, updated)
+	}
+
+	if len(anomalies) > 0 {
+		b.Heading(3, fmt.Sprintf("Anomalies (%d)", len(anomalies)))
+		b.Table([]string{"App", "Anomaly", "Upstream diff"}, anomalies)
+	}
+
+	b.Heading(3, fmt.Sprintf("Failures (%d)", len(failed)))
+	if len(failed) == 0 {
+		b.Line("None.\n")
+	} else {
+		b.Table([]string{"App", "Version", "Reason"}, failed)
+	}
+
+	return b.Write()
+}
+
+// writeResults writes the outcome of every app processed so far to path, so
+// track_failures.go can file an issue for each failing app and close it once
+// the app is collected again
+func writeResults(path string, results []datafile.CollectionResult) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(datafile.CollectionResults{Apps: results}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// detectUnchangedBinary flags a version bump whose binary hash is identical to
+// the previously collected version's
+func detectUnchangedBinary(previous, current datafile.AppSecurityInfo) *datafile.Anomaly {
+	if previous.Version == "" || previous.Version == current.Version {
+		return nil
+	}
+	if current.Sha256 == "" || previous.Sha256 != current.Sha256 {
+		return nil
+	}
+
+	return &datafile.Anomaly{
+		Type:       anomalyUnchangedBinary,
+		Detail:     fmt.Sprintf("version bump %s → %s without binary change (SHA-256 %s)", previous.Version, current.Version, current.Sha256),
+		DetectedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// findOlderVersions lists the published versions after the latest one, up to
+// maxVersions per app in total, that have no security info yet
+func findOlderVersions(apps []datafile.AppVersion, platform string, maxVersions int, existing map[string]datafile.AppSecurityInfo) []datafile.AppVersion {
+	var older []datafile.AppVersion
+	for _, app := range apps {
+		if app.Platform != platform {
+			continue
+		}
+		for i, published := range app.PublishedVersions {
+			if i == 0 {
+				continue // The latest version is collected by the main pass
+			}
+			if i >= maxVersions {
+				break
+			}
+			if published.InstallerURL == "" || published.Version == app.Version {
+				continue
+			}
+			if _, exists := existing[versionKey(app.Slug, published.Version)]; exists {
+				continue
+			}
+			older = append(older, datafile.AppVersion{
+				Slug:         app.Slug,
+				Name:         app.Name,
+				Platform:     app.Platform,
+				Version:      published.Version,
+				InstallerURL: published.InstallerURL,
+			})
+		}
+	}
+	return older
+}
+
+// versionKey identifies the security info of one version of an app
+func versionKey(slug, version string) string {
+	return slug + "@" + version
+}
+
+// upstreamHistoryLink links to the commit history of an app's manifest in fleetdm/fleet
+func upstreamHistoryLink(slug string) string {
+	return fmt.Sprintf("[history](https://github.com/fleetdm/fleet/commits/main/ee/maintained-apps/outputs/%s.json)", slug)
+}
+
+// verifyInstallerHashes re-downloads the installer of every app whose recorded
+// version is still current and compares its hash with the recorded one, to
+// catch vendors republishing different bytes under the same version and URL.
+// It returns the process exit code: 1 when any installer drifted.
+func verifyInstallerHashes(versions *datafile.AppVersions, existingMap map[string]datafile.AppSecurityInfo) int {
+	fmt.Println("🔎 Verify-only mode: re-hashing current installers (no installation)")
+	fmt.Println()
+
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error creating temp directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(tempDir)
+
+	var drifted, failed [][]string
+	var driftEvents []datafile.HashDrift
+	verified, skipped := 0, 0
+	for _, app := range versions.Apps {
+		if app.Platform != "windows" || app.InstallerURL == "" {
+			continue
+		}
+		existing, exists := existingMap[app.Slug]
+		if !exists || existing.Version != app.Version || existing.InstallerSha256 == "" {
+			skipped++ // nothing recorded for this version yet
+			continue
+		}
+
+		// Re-download the installer that was hashed, which may be a mirror
+		installerURL := app.InstallerURL
+		if existing.InstallerURL != "" {
+			installerURL = existing.InstallerURL
+		}
+
+		fmt.Printf("📦 %s (%s)\n", app.Name, app.Version)
+		installerPath, _, err := downloadInstaller(installerURL, app.Slug)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to download installer: %v\n", err)
+			failed = append(failed, []string{app.Name, app.Version, err.Error()})
+			continue
+		}
+		sha256, err := calculateSHA256(installerPath)
+		os.Remove(installerPath)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Failed to hash installer: %v\n", err)
+			failed = append(failed, []string{app.Name, app.Version, err.Error()})
+			continue
+		}
+
+		verified++
+		if sha256 != existing.InstallerSha256 {
+			fmt.Printf("  ❌ Hash drift: recorded %s, now %s\n", existing.InstallerSha256, sha256)
+			drifted = append(drifted, []string{app.Name, app.Version, existing.InstallerSha256, sha256, installerURL})
+			driftEvents = append(driftEvents, datafile.HashDrift{
+				DetectedAt:     time.Now().UTC().Format(time.RFC3339),
+				Slug:           app.Slug,
+				Name:           app.Name,
+				Version:        app.Version,
+				RecordedSha256: existing.InstallerSha256,
+				CurrentSha256:  sha256,
+				InstallerURL:   installerURL,
+			})
+			continue
+		}
+		fmt.Printf("  ✅ Matches recorded hash\n")
+	}
+
+	fmt.Printf("\n📊 Verified %d installers: %d drifted, %d failed to download, %d skipped (no recorded hash)\n", verified, len(drifted), len(failed), skipped)
+	if err := recordHashDrift(driftEvents); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to record hash drift: %v\n", err)
+	}
+
+	if summary.Enabled() {
+		var b summary.Builder
+		b.Heading(2, "🔎 Windows Installer Hash Verification")
+		b.Line("**Verified:** %d  ", verified)
+		b.Line("**Skipped (no recorded hash):** %d", skipped)
+		b.Line("")
+		b.Heading(3, fmt.Sprintf("Hash drift (%d)", len(drifted)))
+		if len(drifted) == 0 {
+			b.Line("None.\n")
+		} else {
+			b.Table([]string{"App", "Version", "Recorded SHA-256", "Current SHA-256", "Installer URL"}, drifted)
+		}
+		if len(failed) > 0 {
+			b.Heading(3, fmt.Sprintf("Download failures (%d)", len(failed)))
+			b.Table([]string{"App", "Version", "Reason"}, failed)
+		}
+		if err := b.Write(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to write step summary: %v\n", err)
+		}
+	}
+
+	if len(drifted) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// recordHashDrift appends one JSON line per drifted installer to hashDriftJSONL
+func recordHashDrift(events []datafile.HashDrift) error {
+	if len(events) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(hashDriftJSONL, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+func commitProgress(processedCount, totalApps int) error {
+	// Check if we're in a git repository
+	if err := exec.Command("git", "rev-parse", "--git-dir").Run(); err != nil {
+		return nil
+	}
+
+	// Check if there are changes
+	statusCmd := exec.Command("git", "status", "--porcelain", securityInfoJSON)
+	output, err := statusCmd.Output()
+	if err != nil {
+		return fmt.Errorf("checking git status: %w", err)
+	}
+
+	if len(output) == 0 {
+		return nil
+	}
+
+	// Configure git (if not already configured), keeping the identity and
+	// signing key the workflow set up so progress commits are signed too
+	for key, value := range map[string]string{"user.email": "action@github.com", "user.name": "GitHub Action"} {
+		if exec.Command("git", "config", key).Run() != nil {
+			exec.Command("git", "config", "--local", key, value).Run()
+		}
+	}
+
+	// Add the file
+	if err := exec.Command("git", "add", securityInfoJSON).Run(); err != nil {
+		return fmt.Errorf("git add: %w", err)
+	}
+
+	// Commit
+	commitMsg := fmt.Sprintf("Update Windows app security info - %d/%d apps processed", processedCount, totalApps)
+	if err := exec.Command("git", "commit", "-m", commitMsg).Run(); err != nil {
+		return nil
+	}
+
+	// Push (non-blocking)
+	go func() {
+		exec.Command("git", "push").Run()
+	}()
+
+	return nil
+}
+
+func loadAppVersions() (*datafile.AppVersions, error) {
+	data, err := os.ReadFile(securityVersionsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.AppVersions, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions datafile.AppVersions
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+func loadSecurityInfo() (*datafile.SecurityInfo, error) {
+	data, err := os.ReadFile(securityInfoJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &datafile.SecurityInfo{Apps: []datafile.AppSecurityInfo{}}, nil
+		}
+		return nil, err
+	}
+
+	data, err = schema.Upgrade(schema.SecurityInfo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var security datafile.SecurityInfo
+	if err := json.Unmarshal(data, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+func collectSecurityInfoForApp(app datafile.AppVersion) (datafile.AppSecurityInfo, error) {
+	var securityInfo datafile.AppSecurityInfo
+
+	// Download installer
+	span := appSpan.Start("download")
+	installerPath, installerURL, downloadTLS, err := downloadWithMirrors(app.InstallerURL, app.Slug, app.Version)
+	span.End(err)
+	if err != nil {
+		return securityInfo, fmt.Errorf("failed to download installer: %w", err)
+	}
+	defer os.Remove(installerPath)
+	for _, warning := range downloadTLS.Warnings() {
+		fmt.Printf("  ⚠️  Warning: Installer %s\n", warning)
+	}
+	installerSha256, err := calculateSHA256(installerPath)
+	if err != nil {
+		return securityInfo, fmt.Errorf("failed to hash installer: %w", err)
+	}
+	var installerSize int64
+	if info, err := os.Stat(installerPath); err == nil {
+		installerSize = info.Size()
+	}
+
+	// Extract/install app to get the executable
+	span = appSpan.Start("install")
+	exePath, err := extractOrInstallApp(installerPath, app)
+	span.End(err)
+	if err != nil {
+		return securityInfo, fmt.Errorf("failed to extract/install app: %w", err)
+	}
+
+	// Calculate SHA-256
+	span = appSpan.Start("hash")
+	sha256, err := calculateSHA256(exePath)
+	span.End(err)
+	if err != nil {
+		return securityInfo, fmt.Errorf("failed to calculate SHA-256: %w", err)
+	}
+
+	// Get Authenticode signature info using PowerShell
+	span = appSpan.Start("signature")
+	sigInfo, err := getAuthenticodeSignature(exePath)
+	span.End(err)
+	if err != nil {
+		// Log warning but continue - app may be unsigned or tools unavailable
+		// This is acceptable - we still have SHA-256 which is the most important
+		fmt.Printf("  ⚠️  Note: Could not extract signature info (app may be unsigned): %v\n", err)
+		// Continue with just SHA-256 - this is acceptable for unsigned apps
+	} else {
+		fmt.Printf("  🔐 Extracted signature info\n")
+		if sigInfo.Status != "" && sigInfo.Status != signatureValid {
+			fmt.Printf("  ⚠️  Signature is not valid (%s): %s\n", sigInfo.Status, sigInfo.StatusDetail)
+		}
+	}
+
+	// The MSI's product code and the executable's version and location are
+	// what Intune detection rules match on (see generate_intune.go)
+	var productCode, productVersion, executablePath string
+	if strings.EqualFold(filepath.Ext(installerPath), ".msi") {
+		if productCode, productVersion, err = msiProductInfo(installerPath); err != nil {
+			fmt.Printf("  ⚠️  Warning: Could not read the MSI product code: %v\n", err)
+		}
+		executablePath = installRelativePath(exePath)
+	}
+	fileVersion := ""
+	if strings.EqualFold(filepath.Ext(exePath), ".exe") && exePath != installerPath {
+		if fileVersion, err = exeFileVersion(exePath); err != nil {
+			fmt.Printf("  ⚠️  Warning: Could not read the file version: %v\n", err)
+		}
+	}
+
+	securityInfo = datafile.AppSecurityInfo{
+		Slug:            app.Slug,
+		Name:            app.Name,
+		Version:         app.Version,
+		Sha256:          sha256,
+		Publisher:       sigInfo.Publisher,
+		Issuer:          sigInfo.Issuer,
+		SerialNumber:    sigInfo.SerialNumber,
+		Thumbprint:      sigInfo.Thumbprint,
+		Timestamp:       sigInfo.Timestamp,
+		SignatureStatus: sigInfo.Status,
+		SignatureDetail: sigInfo.StatusDetail,
+		CertChain:       sigInfo.Chain,
+		Payload:         msiPayload,
+		ProductCode:     productCode,
+		ProductVersion:  productVersion,
+		FileVersion:     fileVersion,
+		ExecutablePath:  executablePath,
+		InstallerSha256: installerSha256,
+		InstallerSize:   installerSize,
+		InstallerURL:    installerURL,
+		DownloadTLS:     downloadTLS,
+		LastUpdated:     time.Now().UTC().Format(time.RFC3339),
+	}
+
+	// Clean up
+	if err := uninstallApp(app); err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to uninstall app: %v\n", err)
+	}
+
+	return securityInfo, nil
+}
+
+// loadInstallerMirrors reads installerMirrorsJSON, an object mapping app
+// slugs (or "*" for every app) to fallback URL templates in which {version}
+// and {filename} (the last path segment of the published URL) are replaced.
+// A missing file means no mirrors.
+func loadInstallerMirrors() (map[string][]string, error) {
+	data, err := os.ReadFile(installerMirrorsJSON)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var mirrors map[string][]string
+	if err := json.Unmarshal(data, &mirrors); err != nil {
+		return nil, fmt.Errorf("failed to parse mirrors: %w", err)
+	}
+	return mirrors, nil
+}
+
+// mirrorURLs expands the fallback URLs for an installer, the app's own
+// mirrors before the ones for every app
+func mirrorURLs(mirrors map[string][]string, slug, version, installerURL string) []string {
+	filename, _, _ := strings.Cut(installerURL, "?")
+	filename = filename[strings.LastIndex(filename, "/")+1:]
+	replacer := strings.NewReplacer("{version}", version, "{filename}", filename)
+
+	var urls []string
+	for _, template := range append(mirrors[slug], mirrors["*"]...) {
+		if mirror := replacer.Replace(template); mirror != installerURL {
+			urls = append(urls, mirror)
+		}
+	}
+	return urls
+}
+
+// downloadWithMirrors downloads the published installer and, when that fails
+// (a 404, a timeout, a download that stays truncated), each mirror in turn.
+// It returns the URL the installer came from.
+func downloadWithMirrors(installerURL, slug, version string) (string, string, *tlsinfo.Info, error) {
+	filename, downloadTLS, err := downloadInstaller(installerURL, slug)
+	if err == nil {
+		return filename, installerURL, downloadTLS, nil
+	}
+
+	mirrors := mirrorURLs(installerMirrors, slug, version, installerURL)
+	for _, mirror := range mirrors {
+		if runCtx.Err() != nil {
+			break
+		}
+		fmt.Printf("  🪞 %v; trying mirror %s\n", err, mirror)
+		filename, downloadTLS, mirrorErr := downloadInstaller(mirror, slug)
+		if mirrorErr == nil {
+			return filename, mirror, downloadTLS, nil
+		}
+		fmt.Printf("  ⚠️  Warning: Mirror failed: %v\n", mirrorErr)
+	}
+	if len(mirrors) > 0 {
+		return "", "", nil, fmt.Errorf("%w (%d mirrors also failed)", err, len(mirrors))
+	}
+	return "", "", nil, err
+}
+
+// Truncated downloads are retried up to maxDownloadAttempts times in all
+const maxDownloadAttempts = 3
+
+// errTruncated marks a download that ended before Content-Length bytes arrived
+var errTruncated = errors.New("download truncated")
+
+// downloadInstaller downloads url into tempDir, retrying when the transfer is
+// cut short so a partial image doesn't surface later as a confusing mount or
+// extraction failure
+func downloadInstaller(url, slug string) (string, *tlsinfo.Info, error) {
+	fmt.Printf("  📥 Downloading installer...\n")
+	for attempt := 1; ; attempt++ {
+		filename, downloadTLS, err := downloadInstallerOnce(url, slug)
+		if !errors.Is(err, errTruncated) || attempt >= maxDownloadAttempts {
+			return filename, downloadTLS, err
+		}
+		fmt.Printf("  🔁 %v, retrying (%d/%d)...\n", err, attempt+1, maxDownloadAttempts)
+		select {
+		case <-time.After(time.Duration(attempt) * 2 * time.Second):
+		case <-runCtx.Done():
+			return "", nil, runCtx.Err()
+		}
+	}
+}
+
+// downloadInstallerOnce makes a single download attempt, checking the bytes
+// written against Content-Length when the server sent one
+func downloadInstallerOnce(url, slug string) (string, *tlsinfo.Info, error) {
+
+	req, err := http.NewRequestWithContext(runCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := doWithRetryAfter(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("failed to download: status %d", resp.StatusCode)
+	}
+
+	downloadTLS := tlsinfo.FromResponse(resp)
+
+	// Skip the app rather than filling the runner's disk halfway through the run
+	if err := checkDiskSpace(resp.ContentLength); err != nil {
+		return "", nil, err
+	}
+
+	// Determine file extension from URL
+	// Handle URLs with version numbers that might confuse extension detection
+	ext := ""
+
+	// Remove query string and fragment first
+	urlPath := url
+	if idx := strings.Index(urlPath, "?"); idx != -1 {
+		urlPath = urlPath[:idx]
+	}
+	if idx := strings.Index(urlPath, "#"); idx != -1 {
+		urlPath = urlPath[:idx]
+	}
+
+	// Check for known installer extensions in order of preference
+	knownExts := []string{".msi", ".exe", ".zip", ".msix", ".appx"}
+	urlPathLower := strings.ToLower(urlPath)
+
+	// Check for extension at the end of URL
+	for _, knownExt := range knownExts {
+		if strings.HasSuffix(urlPathLower, knownExt) {
+			ext = knownExt
+			break
+		}
+	}
+
+	// If no extension found, try filepath.Ext but filter out version-like extensions
+	if ext == "" {
+		candidateExt := filepath.Ext(urlPath)
+		candidateExtLower := strings.ToLower(candidateExt)
+		// Check if it's a known extension
+		for _, knownExt := range knownExts {
+			if candidateExtLower == knownExt {
+				ext = candidateExt
+				break
+			}
+		}
+		// If extension looks like a version number (starts with digit), try to find real extension
+		if ext == "" && len(candidateExt) > 1 {
+			if candidateExt[1] >= '0' && candidateExt[1] <= '9' {
+				// Try to find extension before version number
+				base := strings.TrimSuffix(urlPath, candidateExt)
+				realExt := filepath.Ext(base)
+				realExtLower := strings.ToLower(realExt)
+				for _, knownExt := range knownExts {
+					if realExtLower == knownExt {
+						ext = realExt
+						break
+					}
+				}
+			}
+		}
+	}
+
+	if ext == "" {
+		ext = ".exe" // Default to .exe
+	}
+
+	filename := filepath.Join(tempDir, fmt.Sprintf("%s%s", strings.ReplaceAll(slug, "/", "_"), ext))
+	out, err := os.Create(filename)
+	if err != nil {
+		return "", nil, err
+	}
+	defer out.Close()
+
+	var body io.Reader = resp.Body
+	if downloadRateLimit > 0 {
+		body = throttle.NewReader(body, downloadRateLimit)
+	}
+	written, err := io.Copy(out, body)
+	if errors.Is(err, io.ErrUnexpectedEOF) || (err == nil && resp.ContentLength > 0 && written != resp.ContentLength) {
+		err = fmt.Errorf("%w: got %d of %d bytes", errTruncated, written, resp.ContentLength)
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(filename)
+		return "", nil, err
+	}
+	out.Close()
+
+	// Verify file was written
+	if info, err := os.Stat(filename); err != nil || info.Size() == 0 {
+		if err == nil {
+			os.Remove(filename)
+			return "", nil, fmt.Errorf("downloaded file is empty")
+		}
+		return "", nil, fmt.Errorf("downloaded file not found: %w", err)
+	}
+
+	// Versioned or extensionless URLs can guess wrong, so trust the content
+	actualExt, err := detectActualFileType(filename, ext)
+	if err == nil && actualExt != "" && actualExt != ext {
+		fmt.Printf("  🔍 Installer is %s, not %s\n", actualExt, ext)
+		newFilename := strings.TrimSuffix(filename, ext) + actualExt
+		if err := os.Rename(filename, newFilename); err != nil {
+			return filename, downloadTLS, nil // Return original filename
+		}
+		return newFilename, downloadTLS, nil
+	}
+
+	return filename, downloadTLS, nil
+}
+
+// Magic bytes at the start of each supported installer type
+var (
+	oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1} // MSI (OLE compound file)
+	peMagic  = []byte("MZ")                                           // EXE (PE)
+	zipMagic = []byte("PK\x03\x04")                                   // ZIP, MSIX and APPX
+)
+
+// detectActualFileType reads the file's magic bytes to determine its actual
+// type. MSIX and APPX packages are ZIP files too, so a ZIP keeps urlExt when
+// it names one of those, or when the archive carries an AppxManifest.xml
+func detectActualFileType(path, urlExt string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	header := make([]byte, len(oleMagic))
+	n, err := io.ReadFull(f, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, oleMagic):
+		return ".msi", nil
+	case bytes.HasPrefix(header, peMagic):
+		return ".exe", nil
+	case bytes.HasPrefix(header, zipMagic):
+		if urlExt == ".msix" || urlExt == ".appx" {
+			return urlExt, nil
+		}
+		if isAppxPackage(path) {
+			return ".msix", nil
+		}
+		return ".zip", nil
+	}
+
+	return "", nil // Unknown type, keep original extension
+}
+
+// isAppxPackage reports whether the ZIP at path is an MSIX/APPX package
+func isAppxPackage(path string) bool {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if strings.EqualFold(f.Name, "AppxManifest.xml") || strings.EqualFold(f.Name, "AppxMetadata/AppxBundleManifest.xml") {
+			return true
+		}
+	}
+	return false
+}
+
+// Downloads honor Retry-After at most this many times, for waits up to maxRetryAfter
+const (
+	maxDownloadWaits = 3
+	maxRetryAfter    = 5 * time.Minute
+)
+
+// doWithRetryAfter sends req and, when the server answers 429 or 503 (or a 403
+// with Retry-After, as GitHub's release CDN does for secondary rate limits),
+// waits as asked and sends it again instead of failing the app
+func doWithRetryAfter(req *http.Request) (*http.Response, error) {
+	for waits := 0; ; waits++ {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		wait, ok := retryAfter(resp)
+		if !ok || waits >= maxDownloadWaits || wait > maxRetryAfter {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		fmt.Printf("  ⏳ %s asked to retry in %s...\n", req.URL.Host, wait.Round(time.Second))
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryAfter returns the Retry-After delay of a throttled response, in
+// seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusForbidden:
+	default:
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return max(time.Duration(seconds)*time.Second, time.Second), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), time.Second), true
+	}
+	return 0, false
+}
+
+func extractOrInstallApp(installerPath string, app datafile.AppVersion) (string, error) {
+	fmt.Printf("  📦 Extracting/installing app...\n")
+	msiPayload = nil
+
+	ext := strings.ToLower(filepath.Ext(installerPath))
+
+	switch ext {
+	case ".msi":
+		// For MSI, we can extract files without installing
+		return extractFromMSI(installerPath, app)
+	case ".exe":
+		// For EXE, try to extract or install
+		return extractFromEXE(installerPath, app)
+	case ".zip":
+		// Extract ZIP
+		return extractFromZIP(installerPath, app)
+	case ".msix", ".appx":
+		// MSIX/APPX are containerized app packages
+		// We can try to extract them or use the package itself if signed
+		return extractFromMSIX(installerPath, app)
+	default:
+		return "", fmt.Errorf("unsupported installer type: %s", ext)
+	}
+}
+
+// msiPayload holds the hashed payload of the last administratively extracted MSI
+var msiPayload []datafile.PayloadFile
+
+func extractFromMSI(msiPath string, app datafile.AppVersion) (string, error) {
+	if useSandbox {
+		exe, err := extractInSandbox(msiPath, app, sandboxMSIScript)
+		if err == nil {
+			hashMSIPayload(filepath.Join(tempDir, "sandbox", "output"), msiPath)
+		}
+		return exe, err
+	}
+
+	// Use msiexec to extract files
+	extractDir := filepath.Join(tempDir, "extracted")
+	os.RemoveAll(extractDir)
+	if err := os.MkdirAll(extractDir, 0755); err != nil {
+		return "", err
+	}
+
+	// Try to extract using msiexec /a (administrative install)
+	// This extracts files without installing
+	// Use /L*v to enable verbose logging to see what's happening
+	cmd := exec.CommandContext(runCtx, "msiexec", "/a", msiPath, "/qn", "TARGETDIR="+extractDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Check if any files were extracted anyway (sometimes msiexec returns error but still extracts)
+		if entries, err := os.ReadDir(extractDir); err == nil && len(entries) > 0 {
+			// Some files were extracted, try to find executable
+			if exe, err := findMainExecutable(extractDir, app); err == nil {
+				hashMSIPayload(extractDir, msiPath)
+				return exe, nil
+			}
+		}
+		// If that didn't work, the MSI might need to be analyzed differently
+		// For some MSIs, we can try to use the MSI itself as it may contain embedded executables
+		// But first, let's check if the MSI file itself is signed
+		if _, err := getAuthenticodeSignature(msiPath); err == nil {
+			// MSI itself is signed, we can use it
+			return msiPath, nil
+		}
+		return "", fmt.Errorf("MSI extraction failed: %w (stderr: %s)", err, stderr.String())
+	}
+
+	// Wait a moment for extraction to complete
+	time.Sleep(3 * time.Second)
+	hashMSIPayload(extractDir, msiPath)
+
+	// MSI extraction often creates a structure like:
+	// extracted/
+	//   ProgramFiles[64]Folder/
+	//     AppName/
+	//       app.exe
+	// So we need to search recursively
+	exePath, err := findMainExecutable(extractDir, app)
+	if err != nil {
+		// Try searching in common MSI extraction directories
+		commonDirs := []string{
+			filepath.Join(extractDir, "ProgramFiles64Folder"),
+			filepath.Join(extractDir, "ProgramFilesFolder"),
+			filepath.Join(extractDir, "ProgramFiles(x86)Folder"),
+			filepath.Join(extractDir, "CommonFilesFolder"),
+			filepath.Join(extractDir, "CommonFiles64Folder"),
+		}
+
+		for _, dir := range commonDirs {
+			if _, err := os.Stat(dir); err == nil {
+				if exe, err := findMainExecutable(dir, app); err == nil {
+					return exe, nil
+				}
+			}
+		}
+
+		// List what was extracted for debugging
+		var extractedFiles []string
+		filepath.Walk(extractDir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				relPath, _ := filepath.Rel(extractDir, path)
+				extractedFiles = append(extractedFiles, relPath)
+			}
+			return nil
+		})
+
+		// As a last resort, check if the MSI itself is signed
+		if _, err := getAuthenticodeSignature(msiPath); err == nil {
+			return msiPath, nil
+		}
+
+		return "", fmt.Errorf("no executable found after MSI extraction: %w (extracted files: %v)", err, extractedFiles[:min(10, len(extractedFiles))])
+	}
+
+	return exePath, nil
+}
+
+// hashMSIPayload hashes every file an administrative install extracted into
+// msiPayload. msiexec /a decompresses the MSI's embedded (and external) CABs,
+// so these are the bytes that land on disk, not just the outer MSI; the copy of
+// the MSI it leaves at the root of the install point is skipped.
+func hashMSIPayload(extractDir, msiPath string) {
+	var payload []datafile.PayloadFile
+	err := filepath.Walk(extractDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(extractDir, path)
+		if err != nil {
+			return err
+		}
+		if !strings.ContainsRune(rel, filepath.Separator) && strings.EqualFold(filepath.Ext(rel), ".msi") {
+			return nil
+		}
+		sum, err := calculateSHA256(path)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", rel, err)
+		}
+		payload = append(payload, datafile.PayloadFile{Path: filepath.ToSlash(rel), Sha256: sum, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("  ⚠️  Warning: Failed to hash MSI payload of %s: %v\n", filepath.Base(msiPath), err)
+		return
+	}
+	msiPayload = payload
+	fmt.Printf("  🧾 Hashed %d files in the MSI payload\n", len(payload))
+}
+
+// msiProductQuery reads ProductCode and ProductVersion from an MSI's
+// Property table through the WindowsInstaller COM object, one per line
+const msiProductQuery = `$installer = New-Object -ComObject WindowsInstaller.Installer
+$db = $installer.GetType().InvokeMember('OpenDatabase', 'InvokeMethod', $null, $installer, @('{{msi}}', 0))
+foreach ($property in 'ProductCode', 'ProductVersion') {
+  $view = $db.GetType().InvokeMember('OpenView', 'InvokeMethod', $null, $db, @("SELECT Value FROM Property WHERE Property = '$property'"))
+  $view.GetType().InvokeMember('Execute', 'InvokeMethod', $null, $view, $null)
+  $record = $view.GetType().InvokeMember('Fetch', 'InvokeMethod', $null, $view, $null)
+  if ($record) { $record.GetType().InvokeMember('StringData', 'GetProperty', $null, $record, 1) } else { '' }
+  $view.GetType().InvokeMember('Close', 'InvokeMethod', $null, $view, $null)
+}
+`
+
+// msiProductInfo returns the ProductCode and ProductVersion of an MSI
+func msiProductInfo(msiPath string) (string, string, error) {
+	script := strings.ReplaceAll(msiProductQuery, "{{msi}}", strings.ReplaceAll(msiPath, "'", "''"))
+	output, err := exec.CommandContext(runCtx, "powershell", "-NoProfile", "-Command", script).Output()
+	if err != nil {
+		return "", "", err
+	}
+	return parseMSIProductInfo(string(output))
+}
+
+// parseMSIProductInfo reads the ProductCode and ProductVersion lines
+// msiProductQuery prints
+func parseMSIProductInfo(output string) (string, string, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || strings.TrimSpace(lines[0]) == "" {
+		return "", "", fmt.Errorf("unexpected output %q", strings.TrimSpace(output))
+	}
+	return strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]), nil
+}
+
+// exeFileVersion returns the FileVersion resource of an executable, built
+// from its numeric parts since the string form may contain commas or text
+func exeFileVersion(exePath string) (string, error) {
+	script := fmt.Sprintf("$v = (Get-Item -LiteralPath '%s').VersionInfo; '{0}.{1}.{2}.{3}' -f $v.FileMajorPart, $v.FileMinorPart, $v.FileBuildPart, $v.FilePrivatePart", strings.ReplaceAll(exePath, "'", "''"))
+	output, err := exec.CommandContext(runCtx, "powershell", "-NoProfile", "-Command", script).Output()
+	if err != nil {
+		return "", err
+	}
+	return parseFileVersion(string(output))
+}
+
+// parseFileVersion reads the version exeFileVersion's script prints, which is
+// 0.0.0.0 for an executable without a version resource
+func parseFileVersion(output string) (string, error) {
+	version := strings.TrimSpace(output)
+	if version == "" || version == "0.0.0.0" {
+		return "", fmt.Errorf("no version resource")
+	}
+	return version, nil
+}
+
+// installRelativePath returns exePath relative to the administrative install
+// point it was extracted to, e.g. "PFiles64/Zoom/bin/Zoom.exe", or "" when it
+// wasn't extracted from an MSI
+func installRelativePath(exePath string) string {
+	for _, root := range []string{filepath.Join(tempDir, "extracted"), filepath.Join(tempDir, "sandbox", "output")} {
+		rel, err := filepath.Rel(root, exePath)
+		if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
+
+func extractFromEXE(exePath string, app datafile.AppVersion) (string, error) {
+	if useSandbox {
+		exe, err := extractInSandbox(exePath, app, sandboxEXEScript)
+		if err == nil {
+			return exe, nil
+		}
+		fmt.Printf("  ⚠️  Warning: Sandboxed install found no executable, hashing the installer instead: %v\n", err)
+	}
+
+	// Many Windows installers are self-extracting archives
+	// For now, we'll use the installer itself as the executable
+	// In a full implementation, you might want to use tools like 7-Zip to extract
+
+	// Check if it's a signed executable we can analyze directly
+	if _, err := getAuthenticodeSignature(exePath); err == nil {
+		return exePath, nil
+	}
+
+	// Try to find if it extracts to a temp location
+	// For now, return the exe itself
+	return exePath, nil
+}
+
+func extractFromMSIX(msixPath string, app datafile.AppVersion) (string, error) {
+	// MSIX files are actually ZIP archives, so we can extract them
+	// But first check if the MSIX itself is signed
+	if _, err := getAuthenticodeSignature(msixPath); err == nil {
+		// MSIX package is signed, we can use it directly
+		return msixPath, nil
+	}
+
+	// Try to extract MSIX (it's a ZIP file)
+	extractDir := filepath.Join(tempDir, "extracted")
+	os.RemoveAll(extractDir)
+	if err := os.MkdirAll(extractDir, 0755); err != nil {
+		return "", err
+	}
+
+	// MSIX files are ZIP archives, extract using PowerShell
+	psScript := fmt.Sprintf("Expand-Archive -Path '%s' -DestinationPath '%s' -Force", msixPath, extractDir)
+	cmd := exec.CommandContext(runCtx, "powershell", "-Command", psScript)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to extract MSIX: %w", err)
+	}
+
+	// Find the main executable in the extracted package
+	return findMainExecutable(extractDir, app)
+}
+
+func extractFromZIP(zipPath string, app datafile.AppVersion) (string, error) {
+	extractDir := filepath.Join(tempDir, "extracted")
+	os.RemoveAll(extractDir)
+	if err := os.MkdirAll(extractDir, 0755); err != nil {
+		return "", err
+	}
+
+	// Use PowerShell to extract ZIP
+	psScript := fmt.Sprintf("Expand-Archive -Path '%s' -DestinationPath '%s' -Force", zipPath, extractDir)
+	cmd := exec.CommandContext(runCtx, "powershell", "-Command", psScript)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to extract ZIP: %w", err)
+	}
+
+	// Wait for extraction to complete
+	time.Sleep(2 * time.Second)
+
+	// Some vendors wrap an MSI in a ZIP; run it through the MSI pipeline. It
+	// extracts into the same directory, so move the MSI out of the way first
+	if msi := findMSIPayload(extractDir, app); msi != "" {
+		fmt.Printf("  📦 ZIP contains %s, extracting it as an MSI\n", filepath.Base(msi))
+		msiPath := filepath.Join(tempDir, strings.ReplaceAll(app.Slug, "/", "_")+"_payload.msi")
+		if err := os.Rename(msi, msiPath); err != nil {
+			return "", fmt.Errorf("failed to move %s out of the ZIP: %w", filepath.Base(msi), err)
+		}
+		return extractFromMSI(msiPath, app)
+	}
+
+	// Check if we extracted a nested archive (e.g., .appxupload which is a ZIP containing .appx)
+	// Look for .appxupload, .appx, .appxbundle, or .msix files
+	var nestedArchives []string
+	_ = filepath.Walk(extractDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			ext := strings.ToLower(filepath.Ext(path))
+			if ext == ".appxupload" || ext == ".appx" || ext == ".appxbundle" || ext == ".msix" {
+				nestedArchives = append(nestedArchives, path)
+			}
+		}
+		return nil
+	})
+
+	// If we found nested archives, extract the first one
+	if len(nestedArchives) > 0 {
+		// .appxupload is a ZIP file, extract it
+		for _, archive := range nestedArchives {
+			ext := strings.ToLower(filepath.Ext(archive))
+			if ext == ".appxupload" {
+				// Extract the .appxupload (it's a ZIP)
+				nestedExtractDir := filepath.Join(tempDir, "nested_extracted")
+				os.RemoveAll(nestedExtractDir)
+				if err := os.MkdirAll(nestedExtractDir, 0755); err == nil {
+					psScript := fmt.Sprintf("Expand-Archive -Path '%s' -DestinationPath '%s' -Force", archive, nestedExtractDir)
+					cmd := exec.CommandContext(runCtx, "powershell", "-Command", psScript)
+					if cmd.Run() == nil {
+						time.Sleep(2 * time.Second)
+						// Look for .appx files in the nested extraction
+						var foundAppx string
+						filepath.Walk(nestedExtractDir, func(path string, info os.FileInfo, err error) error {
+							if err == nil && !info.IsDir() {
+								if strings.ToLower(filepath.Ext(path)) == ".appx" {
+									// Check if the .appx is signed
+									if _, err := getAuthenticodeSignature(path); err == nil {
+										foundAppx = path
+										return filepath.SkipAll // Found signed .appx, use it
+									}
+								}
+							}
+							return nil
+						})
+						if foundAppx != "" {
+							return foundAppx, nil
+						}
+						// Try to find executable in nested extraction
+						if exe, err := findMainExecutable(nestedExtractDir, app); err == nil {
+							return exe, nil
+						}
+					}
+				}
+			} else if ext == ".appx" || ext == ".appxbundle" || ext == ".msix" {
+				// Check if the .appx/.msix itself is signed
+				if _, err := getAuthenticodeSignature(archive); err == nil {
+					return archive, nil
+				}
+			}
+		}
+	}
+
+	return findMainExecutable(extractDir, app)
+}
+
+// findMSIPayload returns the MSI in an extracted archive, preferring one
+// named after the app when there are several, or "" when there is none
+func findMSIPayload(dir string, app datafile.AppVersion) string {
+	var msis []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() && strings.EqualFold(filepath.Ext(path), ".msi") {
+			msis = append(msis, path)
+		}
+		return nil
+	})
+	if len(msis) == 0 {
+		return ""
+	}
+
+	appName := strings.ToLower(strings.ReplaceAll(app.Name, " ", ""))
+	for _, msi := range msis {
+		name := strings.ToLower(strings.ReplaceAll(filepath.Base(msi), " ", ""))
+		if strings.Contains(name, appName) {
+			return msi
+		}
+	}
+	return msis[0]
+}
+
+func findMainExecutable(dir string, app datafile.AppVersion) (string, error) {
+	// Look for .exe, .appx, .appxbundle, .msix files, prioritizing main executables
+	var exeFiles []string
+	var appxFiles []string
+	var mainExes []string // Executables that look like main apps (not helpers, installers, etc.)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		pathLower := strings.ToLower(path)
+		if !info.IsDir() {
+			if strings.HasSuffix(pathLower, ".exe") {
+				exeName := strings.ToLower(filepath.Base(path))
+				// Skip obvious helper/installer files
+				skipPatterns := []string{
+					"installer", "setup", "uninstall", "helper", "service", "daemon",
+					"updater", "update", "launcher", "bootstrapper", "msiexec",
+				}
+				shouldSkip := false
+				for _, pattern := range skipPatterns {
+					if strings.Contains(exeName, pattern) {
+						shouldSkip = true
+						break
+					}
+				}
+
+				if !shouldSkip {
+					mainExes = append(mainExes, path)
+				}
+				exeFiles = append(exeFiles, path)
+			} else if strings.HasSuffix(pathLower, ".appx") || strings.HasSuffix(pathLower, ".appxbundle") || strings.HasSuffix(pathLower, ".msix") {
+				// Check if the appx/msix is signed
+				if _, err := getAuthenticodeSignature(path); err == nil {
+					appxFiles = append(appxFiles, path)
+				}
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	// If we found signed .appx/.msix files, prefer those
+	if len(appxFiles) > 0 {
+		return appxFiles[0], nil
+	}
+
+	if len(exeFiles) == 0 {
+		return "", fmt.Errorf("no executable found in %s", dir)
+	}
+
+	// Prefer executables that match the app name
+	appNameLower := strings.ToLower(app.Name)
+	appNameWords := strings.Fields(appNameLower)
+
+	// First, try main executables that match app name
+	for _, exe := range mainExes {
+		exeName := strings.ToLower(filepath.Base(exe))
+		exeBase := strings.TrimSuffix(exeName, ".exe")
+
+		// Exact match
+		if exeBase == appNameLower {
+			return exe, nil
+		}
+
+		// Check if exe name contains key words from app name
+		matches := 0
+		for _, word := range appNameWords {
+			if len(word) > 2 && strings.Contains(exeBase, word) {
+				matches++
+			}
+		}
+		if matches > 0 {
+			return exe, nil
+		}
+	}
+
+	// If no match in main exes, try all exes
+	for _, exe := range exeFiles {
+		exeName := strings.ToLower(filepath.Base(exe))
+		exeBase := strings.TrimSuffix(exeName, ".exe")
+
+		if exeBase == appNameLower {
+			return exe, nil
+		}
+
+		// Check if exe name contains key words from app name
+		matches := 0
+		for _, word := range appNameWords {
+			if len(word) > 2 && strings.Contains(exeBase, word) {
+				matches++
+			}
+		}
+		if matches > 0 {
+			return exe, nil
+		}
+	}
+
+	// If we have main executables, prefer the first one
+	if len(mainExes) > 0 {
+		return mainExes[0], nil
+	}
+
+	// Return the first executable found
+	return exeFiles[0], nil
+}
+
+// checkDiskSpace makes sure the download directory and the install location can
+// take an installer of size bytes (-1 when the server didn't say) plus a margin
+func checkDiskSpace(size int64) error {
+	required := uint64(diskSpaceMargin)
+	if size > 0 {
+		required += uint64(size)
+	}
+
+	for _, dir := range []string{tempDir, programFilesDir} {
+		free, err := freeDiskSpace(dir)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: Could not check free space on %s: %v\n", dir, err)
+			continue
+		}
+		if free < required {
+			return fmt.Errorf("insufficient disk space on %s: %s free, %s needed", dir, formatSize(free), formatSize(required))
+		}
+	}
+	return nil
+}
+
+// freeDiskSpace returns the bytes available to the current user on the drive holding path
+func freeDiskSpace(path string) (uint64, error) {
+	psScript := fmt.Sprintf("(Get-Item -LiteralPath '%s').PSDrive.Free", strings.ReplaceAll(path, "'", "''"))
+	output, err := exec.Command("powershell", "-NoProfile", "-Command", psScript).Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(output)), 10, 64)
+}
+
+func formatSize(bytes uint64) string {
+	return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+}
+
+func calculateSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type signatureInfo struct {
+	Publisher    string
+	Issuer       string
+	SerialNumber string
+	Thumbprint   string
+	Timestamp    string
+	Status       string // One of the signature* verdicts, empty when unknown
+	StatusDetail string
+	Chain        []datafile.ChainCert // Leaf first; only read via PowerShell
+}
+
+// Signature verdicts recorded in signatureStatus, derived from the
+// WinVerifyTrust result that Get-AuthenticodeSignature reports
+const (
+	signatureValid         = "valid"
+	signatureExpired       = "expired"
+	signatureUntrustedRoot = "untrusted-root"
+	signatureRevoked       = "revoked"
+	signatureHashMismatch  = "hash-mismatch"
+	signatureInvalid       = "invalid"
+)
+
+// signatureVerdict maps a Get-AuthenticodeSignature Status and StatusMessage
+// to a verdict. NotTrusted and UnknownError cover several WinVerifyTrust
+// failures, so those are told apart by the message.
+func signatureVerdict(status, message string) string {
+	switch status {
+	case "":
+		return ""
+	case "Valid":
+		return signatureValid
+	case "HashMismatch":
+		return signatureHashMismatch
+	}
+
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "revoked"):
+		return signatureRevoked
+	case strings.Contains(message, "validity period") || strings.Contains(message, "expired"):
+		return signatureExpired
+	case strings.Contains(message, "root certificate"):
+		return signatureUntrustedRoot
+	}
+	return signatureInvalid
+}
+
+func getAuthenticodeSignature(exePath string) (signatureInfo, error) {
+	var sigInfo signatureInfo
+
+	// Try PowerShell first
+	psResult, psErr := getSignatureViaPowerShell(exePath)
+	if psErr == nil {
+		return psResult, nil
+	}
+
+	// Fallback to signtool.exe if available
+	signtoolResult, signtoolErr := getSignatureViaSigntool(exePath)
+	if signtoolErr == nil {
+		return signtoolResult, nil
+	}
+
+	// Try certutil as another fallback
+	certutilResult, certutilErr := getSignatureViaCertutil(exePath)
+	if certutilErr == nil {
+		return certutilResult, nil
+	}
+
+	// If all methods fail, return a combined error
+	return sigInfo, fmt.Errorf("all signature extraction methods failed: PowerShell: %v, signtool: %v, certutil: %v", psErr, signtoolErr, certutilErr)
+}
+
+func getSignatureViaPowerShell(exePath string) (signatureInfo, error) {
+	var sigInfo signatureInfo
+
+	// Try using Windows PowerShell (powershell.exe) instead of PowerShell Core
+	// First try with explicit module import using a different approach
+	psScriptFile := filepath.Join(tempDir, "get-signature.ps1")
+	defer os.Remove(psScriptFile)
+
+	// Escape backslashes and quotes for PowerShell
+	escapedPath := strings.ReplaceAll(exePath, "`", "``")
+	escapedPath = strings.ReplaceAll(escapedPath, "$", "`$")
+
+	// Try using the cmdlet with explicit error handling and module loading
+	psScript := fmt.Sprintf(`$ErrorActionPreference = "SilentlyContinue"
+try {
+    # Try to use the cmdlet directly - it should auto-load
+    $sig = & { Get-AuthenticodeSignature -FilePath '%s' } 2>&1
+    
+    if ($sig -and $sig.SignerCertificate) {
+        $cert = $sig.SignerCertificate
+        $publisher = $cert.Subject
+        $issuer = $cert.Issuer
+        $serial = $cert.SerialNumber
+        $thumbprint = $cert.Thumbprint
+        $timestamp = if ($sig.TimeStamperCertificate) { $sig.TimeStamperCertificate.Subject } else { "" }
+        $status = "$($sig.Status)"
+        $statusMessage = "$($sig.StatusMessage)" -replace '[|\r\n]+', ' '
+        Write-Output "SIGNATURE|$publisher|$issuer|$serial|$thumbprint|$timestamp|$status|$statusMessage"
+        # Build the chain without revocation checks so WDAC/AppLocker anchors are
+        # recorded even for untrusted or expired signatures
+        $chain = New-Object System.Security.Cryptography.X509Certificates.X509Chain
+        $chain.ChainPolicy.RevocationMode = 'NoCheck'
+        $chain.ChainPolicy.VerificationFlags = 'AllowUnknownCertificateAuthority'
+        [void]$chain.Build($cert)
+        foreach ($element in $chain.ChainElements) {
+            $c = $element.Certificate
+            $subject = $c.Subject -replace '[|\r\n]+', ' '
+            $notBefore = $c.NotBefore.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+            $notAfter = $c.NotAfter.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+            Write-Output "CHAIN|$subject|$($c.Thumbprint)|$notBefore|$notAfter"
+        }
+    } else {
+        Write-Error "No certificate found"
+        exit 1
+    }
+} catch {
+    Write-Error $_.Exception.Message
+    exit 1
+}`, escapedPath)
+
+	if err := os.WriteFile(psScriptFile, []byte(psScript), 0644); err != nil {
+		return sigInfo, fmt.Errorf("failed to create PowerShell script: %w", err)
+	}
+
+	// Try Windows PowerShell first (powershell.exe), then PowerShell Core (pwsh)
+	powershellPaths := []string{"powershell.exe", "pwsh.exe", "powershell"}
+
+	var lastErr error
+	for _, psPath := range powershellPaths {
+		cmd := exec.CommandContext(runCtx, psPath, "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", psScriptFile)
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			if strings.TrimSpace(string(output)) == "" {
+				continue
+			}
+			if sigInfo, ok := parsePowerShellSignature(string(output)); ok {
+				return sigInfo, nil
+			}
+		}
+		lastErr = fmt.Errorf("%s failed: %w (output: %s)", psPath, err, string(output))
+	}
+
+	return sigInfo, lastErr
+}
+
+// parsePowerShellSignature reads the SIGNATURE and CHAIN lines the script in
+// getSignatureViaPowerShell prints, skipping any error output around them. It
+// reports false when there is no complete SIGNATURE line.
+func parsePowerShellSignature(output string) (signatureInfo, bool) {
+	var sigInfo signatureInfo
+
+	// Pick the data and chain lines out of any error output
+	var dataLine string
+	var chain []datafile.ChainCert
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		line = strings.TrimSpace(line)
+		// The status itself may read "UnknownError", so match the marker
+		// rather than filtering on the word
+		if strings.HasPrefix(line, "SIGNATURE|") && dataLine == "" {
+			dataLine = strings.TrimPrefix(line, "SIGNATURE|")
+		} else if strings.HasPrefix(line, "CHAIN|") {
+			if parts := strings.Split(strings.TrimPrefix(line, "CHAIN|"), "|"); len(parts) == 4 {
+				chain = append(chain, datafile.ChainCert{
+					Subject:    strings.TrimSpace(parts[0]),
+					Thumbprint: strings.TrimSpace(parts[1]),
+					NotBefore:  strings.TrimSpace(parts[2]),
+					NotAfter:   strings.TrimSpace(parts[3]),
+				})
+			}
+		}
+	}
+
+	parts := strings.Split(dataLine, "|")
+	if dataLine == "" || len(parts) < 4 {
+		return sigInfo, false
+	}
+	sigInfo.Publisher = strings.TrimSpace(parts[0])
+	sigInfo.Issuer = strings.TrimSpace(parts[1])
+	sigInfo.SerialNumber = strings.TrimSpace(parts[2])
+	sigInfo.Thumbprint = strings.TrimSpace(parts[3])
+	if len(parts) >= 5 && strings.TrimSpace(parts[4]) != "" {
+		sigInfo.Timestamp = strings.TrimSpace(parts[4])
+	}
+	if len(parts) >= 7 {
+		sigInfo.Status = signatureVerdict(strings.TrimSpace(parts[5]), parts[6])
+		if sigInfo.Status != signatureValid {
+			sigInfo.StatusDetail = strings.TrimSpace(parts[6])
+		}
+	}
+	sigInfo.Chain = chain
+	return sigInfo, true
+}
+
+func getSignatureViaSigntool(exePath string) (signatureInfo, error) {
+	var sigInfo signatureInfo
+
+	// Try to find signtool.exe in common locations
+	signtoolPaths := []string{
+		"C:\\Program Files (x86)\\Windows Kits\\10\\bin\\x64\\signtool.exe",
+		"C:\\Program Files (x86)\\Windows Kits\\10\\bin\\10.0.22621.0\\x64\\signtool.exe",
+		"C:\\Program Files\\Windows Kits\\10\\bin\\x64\\signtool.exe",
+	}
+
+	var signtoolPath string
+	for _, path := range signtoolPaths {
+		if _, err := os.Stat(path); err == nil {
+			signtoolPath = path
+			break
+		}
+	}
+
+	if signtoolPath == "" {
+		return sigInfo, fmt.Errorf("signtool.exe not found")
+	}
+
+	// Use signtool to verify and get certificate info
+	cmd := exec.Command(signtoolPath, "verify", "/pa", "/v", exePath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return sigInfo, fmt.Errorf("signtool verify failed: %w", err)
+	}
+
+	return parseSigntoolOutput(string(output))
+}
+
+// parseSigntoolOutput reads the signer certificate from signtool verify /v
+// output. This is a simplified parser - signtool output format can vary.
+func parseSigntoolOutput(output string) (signatureInfo, error) {
+	var sigInfo signatureInfo
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "Subject:") {
+			sigInfo.Publisher = strings.TrimPrefix(line, "Subject:")
+			sigInfo.Publisher = strings.TrimSpace(sigInfo.Publisher)
+		}
+		if strings.Contains(line, "Issuer:") {
+			sigInfo.Issuer = strings.TrimPrefix(line, "Issuer:")
+			sigInfo.Issuer = strings.TrimSpace(sigInfo.Issuer)
+		}
+		if strings.Contains(line, "Serial Number:") {
+			sigInfo.SerialNumber = strings.TrimPrefix(line, "Serial Number:")
+			sigInfo.SerialNumber = strings.TrimSpace(sigInfo.SerialNumber)
+		}
+		if strings.Contains(line, "Thumbprint:") {
+			sigInfo.Thumbprint = strings.TrimPrefix(line, "Thumbprint:")
+			sigInfo.Thumbprint = strings.TrimSpace(sigInfo.Thumbprint)
+		}
+	}
+
+	if sigInfo.Publisher == "" {
+		return sigInfo, fmt.Errorf("could not extract certificate info from signtool output")
+	}
+
+	// signtool verify /pa only succeeds when WinVerifyTrust accepts the signature
+	sigInfo.Status = signatureValid
+
+	return sigInfo, nil
+}
+
+func getSignatureViaCertutil(exePath string) (signatureInfo, error) {
+	var sigInfo signatureInfo
+
+	// certutil is built into Windows and can verify signatures
+	// Use certutil to dump the certificate
+	cmd := exec.Command("certutil", "-verify", "-v", exePath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return sigInfo, fmt.Errorf("certutil verify failed: %w", err)
+	}
+
+	return parseCertutilOutput(string(output))
+}
+
+// parseCertutilOutput reads the signer certificate from certutil -verify -v
+// output, keeping the first subject, issuer, serial and thumbprint it lists
+func parseCertutilOutput(output string) (signatureInfo, error) {
+	var sigInfo signatureInfo
+	lines := strings.Split(output, "\n")
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+
+		// Look for certificate subject (Publisher)
+		if strings.Contains(line, "Subject:") || strings.Contains(line, "Issuer:") {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				value := strings.TrimSpace(parts[1])
+				if strings.Contains(line, "Subject:") && sigInfo.Publisher == "" {
+					sigInfo.Publisher = value
+				} else if strings.Contains(line, "Issuer:") && sigInfo.Issuer == "" {
+					sigInfo.Issuer = value
+				}
+			}
+		}
+
+		// Look for serial number
+		if strings.Contains(line, "Serial Number:") || strings.Contains(line, "Serial:") {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 && sigInfo.SerialNumber == "" {
+				sigInfo.SerialNumber = strings.TrimSpace(parts[1])
+			}
+		}
+
+		// Look for thumbprint (SHA1 hash)
+		if strings.Contains(line, "Cert Hash(sha1):") || strings.Contains(line, "Thumbprint:") {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 && sigInfo.Thumbprint == "" {
+				sigInfo.Thumbprint = strings.TrimSpace(parts[1])
+				// Remove spaces from thumbprint
+				sigInfo.Thumbprint = strings.ReplaceAll(sigInfo.Thumbprint, " ", "")
+			}
+		}
+
+		// Look for timestamp info in subsequent lines
+		if strings.Contains(line, "Time Stamp") && i+1 < len(lines) {
+			nextLine := strings.TrimSpace(lines[i+1])
+			if nextLine != "" {
+				sigInfo.Timestamp = nextLine
+			}
+		}
+	}
+
+	if sigInfo.Publisher == "" && sigInfo.Thumbprint == "" {
+		return sigInfo, fmt.Errorf("could not extract certificate info from certutil output")
+	}
+
+	return sigInfo, nil
+}
+
+// Windows Sandbox settings: the sandbox sees the work directory as
+// sandboxFolder and gets sandboxTimeout to install and copy files out
+const (
+	sandboxExecutable = "WindowsSandbox.exe"
+	sandboxFolder     = `C:\Collector`
+	sandboxTimeout    = 20 * time.Minute
+)
+
+// sandboxConfig is the .wsb file that maps the work directory into a sandbox
+// without networking and runs the collection script at logon
+const sandboxConfig = `<Configuration>
+  <Networking>Disable</Networking>
+  <MappedFolders>
+    <MappedFolder>
+      <HostFolder>{{workDir}}</HostFolder>
+      <SandboxFolder>` + sandboxFolder + `</SandboxFolder>
+      <ReadOnly>false</ReadOnly>
+    </MappedFolder>
+  </MappedFolders>
+  <LogonCommand>
+    <Command>powershell.exe -ExecutionPolicy Bypass -File ` + sandboxFolder + `\run.ps1</Command>
+  </LogonCommand>
+</Configuration>
+`
+
+// sandboxMSIScript extracts an MSI the same way extractFromMSI does on the
+// host. Every script writes "ok" or the error to done.txt when it finishes.
+const sandboxMSIScript = `$ErrorActionPreference = 'Stop'
+try {
+  $p = Start-Process msiexec.exe -ArgumentList '/a', '{{installer}}', '/qn', 'TARGETDIR={{output}}' -Wait -PassThru
+  if ($p.ExitCode -ne 0) { throw "msiexec exited with $($p.ExitCode)" }
+  Set-Content -Path '{{done}}' -Value 'ok'
+} catch {
+  Set-Content -Path '{{done}}' -Value $_.Exception.Message
+}
+`
+
+// sandboxEXEScript runs an EXE installer silently (/S, which NSIS and most
+// bootstrappers accept) and copies out every directory it added under the
+// Program Files folders and the user's Programs folder
+const sandboxEXEScript = `$ErrorActionPreference = 'Stop'
+try {
+  $roots = @($env:ProgramFiles, ${env:ProgramFiles(x86)}, "$env:LOCALAPPDATA\Programs") | Where-Object { $_ -and (Test-Path $_) }
+  $before = foreach ($root in $roots) { Get-ChildItem -Path $root -Directory | ForEach-Object FullName }
+  $p = Start-Process -FilePath '{{installer}}' -ArgumentList '/S' -PassThru
+  $p | Wait-Process -Timeout 900 -ErrorAction SilentlyContinue
+  New-Item -ItemType Directory -Force -Path '{{output}}' | Out-Null
+  $roots = @($env:ProgramFiles, ${env:ProgramFiles(x86)}, "$env:LOCALAPPDATA\Programs") | Where-Object { $_ -and (Test-Path $_) }
+  foreach ($root in $roots) {
+    Get-ChildItem -Path $root -Directory | Where-Object { $before -notcontains $_.FullName } | ForEach-Object {
+      Copy-Item -Path $_.FullName -Destination '{{output}}' -Recurse -Force
+    }
+  }
+  Set-Content -Path '{{done}}' -Value 'ok'
+} catch {
+  Set-Content -Path '{{done}}' -Value $_.Exception.Message
+}
+`
+
+// extractInSandbox copies the installer into a fresh work directory, runs
+// script inside Windows Sandbox and looks for the main executable among the
+// files it copied out. The sandbox is discarded afterwards, so nothing the
+// installer does persists on the runner.
+func extractInSandbox(installerPath string, app datafile.AppVersion, script string) (string, error) {
+	workDir := filepath.Join(tempDir, "sandbox")
+	os.RemoveAll(workDir)
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return "", err
+	}
+
+	installerName := "installer" + strings.ToLower(filepath.Ext(installerPath))
+	if err := linkOrCopy(installerPath, filepath.Join(workDir, installerName)); err != nil {
+		return "", fmt.Errorf("failed to copy installer into the sandbox folder: %w", err)
+	}
+
+	inSandbox := strings.NewReplacer(
+		"{{installer}}", sandboxFolder+`\`+installerName,
+		"{{output}}", sandboxFolder+`\output`,
+		"{{done}}", sandboxFolder+`\done.txt`,
+	)
+	if err := os.WriteFile(filepath.Join(workDir, "run.ps1"), []byte(inSandbox.Replace(script)), 0644); err != nil {
+		return "", err
+	}
+	configPath := filepath.Join(workDir, "collector.wsb")
+	config := strings.ReplaceAll(sandboxConfig, "{{workDir}}", workDir)
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("  🧪 Installing inside Windows Sandbox...\n")
+	if err := exec.CommandContext(runCtx, sandboxExecutable, configPath).Start(); err != nil {
+		return "", fmt.Errorf("failed to start Windows Sandbox: %w", err)
+	}
+	defer stopSandbox()
+
+	result, err := waitForSandbox(filepath.Join(workDir, "done.txt"))
+	if err != nil {
+		return "", err
+	}
+	if result != "ok" {
+		return "", fmt.Errorf("sandboxed install failed: %s", result)
+	}
+
+	return findMainExecutable(filepath.Join(workDir, "output"), app)
+}
+
+// linkOrCopy hard-links src to dst, copying it when they're on different volumes
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// waitForSandbox polls for the file the sandbox script writes when it's done
+// and returns its contents
+func waitForSandbox(donePath string) (string, error) {
+	deadline := time.Now().Add(sandboxTimeout)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(donePath); err == nil {
+			return strings.TrimSpace(strings.TrimPrefix(string(data), "\ufeff")), nil
+		}
+		select {
+		case <-time.After(5 * time.Second):
+		case <-runCtx.Done():
+			return "", runCtx.Err()
+		}
+	}
+	return "", fmt.Errorf("Windows Sandbox didn't finish within %s", sandboxTimeout)
+}
+
+// stopSandbox closes Windows Sandbox, which discards everything inside it
+func stopSandbox() {
+	for _, process := range []string{"WindowsSandboxClient.exe", "WindowsSandboxRemoteSession.exe", sandboxExecutable} {
+		exec.Command("taskkill", "/F", "/IM", process).Run()
+	}
+	// Only one sandbox can run at a time; give it a moment to shut down
+	time.Sleep(5 * time.Second)
+}
+
+func uninstallApp(app datafile.AppVersion) error {
+	fmt.Printf("  🗑️  Cleaning up...\n")
+	// For Windows, we typically don't need to uninstall since we extract to temp
+	// But we can clean up temp files
+	return nil
+}
+
+func cleanupTempFiles() {
+	os.RemoveAll(tempDir)
+	os.MkdirAll(tempDir, 0755)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,323 @@
+package winsecurity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/datafile"
+)
+
+func TestParseMSIProductInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantCode    string
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:        "code and version",
+			output:      "{2A6B4C1E-1234-4F7A-9C3D-5E6F7A8B9C0D}\r\n24.09.00.0\r\n",
+			wantCode:    "{2A6B4C1E-1234-4F7A-9C3D-5E6F7A8B9C0D}",
+			wantVersion: "24.09.00.0",
+		},
+		{
+			name:        "surrounding blank lines",
+			output:      "\n  {AAAA}  \n1.2.3\n\n",
+			wantCode:    "{AAAA}",
+			wantVersion: "1.2.3",
+		},
+		{name: "no output", output: "", wantErr: true},
+		{name: "empty product code", output: "\n1.2.3", wantErr: true},
+		{name: "extra lines", output: "{AAAA}\n1.2.3\nwarning", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, version, err := parseMSIProductInfo(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMSIProductInfo() error = %v, want error %v", err, tt.wantErr)
+			}
+			if code != tt.wantCode || version != tt.wantVersion {
+				t.Errorf("parseMSIProductInfo() = %q, %q; want %q, %q", code, version, tt.wantCode, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParseFileVersion(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{output: "6.3.5.51872\r\n", want: "6.3.5.51872"},
+		{output: "24.9.0.0", want: "24.9.0.0"},
+		{output: "0.0.0.0\r\n", wantErr: true},
+		{output: "  \n", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseFileVersion(tt.output)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseFileVersion(%q) = %q, %v; want %q, error %v", tt.output, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSignatureVerdict(t *testing.T) {
+	tests := []struct {
+		status  string
+		message string
+		want    string
+	}{
+		{"", "", ""},
+		{"Valid", "Signature verified.", signatureValid},
+		{"HashMismatch", "The contents of the file may have been tampered with.", signatureHashMismatch},
+		{"NotTrusted", "A certificate was explicitly revoked by its issuer.", signatureRevoked},
+		{"NotTrusted", "A required certificate is not within its validity period.", signatureExpired},
+		{"UnknownError", "A certificate chain processed, but terminated in a root certificate which is not trusted by the trust provider.", signatureUntrustedRoot},
+		{"NotSigned", "The file is not digitally signed.", signatureInvalid},
+	}
+	for _, tt := range tests {
+		if got := signatureVerdict(tt.status, tt.message); got != tt.want {
+			t.Errorf("signatureVerdict(%q, %q) = %q, want %q", tt.status, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestParsePowerShellSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   signatureInfo
+		wantOK bool
+	}{
+		{
+			name: "valid signature with chain",
+			output: "SIGNATURE|CN=Igor Pavlov, O=Igor Pavlov, C=RU|CN=Sectigo Public Code Signing CA R36|00AB12| 3F2A9C |CN=DigiCert Timestamp 2023|Valid|Signature verified.\r\n" +
+				"CHAIN|CN=Igor Pavlov, O=Igor Pavlov, C=RU|3F2A9C|2024-01-02T00:00:00Z|2027-01-01T23:59:59Z\r\n" +
+				"CHAIN|CN=Sectigo Public Code Signing Root R46|77AA00|2021-03-22T00:00:00Z|2046-03-21T23:59:59Z\r\n",
+			want: signatureInfo{
+				Publisher:    "CN=Igor Pavlov, O=Igor Pavlov, C=RU",
+				Issuer:       "CN=Sectigo Public Code Signing CA R36",
+				SerialNumber: "00AB12",
+				Thumbprint:   "3F2A9C",
+				Timestamp:    "CN=DigiCert Timestamp 2023",
+				Status:       signatureValid,
+				Chain: []datafile.ChainCert{
+					{Subject: "CN=Igor Pavlov, O=Igor Pavlov, C=RU", Thumbprint: "3F2A9C", NotBefore: "2024-01-02T00:00:00Z", NotAfter: "2027-01-01T23:59:59Z"},
+					{Subject: "CN=Sectigo Public Code Signing Root R46", Thumbprint: "77AA00", NotBefore: "2021-03-22T00:00:00Z", NotAfter: "2046-03-21T23:59:59Z"},
+				},
+			},
+			wantOK: true,
+		},
+		{
+			name: "untrusted signature among error output",
+			output: "WARNING: module auto-loading failed\n" +
+				"SIGNATURE|CN=Example Corp|CN=Example CA|01|AAAA||UnknownError|A certificate chain processed, but terminated in a root certificate which is not trusted by the trust provider.\n" +
+				"CHAIN|CN=Example Corp|AAAA|2024-01-01T00:00:00Z\n",
+			want: signatureInfo{
+				Publisher:    "CN=Example Corp",
+				Issuer:       "CN=Example CA",
+				SerialNumber: "01",
+				Thumbprint:   "AAAA",
+				Status:       signatureUntrustedRoot,
+				StatusDetail: "A certificate chain processed, but terminated in a root certificate which is not trusted by the trust provider.",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "older script without status",
+			output: "SIGNATURE|CN=Example Corp|CN=Example CA|01|AAAA",
+			want: signatureInfo{
+				Publisher:    "CN=Example Corp",
+				Issuer:       "CN=Example CA",
+				SerialNumber: "01",
+				Thumbprint:   "AAAA",
+			},
+			wantOK: true,
+		},
+		{name: "no signature line", output: "Get-AuthenticodeSignature : No certificate found\n", wantOK: false},
+		{name: "truncated signature line", output: "SIGNATURE|CN=Example Corp|CN=Example CA", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parsePowerShellSignature(tt.output)
+			if ok != tt.wantOK {
+				t.Fatalf("parsePowerShellSignature() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePowerShellSignature() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSigntoolOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    signatureInfo
+		wantErr bool
+	}{
+		{
+			name: "signer certificate",
+			output: "Verifying: C:\\Temp\\7z.exe\r\n" +
+				"Signing Certificate Chain:\r\n" +
+				"Subject: CN=Igor Pavlov, O=Igor Pavlov, C=RU\r\n" +
+				"Issuer: CN=Sectigo Public Code Signing CA R36\r\n" +
+				"Serial Number: 00AB12\r\n" +
+				"Thumbprint: 3F2A9C\r\n" +
+				"Successfully verified: C:\\Temp\\7z.exe\r\n",
+			want: signatureInfo{
+				Publisher:    "CN=Igor Pavlov, O=Igor Pavlov, C=RU",
+				Issuer:       "CN=Sectigo Public Code Signing CA R36",
+				SerialNumber: "00AB12",
+				Thumbprint:   "3F2A9C",
+				Status:       signatureValid,
+			},
+		},
+		{name: "no subject", output: "Number of files successfully Verified: 1\r\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSigntoolOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSigntoolOutput() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSigntoolOutput() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCertutilOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    signatureInfo
+		wantErr bool
+	}{
+		{
+			name: "first certificate wins and the thumbprint loses its spaces",
+			output: "Issuer:\r\n" +
+				"Subject: CN=Example Corp, O=Example Corp, C=US\r\n" +
+				"Issuer: CN=Example Code Signing CA\r\n" +
+				"Serial Number: 0a1b2c\r\n" +
+				"Cert Hash(sha1): 3f 2a 9c 01\r\n" +
+				"Time Stamp:\r\n" +
+				"  03/04/2025 21:15\r\n" +
+				"Subject: CN=Example Code Signing CA\r\n" +
+				"Cert Hash(sha1): ff ff\r\n",
+			want: signatureInfo{
+				Publisher:    "CN=Example Corp, O=Example Corp, C=US",
+				Issuer:       "CN=Example Code Signing CA",
+				SerialNumber: "0a1b2c",
+				Thumbprint:   "3f2a9c01",
+				Timestamp:    "03/04/2025 21:15",
+			},
+		},
+		{
+			name:   "thumbprint only",
+			output: "Thumbprint: AA BB\n",
+			want:   signatureInfo{Thumbprint: "AABB"},
+		},
+		{name: "nothing recognisable", output: "CertUtil: -verify command FAILED\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCertutilOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCertutilOutput() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCertutilOutput() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSecurityInfo(t *testing.T) {
+	info := func(slug, version, sha string) datafile.AppSecurityInfo {
+		return datafile.AppSecurityInfo{Slug: slug, Version: version, Sha256: sha}
+	}
+	listed := []datafile.AppVersion{
+		{Slug: "7-zip/windows"},
+		{Slug: "zoom/windows"},
+		{Slug: "slack/darwin"},
+	}
+
+	tests := []struct {
+		name              string
+		existing          map[string]datafile.AppSecurityInfo
+		existingVersions  map[string]datafile.AppSecurityInfo
+		collected         map[string]datafile.AppSecurityInfo
+		collectedVersions map[string]datafile.AppSecurityInfo
+		processed         map[string]bool
+		wantApps          []datafile.AppSecurityInfo
+		wantVersions      []datafile.AppSecurityInfo
+	}{
+		{
+			name: "collected entries replace existing ones and the rest are sorted",
+			existing: map[string]datafile.AppSecurityInfo{
+				"zoom/windows":  info("zoom/windows", "6.3.0", "old"),
+				"7-zip/windows": info("7-zip/windows", "24.08", "old"),
+			},
+			collected: map[string]datafile.AppSecurityInfo{
+				"zoom/windows": info("zoom/windows", "6.3.5", "new"),
+			},
+			processed: map[string]bool{"zoom/windows": true},
+			wantApps: []datafile.AppSecurityInfo{
+				info("7-zip/windows", "24.08", "old"),
+				info("zoom/windows", "6.3.5", "new"),
+			},
+		},
+		{
+			name: "another platform's entry is kept while any platform is listed",
+			existing: map[string]datafile.AppSecurityInfo{
+				"slack/darwin":  info("slack/darwin", "4.41", "mac"),
+				"zoom/darwin":   info("zoom/darwin", "6.3.5", "mac"),
+				"notion/darwin": info("notion/darwin", "4.0", "mac"),
+			},
+			wantApps: []datafile.AppSecurityInfo{
+				info("slack/darwin", "4.41", "mac"),
+				info("zoom/darwin", "6.3.5", "mac"),
+			},
+		},
+		{
+			name: "a processed app that failed is dropped rather than kept stale",
+			existing: map[string]datafile.AppSecurityInfo{
+				"7-zip/windows": info("7-zip/windows", "24.08", "old"),
+			},
+			processed: map[string]bool{"7-zip/windows": true},
+		},
+		{
+			name: "older versions are kept while their own slug is listed",
+			existingVersions: map[string]datafile.AppSecurityInfo{
+				"zoom/windows@6.2.0":  info("zoom/windows", "6.2.0", "a"),
+				"zoom/darwin@6.2.0":   info("zoom/darwin", "6.2.0", "b"),
+				"7-zip/windows@24.07": info("7-zip/windows", "24.07", "old"),
+			},
+			collectedVersions: map[string]datafile.AppSecurityInfo{
+				"7-zip/windows@24.07": info("7-zip/windows", "24.07", "new"),
+				"zoom/windows@6.1.0":  info("zoom/windows", "6.1.0", "c"),
+			},
+			wantVersions: []datafile.AppSecurityInfo{
+				info("7-zip/windows", "24.07", "new"),
+				info("zoom/windows", "6.1.0", "c"),
+				info("zoom/windows", "6.2.0", "a"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps, versions := mergeSecurityInfo(tt.existing, tt.existingVersions, tt.collected, tt.collectedVersions, tt.processed, listed)
+			if !reflect.DeepEqual(apps, tt.wantApps) {
+				t.Errorf("apps = %+v\nwant %+v", apps, tt.wantApps)
+			}
+			if !reflect.DeepEqual(versions, tt.wantVersions) {
+				t.Errorf("versions = %+v\nwant %+v", versions, tt.wantVersions)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+// Package datafile defines the JSON files the tracker keeps in data/. The
+// command that writes a file and every command that reads it share these
+// types; readers decode whole entries and use the fields they need. Files are
+// upgraded with internal/schema before they're decoded.
+package datafile
+
+import "github.com/fleetdm/fleet-apps-growth-tracker/internal/tlsinfo"
+
+// AppVersions is data/app_versions.json, written by main.go
+type AppVersions struct {
+	SchemaVersion    int          `json:"schemaVersion"`
+	GeneratorVersion string       `json:"generatorVersion,omitempty"`
+	LastUpdated      string       `json:"lastUpdated"`
+	Apps             []AppVersion `json:"apps"`
+}
+
+// AppVersion is an app's latest version in Fleet's catalog
+type AppVersion struct {
+	Slug              string             `json:"slug"`
+	Name              string             `json:"name"`
+	Platform          string             `json:"platform"`
+	Version           string             `json:"version"`
+	InstallerURL      string             `json:"installerUrl"`
+	Arch              string             `json:"arch,omitempty"`              // macOS: arm64, x86_64 or universal, when the installer URL says
+	Variants          []PublishedVersion `json:"variants,omitempty"`          // macOS: installers of the same version for other architectures
+	PublishedVersions []PublishedVersion `json:"publishedVersions,omitempty"` // Every version Fleet lists, latest first
+	Flags             *ManifestFlags     `json:"flags,omitempty"`             // Metadata of the latest version beyond its installer
+	Scripts           *ScriptHashes      `json:"scripts,omitempty"`           // Install and uninstall scripts of the latest version
+}
+
+// PublishedVersion is one entry of the versions list in Fleet's per-app JSON
+type PublishedVersion struct {
+	Version      string            `json:"version"`
+	InstallerURL string            `json:"installerUrl"`
+	Arch         string            `json:"arch,omitempty"`
+	Scripts      *ScriptHashes     `json:"scripts,omitempty"`
+	Queries      map[string]string `json:"-"` // osquery queries Fleet runs for the version, only kept in archive/
+}
+
+// ManifestFlags is the metadata Fleet's per-app JSON carries for the latest
+// version: the categories and install options applied when the app is added
+// to a team. Options the manifest doesn't set are left nil.
+type ManifestFlags struct {
+	DefaultCategories []string `json:"defaultCategories,omitempty"`
+	SelfService       *bool    `json:"selfService,omitempty"`
+	AutomaticInstall  *bool    `json:"automaticInstall,omitempty"`
+}
+
+// ScriptHashes identifies the scripts Fleet runs for a version by their SHA-256
+type ScriptHashes struct {
+	Install   string `json:"install,omitempty"`
+	Uninstall string `json:"uninstall,omitempty"`
+}
+
+// VersionHistory is data/version_history.json, written by main.go and
+// build_history.go
+type VersionHistory struct {
+	SchemaVersion    int             `json:"schemaVersion"`
+	GeneratorVersion string          `json:"generatorVersion,omitempty"`
+	Changes          []VersionChange `json:"changes"`
+}
+
+// VersionChange is an app's version bump
+type VersionChange struct {
+	Date         string `json:"date"`
+	AppName      string `json:"appName"`
+	Slug         string `json:"slug"`
+	Platform     string `json:"platform"`
+	OldVersion   string `json:"oldVersion"`
+	NewVersion   string `json:"newVersion"`
+	InstallerURL string `json:"installerUrl"`
+}
+
+// FirstSeen is data/app_first_seen.json, written by build_history.go
+type FirstSeen struct {
+	SchemaVersion    int            `json:"schemaVersion"`
+	GeneratorVersion string         `json:"generatorVersion,omitempty"`
+	Apps             []AppFirstSeen `json:"apps"`
+	LastUpdated      string         `json:"lastUpdated"`
+}
+
+// AppFirstSeen records the commit in which a slug's platform entry first
+// appeared in apps.json
+type AppFirstSeen struct {
+	Slug      string `json:"slug"`
+	Name      string `json:"name"`
+	Platform  string `json:"platform"`
+	FirstSeen string `json:"firstSeen"`
+	CommitSha string `json:"commitSha"`
+}
+
+// ScriptChange is a line of data/script_changes.jsonl: an app whose install or
+// uninstall script changed, with a unified diff when the previous script is
+// still in data/scripts
+type ScriptChange struct {
+	DetectedAt string `json:"detectedAt"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	Script     string `json:"script"` // install or uninstall
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+	OldSha256  string `json:"oldSha256"`
+	NewSha256  string `json:"newSha256"`
+	Diff       string `json:"diff,omitempty"`
+}
+
+// AppsMetadata is data/apps_metadata.json, the part of apps.json that
+// generate_html.go caches
+type AppsMetadata struct {
+	SchemaVersion    int           `json:"schemaVersion"`
+	GeneratorVersion string        `json:"generatorVersion,omitempty"`
+	LastUpdated      string        `json:"lastUpdated"`
+	Apps             []AppMetadata `json:"apps"`
+}
+
+// AppMetadata is an app's entry of apps.json
+type AppMetadata struct {
+	Slug        string   `json:"slug"`
+	Name        string   `json:"name"`
+	Platform    string   `json:"platform"`
+	Description string   `json:"description"`
+	Categories  []string `json:"categories,omitempty"`
+}
+
+// SecurityInfo is data/app_security_info.json, written by the security
+// collectors
+type SecurityInfo struct {
+	SchemaVersion    int               `json:"schemaVersion"`
+	GeneratorVersion string            `json:"generatorVersion,omitempty"`
+	LastUpdated      string            `json:"lastUpdated"`
+	Apps             []AppSecurityInfo `json:"apps"`
+	Versions         []AppSecurityInfo `json:"versions,omitempty"` // Superseded and older published versions, keyed by (slug, version)
+}
+
+// AppSecurityInfo is what a collector recorded about one version of an app.
+// Each collector keeps the other platform's fields when it rewrites the file.
+type AppSecurityInfo struct {
+	Slug            string            `json:"slug"`
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	Sha256          string            `json:"sha256,omitempty"`
+	Cdhash          string            `json:"cdhash,omitempty"`
+	SigningID       string            `json:"signingId,omitempty"`
+	TeamID          string            `json:"teamId,omitempty"`
+	BundleID        string            `json:"bundleId,omitempty"`        // CFBundleIdentifier from Info.plist
+	BundleVersion   string            `json:"bundleVersion,omitempty"`   // CFBundleShortVersionString from Info.plist
+	BundlePath      string            `json:"bundlePath,omitempty"`      // Where the installer put the app, e.g. /Applications/zoom.us.app
+	RequiresRosetta *bool             `json:"requiresRosetta,omitempty"` // Main executable has no arm64 slice
+	Publisher       string            `json:"publisher,omitempty"`       // Windows: Certificate subject
+	Issuer          string            `json:"issuer,omitempty"`          // Windows: Certificate authority
+	SerialNumber    string            `json:"serialNumber,omitempty"`    // Windows: Certificate serial
+	Thumbprint      string            `json:"thumbprint,omitempty"`      // Windows: Certificate thumbprint
+	Timestamp       string            `json:"timestamp,omitempty"`       // Windows: Signing timestamp
+	SignatureStatus string            `json:"signatureStatus,omitempty"` // Windows: WinVerifyTrust verdict
+	SignatureDetail string            `json:"signatureDetail,omitempty"` // Windows: WinVerifyTrust status message
+	Payload         []PayloadFile     `json:"payload,omitempty"`         // Windows: Files an administrative MSI install extracts
+	CertChain       []ChainCert       `json:"certChain,omitempty"`       // Windows: Authenticode chain, leaf first
+	ProductCode     string            `json:"productCode,omitempty"`     // Windows: MSI ProductCode
+	ProductVersion  string            `json:"productVersion,omitempty"`  // Windows: MSI ProductVersion
+	FileVersion     string            `json:"fileVersion,omitempty"`     // Windows: FileVersion of the main executable
+	ExecutablePath  string            `json:"executablePath,omitempty"`  // Windows: Main executable, relative to the MSI install point
+	InstallerSha256 string            `json:"installerSha256,omitempty"` // Hash of the downloaded installer itself
+	InstallerSize   int64             `json:"installerSize,omitempty"`   // Bytes downloaded, matching Content-Length when the server sent one
+	InstallerURL    string            `json:"installerUrl,omitempty"`    // Installer that was downloaded: the runner's architecture, or a mirror
+	DownloadTLS     *tlsinfo.Info     `json:"downloadTls,omitempty"`     // How the installer was served
+	Anomalies       []Anomaly         `json:"anomalies,omitempty"`       // Problems detected when this version was collected
+	Arch            string            `json:"arch,omitempty"`            // Architecture the installer targets, from app_versions.json
+	Architectures   []ArchSlice       `json:"architectures,omitempty"`   // Slices of the main executable
+	Variants        []AppSecurityInfo `json:"variants,omitempty"`        // Installers of the same version for other architectures
+	Components      []Component       `json:"components,omitempty"`      // Frameworks and dylibs in Contents/Frameworks (--components)
+	Sandboxed       *bool             `json:"sandboxed,omitempty"`       // Has the com.apple.security.app-sandbox entitlement
+	HardenedRuntime *bool             `json:"hardenedRuntime,omitempty"` // Code signature has the runtime flag
+	SignatureFormat string            `json:"signatureFormat,omitempty"` // codesign Format, e.g. "app bundle with Mach-O universal (x86_64 arm64)"
+	SigningTime     string            `json:"signingTime,omitempty"`     // When the app was signed (RFC 3339 when codesign's date parses)
+	LeafCertificate string            `json:"leafCertificate,omitempty"` // Common name of the signing certificate
+	Stapled         *bool             `json:"stapled,omitempty"`         // A notarization ticket is stapled to the bundle
+	NotarizedAt     string            `json:"notarizedAt,omitempty"`     // When Apple issued the notarization ticket (RFC 3339)
+	Persistence     []PersistenceItem `json:"persistence,omitempty"`     // Launch daemons, launch agents and login items the installer added
+	PrivacyUsage    []PrivacyUsage    `json:"privacyUsage,omitempty"`    // NS*UsageDescription keys of Info.plist: the privacy permissions the app asks for
+	LastUpdated     string            `json:"lastUpdated"`
+	Apps            []AppSecurityInfo `json:"apps,omitempty"` // For suites with multiple apps
+}
+
+// Anomaly records something suspicious about a collected version
+type Anomaly struct {
+	Type       string `json:"type"`
+	Detail     string `json:"detail"`
+	DetectedAt string `json:"detectedAt"`
+}
+
+// ArchSlice is one architecture of a (possibly universal) Mach-O executable
+type ArchSlice struct {
+	Arch   string `json:"arch"`
+	Sha256 string `json:"sha256"`
+}
+
+// ChainCert is a certificate of a Windows Authenticode chain
+type ChainCert struct {
+	Subject    string `json:"subject"`
+	Thumbprint string `json:"thumbprint"`
+	NotBefore  string `json:"notBefore,omitempty"`
+	NotAfter   string `json:"notAfter,omitempty"`
+}
+
+// PayloadFile is a file of a Windows MSI's payload
+type PayloadFile struct {
+	Path   string `json:"path"` // Relative to the administrative install point
+	Sha256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Component is a framework or dylib bundled in an app's Contents/Frameworks
+type Component struct {
+	Path      string `json:"path"` // Relative to the app bundle
+	Sha256    string `json:"sha256"`
+	SigningID string `json:"signingId,omitempty"`
+	TeamID    string `json:"teamId,omitempty"`
+}
+
+// PersistenceItem is a launchd job or login item an installer added, so it
+// keeps running or starts at login independently of the app
+type PersistenceItem struct {
+	Kind    string `json:"kind"`              // launch-daemon, launch-agent or login-item
+	Path    string `json:"path"`              // The launchd plist, or the login item's app
+	Label   string `json:"label,omitempty"`   // launchd Label
+	Program string `json:"program,omitempty"` // Program, or the first of ProgramArguments
+}
+
+// PrivacyUsage is a privacy permission an app declares in Info.plist with the
+// prompt macOS shows when asking for it
+type PrivacyUsage struct {
+	Key         string `json:"key"`               // e.g. NSCameraUsageDescription
+	Service     string `json:"service,omitempty"` // PPPC service the permission is granted through, e.g. Camera
+	Description string `json:"description"`
+}
+
+// HashDrift is a line of data/hash_drift.jsonl: an installer whose bytes
+// changed under the same version
+type HashDrift struct {
+	DetectedAt     string `json:"detectedAt"`
+	Slug           string `json:"slug"`
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	RecordedSha256 string `json:"recordedSha256"`
+	CurrentSha256  string `json:"currentSha256"`
+	InstallerURL   string `json:"installerUrl"`
+}
+
+// CollectionResults is the file a security collector's --results writes for
+// track_failures.go
+type CollectionResults struct {
+	Apps []CollectionResult `json:"apps"`
+}
+
+// CollectionResult is whether an app's current version was collected
+type CollectionResult struct {
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PackageParity is data/package_parity.json, written by crossref_packages.go
+type PackageParity struct {
+	SchemaVersion    int         `json:"schemaVersion"`
+	GeneratorVersion string      `json:"generatorVersion,omitempty"`
+	LastUpdated      string      `json:"lastUpdated"`
+	Apps             []AppParity `json:"apps"`
+}
+
+// AppParity is a Windows app's packages in winget and Chocolatey
+type AppParity struct {
+	Slug         string        `json:"slug"`
+	Name         string        `json:"name"`
+	FleetVersion string        `json:"fleetVersion"`
+	Winget       *PackageMatch `json:"winget,omitempty"`
+	Chocolatey   *PackageMatch `json:"chocolatey,omitempty"`
+}
+
+// PackageMatch is an app's package in one repository
+type PackageMatch struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	Status  string `json:"status"` // same, fleet-behind or fleet-ahead
+}
+
+// UpstreamLag is data/upstream_lag.json, written by upstream_lag.go
+type UpstreamLag struct {
+	SchemaVersion    int      `json:"schemaVersion"`
+	GeneratorVersion string   `json:"generatorVersion,omitempty"`
+	LastUpdated      string   `json:"lastUpdated"`
+	Apps             []AppLag `json:"apps"`
+}
+
+// AppLag is how far Fleet's version of an app is behind the vendor's
+type AppLag struct {
+	Slug            string `json:"slug"`
+	Name            string `json:"name"`
+	Platform        string `json:"platform"`
+	FleetVersion    string `json:"fleetVersion"`
+	UpstreamVersion string `json:"upstreamVersion"`
+	Source          string `json:"source"`
+	ReleasedAt      string `json:"releasedAt,omitempty"`
+	Status          string `json:"status"`                // same, fleet-behind or fleet-ahead
+	BehindSince     string `json:"behindSince,omitempty"` // When a newer upstream version was released, or first seen
+	LagDays         int    `json:"lagDays"`
+}
+
+// RunStats is data/run_stats.json, written by main.go
+type RunStats struct {
+	SchemaVersion     int                 `json:"schemaVersion"`
+	Runs              []RunStatsEntry     `json:"runs"`
+	CollectionLatency []CollectionLatency `json:"collectionLatency,omitempty"`
+}
+
+// RunStatsEntry records the totals for a single run
+type RunStatsEntry struct {
+	StartedAt          string             `json:"startedAt"`
+	GeneratorVersion   string             `json:"generatorVersion,omitempty"`
+	DurationSeconds    float64            `json:"durationSeconds"`
+	Success            bool               `json:"success"`
+	GitHubRequests     int64              `json:"githubRequests"`
+	RateLimitRemaining *int64             `json:"rateLimitRemaining,omitempty"`
+	BytesDownloaded    int64              `json:"bytesDownloaded"`
+	Failures           int                `json:"failures"`
+	Stages             map[string]float64 `json:"stages"` // Stage name -> wall time in seconds
+}
+
+// CollectionLatency records how long the security info of a version bump
+// took: from main.go first recording the new version to a collector
+// recording security info for it
+type CollectionLatency struct {
+	Slug        string  `json:"slug"`
+	Version     string  `json:"version"`
+	DetectedAt  string  `json:"detectedAt"`
+	CollectedAt string  `json:"collectedAt"`
+	Seconds     float64 `json:"seconds"`
+}
+
+// ProbeResult is one line of data/installer_uptime.jsonl, written by
+// probe_installers.go
+type ProbeResult struct {
+	Time      string `json:"time"`
+	Slug      string `json:"slug"`
+	Status    int    `json:"status,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+}
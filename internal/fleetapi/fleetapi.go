@@ -0,0 +1,133 @@
+// Package fleetapi reads the Fleet-maintained apps catalog from a Fleet
+// server's REST API, so the tracker can follow what a specific server offers
+// instead of the fleetdm/fleet main branch.
+package fleetapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const perPage = 100
+
+// StatusError is returned when the Fleet server responds with a non-200 status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("Fleet API error (status %d)", e.StatusCode)
+	}
+	return fmt.Sprintf("Fleet API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// App is one entry of the server's Fleet-maintained apps list
+type App struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+}
+
+// AppDetail is a single Fleet-maintained app, including its installer URL
+type AppDetail struct {
+	App
+	InstallerURL string `json:"url"`
+}
+
+// Client performs authenticated GET requests against a Fleet server
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+}
+
+// NewClient returns a client for the Fleet server at baseURL that
+// authenticates with the given API token
+func NewClient(baseURL, token string) (*Client, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("Fleet server URL is required")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Fleet API token is required")
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid Fleet server URL %q: %w", baseURL, err)
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+	}, nil
+}
+
+// ListApps returns every Fleet-maintained app the server offers, following
+// the API's page-based pagination
+func (c *Client) ListApps() ([]App, error) {
+	var apps []App
+	for page := 0; ; page++ {
+		var resp struct {
+			Apps []App `json:"fleet_maintained_apps"`
+			Meta struct {
+				HasNextResults bool `json:"has_next_results"`
+			} `json:"meta"`
+		}
+		path := fmt.Sprintf("/api/v1/fleet/software/fleet_maintained_apps?page=%d&per_page=%d", page, perPage)
+		if err := c.get(path, &resp); err != nil {
+			return nil, err
+		}
+
+		apps = append(apps, resp.Apps...)
+		if !resp.Meta.HasNextResults || len(resp.Apps) == 0 {
+			return apps, nil
+		}
+	}
+}
+
+// GetApp returns the details of the Fleet-maintained app with the given ID
+func (c *Client) GetApp(id uint) (*AppDetail, error) {
+	var resp struct {
+		App AppDetail `json:"fleet_maintained_app"`
+	}
+	if err := c.get(fmt.Sprintf("/api/v1/fleet/software/fleet_maintained_apps/%d", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp.App, nil
+}
+
+func (c *Client) get(path string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+// Package github provides the GitHub client shared by the data scripts. It
+// authenticates with GITHUB_TOKEN when available, follows Link header
+// pagination and retries transient failures and rate limiting.
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 5
+	maxRateLimitWait  = time.Hour
+
+	// Secondary rate limits: GitHub asks for at least a minute between
+	// retries when it doesn't send Retry-After
+	minSecondaryWait  = time.Minute
+	maxSecondaryWaits = 10
+
+	// Bounds for the adaptive delay between requests
+	minThrottleStep = 250 * time.Millisecond
+	maxThrottle     = 10 * time.Second
+)
+
+// TokenFlagUsage is the help text shared by every script's --github-token flag
+const TokenFlagUsage = "GitHub token to authenticate API requests with, raising the rate limit from 60 to 5,000 requests an hour (default: GITHUB_TOKEN or GH_TOKEN from the environment)"
+
+// StatusError is returned when GitHub responds with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("GitHub error (status %d)", e.StatusCode)
+	}
+	return fmt.Sprintf("GitHub error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Client performs authenticated, retrying requests against GitHub. It is
+// safe for concurrent use; requests are paced by an adaptive throttle that
+// backs off when GitHub pushes back and speeds up again as requests succeed.
+type Client struct {
+	httpClient *http.Client
+	token      string
+	maxRetries int
+
+	requests      atomic.Int64
+	bytes         atomic.Int64
+	rateRemaining atomic.Int64 // -1 until a response reports it
+
+	mu       sync.Mutex
+	interval time.Duration // current minimum spacing between requests
+	next     time.Time     // earliest start time for the next request
+}
+
+// NewClient returns a client authenticated with GITHUB_TOKEN (or GH_TOKEN),
+// falling back to unauthenticated requests when neither is set
+func NewClient() *Client {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+
+	c := &Client{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		token:      token,
+		maxRetries: defaultMaxRetries,
+	}
+	c.rateRemaining.Store(-1)
+
+	return c
+}
+
+// SetToken authenticates later requests with token, e.g. from a --github-token
+// flag. An empty token keeps the one from the environment.
+func (c *Client) SetToken(token string) {
+	if token != "" {
+		c.token = token
+	}
+}
+
+// Authenticated reports whether requests carry a token
+func (c *Client) Authenticated() bool {
+	return c.token != ""
+}
+
+// Requests returns the number of HTTP requests made so far, including retries
+func (c *Client) Requests() int64 {
+	return c.requests.Load()
+}
+
+// BytesDownloaded returns the total size of response bodies read so far
+func (c *Client) BytesDownloaded() int64 {
+	return c.bytes.Load()
+}
+
+// RateLimitRemaining returns the API rate limit remaining as of the latest
+// response that reported it, and whether any response has
+func (c *Client) RateLimitRemaining() (int64, bool) {
+	remaining := c.rateRemaining.Load()
+	return remaining, remaining >= 0
+}
+
+// Get fetches url and returns the response body
+func (c *Client) Get(url string) ([]byte, error) {
+	body, _, err := c.get(url)
+	return body, err
+}
+
+// GetPages fetches url and every following page advertised by the Link
+// header, calling fn with the page number and body of each page
+func (c *Client) GetPages(url string, fn func(page int, body []byte) error) error {
+	for page := 1; url != ""; page++ {
+		body, header, err := c.get(url)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(page, body); err != nil {
+			return err
+		}
+
+		url = nextPageURL(header.Get("Link"))
+	}
+
+	return nil
+}
+
+// Post sends payload, a JSON document, to url and returns the response body
+func (c *Client) Post(url string, payload []byte) ([]byte, error) {
+	body, _, err := c.do(http.MethodPost, url, payload)
+	return body, err
+}
+
+// Patch sends payload, a JSON document, to url and returns the response body
+func (c *Client) Patch(url string, payload []byte) ([]byte, error) {
+	body, _, err := c.do(http.MethodPatch, url, payload)
+	return body, err
+}
+
+func (c *Client) get(url string) ([]byte, http.Header, error) {
+	return c.do(http.MethodGet, url, nil)
+}
+
+func (c *Client) do(method, url string, payload []byte) ([]byte, http.Header, error) {
+	var lastErr error
+
+	secondaryWaits := 0
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<(attempt-1)) * time.Second
+			time.Sleep(backoff)
+		}
+
+		c.throttle()
+
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequest(method, url, reqBody)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+		c.requests.Add(1)
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to fetch %s: %w", url, err)
+			if method != http.MethodGet {
+				lastErr = fmt.Errorf("failed to send %s %s: %w", method, url, err)
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		c.bytes.Add(int64(len(body)))
+		if remaining, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Remaining"), 10, 64); err == nil {
+			c.rateRemaining.Store(remaining)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read response: %w", err)
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			c.adjust(resp, false)
+			return body, resp.Header, nil
+		}
+
+		lastErr = &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+		c.adjust(resp, resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden || resp.StatusCode >= 500)
+
+		// Primary rate limit exhausted: wait for the reset time, then retry
+		if wait, ok := rateLimitWait(resp); ok {
+			if wait > maxRateLimitWait {
+				return nil, nil, fmt.Errorf("rate limit resets in %s: %w", wait.Round(time.Second), lastErr)
+			}
+			fmt.Printf("⏳ GitHub rate limit reached, pausing %s until it resets at %s...\n", wait.Round(time.Second), time.Now().Add(wait).UTC().Format("15:04:05 UTC"))
+			time.Sleep(wait)
+			fmt.Println("▶️  Rate limit reset, resuming")
+			continue
+		}
+
+		// Secondary (abuse detection) limit: wait as told and try again without
+		// using up a retry, so long runs resume instead of failing halfway
+		if wait, ok := secondaryLimitWait(resp, body, secondaryWaits); ok {
+			if wait > maxRateLimitWait || secondaryWaits >= maxSecondaryWaits {
+				return nil, nil, fmt.Errorf("secondary rate limit persists after %d waits: %w", secondaryWaits, lastErr)
+			}
+			secondaryWaits++
+			fmt.Printf("⏳ GitHub secondary rate limit hit, waiting %s...\n", wait.Round(time.Second))
+			time.Sleep(wait)
+			attempt--
+			continue
+		}
+
+		// Only server errors are worth retrying, and not for a POST that may
+		// have been applied before the server failed
+		if resp.StatusCode < 500 || method == http.MethodPost {
+			return nil, nil, lastErr
+		}
+	}
+
+	return nil, nil, lastErr
+}
+
+// throttle blocks until the next request is allowed to start
+func (c *Client) throttle() {
+	c.mu.Lock()
+	now := time.Now()
+	start := c.next
+	if start.Before(now) {
+		start = now
+	}
+	c.next = start.Add(c.interval)
+	c.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
+
+// adjust updates the request spacing after a response: it doubles on
+// pushback, decays on success and never drops below the pace needed to
+// spread the remaining rate limit budget until its reset
+func (c *Client) adjust(resp *http.Response, pushback bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pushback {
+		c.interval *= 2
+		if c.interval < minThrottleStep {
+			c.interval = minThrottleStep
+		}
+		if c.interval > maxThrottle {
+			c.interval = maxThrottle
+		}
+		return
+	}
+
+	c.interval = c.interval * 3 / 4
+	if c.interval < 10*time.Millisecond {
+		c.interval = 0
+	}
+
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining <= 0 {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	if pace := time.Until(time.Unix(reset, 0)) / time.Duration(remaining); pace > c.interval {
+		c.interval = min(pace, maxThrottle)
+	}
+}
+
+// rateLimitWait returns how long to wait when resp reports an exhausted rate limit
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	wait := time.Until(time.Unix(reset, 0)) + time.Second
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return wait, true
+}
+
+// secondaryLimitWait returns how long to wait when resp is a secondary rate
+// limit response: a 403 or 429 with Retry-After, or whose message says so.
+// Without Retry-After the wait starts at a minute and doubles with each
+// previous wait.
+func secondaryLimitWait(resp *http.Response, body []byte, previousWaits int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		return wait, true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		return minSecondaryWait << min(previousWaits, 5), true
+	}
+	return 0, false
+}
+
+// parseRetryAfter reads a Retry-After value in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return max(time.Duration(seconds)*time.Second, time.Second), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), time.Second), true
+	}
+	return 0, false
+}
+
+var nextLinkRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPageURL extracts the rel="next" URL from a Link header
+func nextPageURL(link string) string {
+	m := nextLinkRe.FindStringSubmatch(link)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
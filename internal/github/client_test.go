@@ -0,0 +1,125 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"30", 30 * time.Second, true},
+		{"0", time.Second, true},
+		{"Tue, 07 Jan 2025 12:02:00 GMT", 2 * time.Minute, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSecondaryLimitWait(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		retryAfter    string
+		body          string
+		previousWaits int
+		want          time.Duration
+		wantOK        bool
+	}{
+		{"retry-after", http.StatusForbidden, "45", "", 0, 45 * time.Second, true},
+		{"secondary message", http.StatusForbidden, "", `{"message":"You have exceeded a secondary rate limit."}`, 0, time.Minute, true},
+		{"backs off", http.StatusTooManyRequests, "", "", 2, 4 * time.Minute, true},
+		{"permission denied", http.StatusForbidden, "", `{"message":"Resource not accessible"}`, 0, 0, false},
+		{"not found", http.StatusNotFound, "10", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		if tt.retryAfter != "" {
+			resp.Header.Set("Retry-After", tt.retryAfter)
+		}
+		got, ok := secondaryLimitWait(resp, []byte(tt.body), tt.previousWaits)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: got %s, %v; want %s, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGetResumesAfterSecondaryLimit(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// With no retries left, only the secondary limit handling can recover
+	c := NewClient()
+	c.maxRetries = 0
+
+	body, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "ok" || calls != 2 {
+		t.Errorf("body = %q after %d calls, want \"ok\" after 2", body, calls)
+	}
+}
+
+func TestPostSendsJSONWithoutRetrying(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	// A POST the server may have applied must not be repeated
+	c := NewClient()
+	if _, err := c.Post(server.URL, []byte(`{"title":"x"}`)); err == nil {
+		t.Fatal("Post succeeded on a 502")
+	}
+	if calls != 1 {
+		t.Errorf("server called %d times, want 1", calls)
+	}
+}
+
+func TestSetTokenOverridesEnvironment(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.SetToken("")
+	c.Get(server.URL)
+	c.SetToken("from-flag")
+	c.Get(server.URL)
+
+	if want := []string{"Bearer from-env", "Bearer from-flag"}; !reflect.DeepEqual(auth, want) {
+		t.Errorf("Authorization headers = %q, want %q", auth, want)
+	}
+}
@@ -0,0 +1,13 @@
+package github
+
+// Commit is an entry of the list commits endpoint, as much of it as the
+// scripts read
+type Commit struct {
+	Sha    string `json:"sha"`
+	Commit struct {
+		Author struct {
+			Date string `json:"date"`
+		} `json:"author"`
+		Message string `json:"message"`
+	} `json:"commit"`
+}
@@ -0,0 +1,36 @@
+package github
+
+import (
+	"os"
+	"strings"
+)
+
+// Endpoints of github.com, used unless the environment points elsewhere
+const (
+	DefaultAPIBase = "https://api.github.com"
+	DefaultRawBase = "https://raw.githubusercontent.com"
+)
+
+// APIBase returns the REST API root: GITHUB_API_URL when set (GitHub Actions
+// sets it on every runner, including GitHub Enterprise Server's, e.g.
+// https://ghe.example.com/api/v3), otherwise api.github.com
+func APIBase() string {
+	if base := os.Getenv("GITHUB_API_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return DefaultAPIBase
+}
+
+// RawBase returns the root that serves raw file contents as
+// {base}/{owner}/{repo}/{ref}/{path}: GITHUB_RAW_URL when set, otherwise
+// https://HOST/raw for a GitHub Enterprise Server API root of
+// https://HOST/api/v3, otherwise raw.githubusercontent.com
+func RawBase() string {
+	if base := os.Getenv("GITHUB_RAW_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	if api := APIBase(); strings.HasSuffix(api, "/api/v3") {
+		return strings.TrimSuffix(api, "/api/v3") + "/raw"
+	}
+	return DefaultRawBase
+}
@@ -0,0 +1,637 @@
+// Package graphql is a small, dependency-free GraphQL query executor for
+// read-only APIs. It supports queries with variables, aliases, fragments,
+// @skip/@include and __typename; mutations, subscriptions and introspection
+// are not supported (use Schema.SDL to publish the schema instead).
+//
+// A schema is a set of object types whose fields are declared with SDL type
+// references such as "[App!]!". Fields without a resolver read the value of
+// the same name from the parent, which may be a map or a struct with json tags.
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Built-in scalar types
+var scalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+// ResolveFunc returns the value of a field given its parent value and coerced arguments
+type ResolveFunc func(source interface{}, args map[string]interface{}) (interface{}, error)
+
+// Schema describes the types reachable from the query root
+type Schema struct {
+	Query string
+	Types map[string]*Object
+}
+
+// Object is an object type
+type Object struct {
+	Description string
+	Fields      map[string]*FieldDef
+}
+
+// FieldDef declares a field, its arguments (name to SDL type) and resolver
+type FieldDef struct {
+	Type        string
+	Description string
+	Args        map[string]string
+	Resolve     ResolveFunc
+}
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL response; Data is omitted when the request failed before execution
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is a request or field error
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Execute parses and runs a request against the schema
+func (s *Schema) Execute(req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	vars, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{schema: s, doc: doc, vars: vars}
+	data, _ := e.selectionSet(s.Query, nil, op.Selections, nil)
+
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func (d *Document) operation(name string) (*Operation, error) {
+	if name == "" {
+		if len(d.Operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document contains several operations")
+		}
+		return d.Operations[0], nil
+	}
+
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+func coerceVariables(op *Operation, values map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	for _, def := range op.Variables {
+		value, provided := values[def.Name]
+		if !provided {
+			value = def.Default
+		}
+		if value == nil && strings.HasSuffix(def.Type, "!") {
+			return nil, fmt.Errorf("variable $%s of required type %s was not provided", def.Name, def.Type)
+		}
+
+		coerced, err := coerceInput(def.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("variable $%s: %w", def.Name, err)
+		}
+		vars[def.Name] = coerced
+	}
+	return vars, nil
+}
+
+type executor struct {
+	schema *Schema
+	doc    *Document
+	vars   map[string]interface{}
+	errors []*Error
+}
+
+func (e *executor) fail(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, &Error{Message: fmt.Sprintf(format, args...), Path: append([]interface{}{}, path...)})
+}
+
+// selectionSet resolves the selections on an object; ok is false when a
+// non-null field came back null and the object itself must become null
+func (e *executor) selectionSet(typeName string, source interface{}, selections []Selection, path []interface{}) (value interface{}, ok bool) {
+	obj := e.schema.Types[typeName]
+	if obj == nil {
+		e.fail(path, "unknown type %q", typeName)
+		return nil, false
+	}
+
+	fields, err := e.collectFields(typeName, selections, nil, make(map[string]bool))
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil, false
+	}
+
+	result := &orderedMap{}
+	for _, group := range fields {
+		field := group.fields[0]
+		fieldPath := append(path, group.key)
+
+		if field.Name == "__typename" {
+			result.set(group.key, typeName)
+			continue
+		}
+
+		def := obj.Fields[field.Name]
+		if def == nil {
+			e.fail(fieldPath, "cannot query field %q on type %q", field.Name, typeName)
+			result.set(group.key, nil)
+			continue
+		}
+
+		fieldValue, fieldOK := e.resolveField(def, source, group.fields, fieldPath)
+		if !fieldOK {
+			return nil, false
+		}
+		result.set(group.key, fieldValue)
+	}
+
+	return result, true
+}
+
+type fieldGroup struct {
+	key    string
+	fields []*Field
+}
+
+// collectFields flattens fragments and groups fields by response key, in query order
+func (e *executor) collectFields(typeName string, selections []Selection, groups []*fieldGroup, visited map[string]bool) ([]*fieldGroup, error) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *Field:
+			include, err := e.included(sel.Directives)
+			if err != nil {
+				return nil, err
+			}
+			if !include {
+				continue
+			}
+
+			var group *fieldGroup
+			for _, g := range groups {
+				if g.key == sel.Key() {
+					group = g
+					break
+				}
+			}
+			if group == nil {
+				group = &fieldGroup{key: sel.Key()}
+				groups = append(groups, group)
+			} else if group.fields[0].Name != sel.Name {
+				return nil, fmt.Errorf("fields %q and %q conflict on response key %q", group.fields[0].Name, sel.Name, sel.Key())
+			}
+			group.fields = append(group.fields, sel)
+
+		case *FragmentSpread:
+			include, err := e.included(sel.Directives)
+			if err != nil {
+				return nil, err
+			}
+			if !include || visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+
+			frag := e.doc.Fragments[sel.Name]
+			if frag == nil {
+				return nil, fmt.Errorf("unknown fragment %q", sel.Name)
+			}
+			if frag.TypeName != typeName {
+				continue
+			}
+			if groups, err = e.collectFields(typeName, frag.Selections, groups, visited); err != nil {
+				return nil, err
+			}
+
+		case *InlineFragment:
+			include, err := e.included(sel.Directives)
+			if err != nil {
+				return nil, err
+			}
+			if !include || (sel.TypeName != "" && sel.TypeName != typeName) {
+				continue
+			}
+			if groups, err = e.collectFields(typeName, sel.Selections, groups, visited); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// included evaluates @skip and @include
+func (e *executor) included(directives []*Directive) (bool, error) {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			return false, fmt.Errorf("unknown directive @%s", d.Name)
+		}
+
+		arg, err := e.value(d.Arguments["if"])
+		if err != nil {
+			return false, err
+		}
+		cond, ok := arg.(bool)
+		if !ok {
+			return false, fmt.Errorf("@%s requires a Boolean \"if\" argument", d.Name)
+		}
+
+		if (d.Name == "skip" && cond) || (d.Name == "include" && !cond) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (e *executor) resolveField(def *FieldDef, source interface{}, fields []*Field, path []interface{}) (interface{}, bool) {
+	field := fields[0]
+
+	args, err := e.arguments(def, field)
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil, !isNonNull(def.Type)
+	}
+
+	var value interface{}
+	if def.Resolve != nil {
+		value, err = def.Resolve(source, args)
+	} else {
+		value = defaultResolve(source, field.Name)
+	}
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil, !isNonNull(def.Type)
+	}
+
+	// Merge the sub-selections of fields sharing a response key
+	var selections []Selection
+	for _, f := range fields {
+		selections = append(selections, f.Selections...)
+	}
+
+	return e.complete(def.Type, value, selections, path)
+}
+
+// complete converts a resolved value to its response form according to typ
+func (e *executor) complete(typ string, value interface{}, selections []Selection, path []interface{}) (interface{}, bool) {
+	nonNull := isNonNull(typ)
+	typ = strings.TrimSuffix(typ, "!")
+
+	if isNil(value) {
+		if nonNull {
+			e.fail(path, "cannot return null for non-nullable type %s!", typ)
+			return nil, false
+		}
+		return nil, true
+	}
+
+	if strings.HasPrefix(typ, "[") {
+		inner := typ[1 : len(typ)-1]
+
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fail(path, "expected a list for type %s", typ)
+			return nil, !nonNull
+		}
+
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			item, ok := e.complete(inner, rv.Index(i).Interface(), selections, append(path, i))
+			if !ok {
+				return nil, !nonNull
+			}
+			list[i] = item
+		}
+		return list, true
+	}
+
+	if scalars[typ] {
+		if len(selections) > 0 {
+			e.fail(path, "field of scalar type %s can't have a selection set", typ)
+			return nil, !nonNull
+		}
+		return value, true
+	}
+
+	if len(selections) == 0 {
+		e.fail(path, "field of type %s must have a selection set", typ)
+		return nil, !nonNull
+	}
+
+	result, ok := e.selectionSet(typ, value, selections, path)
+	if !ok {
+		return nil, !nonNull
+	}
+	return result, true
+}
+
+// arguments resolves variables and coerces the field's arguments to their declared types
+func (e *executor) arguments(def *FieldDef, field *Field) (map[string]interface{}, error) {
+	for name := range field.Arguments {
+		if _, ok := def.Args[name]; !ok {
+			return nil, fmt.Errorf("unknown argument %q on field %q", name, field.Name)
+		}
+	}
+
+	args := make(map[string]interface{})
+	for name, typ := range def.Args {
+		raw, provided := field.Arguments[name]
+		value, err := e.value(raw)
+		if err != nil {
+			return nil, err
+		}
+		if !provided || value == nil {
+			if isNonNull(typ) {
+				return nil, fmt.Errorf("argument %q of type %s is required", name, typ)
+			}
+			continue
+		}
+
+		coerced, err := coerceInput(typ, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", name, err)
+		}
+		args[name] = coerced
+	}
+
+	return args, nil
+}
+
+// value substitutes variables in an argument literal
+func (e *executor) value(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case Variable:
+		value, ok := e.vars[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", v.Name)
+		}
+		return value, nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			value, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			value, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = value
+		}
+		return obj, nil
+	}
+	return v, nil
+}
+
+// coerceInput converts an input value to the Go type used for typ: string,
+// int, float64, bool or []interface{} for lists. Null is rejected for
+// non-null types, which matters for list items: a missing argument or variable
+// is caught before coercion, but [null] for [String!] only shows up here
+func coerceInput(typ string, value interface{}) (interface{}, error) {
+	nonNull := strings.HasSuffix(typ, "!")
+	typ = strings.TrimSuffix(typ, "!")
+	if value == nil {
+		if nonNull {
+			return nil, fmt.Errorf("expected %s!, got null", typ)
+		}
+		return nil, nil
+	}
+
+	if strings.HasPrefix(typ, "[") {
+		inner := typ[1 : len(typ)-1]
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value} // a single value is accepted as a one-item list
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			coerced, err := coerceInput(inner, item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = coerced
+		}
+		return list, nil
+	}
+
+	switch typ {
+	case "String", "ID":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int:
+			if typ == "ID" {
+				return fmt.Sprint(v), nil
+			}
+		}
+	case "Int":
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64:
+			// JSON variables decode numbers as float64
+			if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+				return int(v), nil
+			}
+		}
+	case "Float":
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case "Boolean":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported input type %s", typ)
+	}
+
+	return nil, fmt.Errorf("expected %s, got %v", typ, value)
+}
+
+// defaultResolve reads name from a map or a struct field tagged json:"name"
+func defaultResolve(source interface{}, name string) interface{} {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil
+		}
+		return v.Interface()
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if tag == name || (tag == "" && t.Field(i).Name == name) {
+				return rv.Field(i).Interface()
+			}
+		}
+	}
+
+	return nil
+}
+
+func isNonNull(typ string) bool {
+	return strings.HasSuffix(typ, "!")
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// SDL renders the schema in the GraphQL schema definition language
+func (s *Schema) SDL() string {
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// The query root first, then alphabetically
+		if (names[i] == s.Query) != (names[j] == s.Query) {
+			return names[i] == s.Query
+		}
+		return names[i] < names[j]
+	})
+
+	var sb strings.Builder
+	for i, name := range names {
+		obj := s.Types[name]
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if obj.Description != "" {
+			fmt.Fprintf(&sb, "\"\"\"%s\"\"\"\n", obj.Description)
+		}
+		fmt.Fprintf(&sb, "type %s {\n", name)
+
+		fieldNames := make([]string, 0, len(obj.Fields))
+		for fieldName := range obj.Fields {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+
+		for _, fieldName := range fieldNames {
+			def := obj.Fields[fieldName]
+			if def.Description != "" {
+				fmt.Fprintf(&sb, "  \"%s\"\n", def.Description)
+			}
+			fmt.Fprintf(&sb, "  %s%s: %s\n", fieldName, sdlArgs(def.Args), def.Type)
+		}
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+func sdlArgs(args map[string]string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ": " + args[name]
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// orderedMap is a JSON object that keeps keys in insertion order, as GraphQL
+// responses follow the order of the query
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,371 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testAuthor struct {
+	Name string `json:"name"`
+}
+
+type testBook struct {
+	ID     string      `json:"id"`
+	Title  string      `json:"title"`
+	Pages  int         `json:"pages,omitempty"`
+	Author *testAuthor `json:"author"`
+	Tags   []string    `json:"tags"`
+}
+
+var testBooks = []testBook{
+	{ID: "1", Title: "Dune", Pages: 412, Author: &testAuthor{Name: "Frank Herbert"}, Tags: []string{"sf"}},
+	{ID: "2", Title: "Emma", Pages: 474, Author: &testAuthor{Name: "Jane Austen"}},
+	{ID: "3", Title: "Neuromancer", Author: &testAuthor{Name: "William Gibson"}, Tags: []string{"sf", "cyberpunk"}},
+}
+
+// testSchema serves testBooks, plus fields that fail in each way a resolver can
+func testSchema() *Schema {
+	return &Schema{
+		Query: "Query",
+		Types: map[string]*Object{
+			"Query": {Fields: map[string]*FieldDef{
+				"books": {
+					Type: "[Book!]!",
+					Args: map[string]string{"author": "String", "limit": "Int", "ids": "[ID!]"},
+					Resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+						ids := make(map[string]bool)
+						if list, ok := args["ids"].([]interface{}); ok {
+							for _, id := range list {
+								ids[id.(string)] = true
+							}
+						}
+						result := []testBook{}
+						for _, book := range testBooks {
+							if author, ok := args["author"].(string); ok && book.Author.Name != author {
+								continue
+							}
+							if len(ids) > 0 && !ids[book.ID] {
+								continue
+							}
+							result = append(result, book)
+						}
+						if limit, ok := args["limit"].(int); ok && limit < len(result) {
+							result = result[:limit]
+						}
+						return result, nil
+					},
+				},
+				"book": {
+					Type: "Book",
+					Args: map[string]string{"id": "ID!"},
+					Resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+						for i := range testBooks {
+							if testBooks[i].ID == args["id"] {
+								return &testBooks[i], nil
+							}
+						}
+						return nil, nil
+					},
+				},
+				"broken": {
+					Type: "String",
+					Resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+						return nil, errors.New("boom")
+					},
+				},
+				"mustExist": {
+					Type: "Book!",
+					Resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+						return nil, nil
+					},
+				},
+				"meta": {
+					Type: "Meta",
+					Resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+						return map[string]interface{}{"count": 3, "ratio": 0.5, "open": true}, nil
+					},
+				},
+			}},
+			"Book": {Description: "A book", Fields: map[string]*FieldDef{
+				"id":     {Type: "ID!"},
+				"title":  {Type: "String!", Description: "Title as printed"},
+				"pages":  {Type: "Int"},
+				"author": {Type: "Author"},
+				"tags":   {Type: "[String!]"},
+			}},
+			"Author": {Fields: map[string]*FieldDef{
+				"name": {Type: "String!"},
+			}},
+			"Meta": {Fields: map[string]*FieldDef{
+				"count": {Type: "Int!"},
+				"ratio": {Type: "Float"},
+				"open":  {Type: "Boolean"},
+			}},
+		},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables string // JSON, as sent over HTTP
+		want      string
+	}{
+		{
+			name:  "lists, nested objects and default resolvers",
+			query: "{ books { title author { name } tags } meta { count ratio open } }",
+			want: `{"data":{"books":[
+				{"title":"Dune","author":{"name":"Frank Herbert"},"tags":["sf"]},
+				{"title":"Emma","author":{"name":"Jane Austen"},"tags":null},
+				{"title":"Neuromancer","author":{"name":"William Gibson"},"tags":["sf","cyberpunk"]}
+			],"meta":{"count":3,"ratio":0.5,"open":true}}}`,
+		},
+		{
+			name:  "response keys follow the query order",
+			query: "{ book(id: 1) { title id pages } }",
+			want:  `{"data":{"book":{"title":"Dune","id":"1","pages":412}}}`,
+		},
+		{
+			name:  "aliases",
+			query: `{ first: book(id: "1") { name: title } second: book(id: "2") { title } missing: book(id: "9") { title } }`,
+			want:  `{"data":{"first":{"name":"Dune"},"second":{"title":"Emma"},"missing":null}}`,
+		},
+		{
+			name:  "fields sharing a response key are merged",
+			query: `{ book(id: "1") { author { name } title author { name } } }`,
+			want:  `{"data":{"book":{"author":{"name":"Frank Herbert"},"title":"Dune"}}}`,
+		},
+		{
+			name:  "__typename",
+			query: `{ __typename book(id: "1") { __typename kind: __typename } }`,
+			want:  `{"data":{"__typename":"Query","book":{"__typename":"Book","kind":"Book"}}}`,
+		},
+		{
+			name:      "variables with defaults",
+			query:     "query ($author: String, $limit: Int = 1) { books(author: $author, limit: $limit) { title } }",
+			variables: `{"author": "William Gibson"}`,
+			want:      `{"data":{"books":[{"title":"Neuromancer"}]}}`,
+		},
+		{
+			name:      "JSON numbers coerce to Int",
+			query:     "query ($limit: Int) { books(limit: $limit) { id } }",
+			variables: `{"limit": 2}`,
+			want:      `{"data":{"books":[{"id":"1"},{"id":"2"}]}}`,
+		},
+		{
+			name:      "a single value is accepted for a list",
+			query:     "query ($ids: [ID!]) { one: books(ids: $ids) { id } two: books(ids: [1, \"3\"]) { id } }",
+			variables: `{"ids": "2"}`,
+			want:      `{"data":{"one":[{"id":"2"}],"two":[{"id":"1"},{"id":"3"}]}}`,
+		},
+		{
+			name:  "missing required variable",
+			query: "query ($id: ID!) { book(id: $id) { title } }",
+			want:  `{"errors":[{"message":"variable $id of required type ID! was not provided"}]}`,
+		},
+		{
+			name:      "variable of the wrong type",
+			query:     "query ($limit: Int) { books(limit: $limit) { id } }",
+			variables: `{"limit": 1.5}`,
+			want:      `{"errors":[{"message":"variable $limit: expected Int, got 1.5"}]}`,
+		},
+		{
+			name:  "undefined variable",
+			query: "{ books(limit: $limit) { id } }",
+			want:  `{"errors":[{"message":"variable $limit is not defined","path":["books"]}]}`,
+		},
+		{
+			name:  "named and inline fragments",
+			query: `{ book(id: "2") { ...Basics ... on Book { pages } ... { author { ...Who } } } } fragment Basics on Book { id title } fragment Who on Author { name }`,
+			want:  `{"data":{"book":{"id":"2","title":"Emma","pages":474,"author":{"name":"Jane Austen"}}}}`,
+		},
+		{
+			name:  "fragments on another type are skipped",
+			query: `{ book(id: "1") { ...Who title ... on Author { name } } } fragment Who on Author { name }`,
+			want:  `{"data":{"book":{"title":"Dune"}}}`,
+		},
+		{
+			name:  "recursive fragments are spread once",
+			query: `{ book(id: "1") { ...A } } fragment A on Book { title ...A }`,
+			want:  `{"data":{"book":{"title":"Dune"}}}`,
+		},
+		{
+			name:  "unknown fragment",
+			query: `{ book(id: "1") { ...Nope } }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"unknown fragment \"Nope\"","path":["book"]}]}`,
+		},
+		{
+			name:      "skip and include",
+			query:     `query ($yes: Boolean!) { book(id: "1") { title @skip(if: $yes) pages @include(if: $yes) id @include(if: false) ...F @skip(if: true) } } fragment F on Book { tags }`,
+			variables: `{"yes": true}`,
+			want:      `{"data":{"book":{"pages":412}}}`,
+		},
+		{
+			name:  "unknown directive",
+			query: `{ book(id: "1") { title @deprecated } }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"unknown directive @deprecated","path":["book"]}]}`,
+		},
+		{
+			name:      "operationName picks an operation",
+			query:     `query A { book(id: "1") { title } } query B { book(id: "2") { title } }`,
+			operation: "B",
+			want:      `{"data":{"book":{"title":"Emma"}}}`,
+		},
+		{
+			name:  "operationName is required with several operations",
+			query: `query A { broken } query B { broken }`,
+			want:  `{"errors":[{"message":"operationName is required when the document contains several operations"}]}`,
+		},
+		{
+			name:      "unknown operationName",
+			query:     `query A { broken }`,
+			operation: "C",
+			want:      `{"errors":[{"message":"unknown operation \"C\""}]}`,
+		},
+		{
+			name:  "resolver errors null the field and keep the rest",
+			query: `{ broken book(id: "1") { title } }`,
+			want:  `{"data":{"broken":null,"book":{"title":"Dune"}},"errors":[{"message":"boom","path":["broken"]}]}`,
+		},
+		{
+			name:  "null in a non-null field nulls the whole response",
+			query: `{ book(id: "1") { title } mustExist { title } }`,
+			want:  `{"errors":[{"message":"cannot return null for non-nullable type Book!","path":["mustExist"]}]}`,
+		},
+		{
+			name:  "errors carry list indexes in their path",
+			query: `{ books { title { x } } }`,
+			want:  `{"errors":[{"message":"field of scalar type String can't have a selection set","path":["books",0,"title"]}]}`,
+		},
+		{
+			name:  "unknown field",
+			query: `{ nope book(id: "1") { nope } }`,
+			want: `{"data":{"nope":null,"book":{"nope":null}},"errors":[
+				{"message":"cannot query field \"nope\" on type \"Query\"","path":["nope"]},
+				{"message":"cannot query field \"nope\" on type \"Book\"","path":["book","nope"]}
+			]}`,
+		},
+		{
+			name:  "unknown argument",
+			query: `{ book(id: "1", by: "me") { title } }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"unknown argument \"by\" on field \"book\"","path":["book"]}]}`,
+		},
+		{
+			name:  "missing required argument",
+			query: `{ book { title } }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"argument \"id\" of type ID! is required","path":["book"]}]}`,
+		},
+		{
+			name:  "argument of the wrong type",
+			query: `{ books(limit: "two") { title } }`,
+			want:  `{"errors":[{"message":"argument \"limit\": expected Int, got two","path":["books"]}]}`,
+		},
+		{
+			name:  "null item in a list of non-null items",
+			query: `{ books(ids: [null]) { title } }`,
+			want:  `{"errors":[{"message":"argument \"ids\": expected ID!, got null","path":["books"]}]}`,
+		},
+		{
+			name:      "null item in a list variable",
+			query:     "query ($ids: [ID!]) { books(ids: $ids) { title } }",
+			variables: `{"ids": ["1", null]}`,
+			want:      `{"errors":[{"message":"variable $ids: expected ID!, got null"}]}`,
+		},
+		{
+			name:  "list item of the wrong type",
+			query: `{ books(ids: ["1", true]) { title } }`,
+			want:  `{"errors":[{"message":"argument \"ids\": expected ID, got true","path":["books"]}]}`,
+		},
+		{
+			name:  "null for a required argument",
+			query: `{ book(id: null) { title } }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"argument \"id\" of type ID! is required","path":["book"]}]}`,
+		},
+		{
+			name:  "object without a selection set",
+			query: `{ book(id: "1") }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"field of type Book must have a selection set","path":["book"]}]}`,
+		},
+		{
+			name:  "conflicting aliases",
+			query: `{ book(id: "1") { x: title x: pages } }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"fields \"title\" and \"pages\" conflict on response key \"x\"","path":["book"]}]}`,
+		},
+		{
+			name:  "syntax errors are reported with their offset",
+			query: "{\n  book(id: 1 {\n}",
+			want:  `{"errors":[{"message":"syntax error at offset 15: unexpected \"{\""}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{Query: tt.query, OperationName: tt.operation}
+			if tt.variables != "" {
+				if err := json.Unmarshal([]byte(tt.variables), &req.Variables); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := json.Marshal(testSchema().Execute(req))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want bytes.Buffer
+			if err := json.Compact(&want, []byte(tt.want)); err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want.String() {
+				t.Errorf("Execute(%q)\n got %s\nwant %s", tt.query, got, want.String())
+			}
+		})
+	}
+}
+
+func TestSDL(t *testing.T) {
+	want := `type Query {
+  book(id: ID!): Book
+  books(author: String, ids: [ID!], limit: Int): [Book!]!
+  broken: String
+  meta: Meta
+  mustExist: Book!
+}
+
+type Author {
+  name: String!
+}
+
+"""A book"""
+type Book {
+  author: Author
+  id: ID!
+  pages: Int
+  tags: [String!]
+  "Title as printed"
+  title: String!
+}
+
+type Meta {
+  count: Int!
+  open: Boolean
+  ratio: Float
+}
+`
+	if got := testSchema().SDL(); got != want {
+		t.Errorf("SDL() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// dump renders a parsed document for failure messages
+func dump(v interface{}) string {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return strings.TrimSpace(string(out))
+}
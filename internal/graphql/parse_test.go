@@ -0,0 +1,156 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  *Document
+	}{
+		{
+			name:  "shorthand query",
+			query: "{ apps { slug } }",
+			want: &Document{
+				Operations: []*Operation{{Selections: []Selection{
+					&Field{Name: "apps", Arguments: map[string]interface{}{}, Selections: []Selection{
+						&Field{Name: "slug", Arguments: map[string]interface{}{}},
+					}},
+				}}},
+				Fragments: map[string]*Fragment{},
+			},
+		},
+		{
+			name:  "named operation with variables and defaults",
+			query: `query Recent($since: String = "2025-01-01", $limit: Int!, $slugs: [String!]) { apps(changedSince: $since) { slug } }`,
+			want: &Document{
+				Operations: []*Operation{{
+					Name: "Recent",
+					Variables: []*VariableDefinition{
+						{Name: "since", Type: "String", Default: "2025-01-01"},
+						{Name: "limit", Type: "Int!"},
+						{Name: "slugs", Type: "[String!]"},
+					},
+					Selections: []Selection{
+						&Field{Name: "apps", Arguments: map[string]interface{}{"changedSince": Variable{Name: "since"}}, Selections: []Selection{
+							&Field{Name: "slug", Arguments: map[string]interface{}{}},
+						}},
+					},
+				}},
+				Fragments: map[string]*Fragment{},
+			},
+		},
+		{
+			name:  "aliases, directives and argument literals",
+			query: "{ mac: apps(platform: \"darwin\", limit: -3, ratio: 1.5e2, on: true, off: false, none: null, kind: DARWIN, list: [1, \"two\"], obj: {a: 1}) @include(if: $all) { slug } }",
+			want: &Document{
+				Operations: []*Operation{{Selections: []Selection{
+					&Field{
+						Alias: "mac",
+						Name:  "apps",
+						Arguments: map[string]interface{}{
+							"platform": "darwin",
+							"limit":    -3,
+							"ratio":    150.0,
+							"on":       true,
+							"off":      false,
+							"none":     nil,
+							"kind":     EnumValue("DARWIN"),
+							"list":     []interface{}{1, "two"},
+							"obj":      map[string]interface{}{"a": 1},
+						},
+						Directives: []*Directive{{Name: "include", Arguments: map[string]interface{}{"if": Variable{Name: "all"}}}},
+						Selections: []Selection{&Field{Name: "slug", Arguments: map[string]interface{}{}}},
+					},
+				}}},
+				Fragments: map[string]*Fragment{},
+			},
+		},
+		{
+			name:  "fragments and inline fragments",
+			query: "query { app(slug: \"zoom/darwin\") { ...Names ... on App { version } ... @skip(if: true) { platform } } } fragment Names on App { slug name }",
+			want: &Document{
+				Operations: []*Operation{{Selections: []Selection{
+					&Field{Name: "app", Arguments: map[string]interface{}{"slug": "zoom/darwin"}, Selections: []Selection{
+						&FragmentSpread{Name: "Names"},
+						&InlineFragment{TypeName: "App", Selections: []Selection{&Field{Name: "version", Arguments: map[string]interface{}{}}}},
+						&InlineFragment{
+							Directives: []*Directive{{Name: "skip", Arguments: map[string]interface{}{"if": true}}},
+							Selections: []Selection{&Field{Name: "platform", Arguments: map[string]interface{}{}}},
+						},
+					}},
+				}}},
+				Fragments: map[string]*Fragment{
+					"Names": {Name: "Names", TypeName: "App", Selections: []Selection{
+						&Field{Name: "slug", Arguments: map[string]interface{}{}},
+						&Field{Name: "name", Arguments: map[string]interface{}{}},
+					}},
+				},
+			},
+		},
+		{
+			name:  "strings, comments, commas and a BOM",
+			query: "\uFEFF# leading comment\n{ a(s: \"tab\\there \\\"quoted\\\" \\u00e9\", b: \"\"\"\n  block \"string\"\n\"\"\"),,, }",
+			want: &Document{
+				Operations: []*Operation{{Selections: []Selection{
+					&Field{Name: "a", Arguments: map[string]interface{}{
+						"s": "tab\there \"quoted\" é",
+						"b": `block "string"`,
+					}},
+				}}},
+				Fragments: map[string]*Fragment{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			if !reflect.DeepEqual(doc, tt.want) {
+				t.Errorf("Parse() = %s, want %s", dump(doc), dump(tt.want))
+			}
+		})
+	}
+}
+
+// TestParseErrors checks syntax errors point at the offending token
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "document contains no operations"},
+		{"{ }", "syntax error at offset 2: empty selection set"},
+		{"{ a(x: 1 }", `syntax error at offset 9: unexpected "}"`},
+		{"{ apps(", "syntax error at offset 7: unexpected end of document"},
+		{"query Q($id: ID!) {\n  app(slug: $id) {\n    slug\n  }\n", "syntax error at offset 52: unexpected end of document"},
+		{`{ a(s: "abc) }`, "syntax error at offset 7: unterminated string"},
+		{"{ a(s: \"x\ny\") }", "syntax error at offset 7: unterminated string"},
+		{`{ a(s: "\q") }`, `syntax error at offset 7: invalid escape \q`},
+		{`{ a(s: "\u00") }`, "syntax error at offset 7: invalid unicode escape"},
+		{`{ a(s: """open) }`, "syntax error at offset 7: unterminated block string"},
+		{"{ a }\n{ b }\n%", "syntax error at offset 12: unexpected character '%'"},
+		{"{ a.b }", `syntax error at offset 3: unexpected "."`},
+		{"query ($v: [Int!]! = [1, $w]) { a }", `syntax error at offset 25: unexpected "$"`},
+		{"query Q($v: [Int!) { a }", `syntax error at offset 17: unexpected ")"`},
+		{"{ a } garbage", `syntax error at offset 6: unexpected "garbage"`},
+		{"mutation { a }", "mutation operations are not supported"},
+		{"subscription { a }", "subscription operations are not supported"},
+		{"fragment F on App { slug }", "document contains no operations"},
+		{"{ a } fragment F on B { x } fragment F on B { y }", `fragment "F" is defined more than once`},
+		{"{ a(x: 1, x: 2) }", `argument "x" is given more than once`},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.query, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package httpcache keeps GitHub raw file and API responses on disk and
+// revalidates them with their ETag or Last-Modified date, so repeated runs
+// download only what changed (a 304 doesn't count against the API rate
+// limit) and a flaky fetch falls back to the last good copy instead of
+// changing the output.
+// Scripts call InstallFromEnv at startup, before vcr.InstallFromEnv so that
+// recorded cassettes never see a 304:
+//
+//	HTTP_CACHE_DIR=/tmp/fleet-cache go run main.go   # cache elsewhere
+//	HTTP_CACHE_DIR=off go run main.go                # disable the cache
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/github"
+)
+
+// DirEnv overrides DefaultDir; "off" disables the cache
+const DirEnv = "HTTP_CACHE_DIR"
+
+// DefaultDir is where responses are cached, relative to the repository root
+const DefaultDir = "data/.cache"
+
+// MaxAge is how long an entry is kept without being used, so URLs that are
+// no longer requested (such as shifted pages of a commit listing) don't
+// accumulate in a cache that persists across CI runs
+const MaxAge = 30 * 24 * time.Hour
+
+// entry is one cached response, stored as <dir>/<sha256 of URL>.json
+type entry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// Transport is an http.RoundTripper that caches GET responses for URLs under
+// one of its prefixes. Only 200 responses carrying an ETag or Last-Modified
+// date are stored.
+type Transport struct {
+	dir      string
+	prefixes []string
+	next     http.RoundTripper
+}
+
+// New returns a transport caching responses for URLs starting with any of
+// prefixes in dir, sending requests through next
+func New(dir string, prefixes []string, next http.RoundTripper) *Transport {
+	return &Transport{dir: dir, prefixes: prefixes, next: next}
+}
+
+// InstallFromEnv wraps http.DefaultTransport with a cache of GitHub raw file
+// contents (GITHUB_RAW_URL or raw.githubusercontent.com) and repository API
+// responses (GITHUB_API_URL or api.github.com) in HTTP_CACHE_DIR, defaulting
+// to data/.cache. Entries unused for MaxAge are removed first.
+func InstallFromEnv() {
+	dir := os.Getenv(DirEnv)
+	if dir == "off" {
+		return
+	}
+	if dir == "" {
+		dir = DefaultDir
+	}
+
+	prune(dir, time.Now().Add(-MaxAge))
+	http.DefaultTransport = New(dir, []string{github.RawBase() + "/", github.APIBase() + "/repos/"}, http.DefaultTransport)
+}
+
+// RoundTrip implements http.RoundTripper. A cached response is revalidated
+// with If-None-Match, or If-Modified-Since when the server sent no ETag, and
+// served again on 304; it is also served when the request fails or the
+// server answers with a 5xx.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !t.cacheable(req.URL.String()) {
+		return t.next.RoundTrip(req)
+	}
+
+	cached := t.load(req.URL.String())
+	if cached != nil && req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == "" {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		} else {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		if cached != nil {
+			fmt.Printf("⚠️  Using cached %s: %v\n", cached.URL, err)
+			return cached.response(req, nil), nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		t.touch(cached.URL)
+		return cached.response(req, resp.Header), nil
+	case resp.StatusCode >= 500 && cached != nil:
+		resp.Body.Close()
+		fmt.Printf("⚠️  Using cached %s: status %d\n", cached.URL, resp.StatusCode)
+		return cached.response(req, nil), nil
+	case resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""):
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		// A cache that can't be written only costs a download next time
+		t.save(&entry{
+			URL:          req.URL.String(),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Header:       resp.Header,
+			Body:         body,
+		})
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) cacheable(url string) bool {
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(url, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Transport) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached response for url, or nil when there is none
+func (t *Transport) load(url string) *entry {
+	data, err := os.ReadFile(t.path(url))
+	if err != nil {
+		return nil
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.URL != url || (e.ETag == "" && e.LastModified == "") {
+		return nil
+	}
+
+	return &e
+}
+
+// touch marks the entry for url as used, so prune keeps it
+func (t *Transport) touch(url string) {
+	now := time.Now()
+	os.Chtimes(t.path(url), now, now)
+}
+
+// prune removes the entries in dir last written or revalidated before cutoff
+func prune(dir string, cutoff time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		if info, err := e.Info(); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+// save writes e through a temporary file, so concurrent requests for the same
+// URL never leave a partial entry behind
+func (t *Transport) save(e *entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(t.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), t.path(e.URL))
+}
+
+// response rebuilds the cached 200 response, with any headers of the 304 that
+// revalidated it (such as the rate limit counters) taking precedence
+func (e *entry) response(req *http.Request, fresh http.Header) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for name, values := range fresh {
+		if name != "Content-Length" {
+			header[name] = values
+		}
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,143 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRevalidateAndServeStale(t *testing.T) {
+	var notModified, failing int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing > 0 {
+			failing--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.Header().Set("X-RateLimit-Remaining", "41")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		io.WriteString(w, "apps")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: New(t.TempDir(), []string{server.URL + "/"}, http.DefaultTransport)}
+	get := func() (string, *http.Response) {
+		t.Helper()
+		resp, err := client.Get(server.URL + "/apps.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), resp
+	}
+
+	if body, _ := get(); body != "apps" {
+		t.Fatalf("first body = %q, want %q", body, "apps")
+	}
+
+	body, resp := get()
+	if body != "apps" || resp.StatusCode != http.StatusOK || notModified != 1 {
+		t.Errorf("revalidated: body %q, status %d after %d 304s; want \"apps\", 200 after 1", body, resp.StatusCode, notModified)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "41" {
+		t.Errorf("X-RateLimit-Remaining = %q, want the 304's \"41\"", got)
+	}
+
+	failing = 1
+	if body, resp := get(); body != "apps" || resp.StatusCode != http.StatusOK {
+		t.Errorf("server error: body %q, status %d; want the cached copy", body, resp.StatusCode)
+	}
+}
+
+func TestOnlyPrefixedURLsAreCached(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("%s was revalidated, want it uncached", r.URL.Path)
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, "installer")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: New(t.TempDir(), []string{server.URL + "/raw/"}, http.DefaultTransport)}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/installer.pkg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if calls != 2 {
+		t.Errorf("server saw %d requests, want 2", calls)
+	}
+}
+
+func TestRevalidateWithLastModified(t *testing.T) {
+	const lastModified = "Mon, 02 Jun 2025 10:00:00 GMT"
+	notModified := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("sent If-None-Match %q without a cached ETag", r.Header.Get("If-None-Match"))
+		}
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		io.WriteString(w, `[{"sha":"abc"}]`)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: New(t.TempDir(), []string{server.URL + "/repos/"}, http.DefaultTransport)}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/repos/fleetdm/fleet/commits")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != `[{"sha":"abc"}]` {
+			t.Errorf("request %d: body = %q", i+1, body)
+		}
+	}
+	if notModified != 1 {
+		t.Errorf("server answered %d requests with 304, want 1", notModified)
+	}
+}
+
+func TestPruneRemovesUnusedEntries(t *testing.T) {
+	dir := t.TempDir()
+	old, recent := filepath.Join(dir, "old.json"), filepath.Join(dir, "recent.json")
+	for _, path := range []string{old, recent} {
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale := time.Now().Add(-MaxAge - time.Hour)
+	if err := os.Chtimes(old, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	prune(dir, time.Now().Add(-MaxAge))
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("entry unused for longer than MaxAge was kept (stat: %v)", err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent entry was removed: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package metrics collects run metrics and writes them in the Prometheus text
+// exposition format, either as a node_exporter textfile or pushed to a
+// Pushgateway.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	typeGauge   = "gauge"
+	typeCounter = "counter"
+)
+
+type metric struct {
+	help  string
+	typ   string
+	value float64
+}
+
+// Registry holds the metrics for a single run. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]*metric
+}
+
+// New returns an empty registry
+func New() *Registry {
+	return &Registry{metrics: make(map[string]*metric)}
+}
+
+// Set sets a gauge to value
+func (r *Registry) Set(name, help string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.get(name, help, typeGauge).value = value
+}
+
+// SetTime sets a gauge to t as Unix seconds, the convention for timestamps
+func (r *Registry) SetTime(name, help string, t time.Time) {
+	r.Set(name, help, float64(t.UnixNano())/1e9)
+}
+
+// Add increments a counter by delta
+func (r *Registry) Add(name, help string, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.get(name, help, typeCounter).value += delta
+}
+
+func (r *Registry) get(name, help, typ string) *metric {
+	m, ok := r.metrics[name]
+	if !ok {
+		m = &metric{help: help, typ: typ}
+		r.metrics[name] = m
+	}
+	return m
+}
+
+// Text renders the registry in the Prometheus text exposition format
+func (r *Registry) Text() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		m := r.metrics[name]
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, strings.ReplaceAll(m.help, "\n", " "))
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, m.typ)
+		fmt.Fprintf(&buf, "%s %s\n", name, strconv.FormatFloat(m.value, 'g', -1, 64))
+	}
+
+	return buf.Bytes()
+}
+
+// WriteFile writes the metrics to path atomically, so a textfile collector
+// never reads a partially written file
+func (r *Registry) WriteFile(path string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create metrics directory: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, r.Text(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	return nil
+}
+
+// Push replaces the metrics for job on the Pushgateway at gatewayURL
+func (r *Registry) Push(gatewayURL, job string) error {
+	url := strings.TrimRight(gatewayURL, "/") + "/metrics/job/" + job
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(r.Text()))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to push metrics (status %d)", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+// Package milestone projects when the library will reach its next round app
+// counts, for the dashboard and the README.
+package milestone
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	Step       = 50 // Milestones are multiples of this many apps
+	Count      = 2  // How many upcoming milestones to project
+	WindowDays = 90 // The growth rate is measured over this many trailing days
+)
+
+// Milestone is a projected date (YYYY-MM-DD) for reaching an app count
+type Milestone struct {
+	Count int    `json:"count"`
+	Date  string `json:"date"`
+}
+
+// Project extrapolates the growth over the trailing WindowDays of daily
+// counts to the next Count multiples of Step. It returns nil when the library
+// hasn't grown over that window.
+func Project(dates []string, counts []int) []Milestone {
+	if len(dates) < 2 {
+		return nil
+	}
+
+	last := len(dates) - 1
+	start := last - WindowDays
+	if start < 0 {
+		start = 0
+	}
+	lastDate, err := time.Parse("2006-01-02", dates[last])
+	if err != nil {
+		return nil
+	}
+	startDate, err := time.Parse("2006-01-02", dates[start])
+	if err != nil {
+		return nil
+	}
+
+	days := lastDate.Sub(startDate).Hours() / 24
+	if days <= 0 || counts[last] <= counts[start] {
+		return nil
+	}
+	perDay := float64(counts[last]-counts[start]) / days
+
+	var projected []Milestone
+	target := (counts[last]/Step + 1) * Step
+	for i := 0; i < Count; i++ {
+		daysAhead := int(math.Ceil(float64(target-counts[last]) / perDay))
+		projected = append(projected, Milestone{
+			Count: target,
+			Date:  lastDate.AddDate(0, 0, daysAhead).Format("2006-01-02"),
+		})
+		target += Step
+	}
+
+	return projected
+}
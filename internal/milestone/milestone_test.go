@@ -0,0 +1,39 @@
+package milestone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		name   string
+		dates  []string
+		counts []int
+		want   []Milestone
+	}{
+		{
+			name:   "steady growth",
+			dates:  []string{"2025-01-01", "2025-01-11"},
+			counts: []int{100, 120},
+			want:   []Milestone{{Count: 150, Date: "2025-01-26"}, {Count: 200, Date: "2025-02-20"}},
+		},
+		{
+			name:   "no growth",
+			dates:  []string{"2025-01-01", "2025-01-11"},
+			counts: []int{120, 120},
+		},
+		{
+			name:   "single day",
+			dates:  []string{"2025-01-01"},
+			counts: []int{120},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Project(tt.dates, tt.counts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Project() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Package objectstore uploads files to an S3 or Google Cloud Storage bucket
+// using the S3 XML API and AWS Signature Version 4. GCS accepts the same
+// requests at storage.googleapis.com when authenticated with an HMAC key, so
+// one small client covers both without pulling in either SDK.
+package objectstore
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	gcsEndpoint   = "https://storage.googleapis.com"
+	defaultRegion = "us-east-1"
+)
+
+// now is the clock requests are signed with; tests fix it
+var now = time.Now
+
+// Object is an entry of a bucket listing
+type Object struct {
+	Key  string // Relative to the bucket's prefix
+	ETag string // Without quotes; the MD5 of the body for single-part uploads
+}
+
+// Bucket is a bucket (and optional key prefix) that objects are synced to
+type Bucket struct {
+	httpClient   *http.Client
+	endpoint     string
+	name         string
+	prefix       string
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// Open parses a bucket URI such as s3://my-bucket/site or gs://my-bucket and
+// reads credentials from the environment: AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN for S3, GCS_HMAC_ACCESS_ID and
+// GCS_HMAC_SECRET for GCS. endpoint overrides the service URL (e.g. for MinIO
+// or Cloudflare R2) and region defaults to AWS_REGION or us-east-1.
+func Open(uri, endpoint, region string) (*Bucket, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid bucket %q (expected s3://bucket[/prefix] or gs://bucket[/prefix])", uri)
+	}
+
+	b := &Bucket{
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+		name:       u.Host,
+		prefix:     strings.Trim(u.Path, "/"),
+		region:     region,
+	}
+	if b.prefix != "" {
+		b.prefix += "/"
+	}
+
+	switch u.Scheme {
+	case "s3":
+		b.accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		b.secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		b.sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		if b.region == "" {
+			b.region = os.Getenv("AWS_REGION")
+		}
+		if b.region == "" {
+			b.region = defaultRegion
+		}
+		b.endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", b.region)
+	case "gs":
+		b.accessKey = os.Getenv("GCS_HMAC_ACCESS_ID")
+		b.secretKey = os.Getenv("GCS_HMAC_SECRET")
+		if b.region == "" {
+			b.region = "auto"
+		}
+		b.endpoint = gcsEndpoint
+	default:
+		return nil, fmt.Errorf("unsupported bucket scheme %q (want s3 or gs)", u.Scheme)
+	}
+	if endpoint != "" {
+		b.endpoint = strings.TrimRight(endpoint, "/")
+	}
+
+	if b.accessKey == "" || b.secretKey == "" {
+		return nil, fmt.Errorf("no credentials for %s:// buckets in the environment", u.Scheme)
+	}
+
+	return b, nil
+}
+
+// String returns the bucket URI the objects are synced to
+func (b *Bucket) String() string {
+	return fmt.Sprintf("%s/%s/%s", b.endpoint, b.name, b.prefix)
+}
+
+// List returns every object under the bucket's prefix
+func (b *Bucket) List() ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if b.prefix != "" {
+			query.Set("prefix", b.prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		body, err := b.do(http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", b, err)
+		}
+
+		var result struct {
+			Contents []struct {
+				Key  string `xml:"Key"`
+				ETag string `xml:"ETag"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse bucket listing: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, Object{
+				Key:  strings.TrimPrefix(c.Key, b.prefix),
+				ETag: strings.Trim(c.ETag, `"`),
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// Put uploads body as key (relative to the prefix); contentEncoding and
+// cacheControl are left unset when empty
+func (b *Bucket) Put(key string, body []byte, contentType, contentEncoding, cacheControl string) error {
+	headers := map[string]string{"Content-Type": contentType}
+	if contentEncoding != "" {
+		headers["Content-Encoding"] = contentEncoding
+	}
+	if cacheControl != "" {
+		headers["Cache-Control"] = cacheControl
+	}
+	if _, err := b.do(http.MethodPut, b.prefix+key, nil, headers, body); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key (relative to the prefix)
+func (b *Bucket) Delete(key string) error {
+	if _, err := b.do(http.MethodDelete, b.prefix+key, nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// do sends a signed path-style request for key and returns the response body
+func (b *Bucket) do(method, key string, query url.Values, headers map[string]string, body []byte) ([]byte, error) {
+	path := "/" + b.name
+	if key != "" {
+		path += "/" + key
+	}
+	rawURL := b.endpoint + escapePath(path)
+	if len(query) > 0 {
+		rawURL += "?" + canonicalQuery(query)
+	}
+
+	req, err := http.NewRequest(method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	b.sign(req, body, now().UTC())
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req
+func (b *Bucket) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if b.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", b.sessionToken)
+	}
+
+	// Sign the host and every x-amz-* header
+	signed := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			signed[lower] = strings.TrimSpace(values[0])
+		}
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + b.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+b.secretKey), day)
+	key = hmacSHA256(key, b.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		b.accessKey, scope, signedHeaders, signature))
+}
+
+// escapePath percent-encodes everything but unreserved characters and slashes,
+// as Signature Version 4 expects for S3 object keys
+func escapePath(path string) string {
+	var sb strings.Builder
+	for _, c := range []byte(path) {
+		if c == '/' || isUnreserved(c) {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// canonicalQuery encodes query sorted by name, with spaces as %20
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func isUnreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '~'
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,36 @@
+// Package proxy routes the scripts' HTTP traffic through an egress proxy.
+// Every client in this repo uses http.DefaultTransport, which already honors
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY; Configure adds an explicit override
+// for the --proxy flag.
+package proxy
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// FlagUsage is the help text shared by every script's --proxy flag
+const FlagUsage = "send HTTP(S) requests through this proxy URL (default: HTTP_PROXY/HTTPS_PROXY from the environment)"
+
+// Configure points HTTP and HTTPS requests at proxyURL, keeping NO_PROXY
+// exclusions. An empty proxyURL leaves the environment settings alone. It must
+// be called before the first request, since the environment is read only once.
+func Configure(proxyURL string) error {
+	if proxyURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy URL %q (expected e.g. http://proxy.example.com:3128)", proxyURL)
+	}
+
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY"} {
+		if err := os.Setenv(name, u.String()); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package schema versions the JSON data files written by the tracker and
+// upgrades older files when they are loaded, so structural changes don't break
+// consumers or the collectors' merge logic.
+//
+// Every data file is a JSON object with a top-level "schemaVersion". Files
+// written before versioning was introduced have no such field and are treated
+// as version 0. To change a file's structure, append a migration to its chain
+// in migrations; the current version is the length of the chain.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Data file kinds
+const (
+	AppVersions       = "app_versions"
+	VersionHistory    = "version_history"
+	SecurityInfo      = "app_security_info"
+	FirstSeen         = "app_first_seen"
+	HistoryCheckpoint = "history_checkpoint"
+	RunStats          = "run_stats"
+	AppsMetadata      = "apps_metadata"
+	PackageParity     = "package_parity"
+	UpstreamLag       = "upstream_lag"
+)
+
+// migration upgrades a decoded document by one version in place
+type migration func(doc map[string]json.RawMessage) error
+
+// migrations lists, per kind, the steps from version i to i+1
+var migrations = map[string][]migration{
+	AppVersions:       {initialVersion},
+	VersionHistory:    {initialVersion},
+	SecurityInfo:      {initialVersion},
+	FirstSeen:         {initialVersion},
+	HistoryCheckpoint: {initialVersion},
+	RunStats:          {initialVersion},
+	AppsMetadata:      {initialVersion},
+	PackageParity:     {initialVersion},
+	UpstreamLag:       {initialVersion},
+}
+
+// initialVersion marks an unversioned file as version 1; the structure is unchanged
+func initialVersion(doc map[string]json.RawMessage) error {
+	return nil
+}
+
+// Current returns the schema version written for kind
+func Current(kind string) int {
+	return len(migrations[kind])
+}
+
+// Version returns the schemaVersion recorded in data (0 if absent)
+func Version(data []byte) (int, error) {
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return header.SchemaVersion, nil
+}
+
+// Upgrade migrates data to the current schema version for kind. Files that are
+// already current are returned unchanged; files from a newer version are rejected.
+func Upgrade(kind string, data []byte) ([]byte, error) {
+	chain, ok := migrations[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown data file kind %q", kind)
+	}
+
+	version, err := Version(data)
+	if err != nil {
+		return nil, err
+	}
+
+	current := len(chain)
+	if version == current {
+		return data, nil
+	}
+	if version > current {
+		return nil, fmt.Errorf("%s schema version %d is newer than supported version %d", kind, version, current)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", kind, err)
+	}
+
+	for v := version; v < current; v++ {
+		if err := chain[v](doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s from version %d: %w", kind, v, err)
+		}
+	}
+
+	doc["schemaVersion"] = json.RawMessage(fmt.Sprintf("%d", current))
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+
+	return upgraded, nil
+}
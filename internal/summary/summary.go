@@ -0,0 +1,76 @@
+// Package summary writes markdown run summaries to the GitHub Actions job
+// summary ($GITHUB_STEP_SUMMARY) so run outcomes are visible without reading logs.
+package summary
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Enabled reports whether a step summary file is available (i.e. running under Actions)
+func Enabled() bool {
+	return os.Getenv("GITHUB_STEP_SUMMARY") != ""
+}
+
+// Builder accumulates a markdown summary
+type Builder struct {
+	sb strings.Builder
+}
+
+// Heading adds a heading of the given level
+func (b *Builder) Heading(level int, text string) {
+	fmt.Fprintf(&b.sb, "%s %s\n\n", strings.Repeat("#", level), text)
+}
+
+// Line adds a paragraph line
+func (b *Builder) Line(format string, args ...interface{}) {
+	fmt.Fprintf(&b.sb, format+"\n", args...)
+}
+
+// Table adds a table; cells are escaped so pipes don't break the layout
+func (b *Builder) Table(header []string, rows [][]string) {
+	b.row(header)
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	b.row(sep)
+	for _, row := range rows {
+		b.row(row)
+	}
+	b.sb.WriteString("\n")
+}
+
+func (b *Builder) row(cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " ")
+	}
+	fmt.Fprintf(&b.sb, "| %s |\n", strings.Join(escaped, " | "))
+}
+
+// String returns the markdown built so far
+func (b *Builder) String() string {
+	return b.sb.String()
+}
+
+// Write appends the summary to $GITHUB_STEP_SUMMARY; it does nothing outside Actions
+func (b *Builder) Write() error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open step summary: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(b.String() + "\n"); err != nil {
+		return fmt.Errorf("failed to write step summary: %w", err)
+	}
+
+	return nil
+}
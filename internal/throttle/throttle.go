@@ -0,0 +1,75 @@
+// Package throttle caps how fast the security info collectors download
+// installers (--max-bandwidth), so a run doesn't saturate a shared link.
+package throttle
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reader slows reads down to rate bytes per second on average
+type reader struct {
+	r     io.Reader
+	rate  int64
+	start time.Time
+	read  int64
+}
+
+// NewReader returns r slowed down to rate bytes per second on average,
+// counted from now
+func NewReader(r io.Reader, rate int64) io.Reader {
+	return &reader{r: r, rate: rate, start: time.Now()}
+}
+
+func (t *reader) Read(p []byte) (int, error) {
+	// Read in slices of at most a tenth of a second so the pace stays smooth
+	if chunk := t.rate / 10; chunk > 0 && int64(len(p)) > chunk {
+		p = p[:chunk]
+	}
+	n, err := t.r.Read(p)
+	t.read += int64(n)
+
+	expected := time.Duration(float64(t.read) / float64(t.rate) * float64(time.Second))
+	if wait := expected - time.Since(t.start); wait > 0 {
+		time.Sleep(wait)
+	}
+	return n, err
+}
+
+// ParseRate parses a byte rate such as "500K", "5M" or "1G" (binary
+// multiples); an empty string means unlimited
+func ParseRate(value string) (int64, error) {
+	input := value
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "/S"), "B")
+	if value == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	switch value[len(value)-1] {
+	case 'K':
+		multiplier = 1 << 10
+	case 'M':
+		multiplier = 1 << 20
+	case 'G':
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a byte rate like 500K or 5M", value)
+	}
+	// A rate that rounds down to 0 would read as unlimited
+	rate := int64(n * float64(multiplier))
+	if rate == 0 && n > 0 {
+		return 0, fmt.Errorf("%q is below 1 byte per second", input)
+	}
+	return rate, nil
+}
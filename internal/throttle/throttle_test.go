@@ -0,0 +1,46 @@
+package throttle
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"500", 500, false},
+		{"500K", 500 << 10, false},
+		{"5M", 5 << 20, false},
+		{"1.5mb/s", 3 << 19, false},
+		{"1G", 1 << 30, false},
+		{"0.5", 0, true},
+		{"0.5B", 0, true},
+		{"-1M", 0, true},
+		{"fast", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseRate(%q) = %d, %v; want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 2000)
+	start := time.Now()
+	got, err := io.ReadAll(NewReader(bytes.NewReader(data), 10000))
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("ReadAll() = %d bytes, %v; want %d bytes", len(got), err, len(data))
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("2000 bytes at 10000 B/s took %v, want about 200ms", elapsed)
+	}
+}
@@ -0,0 +1,82 @@
+// Package tlsinfo records how an installer download was served: the TLS
+// certificate of the host that returned the bytes, or the fact that it was
+// served over plain HTTP.
+package tlsinfo
+
+import (
+	"crypto/x509"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ExpiryWarning is how close to expiry a certificate must be to be flagged
+const ExpiryWarning = 30 * 24 * time.Hour
+
+// Info describes the connection an installer was downloaded over
+type Info struct {
+	Host        string `json:"host"`
+	Subject     string `json:"subject,omitempty"`
+	Issuer      string `json:"issuer,omitempty"`
+	NotAfter    string `json:"notAfter,omitempty"`
+	PlainHTTP   bool   `json:"plainHttp,omitempty"`   // Served (or redirected through) plain HTTP
+	ExpiresSoon bool   `json:"expiresSoon,omitempty"` // Certificate expires within ExpiryWarning
+}
+
+// FromResponse inspects a completed response. Redirects are followed by the
+// client, so the certificate is the one of the host that served the body; a
+// plain HTTP hop anywhere in the redirect chain is flagged.
+func FromResponse(resp *http.Response) *Info {
+	if resp == nil || resp.Request == nil || resp.Request.URL == nil {
+		return nil
+	}
+
+	info := &Info{Host: resp.Request.URL.Hostname()}
+
+	for req := resp.Request; req != nil; req = previous(req) {
+		if strings.EqualFold(req.URL.Scheme, "http") {
+			info.PlainHTTP = true
+		}
+	}
+
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		info.PlainHTTP = true
+		return info
+	}
+
+	leaf := resp.TLS.PeerCertificates[0]
+	info.Subject = leaf.Subject.String()
+	info.Issuer = leaf.Issuer.String()
+	info.NotAfter = leaf.NotAfter.UTC().Format(time.RFC3339)
+	info.ExpiresSoon = expiresWithin(leaf, ExpiryWarning)
+
+	return info
+}
+
+// Warnings returns human-readable problems with the download, if any
+func (i *Info) Warnings() []string {
+	if i == nil {
+		return nil
+	}
+
+	var warnings []string
+	if i.PlainHTTP {
+		warnings = append(warnings, "downloaded over plain HTTP from "+i.Host)
+	}
+	if i.ExpiresSoon {
+		warnings = append(warnings, "TLS certificate for "+i.Host+" expires "+i.NotAfter)
+	}
+	return warnings
+}
+
+// previous returns the request that redirected to req
+func previous(req *http.Request) *http.Request {
+	if req.Response == nil {
+		return nil
+	}
+	return req.Response.Request
+}
+
+func expiresWithin(cert *x509.Certificate, d time.Duration) bool {
+	return time.Until(cert.NotAfter) < d
+}
@@ -0,0 +1,256 @@
+// Package tracing records OpenTelemetry-compatible spans and exports them over
+// OTLP/HTTP (JSON encoding) when an endpoint is configured through the
+// standard environment variables:
+//
+//	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT  full traces URL, e.g. http://localhost:4318/v1/traces
+//	OTEL_EXPORTER_OTLP_ENDPOINT         base URL; /v1/traces is appended
+//	OTEL_EXPORTER_OTLP_HEADERS          comma-separated key=value request headers
+//	OTEL_SERVICE_NAME                   overrides the service name
+//
+// Without an endpoint the tracer is disabled and every operation is a no-op.
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OTLP status and span kind codes
+const (
+	statusOK     = 1
+	statusError  = 2
+	kindInternal = 1
+)
+
+// Tracer collects spans for a single trace and exports them in batches
+type Tracer struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	traceID     string
+	client      *http.Client
+
+	mu    sync.Mutex
+	ended []*Span
+}
+
+// Span is a timed operation within the trace. A nil *Span is valid and
+// ignores all calls, so callers don't need to check whether tracing is enabled.
+type Span struct {
+	tracer   *Tracer
+	id       string
+	parentID string
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    map[string]interface{}
+	err      error
+}
+
+// NewFromEnv returns a tracer configured from the OTEL_* environment variables
+func NewFromEnv(serviceName string) *Tracer {
+	t := &Tracer{
+		serviceName: serviceName,
+		headers:     make(map[string]string),
+		traceID:     randomHex(16),
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		t.serviceName = name
+	}
+
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		t.endpoint = endpoint
+	} else if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		t.endpoint = strings.TrimRight(endpoint, "/") + "/v1/traces"
+	}
+
+	for _, pair := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if decoded, err := url.QueryUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		t.headers[strings.TrimSpace(key)] = value
+	}
+
+	return t
+}
+
+// Enabled reports whether spans are exported
+func (t *Tracer) Enabled() bool {
+	return t != nil && t.endpoint != ""
+}
+
+// Start begins a span; parent may be nil for a root span
+func (t *Tracer) Start(name string, parent *Span) *Span {
+	if !t.Enabled() {
+		return nil
+	}
+
+	span := &Span{
+		tracer: t,
+		id:     randomHex(8),
+		name:   name,
+		start:  time.Now(),
+		attrs:  make(map[string]interface{}),
+	}
+	if parent != nil {
+		span.parentID = parent.id
+	}
+
+	return span
+}
+
+// Start begins a child span of s
+func (s *Span) Start(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.Start(name, s)
+}
+
+// SetAttr records an attribute; values may be strings, bools, ints or floats
+func (s *Span) SetAttr(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.attrs[key] = value
+}
+
+// End finishes the span, marking it as failed when err is non-nil
+func (s *Span) End(err error) {
+	if s == nil || !s.end.IsZero() {
+		return
+	}
+
+	s.end = time.Now()
+	s.err = err
+
+	s.tracer.mu.Lock()
+	s.tracer.ended = append(s.tracer.ended, s)
+	s.tracer.mu.Unlock()
+}
+
+// Flush exports all ended spans that haven't been exported yet
+func (t *Tracer) Flush() error {
+	if !t.Enabled() {
+		return nil
+	}
+
+	t.mu.Lock()
+	spans := t.ended
+	t.ended = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(t.payload(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to export spans (status %d)", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// payload builds an OTLP ExportTraceServiceRequest in its JSON encoding
+func (t *Tracer) payload(spans []*Span) map[string]interface{} {
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, s := range spans {
+		span := map[string]interface{}{
+			"traceId":           t.traceID,
+			"spanId":            s.id,
+			"name":              s.name,
+			"kind":              kindInternal,
+			"startTimeUnixNano": strconv.FormatInt(s.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.end.UnixNano(), 10),
+			"attributes":        attributes(s.attrs),
+			"status":            map[string]interface{}{"code": statusOK},
+		}
+		if s.parentID != "" {
+			span["parentSpanId"] = s.parentID
+		}
+		if s.err != nil {
+			span["status"] = map[string]interface{}{"code": statusError, "message": s.err.Error()}
+		}
+		otlpSpans = append(otlpSpans, span)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": attributes(map[string]interface{}{"service.name": t.serviceName}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "github.com/fleetdm/fleet-apps-growth-tracker"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+// attributes converts a map to OTLP KeyValue attributes
+func attributes(attrs map[string]interface{}) []interface{} {
+	result := make([]interface{}, 0, len(attrs))
+	for key, value := range attrs {
+		var v map[string]interface{}
+		switch value := value.(type) {
+		case bool:
+			v = map[string]interface{}{"boolValue": value}
+		case int:
+			v = map[string]interface{}{"intValue": strconv.Itoa(value)}
+		case int64:
+			v = map[string]interface{}{"intValue": strconv.FormatInt(value, 10)}
+		case float64:
+			v = map[string]interface{}{"doubleValue": value}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprint(value)}
+		}
+		result = append(result, map[string]interface{}{"key": key, "value": v})
+	}
+	return result
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,230 @@
+// Package vcr records HTTP responses to a cassette file and replays them, so
+// the data scripts can be tested end to end without network access. Scripts
+// call InstallFromEnv at startup; it does nothing unless VCR_MODE is set:
+//
+//	VCR_MODE=record VCR_CASSETTE=e2e/testdata/main.json go run main.go
+//	VCR_MODE=replay VCR_CASSETTE=e2e/testdata/main.json go run main.go
+package vcr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// Environment variables read by InstallFromEnv
+const (
+	ModeEnv     = "VCR_MODE"
+	CassetteEnv = "VCR_CASSETTE"
+)
+
+// Modes
+const (
+	Record = "record"
+	Replay = "replay"
+)
+
+// recordedHeaders are the response headers kept in cassettes; the rest are
+// dropped so fixtures stay small and free of per-request noise
+var recordedHeaders = []string{
+	"Content-Type",
+	"ETag",
+	"Last-Modified",
+	"Link",
+	"Retry-After",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Status     int               `json:"status"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	BodyBase64 string            `json:"bodyBase64,omitempty"` // Used instead of Body for binary responses
+}
+
+// Cassette is the fixture file format
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Transport is an http.RoundTripper that records to or replays from a cassette
+type Transport struct {
+	mode string
+	path string
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     map[int]bool
+}
+
+// New returns a transport in the given mode. Recording starts a new cassette
+// at path and sends requests through next; replaying loads path and never
+// touches the network.
+func New(mode, path string, next http.RoundTripper) (*Transport, error) {
+	t := &Transport{mode: mode, path: path, next: next, used: make(map[int]bool)}
+
+	switch mode {
+	case Record:
+		if err := t.save(); err != nil {
+			return nil, err
+		}
+	case Replay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &t.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown %s %q (expected %q or %q)", ModeEnv, mode, Record, Replay)
+	}
+
+	return t, nil
+}
+
+// InstallFromEnv wraps http.DefaultTransport according to VCR_MODE and
+// VCR_CASSETTE. Every client in this repo uses the default transport, so this
+// covers GitHub calls and downloads alike.
+func InstallFromEnv() error {
+	mode := os.Getenv(ModeEnv)
+	if mode == "" {
+		return nil
+	}
+	path := os.Getenv(CassetteEnv)
+	if path == "" {
+		return fmt.Errorf("%s is set but %s is not", ModeEnv, CassetteEnv)
+	}
+
+	t, err := New(mode, path, http.DefaultTransport)
+	if err != nil {
+		return err
+	}
+	http.DefaultTransport = t
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == Replay {
+		return t.replay(req), nil
+	}
+	return t.record(req)
+}
+
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			if interaction.Headers == nil {
+				interaction.Headers = make(map[string]string)
+			}
+			interaction.Headers[name] = value
+		}
+	}
+	if utf8.Valid(body) {
+		interaction.Body = string(body)
+	} else {
+		interaction.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// replay answers with the first unused interaction recorded for the request,
+// reusing the last one once all have been played. Requests that were never
+// recorded get a 404 so callers fail fast instead of retrying.
+func (t *Transport) replay(req *http.Request) *http.Response {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	match := -1
+	for i, interaction := range t.cassette.Interactions {
+		if interaction.Method != req.Method || interaction.URL != req.URL.String() {
+			continue
+		}
+		match = i
+		if !t.used[i] {
+			break
+		}
+	}
+
+	if match == -1 {
+		return response(req, http.StatusNotFound, nil, []byte(fmt.Sprintf("vcr: no recorded response for %s %s", req.Method, req.URL)))
+	}
+	t.used[match] = true
+
+	interaction := t.cassette.Interactions[match]
+	body := []byte(interaction.Body)
+	if interaction.BodyBase64 != "" {
+		decoded, err := base64.StdEncoding.DecodeString(interaction.BodyBase64)
+		if err != nil {
+			return response(req, http.StatusInternalServerError, nil, []byte(fmt.Sprintf("vcr: corrupt body for %s: %v", req.URL, err)))
+		}
+		body = decoded
+	}
+
+	return response(req, interaction.Status, interaction.Headers, body)
+}
+
+func (t *Transport) save() error {
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.WriteFile(t.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+func response(req *http.Request, status int, headers map[string]string, body []byte) *http.Response {
+	header := make(http.Header, len(headers))
+	for name, value := range headers {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,107 @@
+package vcr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Link", `<https://example.com/next>; rel="next"`)
+		w.Header().Set("X-Request-Id", "dropped")
+		if r.URL.Path == "/binary" {
+			w.Write([]byte{0xff, 0xfe, 0x00})
+			return
+		}
+		io.WriteString(w, "hello "+r.URL.Path)
+	}))
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder, err := New(Record, cassette, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+	for _, path := range []string{"/a", "/binary"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	server.Close()
+
+	player, err := New(Replay, cassette, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: player}
+
+	resp, err := client.Get(server.URL + "/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "hello /a" {
+		t.Errorf("body = %q, want %q", body, "hello /a")
+	}
+	if got := resp.Header.Get("Link"); got == "" {
+		t.Error("Link header was not replayed")
+	}
+	if got := resp.Header.Get("X-Request-Id"); got != "" {
+		t.Errorf("X-Request-Id = %q, want it dropped from the cassette", got)
+	}
+
+	resp, err = client.Get(server.URL + "/binary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "\xff\xfe\x00" {
+		t.Errorf("binary body = %q", body)
+	}
+
+	if calls != 2 {
+		t.Errorf("server saw %d requests, want 2 (replay must not hit the network)", calls)
+	}
+}
+
+func TestReplayInOrderThenRepeatLast(t *testing.T) {
+	player := &Transport{mode: Replay, used: make(map[int]bool), cassette: Cassette{Interactions: []Interaction{
+		{Method: "GET", URL: "https://example.com/x", Status: 500, Body: "first"},
+		{Method: "GET", URL: "https://example.com/x", Status: 200, Body: "second"},
+	}}}
+	client := &http.Client{Transport: player}
+
+	for _, want := range []int{500, 200, 200} {
+		resp, err := client.Get("https://example.com/x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("status = %d, want %d", resp.StatusCode, want)
+		}
+	}
+}
+
+func TestReplayMissIsNotFound(t *testing.T) {
+	player := &Transport{mode: Replay, used: make(map[int]bool)}
+	client := &http.Client{Transport: player}
+
+	resp, err := client.Get("https://example.com/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+}
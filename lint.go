@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/lint"
+)
+
+// lint.go - Checks the upstream apps.json and the generated data files for
+// consistency problems. Exits non-zero when any problem is found:
+//
+//	go run lint.go [-apps-json path/to/apps.json]
+func main() {
+	lint.Main(cli.Defaults(), os.Args[1:])
+}
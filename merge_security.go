@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/mergesecurity"
+)
+
+// merge_security.go - Merges the app_security_info.json written by each job
+// of a matrix-sharded collection run into one file, so a single job commits
+// the result instead of every shard racing to push its own:
+//
+//	go run merge_security.go shards/*/app_security_info.json
+//
+// Every fragment is validated first (schema version, a slug, version and
+// RFC 3339 lastUpdated on every entry, no slug listed twice). When fragments
+// disagree about an app, or an older version of one, the entry with the
+// newest lastUpdated wins. Pass the current file as well to keep entries no
+// shard wrote.
+func main() {
+	mergesecurity.Main(cli.Defaults(), os.Args[1:])
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/probe"
+)
+
+// probe_installers.go - Checks that every current installer URL is reachable:
+//
+//	go run probe_installers.go [--concurrency 8] [--timeout 30s] [--retention-days 90]
+//
+// Each installer URL gets a HEAD request (falling back to a one-byte ranged GET
+// for hosts that reject HEAD). Results are appended to data/installer_uptime.jsonl,
+// which generate_html.go turns into per-app availability percentages.
+func main() {
+	probe.Main(cli.Defaults(), os.Args[1:])
+}
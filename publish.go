@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/publish"
+)
+
+// publish.go - Syncs the generated site and data files to an S3 or GCS
+// bucket, so the dashboard and JSON files can be served from a CDN:
+//
+//	go run publish.go --bucket s3://my-bucket/fleet-apps [--delete] [--dry-run]
+//	go run publish.go --bucket gs://my-bucket
+//
+// Only files whose MD5 differs from the object's ETag are uploaded.
+func main() {
+	publish.Main(cli.Defaults(), os.Args[1:])
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/report"
+)
+
+// report.go - Renders a monthly summary as reports/YYYY-MM.md, or compares
+// the catalog at two dates as reports/compare-FROM-TO.md and .json:
+//
+//	go run report.go monthly [--month 2025-12]
+//	go run report.go compare --from 2025-01-01 [--to 2025-06-01]
+func main() {
+	report.Main(cli.Defaults(), os.Args[1:])
+}
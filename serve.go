@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/serve"
+)
+
+// serve.go - Serves the generated site and a JSON API over the data directory:
+//
+//	go run serve.go [--addr 127.0.0.1:8080] [--dir .] [--max-age 60] [--watch-interval 2s]
+//
+// Endpoints: /api/apps, /api/apps/{slug}, /api/growth, /api/security/{slug},
+// a GraphQL endpoint at /graphql (schema at /graphql/schema) and a
+// Server-Sent Events stream at /api/events. Slugs include the platform, e.g.
+// /api/apps/1password/darwin. Data files are read on every request, so
+// regenerating them doesn't require a restart.
+func main() {
+	serve.Main(cli.Defaults(), os.Args[1:])
+}
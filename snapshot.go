@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/snapshot"
+)
+
+// snapshot.go - Packs every dataset into snapshots/data-YYYY.MM.tar.gz, the
+// asset of the monthly data-YYYY.MM release:
+//
+//	go run snapshot.go [--month 2025-06]
+//
+// The archive is reproducible: entries are sorted and carry a fixed owner,
+// mode and timestamp (the end of the month), so the same data always yields
+// the same bytes.
+func main() {
+	snapshot.Main(cli.Defaults(), os.Args[1:])
+}
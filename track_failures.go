@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/trackfailures"
+)
+
+// track_failures.go - Keeps one GitHub issue open per app whose security info
+// can't be collected. After a collector run with --results, it files a
+// labeled issue for each newly failing app and, when an app with an open
+// issue is collected again, comments on and closes it:
+//
+//	go run main.go --results /tmp/results.json   # in cmd/collect-security-info
+//	go run track_failures.go --results /tmp/results.json
+//
+// Apps the run didn't get to are left alone. Requires GITHUB_TOKEN with
+// issues: write.
+func main() {
+	trackfailures.Main(cli.Defaults(), os.Args[1:])
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/upstreamlag"
+)
+
+// upstream_lag.go - Compares Fleet's version of each app with the vendor's
+// latest release and records how many days Fleet has been behind:
+//
+//	go run upstream_lag.go
+//
+// Vendor versions come from the Sparkle appcast or GitHub releases listed per
+// slug in data/upstream_sources.json and, for Windows apps, from the winget
+// and Chocolatey versions crossref_packages.go recorded. Results go to
+// data/upstream_lag.json, which generate_html.go ranks in a freshness
+// leaderboard.
+func main() {
+	upstreamlag.Main(cli.Defaults(), os.Args[1:])
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/cli"
+	"github.com/fleetdm/fleet-apps-growth-tracker/internal/commands/verify"
+)
+
+// verify.go - Checks a downloaded installer, or an installed app bundle,
+// against the hashes and signer recorded in data/app_security_info.json,
+// including previous versions, and exits non-zero unless it passes:
+//
+//	go run verify.go --slug zoom/darwin --file ~/Downloads/zoomusInstallerFull.pkg
+//	go run verify.go --slug zoom/darwin --file /Applications/zoom.us.app
+func main() {
+	verify.Main(cli.Defaults(), os.Args[1:])
+}